@@ -2,60 +2,421 @@ package article
 
 import (
 	"context"
+	"math"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/ryanadiputraa/unclatter/app/collection"
 	"github.com/ryanadiputraa/unclatter/app/pagination"
+	"github.com/ryanadiputraa/unclatter/app/tag"
+	"github.com/ryanadiputraa/unclatter/pkg/nlp"
+	"github.com/ryanadiputraa/unclatter/pkg/plaintext"
+	"gorm.io/gorm"
 )
 
+// DefaultLanguage is the text search configuration used when none is given.
+const DefaultLanguage = "english"
+
+// Languages lists the supported postgres text search configurations.
+var Languages = []string{
+	"simple", "arabic", "armenian", "basque", "catalan", "danish", "dutch", "english", "finnish", "french",
+	"german", "greek", "hindi", "hungarian", "indonesian", "irish", "italian", "lithuanian", "nepali",
+	"norwegian", "portuguese", "romanian", "russian", "serbian", "spanish", "swedish", "tamil", "turkish", "yiddish",
+}
+
 type Article struct {
-	ID          string    `json:"id" gorm:"type:varchar"`
-	Title       string    `json:"title" gorm:"type:varchar;unique;not null"`
-	Content     string    `json:"content,omitempty" gorm:"type:text;not null"`
-	ArticleLink string    `json:"article_link" gorm:"type:varchar;not null"`
-	UserID      string    `json:"-" gorm:"type:varchar;not null"`
-	CreatedAt   time.Time `json:"created_at" gorm:"type:timestamptz;not null"`
-	UpdatedAt   time.Time `json:"updated_at" gorm:"type:timestamptz;not null"`
+	ID          string `json:"id" gorm:"type:varchar"`
+	Title       string `json:"title" gorm:"type:varchar;not null"`
+	Content     string `json:"content,omitempty" gorm:"type:text;not null"`
+	ArticleLink string `json:"article_link" gorm:"type:varchar;not null"`
+	// NormalizedLink is unique per user outside the trash.
+	NormalizedLink string `json:"-" gorm:"type:varchar;uniqueIndex:idx_articles_active_user_link,priority:2,where:deleted_at IS NULL"`
+	Language       string `json:"language" gorm:"type:regconfig;not null;default:'english'"`
+	Domain         string `json:"domain" gorm:"type:varchar;not null;default:'';index"`
+	// ReadingTime is in minutes.
+	ReadingTime int    `json:"reading_time" gorm:"type:integer;not null;default:0"`
+	Excerpt     string `json:"excerpt" gorm:"type:text;not null;default:''"`
+	// Fingerprint is a simhash, near-duplicates differ in a few bits.
+	Fingerprint  int64   `json:"-" gorm:"type:bigint;not null;default:0"`
+	Keywords     string  `json:"-" gorm:"type:text;not null;default:''"`
+	UserID       string  `json:"-" gorm:"type:varchar;not null;uniqueIndex:idx_articles_active_user_link,priority:1,where:deleted_at IS NULL"`
+	CollectionID *string `json:"collection_id" gorm:"type:varchar;index"`
+	// Version is bumped on every edit and sent back in If-Match.
+	Version int `json:"version" gorm:"type:integer;not null;default:1"`
+	// IndexedVersion is the version the term vector was built from.
+	IndexedVersion int `json:"-" gorm:"type:integer;not null;default:0;index:idx_articles_unindexed,where:indexed_version <> version"`
+	// SanitizedVersion is the sanitizer version the content was last migrated with.
+	SanitizedVersion int            `json:"-" gorm:"type:integer;not null;default:0;index"`
+	CreatedAt        time.Time      `json:"created_at" gorm:"type:timestamptz;not null"`
+	UpdatedAt        time.Time      `json:"updated_at" gorm:"type:timestamptz;not null"`
+	DeletedAt        gorm.DeletedAt `json:"deleted_at" gorm:"type:timestamptz;index"`
+
+	ReadAt       *time.Time `json:"read_at" gorm:"type:timestamptz"`
+	ArchivedAt   *time.Time `json:"archived_at" gorm:"type:timestamptz"`
+	FavoritedAt  *time.Time `json:"favorited_at" gorm:"type:timestamptz"`
+	SnoozedUntil *time.Time `json:"snoozed_until" gorm:"type:timestamptz;index"`
+
+	Collection *collection.Collection `json:"-" gorm:"constraint:OnDelete:SET NULL"`
+	Tags       []*tag.Tag             `json:"tags,omitempty" gorm:"many2many:article_tags;constraint:OnDelete:CASCADE"`
+
+	// SearchVector is maintained by postgres.
+	SearchVector string `json:"-" gorm:"type:tsvector GENERATED ALWAYS AS (setweight(to_tsvector(language, coalesce(title, '')), 'A') || setweight(to_tsvector(language, coalesce(content, '')), 'B')) STORED;index:idx_articles_search_vector,type:gin;->:false;<-:false"`
+	// Snippet is only set on search results.
+	Snippet string `json:"snippet,omitempty" gorm:"->;-:migration"`
+	// Similarity is only set on related lists.
+	Similarity     float64    `json:"similarity,omitempty" gorm:"->;-:migration"`
+	NearDuplicates []*Article `json:"near_duplicates,omitempty" gorm:"-"`
+	SuggestedTags  []string   `json:"suggested_tags,omitempty" gorm:"-"`
 }
 
 type NewArticleArg struct {
 	Title       string
 	Content     string
 	ArticleLink string
+	Language    string
+	Keywords    []string
 	UserID      string
 }
 
 type BookmarkPayload struct {
-	Title       string `json:"title" validate:"required"`
-	Content     string `json:"content" validate:"required"`
-	ArticleLink string `json:"article_link" validate:"required,http_url"`
+	Title       string   `json:"title" validate:"required"`
+	Content     string   `json:"content" validate:"required"`
+	ArticleLink string   `json:"article_link" validate:"required,http_url"`
+	Language    string   `json:"language"`
+	Keywords    []string `json:"keywords" validate:"max=50,dive,max=256"`
+}
+
+type State string
+
+const (
+	StateRead      State = "read"
+	StateArchived  State = "archived"
+	StateFavorited State = "favorited"
+)
+
+func (s State) Column() string {
+	switch s {
+	case StateRead:
+		return "read_at"
+	case StateArchived:
+		return "archived_at"
+	case StateFavorited:
+		return "favorited_at"
+	default:
+		return ""
+	}
+}
+
+// List filters, no filter hides archived and snoozed articles.
+const (
+	FilterUnread    = "unread"
+	FilterArchived  = "archived"
+	FilterFavorites = "favorites"
+	FilterSnoozed   = "snoozed"
+)
+
+type ListFilter struct {
+	// Query terms are matched as prefixes.
+	Query        string
+	Language     string
+	Status       string
+	CollectionID string
+	// SharedCollection lists the articles of every member of CollectionID.
+	SharedCollection bool
+	Tag              string
+	Domain           string
+	// A nil date bound is open.
+	CreatedFrom  *time.Time
+	CreatedTo    *time.Time
+	UpdatedFrom  *time.Time
+	UpdatedTo    *time.Time
+	Read         *bool
+	SnoozedUntil *time.Time
+	LinkHealth   LinkHealth
+	// Sort defaults to rank for searches and SortUpdatedAt otherwise.
+	Sort string
+	// Order defaults to ascending for titles and descending otherwise.
+	Order string
+}
+
+// List sort fields and directions.
+const (
+	SortCreatedAt   = "created_at"
+	SortUpdatedAt   = "updated_at"
+	SortTitle       = "title"
+	SortReadingTime = "reading_time"
+
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+func (f ListFilter) Direction() string {
+	if f.Order != "" {
+		return f.Order
+	}
+	if f.Sort == SortTitle {
+		return OrderAsc
+	}
+	return OrderDesc
+}
+
+// IsKeysetOrdered reports whether cursors can page the list.
+func (f ListFilter) IsKeysetOrdered() bool {
+	if f.Query != "" && f.Sort == "" {
+		return false
+	}
+	return (f.Sort == "" || f.Sort == SortUpdatedAt) && f.Direction() == OrderDesc
+}
+
+type ListParams struct {
+	Sort         string `validate:"omitempty,oneof=created_at updated_at title reading_time"`
+	Order        string `validate:"omitempty,oneof=asc desc"`
+	Domain       string `validate:"omitempty,max=253"`
+	CreatedFrom  string `validate:"omitempty,iso8601date"`
+	CreatedTo    string `validate:"omitempty,iso8601date"`
+	UpdatedFrom  string `validate:"omitempty,iso8601date"`
+	UpdatedTo    string `validate:"omitempty,iso8601date"`
+	Read         string `validate:"omitempty,oneof=true false"`
+	SnoozedUntil string `validate:"omitempty,iso8601date"`
+	LinkHealth   string `validate:"omitempty,oneof=ok moved changed broken unchecked"`
+}
+
+func (p ListParams) Apply(filter *ListFilter) (errDetail map[string]string) {
+	errDetail = make(map[string]string)
+	filter.Sort = p.Sort
+	filter.Order = p.Order
+	filter.Domain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(p.Domain)), "www.")
+	filter.CreatedFrom = parseDate(p.CreatedFrom)
+	filter.CreatedTo = parseDate(p.CreatedTo)
+	filter.UpdatedFrom = parseDate(p.UpdatedFrom)
+	filter.UpdatedTo = parseDate(p.UpdatedTo)
+	filter.SnoozedUntil = parseDate(p.SnoozedUntil)
+	filter.LinkHealth = LinkHealth(p.LinkHealth)
+	if p.Read != "" {
+		read := p.Read == "true"
+		filter.Read = &read
+	}
+
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && filter.CreatedTo.Before(*filter.CreatedFrom) {
+		errDetail["created_to"] = "created_to should be greater than or equal to created_from"
+	}
+	if filter.UpdatedFrom != nil && filter.UpdatedTo != nil && filter.UpdatedTo.Before(*filter.UpdatedFrom) {
+		errDetail["updated_to"] = "updated_to should be greater than or equal to updated_from"
+	}
+	return
+}
+
+func parseDate(date string) *time.Time {
+	t, err := time.Parse(time.RFC3339Nano, date)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+type SnoozePayload struct {
+	Until string `json:"until" validate:"required,iso8601date"`
+}
+
+const MaxBulkSize = 500
+
+type BulkAction string
+
+const (
+	BulkDelete   BulkAction = "delete"
+	BulkArchive  BulkAction = "archive"
+	BulkMarkRead BulkAction = "mark_read"
+	BulkTag      BulkAction = "tag"
+	BulkMove     BulkAction = "move"
+)
+
+type BulkPayload struct {
+	Action BulkAction  `json:"action" validate:"required,oneof=delete archive mark_read tag move"`
+	IDs    []string    `json:"ids" validate:"max=500,dive,required"`
+	Filter *BulkFilter `json:"filter"`
+	Tags   []string    `json:"tags" validate:"max=50,dive,required,max=64"`
+	// A null CollectionID takes the articles out of their collection.
+	CollectionID *string `json:"collection_id"`
+}
+
+type BulkFilter struct {
+	Query      string `json:"q" validate:"max=256"`
+	Language   string `json:"lang"`
+	Status     string `json:"filter"`
+	Collection string `json:"collection"`
+	Tag        string `json:"tag"`
+}
+
+// BulkOperation is a validated bulk action, Filter is used when IDs is empty.
+type BulkOperation struct {
+	Action       BulkAction
+	IDs          []string
+	Filter       *ListFilter
+	Tags         []tag.Tag
+	CollectionID *string
+	At           time.Time
+}
+
+// Bulk result statuses, missing or foreign articles are skipped.
+const (
+	BulkStatusOK        = "ok"
+	BulkStatusNotFound  = "not_found"
+	BulkStatusForbidden = "forbidden"
+)
+
+type BulkResult struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+type StateCounts struct {
+	Total     int64 `json:"total"`
+	Inbox     int64 `json:"inbox"`
+	Unread    int64 `json:"unread"`
+	Read      int64 `json:"read"`
+	Archived  int64 `json:"archived"`
+	Favorites int64 `json:"favorites"`
+	Snoozed   int64 `json:"snoozed"`
 }
 
 func NewArticle(arg NewArticleArg) *Article {
+	language := arg.Language
+	if language == "" {
+		language = DefaultLanguage
+	}
+	normalizedLink, err := NormalizeLink(arg.ArticleLink)
+	if err != nil {
+		normalizedLink = arg.ArticleLink
+	}
+
 	return &Article{
-		ID:          uuid.NewString(),
-		Title:       arg.Title,
-		Content:     arg.Content,
-		ArticleLink: arg.ArticleLink,
-		UserID:      arg.UserID,
-		CreatedAt:   time.Now().UTC(),
-		UpdatedAt:   time.Now().UTC(),
+		ID:             uuid.NewString(),
+		Title:          arg.Title,
+		Content:        arg.Content,
+		ArticleLink:    arg.ArticleLink,
+		NormalizedLink: normalizedLink,
+		Language:       language,
+		Domain:         LinkDomain(arg.ArticleLink),
+		ReadingTime:    ReadingTime(arg.Content),
+		Excerpt:        Summarize(arg.Content, language),
+		Fingerprint:    Fingerprint(arg.Content),
+		Keywords:       JoinKeywords(arg.Keywords),
+		UserID:         arg.UserID,
+		Version:        1,
+		CreatedAt:      time.Now().UTC(),
+		UpdatedAt:      time.Now().UTC(),
+	}
+}
+
+const wordsPerMinute = 200
+
+var htmlTag = regexp.MustCompile(`<[^>]+>`)
+
+// ReadingTime estimates the minutes to read the content, at least one.
+func ReadingTime(content string) int {
+	words := len(strings.Fields(htmlTag.ReplaceAllString(content, " ")))
+	return max(1, int(math.Ceil(float64(words)/wordsPerMinute)))
+}
+
+const SummarySentences = 3
+
+func Summarize(content, language string) string {
+	return strings.Join(nlp.Summarize(plaintext.FromHTML(content), language, SummarySentences), " ")
+}
+
+func JoinKeywords(keywords []string) string {
+	return strings.Join(keywords, ",")
+}
+
+func SuggestTags(a Article, existing []*tag.Tag) []string {
+	assigned := make([]string, len(a.Tags))
+	for i, t := range a.Tags {
+		assigned[i] = t.Name
+	}
+	return tag.Suggest(tag.Document{
+		Title:    a.Title,
+		Text:     plaintext.FromHTML(a.Content),
+		Language: a.Language,
+		Keywords: []string{a.Keywords},
+		Assigned: assigned,
+	}, existing)
+}
+
+func IsSupportedLanguage(language string) bool {
+	for _, l := range Languages {
+		if l == language {
+			return true
+		}
 	}
+	return false
 }
 
 type ArticleService interface {
 	ScrapeContent(ctx context.Context, url string) (string, error)
 	BookmarkArticle(ctx context.Context, arg BookmarkPayload, userID string) (*Article, error)
-	ListBookmarkedArticles(ctx context.Context, userID string, page pagination.Pagination) ([]*Article, *pagination.Meta, error)
+	ListBookmarkedArticles(ctx context.Context, userID string, filter ListFilter, page pagination.Pagination) ([]*Article, *pagination.Meta, error)
 	GetBookmarkedArticle(ctx context.Context, userID, articleID string) (*Article, error)
-	UpdateArticle(ctx context.Context, userID, articleID string, arg BookmarkPayload) (*Article, error)
+	// A zero version skips the version check of UpdateArticle and PatchArticle.
+	UpdateArticle(ctx context.Context, userID, articleID string, arg BookmarkPayload, version int) (*Article, error)
+	PatchArticle(ctx context.Context, userID, articleID string, arg PatchPayload, version int) (*Article, error)
 	DeleteArticle(ctx context.Context, userID, articleID string) error
+	ListTrashedArticles(ctx context.Context, userID string, page pagination.Pagination) ([]*Article, *pagination.Meta, error)
+	RestoreArticle(ctx context.Context, userID, articleID string) (*Article, error)
+	PurgeArticle(ctx context.Context, userID, articleID string) error
+	PurgeExpiredTrash(ctx context.Context, retention time.Duration) (int64, error)
+	SetArticleState(ctx context.Context, userID, articleID string, state State, enabled bool) (*Article, error)
+	// SnoozeArticle wakes the article up on a nil time.
+	SnoozeArticle(ctx context.Context, userID, articleID string, until *time.Time) (*Article, error)
+	CountArticleStates(ctx context.Context, userID string) (*StateCounts, error)
+	GetReadingStats(ctx context.Context, userID string, period StatsPeriod) (*Stats, error)
+	SummarizeArticle(ctx context.Context, userID, articleID string) (*Article, error)
+	ListRelatedArticles(ctx context.Context, userID, articleID string, limit int) ([]*Article, error)
+	IndexArticles(ctx context.Context, limit int) (int, error)
+	ListDuplicateClusters(ctx context.Context, userID string) ([]*DuplicateCluster, error)
+	MergeDuplicates(ctx context.Context, userID string, arg MergePayload) (*Article, error)
+	BulkUpdateArticles(ctx context.Context, userID string, arg BulkPayload) ([]*BulkResult, error)
+	ListArticleRevisions(ctx context.Context, userID, articleID string, page pagination.Pagination) ([]*Revision, *pagination.Meta, error)
+	GetArticleRevision(ctx context.Context, userID, articleID, revisionID string) (*Revision, error)
+	// DiffArticleRevisions diffs against the current article when toID is empty.
+	DiffArticleRevisions(ctx context.Context, userID, articleID, fromID, toID string) (*RevisionDiff, error)
+	RestoreArticleRevision(ctx context.Context, userID, articleID, revisionID string, version int) (*Article, error)
+	RescrapeArticle(ctx context.Context, userID, articleID string, version int) (*Article, error)
+	MigrateSanitizedContent(ctx context.Context, limit int) (int, error)
 }
 
 type ArticleRepository interface {
 	Save(ctx context.Context, arg Article) error
-	List(ctx context.Context, userID string, page pagination.Pagination) (articles []*Article, total int64, err error)
+	List(ctx context.Context, userID string, filter ListFilter, page pagination.Pagination) (articles []*Article, total int64, err error)
 	FindByID(ctx context.Context, articleID string) (*Article, error)
-	Update(ctx context.Context, arg Article) (*Article, error)
+	FindByLink(ctx context.Context, userID, normalizedLink string) (*Article, error)
+	// Update skips the version check when arg.Version is zero.
+	Update(ctx context.Context, arg Article, source RevisionSource) (*Article, error)
 	Delete(ctx context.Context, userID, articleID string) error
+	ListTrashed(ctx context.Context, userID string, page pagination.Pagination) (articles []*Article, total int64, err error)
+	Restore(ctx context.Context, userID, articleID string) (*Article, error)
+	Purge(ctx context.Context, userID, articleID string) error
+	PurgeTrashedBefore(ctx context.Context, before time.Time) (int64, error)
+	UpdateState(ctx context.Context, userID, articleID string, state State, at *time.Time) (*Article, error)
+	// UpdateSnooze doesn't bump the version.
+	UpdateSnooze(ctx context.Context, userID, articleID string, until *time.Time) (*Article, error)
+	CountStates(ctx context.Context, userID string) (*StateCounts, error)
+	ReadingStats(ctx context.Context, userID string, period StatsPeriod) (*Stats, error)
+	// UpdateExcerpt doesn't bump the version.
+	UpdateExcerpt(ctx context.Context, articleID, excerpt string) error
+	ListUnindexed(ctx context.Context, limit int) ([]*Article, error)
+	// SaveIndex does nothing when the article was edited or trashed since it was read.
+	SaveIndex(ctx context.Context, a Article, terms []*Term) error
+	ListFingerprinted(ctx context.Context, userID string) ([]*Article, error)
+	FindNearDuplicates(ctx context.Context, userID, articleID string, fingerprint int64) ([]*Article, error)
+	Merge(ctx context.Context, userID, keepID string, duplicateIDs []string) (*Article, error)
+	ListRelated(ctx context.Context, userID, articleID string, limit int) ([]*Article, error)
+	BulkUpdate(ctx context.Context, userID string, op BulkOperation) ([]*BulkResult, error)
+	// ListRevisions leaves out the content.
+	ListRevisions(ctx context.Context, articleID string, page pagination.Pagination) (revisions []*Revision, total int64, err error)
+	FindRevision(ctx context.Context, articleID, revisionID string) (*Revision, error)
+	ListUnsanitized(ctx context.Context, version, limit int) ([]*Article, error)
+	// MarkSanitized doesn't bump the version.
+	MarkSanitized(ctx context.Context, articleID string, version int) error
 }
@@ -1,6 +1,7 @@
 package article
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -30,6 +31,9 @@ func TestNewArticle(t *testing.T) {
 				Title:       "Title",
 				Content:     "<p>Sample Content Body</p>",
 				ArticleLink: "https://unclatter.com",
+				Domain:      "unclatter.com",
+				ReadingTime: 1,
+				Excerpt:     "Sample Content Body",
 				UserID:      uuid,
 				CreatedAt:   time.Now().UTC(),
 				UpdatedAt:   time.Now().UTC(),
@@ -46,9 +50,146 @@ func TestNewArticle(t *testing.T) {
 			assert.Equal(t, c.expected.Title, user.Title)
 			assert.Equal(t, c.expected.Content, user.Content)
 			assert.Equal(t, c.expected.ArticleLink, user.ArticleLink)
+			assert.Equal(t, c.expected.Domain, user.Domain)
+			assert.Equal(t, c.expected.ReadingTime, user.ReadingTime)
+			assert.Equal(t, c.expected.Excerpt, user.Excerpt)
 			assert.Equal(t, c.expected.UserID, user.UserID)
 			assert.NotEmpty(t, user.CreatedAt)
 			assert.NotEmpty(t, user.UpdatedAt)
 		})
 	}
 }
+
+func TestReadingTime(t *testing.T) {
+	cases := []struct {
+		name     string
+		content  string
+		expected int
+	}{
+		{
+			name:     "should return at least a minute",
+			content:  "",
+			expected: 1,
+		},
+		{
+			name:     "should round up partial minutes ignoring markup",
+			content:  "<p>" + strings.Repeat("word ", 201) + "</p><img src=\"cover.png\" alt=\"a long alt text\">",
+			expected: 2,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, ReadingTime(c.content))
+		})
+	}
+}
+
+func TestListParamsApply(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	read := false
+
+	cases := []struct {
+		name      string
+		arg       ListParams
+		expected  ListFilter
+		errDetail map[string]string
+	}{
+		{
+			name: "should add sort and filters to the list filter",
+			arg: ListParams{
+				Sort:        SortTitle,
+				Domain:      "WWW.Example.com",
+				CreatedFrom: from.Format(time.RFC3339),
+				CreatedTo:   to.Format(time.RFC3339),
+				Read:        "false",
+			},
+			expected: ListFilter{
+				Sort:        SortTitle,
+				Domain:      "example.com",
+				CreatedFrom: &from,
+				CreatedTo:   &to,
+				Read:        &read,
+			},
+			errDetail: map[string]string{},
+		},
+		{
+			name: "should return err detail when date range is reversed",
+			arg: ListParams{
+				UpdatedFrom: to.Format(time.RFC3339),
+				UpdatedTo:   from.Format(time.RFC3339),
+			},
+			expected: ListFilter{
+				UpdatedFrom: &to,
+				UpdatedTo:   &from,
+			},
+			errDetail: map[string]string{"updated_to": "updated_to should be greater than or equal to updated_from"},
+		},
+		{
+			name: "should add snoozed until to the list filter",
+			arg: ListParams{
+				SnoozedUntil: to.Format(time.RFC3339),
+			},
+			expected: ListFilter{
+				SnoozedUntil: &to,
+			},
+			errDetail: map[string]string{},
+		},
+		{
+			name: "should add link health to the list filter",
+			arg: ListParams{
+				LinkHealth: string(LinkHealthBroken),
+			},
+			expected: ListFilter{
+				LinkHealth: LinkHealthBroken,
+			},
+			errDetail: map[string]string{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var filter ListFilter
+			errDetail := c.arg.Apply(&filter)
+			assert.Equal(t, c.errDetail, errDetail)
+			assert.Equal(t, c.expected, filter)
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	cases := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "should return empty excerpt for empty content",
+			content:  "",
+			expected: "",
+		},
+		{
+			name:     "should fall back to leading fragments when no sentence is long enough",
+			content:  "<h1>Title</h1><p>Short note.</p>",
+			expected: "Title Short note.",
+		},
+		{
+			name: "should pick the most central sentences in reading order",
+			content: "<p>Go is a programming language designed at Google for building reliable software. " +
+				"The weather was sunny during the first week of the conference in town. " +
+				"Go programs compile quickly into a single binary that is easy to deploy. " +
+				"The Go toolchain makes building and testing software with the language simple. " +
+				"Many teams choose Go for building network software and reliable services.</p>",
+			expected: "Go is a programming language designed at Google for building reliable software. " +
+				"The Go toolchain makes building and testing software with the language simple. " +
+				"Many teams choose Go for building network software and reliable services.",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, Summarize(c.content, DefaultLanguage))
+		})
+	}
+}
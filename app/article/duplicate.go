@@ -0,0 +1,112 @@
+package article
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ryanadiputraa/unclatter/pkg/nlp"
+	"github.com/ryanadiputraa/unclatter/pkg/plaintext"
+)
+
+const (
+	// NearDuplicateDistance is the most bits near-duplicate fingerprints differ in.
+	NearDuplicateDistance = 6
+	MaxNearDuplicates     = 10
+	// minFingerprintWords leaves short articles without a fingerprint.
+	minFingerprintWords = 50
+)
+
+// Fingerprint is zero for content too short to compare.
+func Fingerprint(content string) int64 {
+	words := nlp.Words(plaintext.FromHTML(content))
+	if len(words) < minFingerprintWords {
+		return 0
+	}
+	return int64(nlp.SimHash(words))
+}
+
+func IsNearDuplicate(a, b int64) bool {
+	return a != 0 && b != 0 && nlp.HammingDistance(uint64(a), uint64(b)) <= NearDuplicateDistance
+}
+
+type DuplicateCluster struct {
+	Articles []*Article `json:"articles"`
+}
+
+// Clusters groups near-duplicates, directly or through another article, newest cluster first.
+func Clusters(articles []*Article) []*DuplicateCluster {
+	parent := make([]int, len(articles))
+	for i := range parent {
+		parent[i] = i
+	}
+	var root func(i int) int
+	root = func(i int) int {
+		if parent[i] != i {
+			parent[i] = root(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := range articles {
+		for j := i + 1; j < len(articles); j++ {
+			if IsNearDuplicate(articles[i].Fingerprint, articles[j].Fingerprint) {
+				parent[root(j)] = root(i)
+			}
+		}
+	}
+
+	groups := make(map[int]*DuplicateCluster)
+	var clusters []*DuplicateCluster
+	for i, a := range articles {
+		r := root(i)
+		c, ok := groups[r]
+		if !ok {
+			c = &DuplicateCluster{}
+			groups[r] = c
+			clusters = append(clusters, c)
+		}
+		c.Articles = append(c.Articles, a)
+	}
+
+	duplicates := clusters[:0]
+	for _, c := range clusters {
+		if len(c.Articles) < 2 {
+			continue
+		}
+		sort.SliceStable(c.Articles, func(i, j int) bool {
+			return c.Articles[i].CreatedAt.Before(c.Articles[j].CreatedAt)
+		})
+		duplicates = append(duplicates, c)
+	}
+	sort.SliceStable(duplicates, func(i, j int) bool {
+		return duplicates[i].latest().After(duplicates[j].latest())
+	})
+	return duplicates
+}
+
+func (c *DuplicateCluster) latest() time.Time {
+	return c.Articles[len(c.Articles)-1].CreatedAt
+}
+
+type MergePayload struct {
+	KeepID       string   `json:"keep_id" validate:"required"`
+	DuplicateIDs []string `json:"duplicate_ids" validate:"required,min=1,max=50,dive,required"`
+}
+
+// Merge takes the earliest read and favorite times and the first collection found.
+func (a *Article) Merge(duplicates []*Article) {
+	for _, d := range duplicates {
+		a.ReadAt = earliest(a.ReadAt, d.ReadAt)
+		a.FavoritedAt = earliest(a.FavoritedAt, d.FavoritedAt)
+		if a.CollectionID == nil {
+			a.CollectionID = d.CollectionID
+		}
+	}
+}
+
+func earliest(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.Before(*a)) {
+		return b
+	}
+	return a
+}
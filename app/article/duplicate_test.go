@@ -0,0 +1,61 @@
+package article
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const syndicated = "The city council voted on Tuesday to approve a new budget for public transit. The plan includes " +
+	"funding for more frequent buses, two new light rail lines and protected bike lanes across every district. " +
+	"Residents had asked for better service for years, and several council members said the vote was long overdue. " +
+	"The mayor is expected to sign the budget next week, with the first projects starting in the spring."
+
+func TestFingerprint(t *testing.T) {
+	original := Fingerprint("<p>" + syndicated + "</p>")
+	copied := Fingerprint("<p>Originally published by the wire service.</p><p>" + syndicated + "</p>")
+	unrelated := Fingerprint("<p>" + strings.Repeat("Bake the bread at a high temperature until the crust turns golden and crisp. ", 6) + "</p>")
+
+	assert.NotZero(t, original)
+	assert.True(t, IsNearDuplicate(original, copied))
+	assert.False(t, IsNearDuplicate(original, unrelated))
+	assert.Zero(t, Fingerprint("<p>Too short to compare.</p>"))
+	assert.False(t, IsNearDuplicate(0, 0))
+}
+
+func TestClusters(t *testing.T) {
+	now := time.Now().UTC()
+	a := &Article{ID: "a", Fingerprint: 1 << 7, CreatedAt: now.Add(-3 * time.Hour)}
+	b := &Article{ID: "b", Fingerprint: 0b0111, CreatedAt: now.Add(-2 * time.Hour)}
+	c := &Article{ID: "c", Fingerprint: 0b0111 | 0b1111<<56, CreatedAt: now.Add(-1 * time.Hour)}
+	d := &Article{ID: "d", Fingerprint: 0x7f00ff00ff00ff00, CreatedAt: now.Add(-4 * time.Hour)}
+	e := &Article{ID: "e", Fingerprint: 0x7f00ff00ff00ff01, CreatedAt: now.Add(-5 * time.Hour)}
+	f := &Article{ID: "f", Fingerprint: 0x0ff00ff00ff00ff0, CreatedAt: now}
+
+	// a and c differ in more bits than the threshold but are clustered through b
+	clusters := Clusters([]*Article{a, d, b, e, f, c})
+
+	assert.Equal(t, []*DuplicateCluster{
+		{Articles: []*Article{a, b, c}},
+		{Articles: []*Article{e, d}},
+	}, clusters)
+}
+
+func TestArticleMerge(t *testing.T) {
+	earlier := time.Now().UTC().Add(-time.Hour)
+	later := time.Now().UTC()
+	collectionID := "collection"
+
+	keep := &Article{ID: "keep", ReadAt: &later}
+	keep.Merge([]*Article{
+		{ID: "a", ReadAt: &earlier},
+		{ID: "b", FavoritedAt: &later, CollectionID: &collectionID},
+	})
+
+	assert.Equal(t, &earlier, keep.ReadAt)
+	assert.Equal(t, &later, keep.FavoritedAt)
+	assert.Equal(t, &collectionID, keep.CollectionID)
+	assert.Nil(t, keep.ArchivedAt)
+}
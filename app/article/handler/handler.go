@@ -2,7 +2,13 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ryanadiputraa/unclatter/app/article"
 	"github.com/ryanadiputraa/unclatter/app/middleware"
@@ -12,6 +18,8 @@ import (
 	"github.com/ryanadiputraa/unclatter/pkg/validator"
 )
 
+const maxSearchQueryLength = 256
+
 type handler struct {
 	rw             _http.ResponseWriter
 	articleService article.ArticleService
@@ -30,7 +38,31 @@ func NewHandler(web *http.ServeMux, rw _http.ResponseWriter, articleService arti
 	web.Handle("GET /api/articles/bookmarks", authMiddleware.ParseJWTToken(h.ListBookmarkedArticles()))
 	web.Handle("GET /api/articles/bookmarks/{id}", authMiddleware.ParseJWTToken(h.GetBookmarkedArticle()))
 	web.Handle("PUT /api/articles/bookmarks/{id}", authMiddleware.ParseJWTToken(h.UpdateArticle()))
+	web.Handle("PATCH /api/articles/bookmarks/{id}", authMiddleware.ParseJWTToken(h.PatchArticle()))
 	web.Handle("DELETE /api/articles/bookmarks/{id}", authMiddleware.ParseJWTToken(h.DeleteArticle()))
+	web.Handle("PUT /api/articles/bookmarks/{id}/snooze", authMiddleware.ParseJWTToken(h.SnoozeArticle(true)))
+	web.Handle("DELETE /api/articles/bookmarks/{id}/snooze", authMiddleware.ParseJWTToken(h.SnoozeArticle(false)))
+	web.Handle("GET /api/articles/bookmarks/counts", authMiddleware.ParseJWTToken(h.CountArticleStates()))
+	web.Handle("GET /api/articles/stats", authMiddleware.ParseJWTToken(h.GetReadingStats()))
+	web.Handle("PUT /api/articles/bookmarks/{id}/read", authMiddleware.ParseJWTToken(h.SetArticleState(article.StateRead, true)))
+	web.Handle("DELETE /api/articles/bookmarks/{id}/read", authMiddleware.ParseJWTToken(h.SetArticleState(article.StateRead, false)))
+	web.Handle("PUT /api/articles/bookmarks/{id}/archive", authMiddleware.ParseJWTToken(h.SetArticleState(article.StateArchived, true)))
+	web.Handle("DELETE /api/articles/bookmarks/{id}/archive", authMiddleware.ParseJWTToken(h.SetArticleState(article.StateArchived, false)))
+	web.Handle("PUT /api/articles/bookmarks/{id}/favorite", authMiddleware.ParseJWTToken(h.SetArticleState(article.StateFavorited, true)))
+	web.Handle("DELETE /api/articles/bookmarks/{id}/favorite", authMiddleware.ParseJWTToken(h.SetArticleState(article.StateFavorited, false)))
+	web.Handle("POST /api/articles/bookmarks/{id}/summary", authMiddleware.ParseJWTToken(h.SummarizeArticle()))
+	web.Handle("GET /api/articles/bookmarks/{id}/related", authMiddleware.ParseJWTToken(h.ListRelatedArticles()))
+	web.Handle("POST /api/articles/bookmarks/batch", authMiddleware.ParseJWTToken(h.BulkUpdateArticles()))
+	web.Handle("GET /api/articles/bookmarks/{id}/revisions", authMiddleware.ParseJWTToken(h.ListArticleRevisions()))
+	web.Handle("GET /api/articles/bookmarks/{id}/revisions/diff", authMiddleware.ParseJWTToken(h.DiffArticleRevisions()))
+	web.Handle("GET /api/articles/bookmarks/{id}/revisions/{revisionID}", authMiddleware.ParseJWTToken(h.GetArticleRevision()))
+	web.Handle("POST /api/articles/bookmarks/{id}/revisions/{revisionID}/restore", authMiddleware.ParseJWTToken(h.RestoreArticleRevision()))
+	web.Handle("POST /api/articles/bookmarks/{id}/rescrape", authMiddleware.ParseJWTToken(h.RescrapeArticle()))
+	web.Handle("GET /api/articles/duplicates", authMiddleware.ParseJWTToken(h.ListDuplicateClusters()))
+	web.Handle("POST /api/articles/duplicates/merge", authMiddleware.ParseJWTToken(h.MergeDuplicates()))
+	web.Handle("GET /api/articles/trash", authMiddleware.ParseJWTToken(h.ListTrashedArticles()))
+	web.Handle("POST /api/articles/trash/{id}/restore", authMiddleware.ParseJWTToken(h.RestoreArticle()))
+	web.Handle("DELETE /api/articles/trash/{id}", authMiddleware.ParseJWTToken(h.PurgeArticle()))
 }
 
 func (h *handler) ScrapeContent() http.HandlerFunc {
@@ -64,6 +96,10 @@ func (h *handler) BookmarkArticle() http.HandlerFunc {
 
 		bookmarked, err := h.articleService.BookmarkArticle(ac.Context, payload, ac.UserID)
 		if err != nil {
+			if vErr, ok := err.(*validation.Error); ok && vErr.Err == validation.Conflict && bookmarked != nil {
+				h.rw.WriteErrData(w, http.StatusConflict, vErr.Message, bookmarked)
+				return
+			}
 			if vErr, ok := err.(*validation.Error); ok {
 				h.rw.WriteErrMessage(w, validation.HttpErrMap[vErr.Err], vErr.Message)
 				return
@@ -82,14 +118,46 @@ func (h *handler) ListBookmarkedArticles() http.HandlerFunc {
 		query := r.URL.Query()
 		page := query.Get("page")
 		size := query.Get("size")
+		filter := article.ListFilter{
+			Query:        strings.TrimSpace(query.Get("q")),
+			Language:     query.Get("lang"),
+			Status:       query.Get("filter"),
+			CollectionID: query.Get("collection"),
+			Tag:          query.Get("tag"),
+		}
 
-		pagination, errMap, err := pagination.ValidateParam(page, size)
+		params := article.ListParams{
+			Sort:         query.Get("sort"),
+			Order:        query.Get("order"),
+			Domain:       query.Get("domain"),
+			CreatedFrom:  query.Get("created_from"),
+			CreatedTo:    query.Get("created_to"),
+			UpdatedFrom:  query.Get("updated_from"),
+			UpdatedTo:    query.Get("updated_to"),
+			Read:         query.Get("read"),
+			SnoozedUntil: query.Get("snoozed_until"),
+			LinkHealth:   query.Get("link_health"),
+		}
+
+		pagination, errMap, err := pagination.ValidateCursorParam(page, size, query.Get("cursor"))
+		if len(filter.Query) > maxSearchQueryLength {
+			errMap["q"] = fmt.Sprintf("q should have a maximum length of %d", maxSearchQueryLength)
+			err = errors.New("invalid params")
+		}
+		paramsErr, paramsErrMap := h.validator.Validate(params)
+		if paramsErr == nil {
+			paramsErrMap = params.Apply(&filter)
+		}
+		for field, msg := range paramsErrMap {
+			errMap[field] = msg
+			err = errors.New("invalid params")
+		}
 		if err != nil {
 			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
 			return
 		}
 
-		articles, meta, err := h.articleService.ListBookmarkedArticles(ac.Context, ac.UserID, *pagination)
+		articles, meta, err := h.articleService.ListBookmarkedArticles(ac.Context, ac.UserID, filter, *pagination)
 		if err != nil {
 			if vErr, ok := err.(*validation.Error); ok {
 				h.rw.WriteErrMessage(w, validation.HttpErrMap[vErr.Err], vErr.Message)
@@ -118,6 +186,7 @@ func (h *handler) GetBookmarkedArticle() http.HandlerFunc {
 			return
 		}
 
+		w.Header().Set("ETag", etag(article))
 		h.rw.WriteResponseData(w, http.StatusOK, article)
 	}
 }
@@ -126,15 +195,20 @@ func (h *handler) UpdateArticle() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ac := r.Context().(*middleware.AuthContext)
 		id := r.PathValue("id")
-		var payload article.BookmarkPayload
 
+		version, ok := h.requireIfMatch(w, r)
+		if !ok {
+			return
+		}
+
+		var payload article.BookmarkPayload
 		json.NewDecoder(r.Body).Decode(&payload)
 		if err, errMap := h.validator.Validate(payload); err != nil {
 			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
 			return
 		}
 
-		article, err := h.articleService.UpdateArticle(ac, ac.UserID, id, payload)
+		article, err := h.articleService.UpdateArticle(ac, ac.UserID, id, payload, version)
 		if err != nil {
 			if vErr, ok := err.(*validation.Error); ok {
 				h.rw.WriteErrMessage(w, validation.HttpErrMap[vErr.Err], vErr.Message)
@@ -144,6 +218,46 @@ func (h *handler) UpdateArticle() http.HandlerFunc {
 			return
 		}
 
+		w.Header().Set("ETag", etag(article))
+		h.rw.WriteResponseData(w, http.StatusOK, article)
+	}
+}
+
+func (h *handler) PatchArticle() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		id := r.PathValue("id")
+
+		version, ok := h.requireIfMatch(w, r)
+		if !ok {
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			h.rw.WriteErrMessage(w, http.StatusBadRequest, "fail to read request body")
+			return
+		}
+		payload, errMap, err := article.DecodePatch(body)
+		if err == nil {
+			err, errMap = h.validator.Validate(payload)
+		}
+		if err != nil {
+			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+			return
+		}
+
+		article, err := h.articleService.PatchArticle(ac, ac.UserID, id, payload, version)
+		if err != nil {
+			if vErr, ok := err.(*validation.Error); ok {
+				h.rw.WriteErrMessage(w, validation.HttpErrMap[vErr.Err], vErr.Message)
+				return
+			}
+			h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		w.Header().Set("ETag", etag(article))
 		h.rw.WriteResponseData(w, http.StatusOK, article)
 	}
 }
@@ -166,3 +280,422 @@ func (h *handler) DeleteArticle() http.HandlerFunc {
 		h.rw.WriteResponseData(w, http.StatusOK, nil)
 	}
 }
+
+func (h *handler) BulkUpdateArticles() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		var payload article.BulkPayload
+
+		json.NewDecoder(r.Body).Decode(&payload)
+		if err, errMap := h.validator.Validate(payload); err != nil {
+			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+			return
+		}
+
+		results, err := h.articleService.BulkUpdateArticles(ac.Context, ac.UserID, payload)
+		if err != nil {
+			if vErr, ok := err.(*validation.Error); ok {
+				h.rw.WriteErrMessage(w, validation.HttpErrMap[vErr.Err], vErr.Message)
+				return
+			}
+			h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, results)
+	}
+}
+
+func (h *handler) ListDuplicateClusters() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		clusters, err := h.articleService.ListDuplicateClusters(ac.Context, ac.UserID)
+		if err != nil {
+			h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, clusters)
+	}
+}
+
+func (h *handler) MergeDuplicates() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		var payload article.MergePayload
+
+		json.NewDecoder(r.Body).Decode(&payload)
+		if err, errMap := h.validator.Validate(payload); err != nil {
+			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+			return
+		}
+
+		merged, err := h.articleService.MergeDuplicates(ac.Context, ac.UserID, payload)
+		if err != nil {
+			if vErr, ok := err.(*validation.Error); ok {
+				h.rw.WriteErrMessage(w, validation.HttpErrMap[vErr.Err], vErr.Message)
+				return
+			}
+			h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, merged)
+	}
+}
+
+func (h *handler) ListTrashedArticles() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		page := r.URL.Query().Get("page")
+		size := r.URL.Query().Get("size")
+
+		pagination, errMap, err := pagination.ValidateParam(page, size)
+		if err != nil {
+			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+			return
+		}
+
+		articles, meta, err := h.articleService.ListTrashedArticles(ac.Context, ac.UserID, *pagination)
+		if err != nil {
+			h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		h.rw.WriteResponseDataWithPagination(w, http.StatusOK, articles, *meta)
+	}
+}
+
+func (h *handler) RestoreArticle() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		id := r.PathValue("id")
+
+		article, err := h.articleService.RestoreArticle(ac.Context, ac.UserID, id)
+		if err != nil {
+			if vErr, ok := err.(*validation.Error); ok {
+				h.rw.WriteErrMessage(w, validation.HttpErrMap[vErr.Err], vErr.Message)
+				return
+			}
+			h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, article)
+	}
+}
+
+func (h *handler) PurgeArticle() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		id := r.PathValue("id")
+
+		err := h.articleService.PurgeArticle(ac.Context, ac.UserID, id)
+		if err != nil {
+			if vErr, ok := err.(*validation.Error); ok {
+				h.rw.WriteErrMessage(w, validation.HttpErrMap[vErr.Err], vErr.Message)
+				return
+			}
+			h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, nil)
+	}
+}
+
+func (h *handler) SetArticleState(state article.State, enabled bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		id := r.PathValue("id")
+
+		article, err := h.articleService.SetArticleState(ac.Context, ac.UserID, id, state, enabled)
+		if err != nil {
+			if vErr, ok := err.(*validation.Error); ok {
+				h.rw.WriteErrMessage(w, validation.HttpErrMap[vErr.Err], vErr.Message)
+				return
+			}
+			h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, article)
+	}
+}
+
+func (h *handler) SnoozeArticle(snoozed bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		id := r.PathValue("id")
+
+		var until *time.Time
+		if snoozed {
+			var payload article.SnoozePayload
+			json.NewDecoder(r.Body).Decode(&payload)
+			if err, errMap := h.validator.Validate(payload); err != nil {
+				h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+				return
+			}
+			t, _ := time.Parse(time.RFC3339Nano, payload.Until)
+			until = &t
+		}
+
+		article, err := h.articleService.SnoozeArticle(ac.Context, ac.UserID, id, until)
+		if err != nil {
+			if vErr, ok := err.(*validation.Error); ok {
+				h.rw.WriteErrMessage(w, validation.HttpErrMap[vErr.Err], vErr.Message)
+				return
+			}
+			h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, article)
+	}
+}
+
+func (h *handler) CountArticleStates() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		counts, err := h.articleService.CountArticleStates(ac.Context, ac.UserID)
+		if err != nil {
+			h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, counts)
+	}
+}
+
+func (h *handler) GetReadingStats() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		query := r.URL.Query()
+		params := article.StatsParams{
+			From:     query.Get("from"),
+			To:       query.Get("to"),
+			Timezone: query.Get("timezone"),
+		}
+
+		err, errMap := h.validator.Validate(params)
+		if err != nil {
+			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+			return
+		}
+		period, errMap := params.Period(time.Now())
+		if len(errMap) > 0 {
+			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+			return
+		}
+
+		stats, err := h.articleService.GetReadingStats(ac.Context, ac.UserID, period)
+		if err != nil {
+			h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, stats)
+	}
+}
+
+func (h *handler) SummarizeArticle() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		id := r.PathValue("id")
+
+		article, err := h.articleService.SummarizeArticle(ac.Context, ac.UserID, id)
+		if err != nil {
+			if vErr, ok := err.(*validation.Error); ok {
+				h.rw.WriteErrMessage(w, validation.HttpErrMap[vErr.Err], vErr.Message)
+				return
+			}
+			h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, article)
+	}
+}
+
+func (h *handler) ListRelatedArticles() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		limit := article.DefaultRelatedLimit
+		if param := r.URL.Query().Get("limit"); len(param) > 0 {
+			var err error
+			limit, err = strconv.Atoi(param)
+			if err != nil || limit < 1 || limit > article.MaxRelatedLimit {
+				h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", map[string]string{
+					"limit": fmt.Sprintf("'limit' param should be between 1 and %d", article.MaxRelatedLimit),
+				})
+				return
+			}
+		}
+
+		related, err := h.articleService.ListRelatedArticles(ac.Context, ac.UserID, r.PathValue("id"), limit)
+		if err != nil {
+			if vErr, ok := err.(*validation.Error); ok {
+				h.rw.WriteErrMessage(w, validation.HttpErrMap[vErr.Err], vErr.Message)
+				return
+			}
+			h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, related)
+	}
+}
+
+func (h *handler) ListArticleRevisions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		query := r.URL.Query()
+
+		pagination, errMap, err := pagination.ValidateParam(query.Get("page"), query.Get("size"))
+		if err != nil {
+			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+			return
+		}
+
+		revisions, meta, err := h.articleService.ListArticleRevisions(ac.Context, ac.UserID, r.PathValue("id"), *pagination)
+		if err != nil {
+			if vErr, ok := err.(*validation.Error); ok {
+				h.rw.WriteErrMessage(w, validation.HttpErrMap[vErr.Err], vErr.Message)
+				return
+			}
+			h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		h.rw.WriteResponseDataWithPagination(w, http.StatusOK, revisions, *meta)
+	}
+}
+
+func (h *handler) GetArticleRevision() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		revision, err := h.articleService.GetArticleRevision(ac.Context, ac.UserID, r.PathValue("id"), r.PathValue("revisionID"))
+		if err != nil {
+			if vErr, ok := err.(*validation.Error); ok {
+				h.rw.WriteErrMessage(w, validation.HttpErrMap[vErr.Err], vErr.Message)
+				return
+			}
+			h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, revision)
+	}
+}
+
+func (h *handler) DiffArticleRevisions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		from := r.URL.Query().Get("from")
+		if len(from) == 0 {
+			h.rw.WriteErrMessage(w, http.StatusBadRequest, "missing 'from' query param")
+			return
+		}
+
+		diff, err := h.articleService.DiffArticleRevisions(ac.Context, ac.UserID, r.PathValue("id"), from, r.URL.Query().Get("to"))
+		if err != nil {
+			if vErr, ok := err.(*validation.Error); ok {
+				h.rw.WriteErrMessage(w, validation.HttpErrMap[vErr.Err], vErr.Message)
+				return
+			}
+			h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, diff)
+	}
+}
+
+func (h *handler) RestoreArticleRevision() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		version, ok := h.requireIfMatch(w, r)
+		if !ok {
+			return
+		}
+
+		article, err := h.articleService.RestoreArticleRevision(ac.Context, ac.UserID, r.PathValue("id"), r.PathValue("revisionID"), version)
+		if err != nil {
+			if vErr, ok := err.(*validation.Error); ok {
+				h.rw.WriteErrMessage(w, validation.HttpErrMap[vErr.Err], vErr.Message)
+				return
+			}
+			h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		w.Header().Set("ETag", etag(article))
+		h.rw.WriteResponseData(w, http.StatusOK, article)
+	}
+}
+
+func (h *handler) RescrapeArticle() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		version, ok := h.requireIfMatch(w, r)
+		if !ok {
+			return
+		}
+
+		article, err := h.articleService.RescrapeArticle(ac.Context, ac.UserID, r.PathValue("id"), version)
+		if err != nil {
+			if vErr, ok := err.(*validation.Error); ok {
+				h.rw.WriteErrMessage(w, validation.HttpErrMap[vErr.Err], vErr.Message)
+				return
+			}
+			h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		w.Header().Set("ETag", etag(article))
+		h.rw.WriteResponseData(w, http.StatusOK, article)
+	}
+}
+
+func etag(a *article.Article) string {
+	return strconv.Quote(strconv.Itoa(a.Version))
+}
+
+func (h *handler) requireIfMatch(w http.ResponseWriter, r *http.Request) (version int, ok bool) {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		h.rw.WriteErrMessage(w, http.StatusPreconditionRequired, "missing If-Match header")
+		return
+	}
+	version, err := ifMatchVersion(ifMatch)
+	if err != nil {
+		h.rw.WriteErrMessage(w, http.StatusBadRequest, "invalid If-Match header")
+		return
+	}
+	return version, true
+}
+
+// ifMatchVersion returns the article version expected by an If-Match header, it's zero when the header is
+// empty or "*" so any version matches.
+func ifMatchVersion(header string) (int, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	tag, err := strconv.Unquote(strings.TrimPrefix(header, "W/"))
+	if err != nil {
+		return 0, err
+	}
+	version, err := strconv.Atoi(tag)
+	if err != nil || version < 1 {
+		return 0, errors.New("invalid entity tag")
+	}
+	return version, nil
+}
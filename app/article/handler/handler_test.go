@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ryanadiputraa/unclatter/app/middleware"
+	_http "github.com/ryanadiputraa/unclatter/pkg/http"
+	"github.com/ryanadiputraa/unclatter/pkg/validator"
+	"github.com/ryanadiputraa/unclatter/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireIfMatch(t *testing.T) {
+	h := &handler{
+		rw:        _http.NewResponseWriter(),
+		validator: validator.NewValidator(),
+	}
+
+	cases := []struct {
+		name    string
+		method  string
+		target  string
+		handler http.HandlerFunc
+	}{
+		{
+			name:    "should require If-Match to update an article",
+			method:  http.MethodPut,
+			target:  "/api/articles/bookmarks/" + test.TestArticle.ID,
+			handler: h.UpdateArticle(),
+		},
+		{
+			name:    "should require If-Match to patch an article",
+			method:  http.MethodPatch,
+			target:  "/api/articles/bookmarks/" + test.TestArticle.ID,
+			handler: h.PatchArticle(),
+		},
+		{
+			name:    "should require If-Match to restore a revision",
+			method:  http.MethodPost,
+			target:  "/api/articles/bookmarks/" + test.TestArticle.ID + "/revisions/revision/restore",
+			handler: h.RestoreArticleRevision(),
+		},
+		{
+			name:    "should require If-Match to re-scrape an article",
+			method:  http.MethodPost,
+			target:  "/api/articles/bookmarks/" + test.TestArticle.ID + "/rescrape",
+			handler: h.RescrapeArticle(),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ac := &middleware.AuthContext{UserID: test.TestArticle.UserID, Context: context.Background()}
+			r := httptest.NewRequest(c.method, c.target, strings.NewReader(`{"title":"Title"}`)).WithContext(ac)
+			w := httptest.NewRecorder()
+
+			c.handler(w, r)
+
+			var res _http.ErrMessage
+			json.NewDecoder(w.Body).Decode(&res)
+			assert.Equal(t, http.StatusPreconditionRequired, w.Code)
+			assert.Equal(t, "missing If-Match header", res.Message)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package article
+
+import (
+	"net/url"
+	"strings"
+)
+
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "dclid": true, "msclkid": true, "igshid": true,
+	"mc_cid": true, "mc_eid": true, "ref_src": true, "_hsenc": true, "_hsmi": true,
+}
+
+// NormalizeLink returns a canonical form of the link so the same page saved from different sources
+// compares equal.
+func NormalizeLink(link string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return "", err
+	}
+
+	host := strings.ToLower(u.Hostname())
+	host = strings.TrimPrefix(host, "www.")
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		host += ":" + port
+	}
+
+	query := u.Query()
+	for key := range query {
+		if trackingParams[strings.ToLower(key)] || strings.HasPrefix(strings.ToLower(key), "utm_") {
+			query.Del(key)
+		}
+	}
+
+	normalized := url.URL{
+		Scheme:   "https",
+		Host:     host,
+		Path:     strings.TrimRight(u.Path, "/"),
+		RawPath:  strings.TrimRight(u.RawPath, "/"),
+		RawQuery: query.Encode(),
+	}
+	return normalized.String(), nil
+}
+
+// LinkDomain is empty for an invalid link.
+func LinkDomain(link string) string {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+type LinkHealth string
+
+const (
+	LinkHealthOK    LinkHealth = "ok"
+	LinkHealthMoved LinkHealth = "moved"
+	// LinkHealthChanged links work but their content drifted.
+	LinkHealthChanged LinkHealth = "changed"
+	// LinkHealthBroken links are gone, redirect to the homepage or failed every recent check.
+	LinkHealthBroken LinkHealth = "broken"
+	// LinkHealthUnchecked is only a list filter.
+	LinkHealthUnchecked LinkHealth = "unchecked"
+)
@@ -0,0 +1,79 @@
+package article
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeLink(t *testing.T) {
+	cases := []struct {
+		name     string
+		link     string
+		expected string
+	}{
+		{
+			name:     "should keep an already normalized link",
+			link:     "https://unclatter.com/articles/1",
+			expected: "https://unclatter.com/articles/1",
+		},
+		{
+			name:     "should upgrade scheme and lowercase host without www",
+			link:     "http://WWW.UnClatter.com/Articles/1",
+			expected: "https://unclatter.com/Articles/1",
+		},
+		{
+			name:     "should drop default port, trailing slash and fragment",
+			link:     "https://unclatter.com:443/articles/1/#comments",
+			expected: "https://unclatter.com/articles/1",
+		},
+		{
+			name:     "should keep non default port",
+			link:     "http://localhost:8080/",
+			expected: "https://localhost:8080",
+		},
+		{
+			name:     "should drop tracking params and sort the rest",
+			link:     "https://unclatter.com/search?utm_source=newsletter&q=go&fbclid=abc&page=2",
+			expected: "https://unclatter.com/search?page=2&q=go",
+		},
+		{
+			name:     "should keep escaped path",
+			link:     "https://unclatter.com/wiki/Go_%28programming_language%29",
+			expected: "https://unclatter.com/wiki/Go_%28programming_language%29",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			normalized, err := NormalizeLink(c.link)
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, normalized)
+		})
+	}
+}
+
+func TestLinkDomain(t *testing.T) {
+	cases := []struct {
+		name     string
+		link     string
+		expected string
+	}{
+		{
+			name:     "should return lowercased host without www and port",
+			link:     "http://WWW.UnClatter.com:8080/articles/1",
+			expected: "unclatter.com",
+		},
+		{
+			name:     "should return empty domain for an invalid link",
+			link:     "://unclatter",
+			expected: "",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, LinkDomain(c.link))
+		})
+	}
+}
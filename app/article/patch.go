@@ -0,0 +1,66 @@
+package article
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// PatchPayload is a JSON merge patch (RFC 7396), nil fields are left unchanged.
+type PatchPayload struct {
+	Title       *string `validate:"omitnil,min=1"`
+	Content     *string `validate:"omitnil,min=1"`
+	ArticleLink *string `validate:"omitnil,http_url"`
+	// Language is reset to DefaultLanguage when patched with null.
+	Language *string
+}
+
+// DecodePatch keys errors by the patched field.
+func DecodePatch(body []byte) (patch PatchPayload, errDetail map[string]string, err error) {
+	errDetail = make(map[string]string)
+
+	var doc map[string]json.RawMessage
+	if err = json.Unmarshal(body, &doc); err != nil || doc == nil {
+		errDetail["body"] = "body should be a json object"
+		return patch, errDetail, errors.New("invalid params")
+	}
+
+	for field, raw := range doc {
+		var target **string
+		switch field {
+		case "title":
+			target = &patch.Title
+		case "content":
+			target = &patch.Content
+		case "article_link":
+			target = &patch.ArticleLink
+		case "language":
+			target = &patch.Language
+		default:
+			errDetail[field] = fmt.Sprintf("%s can't be patched", field)
+			continue
+		}
+
+		if string(raw) == "null" {
+			if field != "language" {
+				errDetail[field] = fmt.Sprintf("%s can't be removed", field)
+				continue
+			}
+			reset := ""
+			*target = &reset
+			continue
+		}
+
+		var value string
+		if json.Unmarshal(raw, &value) != nil {
+			errDetail[field] = fmt.Sprintf("%s should be a string", field)
+			continue
+		}
+		*target = &value
+	}
+
+	if len(errDetail) > 0 {
+		return patch, errDetail, errors.New("invalid params")
+	}
+	return patch, errDetail, nil
+}
@@ -0,0 +1,61 @@
+package article
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodePatch(t *testing.T) {
+	title := "New Title"
+	reset := ""
+
+	cases := []struct {
+		name      string
+		body      string
+		expected  PatchPayload
+		errDetail map[string]string
+	}{
+		{
+			name:      "should decode patched fields and leave the rest unchanged",
+			body:      `{"title": "New Title"}`,
+			expected:  PatchPayload{Title: &title},
+			errDetail: map[string]string{},
+		},
+		{
+			name:      "should reset language when it's patched with null",
+			body:      `{"language": null}`,
+			expected:  PatchPayload{Language: &reset},
+			errDetail: map[string]string{},
+		},
+		{
+			name:     "should return err detail when removing required fields or patching unknown ones",
+			body:     `{"content": null, "article_link": 1, "user_id": "someone"}`,
+			expected: PatchPayload{},
+			errDetail: map[string]string{
+				"content":      "content can't be removed",
+				"article_link": "article_link should be a string",
+				"user_id":      "user_id can't be patched",
+			},
+		},
+		{
+			name:      "should return err detail when body isn't a json object",
+			body:      `["title"]`,
+			expected:  PatchPayload{},
+			errDetail: map[string]string{"body": "body should be a json object"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			patch, errDetail, err := DecodePatch([]byte(c.body))
+			assert.Equal(t, c.errDetail, errDetail)
+			if len(c.errDetail) > 0 {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, patch)
+		})
+	}
+}
@@ -2,10 +2,17 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/collection"
 	"github.com/ryanadiputraa/unclatter/app/pagination"
+	"github.com/ryanadiputraa/unclatter/app/tag"
 	"github.com/ryanadiputraa/unclatter/app/validation"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -24,23 +31,47 @@ func NewRepository(db *gorm.DB) article.ArticleRepository {
 func (r *repository) Save(ctx context.Context, arg article.Article) error {
 	err := r.db.Create(&arg).Error
 	if errors.Is(err, gorm.ErrDuplicatedKey) {
-		err = validation.NewError(validation.BadRequest, "title is already in use")
+		err = validation.NewError(validation.Conflict, "this url is already bookmarked")
 	}
 	return err
 }
 
-func (r *repository) List(ctx context.Context, userID string, page pagination.Pagination) (articles []*article.Article, total int64, err error) {
-	err = r.db.Model(&article.Article{}).Count(&total).Error
+const (
+	listColumns = "id, title, article_link, language, domain, reading_time, excerpt, collection_id, created_at, updated_at, read_at, archived_at, favorited_at, snoozed_until"
+	// snippetColumn strips the markup so the snippet only contains <mark> tags.
+	snippetColumn = "ts_headline(language, regexp_replace(content, '<[^>]+>', ' ', 'g'), to_tsquery(?::regconfig, ?), " +
+		"'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10') AS snippet"
+	rankColumn = "ts_rank(search_vector, to_tsquery(?::regconfig, ?)) AS rank"
+)
+
+func (r *repository) List(ctx context.Context, userID string, filter article.ListFilter, page pagination.Pagination) (articles []*article.Article, total int64, err error) {
+	tsquery := prefixQuery(filter.Query)
+	language := searchLanguage(filter)
+	scope := listScope(userID, filter, language, tsquery)
+
+	err = r.db.Model(&article.Article{}).Scopes(scope).Count(&total).Error
 	if err != nil {
 		return
 	}
 
-	err = r.db.
-		Select("id, title, article_link, created_at, updated_at").
-		Where("user_id = ?", userID).
-		Order("updated_at DESC, created_at DESC").
-		Limit(page.Limit).Offset(page.Offset).
-		Find(&articles).Error
+	query := r.db.Scopes(scope)
+	if tsquery != "" {
+		query = query.Select(listColumns+", "+snippetColumn+", "+rankColumn, language, tsquery, language, tsquery)
+	} else {
+		query = query.Select(listColumns)
+	}
+	if tsquery != "" && filter.Sort == "" {
+		query = query.Order("rank DESC, updated_at DESC")
+	} else {
+		query = query.Order(listOrder(filter))
+	}
+	if page.Cursor != nil {
+		// keyset pagination can't skip or repeat rows updated in between requests
+		query = query.Where("(updated_at, id) < (?, ?)", page.Cursor.UpdatedAt, page.Cursor.ID)
+	} else {
+		query = query.Offset(page.Offset)
+	}
+	err = query.Preload("Tags").Limit(page.Limit).Find(&articles).Error
 
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		articles = []*article.Article{}
@@ -52,14 +83,22 @@ func (r *repository) List(ctx context.Context, userID string, page pagination.Pa
 }
 
 func (r *repository) FindByID(ctx context.Context, articleID string) (article *article.Article, err error) {
-	err = r.db.First(&article, "id = ?", articleID).Error
+	err = r.db.Preload("Tags").First(&article, "id = ?", articleID).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		err = validation.NewError(validation.NotFound, "no article found with given id")
 	}
 	return
 }
 
-func (r *repository) Update(ctx context.Context, arg article.Article) (updated *article.Article, err error) {
+func (r *repository) FindByLink(ctx context.Context, userID, normalizedLink string) (article *article.Article, err error) {
+	err = r.db.First(&article, "user_id = ? AND normalized_link = ?", userID, normalizedLink).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = validation.NewError(validation.NotFound, "no article found with given link")
+	}
+	return
+}
+
+func (r *repository) Update(ctx context.Context, arg article.Article, source article.RevisionSource) (updated *article.Article, err error) {
 	err = r.db.Transaction(func(tx *gorm.DB) error {
 		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&updated, "id = ?", arg.ID).Error
 		if err != nil {
@@ -72,20 +111,347 @@ func (r *repository) Update(ctx context.Context, arg article.Article) (updated *
 		if updated.UserID != arg.UserID {
 			return validation.NewError(validation.Forbidden, "forbidden access")
 		}
+		if arg.Version != 0 && updated.Version != arg.Version {
+			return validation.NewError(validation.PreconditionFailed, "article has been modified, fetch the latest version and retry")
+		}
+		if err := tx.Create(article.NewRevision(*updated, source)).Error; err != nil {
+			return err
+		}
 
 		updated.Title = arg.Title
 		updated.Content = arg.Content
 		updated.ArticleLink = arg.ArticleLink
+		updated.NormalizedLink = arg.NormalizedLink
+		if arg.Language != "" {
+			updated.Language = arg.Language
+		}
+		updated.Domain = arg.Domain
+		updated.ReadingTime = arg.ReadingTime
+		updated.Excerpt = arg.Excerpt
+		updated.Fingerprint = arg.Fingerprint
+		updated.Version++
 		updated.UpdatedAt = arg.UpdatedAt
 
 		return tx.Model(&updated).Updates(article.Article{
-			Title:       arg.Title,
-			Content:     arg.Content,
-			ArticleLink: arg.ArticleLink,
-			UpdatedAt:   arg.UpdatedAt,
+			Title:          arg.Title,
+			Content:        arg.Content,
+			ArticleLink:    arg.ArticleLink,
+			NormalizedLink: arg.NormalizedLink,
+			Language:       arg.Language,
+			Domain:         arg.Domain,
+			ReadingTime:    arg.ReadingTime,
+			Excerpt:        arg.Excerpt,
+			Fingerprint:    arg.Fingerprint,
+			Version:        updated.Version,
+			UpdatedAt:      arg.UpdatedAt,
 		}).Error
 	})
 
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		err = validation.NewError(validation.Conflict, "this url is already bookmarked")
+	}
+	return
+}
+
+const revisionListColumns = "id, article_id, version, title, article_link, language, source, created_at"
+
+func (r *repository) ListRevisions(ctx context.Context, articleID string, page pagination.Pagination) (revisions []*article.Revision, total int64, err error) {
+	err = r.db.Model(&article.Revision{}).Where("article_id = ?", articleID).Count(&total).Error
+	if err != nil {
+		return
+	}
+
+	err = r.db.Select(revisionListColumns).
+		Where("article_id = ?", articleID).
+		Order("version DESC").
+		Limit(page.Limit).
+		Offset(page.Offset).
+		Find(&revisions).Error
+	return
+}
+
+func (r *repository) FindRevision(ctx context.Context, articleID, revisionID string) (revision *article.Revision, err error) {
+	err = r.db.First(&revision, "id = ? AND article_id = ?", revisionID, articleID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = validation.NewError(validation.NotFound, "no revision found with given id")
+	}
+	return
+}
+
+func (r *repository) UpdateState(ctx context.Context, userID, articleID string, state article.State, at *time.Time) (updated *article.Article, err error) {
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&updated, "id = ?", articleID).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				err = validation.NewError(validation.NotFound, "no article found with given id")
+			}
+			return err
+		}
+
+		if updated.UserID != userID {
+			return validation.NewError(validation.Forbidden, "forbidden access")
+		}
+
+		switch state {
+		case article.StateRead:
+			updated.ReadAt = at
+		case article.StateArchived:
+			updated.ArchivedAt = at
+		case article.StateFavorited:
+			updated.FavoritedAt = at
+		}
+
+		// reading states aren't edits, updated_at is kept so the list order stays stable
+		return tx.Model(&updated).UpdateColumn(state.Column(), at).Error
+	})
+
+	return
+}
+
+func (r *repository) UpdateSnooze(ctx context.Context, userID, articleID string, until *time.Time) (updated *article.Article, err error) {
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&updated, "id = ?", articleID).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				err = validation.NewError(validation.NotFound, "no article found with given id")
+			}
+			return err
+		}
+
+		if updated.UserID != userID {
+			return validation.NewError(validation.Forbidden, "forbidden access")
+		}
+
+		// like reading states, snoozing isn't an edit
+		updated.SnoozedUntil = until
+		return tx.Model(&updated).UpdateColumn("snoozed_until", until).Error
+	})
+
+	return
+}
+
+func (r *repository) CountStates(ctx context.Context, userID string) (counts *article.StateCounts, err error) {
+	err = r.db.Model(&article.Article{}).
+		Select(`COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE archived_at IS NULL AND (snoozed_until IS NULL OR snoozed_until <= now())) AS inbox,
+			COUNT(*) FILTER (WHERE read_at IS NULL AND archived_at IS NULL AND (snoozed_until IS NULL OR snoozed_until <= now())) AS unread,
+			COUNT(*) FILTER (WHERE read_at IS NOT NULL) AS read,
+			COUNT(*) FILTER (WHERE archived_at IS NOT NULL) AS archived,
+			COUNT(*) FILTER (WHERE favorited_at IS NOT NULL) AS favorites,
+			COUNT(*) FILTER (WHERE archived_at IS NULL AND snoozed_until > now()) AS snoozed`).
+		Where("user_id = ?", userID).
+		Scan(&counts).Error
+	return
+}
+
+const (
+	statsTotalsQuery = `SELECT COUNT(*) FILTER (WHERE created_at >= @from AND created_at < @to) AS saved,
+	COUNT(*) FILTER (WHERE read_at >= @from AND read_at < @to) AS read,
+	COALESCE(SUM(reading_time) FILTER (WHERE read_at >= @from AND read_at < @to), 0) AS reading_time,
+	ROUND(AVG(GREATEST(EXTRACT(EPOCH FROM read_at - created_at), 0)) FILTER (WHERE read_at >= @from AND read_at < @to))::bigint AS average_seconds_to_read
+FROM articles
+WHERE user_id = @user AND deleted_at IS NULL
+	AND ((created_at >= @from AND created_at < @to) OR (read_at >= @from AND read_at < @to))`
+
+	// weeks start on Monday in the user's timezone
+	statsWeeksQuery = `SELECT to_char(week, 'YYYY-MM-DD') AS week,
+	COUNT(*) FILTER (WHERE saved) AS saved,
+	COUNT(*) FILTER (WHERE NOT saved) AS read
+FROM (
+	SELECT date_trunc('week', created_at AT TIME ZONE @tz) AS week, TRUE AS saved
+	FROM articles
+	WHERE user_id = @user AND deleted_at IS NULL AND created_at >= @from AND created_at < @to
+	UNION ALL
+	SELECT date_trunc('week', read_at AT TIME ZONE @tz), FALSE
+	FROM articles
+	WHERE user_id = @user AND deleted_at IS NULL AND read_at >= @from AND read_at < @to
+) events
+GROUP BY week
+ORDER BY week`
+
+	statsDomainsQuery = `SELECT domain, COUNT(*) AS saved, COUNT(read_at) AS read
+FROM articles
+WHERE user_id = @user AND deleted_at IS NULL AND domain <> '' AND created_at >= @from AND created_at < @to
+GROUP BY domain
+ORDER BY saved DESC, domain
+LIMIT @limit`
+
+	// consecutive days minus their row number are the same date
+	statsReadRunsQuery = `SELECT MIN(day) AS first_day, MAX(day) AS last_day
+FROM (
+	SELECT day, day - (ROW_NUMBER() OVER (ORDER BY day))::int AS run
+	FROM (
+		SELECT DISTINCT (read_at AT TIME ZONE @tz)::date AS day
+		FROM articles
+		WHERE user_id = @user AND deleted_at IS NULL AND read_at < @to
+	) days
+) numbered
+GROUP BY run
+HAVING MAX(day) >= @first::date
+ORDER BY first_day`
+)
+
+func (r *repository) ReadingStats(ctx context.Context, userID string, period article.StatsPeriod) (stats *article.Stats, err error) {
+	user := sql.Named("user", userID)
+	from := sql.Named("from", period.First.UTC())
+	to := sql.Named("to", period.End().UTC())
+	tz := sql.Named("tz", period.Location.String())
+
+	if err = r.db.Raw(statsTotalsQuery, user, from, to).Scan(&stats).Error; err != nil {
+		return nil, err
+	}
+	if err = r.db.Raw(statsWeeksQuery, user, from, to, tz).Scan(&stats.Weeks).Error; err != nil {
+		return nil, err
+	}
+	err = r.db.Raw(statsDomainsQuery, user, from, to, sql.Named("limit", article.MaxTopDomains)).
+		Scan(&stats.TopDomains).Error
+	if err != nil {
+		return nil, err
+	}
+	err = r.db.Raw(statsReadRunsQuery, user, to, tz, sql.Named("first", period.First.Format("2006-01-02"))).
+		Scan(&stats.ReadRuns).Error
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (r *repository) UpdateExcerpt(ctx context.Context, articleID, excerpt string) error {
+	// the excerpt is derived from the content, so it isn't an edit of its own
+	res := r.db.Model(&article.Article{}).Where("id = ?", articleID).UpdateColumn("excerpt", excerpt)
+	if res.RowsAffected == 0 && res.Error == nil {
+		return validation.NewError(validation.NotFound, "no article found with given id")
+	}
+	return res.Error
+}
+
+func (r *repository) ListUnindexed(ctx context.Context, limit int) (articles []*article.Article, err error) {
+	err = r.db.Where("indexed_version <> version").Order("updated_at").Limit(limit).Find(&articles).Error
+	return
+}
+
+func (r *repository) ListUnsanitized(ctx context.Context, version, limit int) (articles []*article.Article, err error) {
+	err = r.db.Where("sanitized_version < ?", version).Order("id").Limit(limit).Find(&articles).Error
+	return
+}
+
+func (r *repository) MarkSanitized(ctx context.Context, articleID string, version int) error {
+	return r.db.Model(&article.Article{}).Where("id = ?", articleID).UpdateColumn("sanitized_version", version).Error
+}
+
+func (r *repository) SaveIndex(ctx context.Context, a article.Article, terms []*article.Term) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&article.Article{}).
+			Where("id = ? AND version = ?", a.ID, a.Version).
+			UpdateColumns(map[string]any{"indexed_version": a.Version, "fingerprint": a.Fingerprint, "excerpt": a.Excerpt})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+
+		if err := tx.Where("article_id = ?", a.ID).Delete(&article.Term{}).Error; err != nil {
+			return err
+		}
+		if len(terms) == 0 {
+			return nil
+		}
+		return tx.Create(&terms).Error
+	})
+}
+
+// relatedQuery weighs each shared term by its squared idf, terms of trashed articles count neither as candidates
+// nor toward the idf.
+const relatedQuery = `SELECT ` + listColumns + `, related.similarity FROM articles JOIN (
+	SELECT candidate.article_id, SUM(source.weight * candidate.weight * idf.idf * idf.idf) AS similarity
+	FROM article_terms source
+	JOIN article_terms candidate ON candidate.term = source.term AND candidate.user_id = @user AND candidate.article_id <> source.article_id
+	JOIN articles live ON live.id = candidate.article_id AND live.deleted_at IS NULL
+	JOIN (
+		SELECT terms.term, LN(1 + (SELECT COUNT(*) FROM articles WHERE user_id = @user AND deleted_at IS NULL)::float / COUNT(*)) AS idf
+		FROM article_terms terms
+		JOIN articles live ON live.id = terms.article_id AND live.deleted_at IS NULL
+		WHERE terms.user_id = @user AND terms.term IN (SELECT term FROM article_terms WHERE article_id = @article)
+		GROUP BY terms.term
+	) idf ON idf.term = source.term
+	WHERE source.article_id = @article
+	GROUP BY candidate.article_id
+) related ON related.article_id = articles.id
+WHERE articles.deleted_at IS NULL
+ORDER BY related.similarity DESC, articles.id
+LIMIT @limit`
+
+func (r *repository) ListRelated(ctx context.Context, userID, articleID string, limit int) (articles []*article.Article, err error) {
+	err = r.db.Raw(relatedQuery, sql.Named("user", userID), sql.Named("article", articleID), sql.Named("limit", limit)).
+		Scan(&articles).Error
+	return
+}
+
+func (r *repository) ListFingerprinted(ctx context.Context, userID string) (articles []*article.Article, err error) {
+	err = r.db.Select(listColumns+", fingerprint").
+		Where("user_id = ? AND fingerprint <> 0", userID).
+		Order("created_at, id").
+		Find(&articles).Error
+	return
+}
+
+func (r *repository) FindNearDuplicates(ctx context.Context, userID, articleID string, fingerprint int64) (articles []*article.Article, err error) {
+	err = r.db.Select(listColumns).
+		Where("user_id = ? AND id <> ? AND fingerprint <> 0 AND bit_count((fingerprint # ?)::bit(64)) <= ?",
+			userID, articleID, fingerprint, article.NearDuplicateDistance).
+		Order("created_at, id").
+		Limit(article.MaxNearDuplicates).
+		Find(&articles).Error
+	return
+}
+
+func (r *repository) Merge(ctx context.Context, userID, keepID string, duplicateIDs []string) (merged *article.Article, err error) {
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		var locked []*article.Article
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ? AND user_id = ?", append([]string{keepID}, duplicateIDs...), userID).
+			Order("id").
+			Find(&locked).Error
+		if err != nil {
+			return err
+		}
+		if len(locked) != len(duplicateIDs)+1 {
+			return validation.NewError(validation.NotFound, "no article found with given id")
+		}
+
+		var keep *article.Article
+		duplicates := make([]*article.Article, 0, len(duplicateIDs))
+		for _, a := range locked {
+			if a.ID == keepID {
+				keep = a
+			} else {
+				duplicates = append(duplicates, a)
+			}
+		}
+		keep.Merge(duplicates)
+
+		err = tx.Exec("INSERT INTO article_tags (article_id, tag_id) SELECT DISTINCT ?, tag_id FROM article_tags "+
+			"WHERE article_id IN ? ON CONFLICT DO NOTHING", keepID, duplicateIDs).Error
+		if err != nil {
+			return err
+		}
+		// merging isn't an edit, the version and updated_at are kept
+		err = tx.Model(&article.Article{}).Where("id = ?", keepID).UpdateColumns(map[string]any{
+			"read_at":       keep.ReadAt,
+			"favorited_at":  keep.FavoritedAt,
+			"collection_id": keep.CollectionID,
+		}).Error
+		if err != nil {
+			return err
+		}
+		if err = tx.Where("id IN ?", duplicateIDs).Delete(&article.Article{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Preload("Tags").First(&merged, "id = ?", keepID).Error
+	})
+
+	if err != nil {
+		merged = nil
+	}
 	return
 }
 
@@ -96,3 +462,300 @@ func (r *repository) Delete(ctx context.Context, userID, articleID string) error
 	}
 	return res.Error
 }
+
+func (r *repository) ListTrashed(ctx context.Context, userID string, page pagination.Pagination) (articles []*article.Article, total int64, err error) {
+	trashed := r.db.Unscoped().Model(&article.Article{}).Where("user_id = ? AND deleted_at IS NOT NULL", userID)
+
+	err = trashed.Session(&gorm.Session{}).Count(&total).Error
+	if err != nil {
+		return
+	}
+
+	err = trashed.Session(&gorm.Session{}).
+		Select(listColumns + ", deleted_at").
+		Order("deleted_at DESC").
+		Limit(page.Limit).Offset(page.Offset).
+		Find(&articles).Error
+	return
+}
+
+func (r *repository) Restore(ctx context.Context, userID, articleID string) (restored *article.Article, err error) {
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&restored, "id = ? AND deleted_at IS NOT NULL", articleID).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				err = validation.NewError(validation.NotFound, "no trashed article found with given id")
+			}
+			return err
+		}
+
+		if restored.UserID != userID {
+			return validation.NewError(validation.Forbidden, "forbidden access")
+		}
+
+		restored.DeletedAt = gorm.DeletedAt{}
+		return tx.Unscoped().Model(&restored).UpdateColumn("deleted_at", nil).Error
+	})
+
+	// the page was bookmarked again while this one was in the trash
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		err = validation.NewError(validation.Conflict, "this url is already bookmarked")
+	}
+	return
+}
+
+func (r *repository) Purge(ctx context.Context, userID, articleID string) error {
+	res := r.db.Unscoped().
+		Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", articleID, userID).
+		Delete(&article.Article{})
+	if res.RowsAffected == 0 && res.Error == nil {
+		return validation.NewError(validation.NotFound, "no trashed article found with given id")
+	}
+	return res.Error
+}
+
+func (r *repository) PurgeTrashedBefore(ctx context.Context, before time.Time) (int64, error) {
+	res := r.db.Unscoped().Where("deleted_at < ?", before).Delete(&article.Article{})
+	return res.RowsAffected, res.Error
+}
+
+func (r *repository) BulkUpdate(ctx context.Context, userID string, op article.BulkOperation) (results []*article.BulkResult, err error) {
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if op.Action == article.BulkMove && op.CollectionID != nil {
+			var owned int64
+			// editors of a shared collection can move their own articles into it
+			err := tx.Table("collections").
+				Where("id = ? AND (user_id = ? OR EXISTS (SELECT 1 FROM collection_members "+
+					"WHERE collection_id = collections.id AND user_id = ? AND role IN ?))",
+					*op.CollectionID, userID, userID, []collection.Role{collection.RoleEditor, collection.RoleOwner}).
+				Count(&owned).Error
+			if err != nil {
+				return err
+			}
+			if owned == 0 {
+				return validation.NewError(validation.NotFound, "no collection found with given id")
+			}
+		}
+
+		var ids []string
+		var err error
+		results, ids, err = lockBulkTargets(tx, userID, op)
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		switch op.Action {
+		case article.BulkDelete:
+			return tx.Where("id IN ?", ids).Delete(&article.Article{}).Error
+		case article.BulkArchive:
+			// articles archived before keep their original archive time
+			return tx.Model(&article.Article{}).Where("id IN ? AND archived_at IS NULL", ids).UpdateColumn("archived_at", op.At).Error
+		case article.BulkMarkRead:
+			return tx.Model(&article.Article{}).Where("id IN ? AND read_at IS NULL", ids).UpdateColumn("read_at", op.At).Error
+		case article.BulkMove:
+			return tx.Model(&article.Article{}).Where("id IN ?", ids).UpdateColumn("collection_id", op.CollectionID).Error
+		case article.BulkTag:
+			return addTags(tx, userID, ids, op.Tags)
+		}
+		return nil
+	})
+
+	if err != nil {
+		results = nil
+	}
+	return
+}
+
+// lockBulkTargets returns a result for each target along with the IDs of the user's own articles.
+func lockBulkTargets(tx *gorm.DB, userID string, op article.BulkOperation) (results []*article.BulkResult, ids []string, err error) {
+	var targets []*article.Article
+	if len(op.IDs) == 0 && op.Filter != nil {
+		err = tx.Scopes(listScope(userID, *op.Filter, searchLanguage(*op.Filter), prefixQuery(op.Filter.Query))).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id, user_id").
+			Order("id").
+			Limit(article.MaxBulkSize + 1).
+			Find(&targets).Error
+		if err != nil {
+			return
+		}
+		if len(targets) > article.MaxBulkSize {
+			err = validation.NewError(validation.BadRequest, fmt.Sprintf("filter matches more than %d articles", article.MaxBulkSize))
+			return
+		}
+
+		for _, t := range targets {
+			results = append(results, &article.BulkResult{ID: t.ID, Status: article.BulkStatusOK})
+			ids = append(ids, t.ID)
+		}
+		return
+	}
+
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id, user_id").
+		Where("id IN ?", op.IDs).
+		Find(&targets).Error
+	if err != nil {
+		return
+	}
+
+	owners := make(map[string]string, len(targets))
+	for _, t := range targets {
+		owners[t.ID] = t.UserID
+	}
+	for _, id := range op.IDs {
+		owner, ok := owners[id]
+		switch {
+		case !ok:
+			results = append(results, &article.BulkResult{ID: id, Status: article.BulkStatusNotFound})
+		case owner != userID:
+			results = append(results, &article.BulkResult{ID: id, Status: article.BulkStatusForbidden})
+		default:
+			results = append(results, &article.BulkResult{ID: id, Status: article.BulkStatusOK})
+			ids = append(ids, id)
+		}
+	}
+	return
+}
+
+type articleTag struct {
+	ArticleID string
+	TagID     string
+}
+
+func (articleTag) TableName() string {
+	return "article_tags"
+}
+
+// addTags keeps the tags the user already has.
+func addTags(tx *gorm.DB, userID string, articleIDs []string, tags []tag.Tag) error {
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "name"}},
+		DoNothing: true,
+	}).Create(&tags).Error
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, len(tags))
+	for i, t := range tags {
+		names[i] = t.Name
+	}
+	var tagIDs []string
+	err = tx.Model(&tag.Tag{}).Where("user_id = ? AND name IN ?", userID, names).Pluck("id", &tagIDs).Error
+	if err != nil {
+		return err
+	}
+
+	rows := make([]articleTag, 0, len(articleIDs)*len(tagIDs))
+	for _, articleID := range articleIDs {
+		for _, tagID := range tagIDs {
+			rows = append(rows, articleTag{ArticleID: articleID, TagID: tagID})
+		}
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+}
+
+func listScope(userID string, filter article.ListFilter, language, tsquery string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if !filter.SharedCollection {
+			db = db.Where("user_id = ?", userID)
+		}
+		if tsquery != "" {
+			db = db.Where("search_vector @@ to_tsquery(?::regconfig, ?)", language, tsquery)
+		}
+		if filter.CollectionID != "" {
+			db = db.Where("collection_id = ?", filter.CollectionID)
+		}
+		if filter.Tag != "" {
+			db = db.Where("EXISTS (SELECT 1 FROM article_tags JOIN tags ON tags.id = article_tags.tag_id "+
+				"WHERE article_tags.article_id = articles.id AND tags.name = ?)", tag.NormalizeName(filter.Tag))
+		}
+		if filter.Domain != "" {
+			db = db.Where("domain = ?", filter.Domain)
+		}
+		if filter.CreatedFrom != nil {
+			db = db.Where("created_at >= ?", *filter.CreatedFrom)
+		}
+		if filter.CreatedTo != nil {
+			db = db.Where("created_at <= ?", *filter.CreatedTo)
+		}
+		if filter.UpdatedFrom != nil {
+			db = db.Where("updated_at >= ?", *filter.UpdatedFrom)
+		}
+		if filter.UpdatedTo != nil {
+			db = db.Where("updated_at <= ?", *filter.UpdatedTo)
+		}
+		if filter.Read != nil && *filter.Read {
+			db = db.Where("read_at IS NOT NULL")
+		} else if filter.Read != nil {
+			db = db.Where("read_at IS NULL")
+		}
+		switch filter.LinkHealth {
+		case "":
+		case article.LinkHealthUnchecked:
+			db = db.Where("NOT EXISTS (SELECT 1 FROM article_links WHERE article_links.article_id = articles.id AND article_links.health <> '')")
+		default:
+			db = db.Where("EXISTS (SELECT 1 FROM article_links WHERE article_links.article_id = articles.id AND article_links.health = ?)", filter.LinkHealth)
+		}
+		switch {
+		case filter.SnoozedUntil != nil:
+			db = db.Where("snoozed_until > now() AND snoozed_until <= ?", *filter.SnoozedUntil)
+		case filter.Status == article.FilterSnoozed:
+			db = db.Where("snoozed_until > now()")
+		case filter.Status == "" || filter.Status == article.FilterUnread:
+			db = db.Where("(snoozed_until IS NULL OR snoozed_until <= now())")
+		}
+		switch filter.Status {
+		case article.FilterUnread:
+			db = db.Where("read_at IS NULL AND archived_at IS NULL")
+		case article.FilterArchived:
+			db = db.Where("archived_at IS NOT NULL")
+		case article.FilterFavorites:
+			db = db.Where("favorited_at IS NOT NULL")
+		default:
+			db = db.Where("archived_at IS NULL")
+		}
+		return db
+	}
+}
+
+// sortColumns whitelists the columns of the ORDER BY clause.
+var sortColumns = map[string]string{
+	article.SortCreatedAt:   "created_at",
+	article.SortUpdatedAt:   "updated_at",
+	article.SortTitle:       "title",
+	article.SortReadingTime: "reading_time",
+}
+
+// listOrder breaks ties by id so pages are stable.
+func listOrder(filter article.ListFilter) string {
+	column, ok := sortColumns[filter.Sort]
+	if !ok {
+		column = "updated_at"
+	}
+	direction := "DESC"
+	if filter.Direction() == article.OrderAsc {
+		direction = "ASC"
+	}
+	return fmt.Sprintf("%s %s, id %s", column, direction, direction)
+}
+
+func searchLanguage(filter article.ListFilter) string {
+	if filter.Language == "" {
+		return article.DefaultLanguage
+	}
+	return filter.Language
+}
+
+// prefixQuery turns "go concur" into "go:* & concur:*", dropping anything that could inject tsquery operators.
+func prefixQuery(q string) string {
+	terms := strings.FieldsFunc(q, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, term := range terms {
+		terms[i] = term + ":*"
+	}
+	return strings.Join(terms, " & ")
+}
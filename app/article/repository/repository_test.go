@@ -9,13 +9,18 @@ import (
 	"github.com/google/uuid"
 	"github.com/ryanadiputraa/unclatter/app/article"
 	"github.com/ryanadiputraa/unclatter/app/pagination"
+	"github.com/ryanadiputraa/unclatter/app/tag"
 	"github.com/ryanadiputraa/unclatter/app/validation"
 	"github.com/ryanadiputraa/unclatter/test"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
 )
 
-const selectFromArticles = "^SELECT (.+) FROM \"articles\""
+const (
+	selectFromArticles = "^SELECT (.+) FROM \"articles\""
+	preloadArticleTags = "^SELECT \\* FROM \"article_tags\" WHERE \"article_tags\".\"article_id\""
+	preloadTags        = "^SELECT \\* FROM \"tags\" WHERE \"tags\".\"id\""
+)
 
 func TestSave(t *testing.T) {
 	gormDB, db, mock := test.NewMockDB(t)
@@ -35,23 +40,29 @@ func TestSave(t *testing.T) {
 				mock.ExpectBegin()
 				mock.ExpectExec(expectedExec).
 					WithArgs(test.TestArticle.ID, test.TestArticle.Title, test.TestArticle.Content, test.TestArticle.ArticleLink,
-						test.TestArticle.UserID, test.TestArticle.CreatedAt, test.TestArticle.UpdatedAt).
+						test.TestArticle.NormalizedLink, test.TestArticle.Language, test.TestArticle.Domain, test.TestArticle.ReadingTime,
+						test.TestArticle.Excerpt, test.TestArticle.Fingerprint, test.TestArticle.Keywords,
+						test.TestArticle.UserID, nil, test.TestArticle.Version, test.TestArticle.IndexedVersion, test.TestArticle.SanitizedVersion, test.TestArticle.CreatedAt,
+						test.TestArticle.UpdatedAt, nil, nil, nil, nil, nil).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 				mock.ExpectCommit()
 			},
 			err: nil,
 		},
 		{
-			name: "should return error when title already used",
+			name: "should return error when url is already bookmarked by the user",
 			mockBehaviour: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
 				mock.ExpectExec(expectedExec).
 					WithArgs(test.TestArticle.ID, test.TestArticle.Title, test.TestArticle.Content, test.TestArticle.ArticleLink,
-						test.TestArticle.UserID, test.TestArticle.CreatedAt, test.TestArticle.UpdatedAt).
+						test.TestArticle.NormalizedLink, test.TestArticle.Language, test.TestArticle.Domain, test.TestArticle.ReadingTime,
+						test.TestArticle.Excerpt, test.TestArticle.Fingerprint, test.TestArticle.Keywords,
+						test.TestArticle.UserID, nil, test.TestArticle.Version, test.TestArticle.IndexedVersion, test.TestArticle.SanitizedVersion, test.TestArticle.CreatedAt,
+						test.TestArticle.UpdatedAt, nil, nil, nil, nil, nil).
 					WillReturnError(gorm.ErrDuplicatedKey)
 				mock.ExpectRollback()
 			},
-			err: validation.NewError(validation.BadRequest, "title is already in use"),
+			err: validation.NewError(validation.Conflict, "this url is already bookmarked"),
 		},
 		{
 			name: "should return error when fail to insert new bookmarked article",
@@ -59,7 +70,10 @@ func TestSave(t *testing.T) {
 				mock.ExpectBegin()
 				mock.ExpectExec(expectedExec).
 					WithArgs(test.TestArticle.ID, test.TestArticle.Title, test.TestArticle.Content, test.TestArticle.ArticleLink,
-						test.TestArticle.UserID, test.TestArticle.CreatedAt, test.TestArticle.UpdatedAt).
+						test.TestArticle.NormalizedLink, test.TestArticle.Language, test.TestArticle.Domain, test.TestArticle.ReadingTime,
+						test.TestArticle.Excerpt, test.TestArticle.Fingerprint, test.TestArticle.Keywords,
+						test.TestArticle.UserID, nil, test.TestArticle.Version, test.TestArticle.IndexedVersion, test.TestArticle.SanitizedVersion, test.TestArticle.CreatedAt,
+						test.TestArticle.UpdatedAt, nil, nil, nil, nil, nil).
 					WillReturnError(gorm.ErrInvalidDB)
 				mock.ExpectRollback()
 			},
@@ -81,12 +95,18 @@ func TestList(t *testing.T) {
 	defer db.Close()
 
 	r := NewRepository(gormDB)
+	tagID := uuid.NewString()
 	expectedCountQuery := "^SELECT count(.*) FROM \"articles\""
-	expectedSelectQuery := "^SELECT id, title, article_link, created_at, updated_at FROM \"articles\" *"
+	expectedSelectQuery := "^SELECT id, title, article_link, language, domain, reading_time, excerpt, collection_id, created_at, updated_at, read_at, archived_at, favorited_at, snoozed_until FROM \"articles\" *"
+	expectedCursorQuery := "^SELECT (.+) FROM \"articles\" WHERE \\(updated_at, id\\) < \\((.+)\\) AND user_id = (.+) ORDER BY updated_at DESC, id DESC LIMIT (.+)$"
+	expectedFilteredQuery := "^SELECT (.+) FROM \"articles\" WHERE user_id = (.+) AND domain = (.+) AND created_at >= (.+) AND read_at IS NULL " +
+		"AND \\(\\(snoozed_until IS NULL OR snoozed_until <= now\\(\\)\\)\\) AND archived_at IS NULL (.+) ORDER BY title ASC, id ASC LIMIT (.+)$"
+	expectedSearchQuery := "^SELECT id, title, article_link, language, domain, reading_time, excerpt, collection_id, created_at, updated_at, read_at, archived_at, favorited_at, snoozed_until, ts_headline(.+) AS snippet, ts_rank(.+) AS rank FROM \"articles\" WHERE user_id = (.+) AND search_vector @@ to_tsquery(.+) ORDER BY rank DESC"
 
 	cases := []struct {
 		name          string
 		userID        string
+		filter        article.ListFilter
 		page          *pagination.Pagination
 		mockBehaviour func(mock sqlmock.Sqlmock, userID string, page *pagination.Pagination)
 		articles      []*article.Article
@@ -102,15 +122,30 @@ func TestList(t *testing.T) {
 			},
 			mockBehaviour: func(mock sqlmock.Sqlmock, userID string, page *pagination.Pagination) {
 				mock.ExpectQuery(expectedCountQuery).
+					WithArgs(userID).
 					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
 				mock.ExpectQuery(expectedSelectQuery).
 					WithArgs(userID, page.Limit).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "article_link", "created_at", "updated_at"}).
-						AddRow(test.TestArticle.ID, test.TestArticle.Title, test.TestArticle.ArticleLink, test.TestArticle.CreatedAt, test.TestArticle.UpdatedAt).
-						AddRow(test.TestArticle2.ID, test.TestArticle2.Title, test.TestArticle2.ArticleLink, test.TestArticle2.CreatedAt, test.TestArticle2.UpdatedAt))
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "article_link", "language", "created_at", "updated_at"}).
+						AddRow(test.TestArticle.ID, test.TestArticle.Title, test.TestArticle.ArticleLink, test.TestArticle.Language, test.TestArticle.CreatedAt, test.TestArticle.UpdatedAt).
+						AddRow(test.TestArticle2.ID, test.TestArticle2.Title, test.TestArticle2.ArticleLink, test.TestArticle2.Language, test.TestArticle2.CreatedAt, test.TestArticle2.UpdatedAt))
+				mock.ExpectQuery(preloadArticleTags).
+					WithArgs(test.TestArticle.ID, test.TestArticle2.ID).
+					WillReturnRows(sqlmock.NewRows([]string{"article_id", "tag_id"}).AddRow(test.TestArticle.ID, tagID))
+				mock.ExpectQuery(preloadTags).
+					WithArgs(tagID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(tagID, "golang"))
 			},
 			articles: []*article.Article{
-				test.TestArticle,
+				{
+					ID:          test.TestArticle.ID,
+					Title:       test.TestArticle.Title,
+					ArticleLink: test.TestArticle.ArticleLink,
+					Language:    test.TestArticle.Language,
+					CreatedAt:   test.TestArticle.CreatedAt,
+					UpdatedAt:   test.TestArticle.UpdatedAt,
+					Tags:        []*tag.Tag{{ID: tagID, Name: "golang"}},
+				},
 				test.TestArticle2,
 			},
 			total: 2,
@@ -125,11 +160,101 @@ func TestList(t *testing.T) {
 			},
 			mockBehaviour: func(mock sqlmock.Sqlmock, userID string, page *pagination.Pagination) {
 				mock.ExpectQuery(expectedCountQuery).
+					WithArgs(userID).
 					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 				mock.ExpectQuery(expectedSelectQuery).
 					WithArgs(userID, page.Limit, page.Offset).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "article_link", "created_at", "updated_at"}).
-						AddRow(test.TestArticle3.ID, test.TestArticle3.Title, test.TestArticle3.ArticleLink, test.TestArticle3.CreatedAt, test.TestArticle3.UpdatedAt))
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "article_link", "language", "created_at", "updated_at"}).
+						AddRow(test.TestArticle3.ID, test.TestArticle3.Title, test.TestArticle3.ArticleLink, test.TestArticle3.Language, test.TestArticle3.CreatedAt, test.TestArticle3.UpdatedAt))
+				mock.ExpectQuery(preloadArticleTags).
+					WithArgs(test.TestArticle3.ID).
+					WillReturnRows(sqlmock.NewRows([]string{"article_id", "tag_id"}))
+			},
+			articles: []*article.Article{
+				test.TestArticle3,
+			},
+			total: 1,
+			err:   nil,
+		},
+		{
+			name:   "should return articles after the given cursor",
+			userID: test.TestUser.ID,
+			page: &pagination.Pagination{
+				Limit:  2,
+				Cursor: &pagination.Cursor{UpdatedAt: test.TestArticle2.UpdatedAt, ID: test.TestArticle2.ID},
+			},
+			mockBehaviour: func(mock sqlmock.Sqlmock, userID string, page *pagination.Pagination) {
+				mock.ExpectQuery(expectedCountQuery).
+					WithArgs(userID).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+				mock.ExpectQuery(expectedCursorQuery).
+					WithArgs(page.Cursor.UpdatedAt, page.Cursor.ID, userID, page.Limit).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "article_link", "language", "created_at", "updated_at"}).
+						AddRow(test.TestArticle3.ID, test.TestArticle3.Title, test.TestArticle3.ArticleLink, test.TestArticle3.Language, test.TestArticle3.CreatedAt, test.TestArticle3.UpdatedAt))
+				mock.ExpectQuery(preloadArticleTags).
+					WithArgs(test.TestArticle3.ID).
+					WillReturnRows(sqlmock.NewRows([]string{"article_id", "tag_id"}))
+			},
+			articles: []*article.Article{
+				test.TestArticle3,
+			},
+			total: 3,
+			err:   nil,
+		},
+		{
+			name:   "should return filtered articles in the requested order",
+			userID: test.TestUser.ID,
+			filter: article.ListFilter{
+				Domain:      "unclatter.com",
+				CreatedFrom: &test.TestArticle3.CreatedAt,
+				Read:        new(bool),
+				Sort:        article.SortTitle,
+			},
+			page: &pagination.Pagination{
+				Limit:  2,
+				Offset: 0,
+			},
+			mockBehaviour: func(mock sqlmock.Sqlmock, userID string, page *pagination.Pagination) {
+				mock.ExpectQuery(expectedCountQuery).
+					WithArgs(userID, "unclatter.com", test.TestArticle3.CreatedAt).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery(expectedFilteredQuery).
+					WithArgs(userID, "unclatter.com", test.TestArticle3.CreatedAt, page.Limit).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "article_link", "language", "created_at", "updated_at"}).
+						AddRow(test.TestArticle3.ID, test.TestArticle3.Title, test.TestArticle3.ArticleLink, test.TestArticle3.Language, test.TestArticle3.CreatedAt, test.TestArticle3.UpdatedAt))
+				mock.ExpectQuery(preloadArticleTags).
+					WithArgs(test.TestArticle3.ID).
+					WillReturnRows(sqlmock.NewRows([]string{"article_id", "tag_id"}))
+			},
+			articles: []*article.Article{
+				test.TestArticle3,
+			},
+			total: 1,
+			err:   nil,
+		},
+		{
+			name:   "should return matching articles with highlighted snippet when searching",
+			userID: test.TestUser.ID,
+			filter: article.ListFilter{
+				Query: "articl cont",
+			},
+			page: &pagination.Pagination{
+				Limit:  2,
+				Offset: 0,
+			},
+			mockBehaviour: func(mock sqlmock.Sqlmock, userID string, page *pagination.Pagination) {
+				mock.ExpectQuery(expectedCountQuery).
+					WithArgs(userID, article.DefaultLanguage, "articl:* & cont:*").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery(expectedSearchQuery).
+					WithArgs(article.DefaultLanguage, "articl:* & cont:*", article.DefaultLanguage, "articl:* & cont:*",
+						userID, article.DefaultLanguage, "articl:* & cont:*", page.Limit).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "article_link", "language", "created_at", "updated_at", "snippet", "rank"}).
+						AddRow(test.TestArticle3.ID, test.TestArticle3.Title, test.TestArticle3.ArticleLink, test.TestArticle3.Language,
+							test.TestArticle3.CreatedAt, test.TestArticle3.UpdatedAt, "Google <mark>article</mark> <mark>content</mark> 3", 0.6))
+				mock.ExpectQuery(preloadArticleTags).
+					WithArgs(test.TestArticle3.ID).
+					WillReturnRows(sqlmock.NewRows([]string{"article_id", "tag_id"}))
 			},
 			articles: []*article.Article{
 				test.TestArticle3,
@@ -175,7 +300,7 @@ func TestList(t *testing.T) {
 		t.Run(c.name, func(t *testing.T) {
 			c.mockBehaviour(mock, c.userID, c.page)
 
-			articles, total, err := r.List(context.Background(), c.userID, *c.page)
+			articles, total, err := r.List(context.Background(), c.userID, c.filter, *c.page)
 			assert.Equal(t, c.err, err)
 			if err != nil {
 				assert.Zero(t, total)
@@ -189,6 +314,9 @@ func TestList(t *testing.T) {
 				assert.Equal(t, c.articles[i].Title, v.Title)
 				assert.Empty(t, v.Content)
 				assert.Equal(t, c.articles[i].ArticleLink, v.ArticleLink)
+				assert.Equal(t, c.articles[i].Language, v.Language)
+				assert.Equal(t, c.filter.Query != "", v.Snippet != "")
+				assert.Equal(t, len(c.articles[i].Tags), len(v.Tags))
 				assert.Empty(t, v.UserID)
 				assert.Equal(t, c.articles[i].CreatedAt, v.CreatedAt)
 				assert.Equal(t, c.articles[i].UpdatedAt, v.UpdatedAt)
@@ -221,6 +349,9 @@ func TestFindByID(t *testing.T) {
 							test.TestArticle.ID, test.TestArticle.Title, test.TestArticle.Content, test.TestArticle.ArticleLink,
 							test.TestArticle.UserID, test.TestArticle.CreatedAt, test.TestArticle.UpdatedAt,
 						))
+				mock.ExpectQuery(preloadArticleTags).
+					WithArgs(articleID).
+					WillReturnRows(sqlmock.NewRows([]string{"article_id", "tag_id"}))
 			},
 			article: test.TestArticle,
 			err:     nil,
@@ -277,16 +408,20 @@ func TestUpdate(t *testing.T) {
 
 	r := NewRepository(gormDB)
 	newArticle := article.Article{
-		ID:          test.TestArticle.ID,
-		Title:       "New Title",
-		Content:     "<p>New Content</p>",
-		ArticleLink: "https://new.link",
-		UserID:      test.TestArticle.UserID,
-		CreatedAt:   test.TestArticle.CreatedAt,
-		UpdatedAt:   time.Now().UTC(),
+		ID:             test.TestArticle.ID,
+		Title:          "New Title",
+		Content:        "<p>New Content</p>",
+		ArticleLink:    "https://new.link",
+		NormalizedLink: "https://new.link",
+		UserID:         test.TestArticle.UserID,
+		CreatedAt:      test.TestArticle.CreatedAt,
+		UpdatedAt:      time.Now().UTC(),
 	}
+	insertRevision := "^INSERT INTO \"article_revisions\""
 	invalidArticle := newArticle
 	invalidArticle.UserID = uuid.NewString()
+	staleArticle := newArticle
+	staleArticle.Version = 2
 
 	cases := []struct {
 		name          string
@@ -300,19 +435,62 @@ func TestUpdate(t *testing.T) {
 				mock.ExpectBegin()
 				mock.ExpectQuery(selectFromArticles).
 					WithArgs(arg.ID, 1).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "article_link", "user_id", "created_at", "updated_at"}).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "article_link", "user_id", "version", "created_at", "updated_at"}).
 						AddRow(
 							test.TestArticle.ID, test.TestArticle.Title, test.TestArticle.Content, test.TestArticle.ArticleLink,
-							test.TestArticle.UserID, test.TestArticle.CreatedAt, test.TestArticle.UpdatedAt,
+							test.TestArticle.UserID, test.TestArticle.Version, test.TestArticle.CreatedAt, test.TestArticle.UpdatedAt,
 						))
+				mock.ExpectExec(insertRevision).
+					WithArgs(sqlmock.AnyArg(), test.TestArticle.ID, test.TestArticle.Version, test.TestArticle.Title, test.TestArticle.Content,
+						test.TestArticle.ArticleLink, article.DefaultLanguage, article.RevisionUserEdit, test.AnyTime{}).
+					WillReturnResult(sqlmock.NewResult(1, 1))
 				mock.ExpectExec("^UPDATE \"articles\" SET").
-					WithArgs(arg.Title, arg.Content, arg.ArticleLink, test.AnyTime{}, arg.ID).
+					WithArgs(arg.Title, arg.Content, arg.ArticleLink, arg.NormalizedLink, test.TestArticle.Version+1, test.AnyTime{}, arg.ID).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 				mock.ExpectCommit()
 			},
 			arg: newArticle,
 			err: nil,
 		},
+		{
+			name: "should return err when updating link to one already bookmarked",
+			mockBehaviour: func(mock sqlmock.Sqlmock, arg article.Article) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectFromArticles).
+					WithArgs(arg.ID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "article_link", "user_id", "version", "created_at", "updated_at"}).
+						AddRow(
+							test.TestArticle.ID, test.TestArticle.Title, test.TestArticle.Content, test.TestArticle.ArticleLink,
+							test.TestArticle.UserID, test.TestArticle.Version, test.TestArticle.CreatedAt, test.TestArticle.UpdatedAt,
+						))
+				mock.ExpectExec(insertRevision).
+					WithArgs(sqlmock.AnyArg(), test.TestArticle.ID, test.TestArticle.Version, test.TestArticle.Title, test.TestArticle.Content,
+						test.TestArticle.ArticleLink, article.DefaultLanguage, article.RevisionUserEdit, test.AnyTime{}).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("^UPDATE \"articles\" SET").
+					WithArgs(arg.Title, arg.Content, arg.ArticleLink, arg.NormalizedLink, test.TestArticle.Version+1, test.AnyTime{}, arg.ID).
+					WillReturnError(gorm.ErrDuplicatedKey)
+				mock.ExpectRollback()
+			},
+			arg: newArticle,
+			err: validation.NewError(validation.Conflict, "this url is already bookmarked"),
+		},
+		{
+			name: "should return err when article has been modified since the expected version",
+			mockBehaviour: func(mock sqlmock.Sqlmock, arg article.Article) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectFromArticles).
+					WithArgs(arg.ID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "article_link", "user_id", "version", "created_at", "updated_at"}).
+						AddRow(
+							test.TestArticle.ID, test.TestArticle.Title, test.TestArticle.Content, test.TestArticle.ArticleLink,
+							test.TestArticle.UserID, 3, test.TestArticle.CreatedAt, test.TestArticle.UpdatedAt,
+						))
+				mock.ExpectRollback()
+			},
+			arg: staleArticle,
+			err: validation.NewError(validation.PreconditionFailed, "article has been modified, fetch the latest version and retry"),
+		},
 		{
 			name: "should return err when updating non existing article",
 			mockBehaviour: func(mock sqlmock.Sqlmock, arg article.Article) {
@@ -346,72 +524,1271 @@ func TestUpdate(t *testing.T) {
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
 			c.mockBehaviour(mock, c.arg)
-			_, err := r.Update(context.Background(), c.arg)
+			updated, err := r.Update(context.Background(), c.arg, article.RevisionUserEdit)
 			assert.Equal(t, c.err, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, test.TestArticle.Version+1, updated.Version)
 		})
 	}
 }
 
-func TestDelete(t *testing.T) {
+func TestUpdateExcerpt(t *testing.T) {
 	gormDB, db, mock := test.NewMockDB(t)
 	defer db.Close()
 
 	r := NewRepository(gormDB)
-	deleteQuery := "^DELETE FROM \"articles\" WHERE"
+	updateQuery := "^UPDATE \"articles\" SET \"excerpt\"=(.+) WHERE id = (.+) AND \"articles\".\"deleted_at\" IS NULL"
 
 	cases := []struct {
 		name          string
-		mockBehaviour func(mock sqlmock.Sqlmock, userID, articleID string)
-		userID        string
-		articleID     string
+		mockBehaviour func(mock sqlmock.Sqlmock)
 		err           error
 	}{
 		{
-			name: "should successfully delete article record",
-			mockBehaviour: func(mock sqlmock.Sqlmock, userID, articleID string) {
+			name: "should update article excerpt",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectExec(deleteQuery).
-					WithArgs(articleID, userID).
+				mock.ExpectExec(updateQuery).
+					WithArgs("excerpt", test.TestArticle.ID).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 				mock.ExpectCommit()
 			},
-			userID:    test.TestArticle.UserID,
-			articleID: test.TestArticle.ID,
-			err:       nil,
+			err: nil,
 		},
 		{
-			name: "should return err whene delete other user's article record",
-			mockBehaviour: func(mock sqlmock.Sqlmock, userID, articleID string) {
+			name: "should return err when article doesn't exists",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(updateQuery).
+					WithArgs("excerpt", test.TestArticle.ID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			err: validation.NewError(validation.NotFound, "no article found with given id"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+			err := r.UpdateExcerpt(context.Background(), test.TestArticle.ID, "excerpt")
+			assert.Equal(t, c.err, err)
+		})
+	}
+}
+
+func TestListUnindexed(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+
+	mock.ExpectQuery("^SELECT \\* FROM \"articles\" WHERE indexed_version <> version AND \"articles\".\"deleted_at\" IS NULL ORDER BY updated_at LIMIT").
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "version", "indexed_version"}).
+			AddRow(test.TestArticle.ID, test.TestArticle.Title, test.TestArticle.Content, 2, 1))
+
+	articles, err := r.ListUnindexed(context.Background(), 10)
+	assert.Nil(t, err)
+	assert.Len(t, articles, 1)
+	assert.Equal(t, test.TestArticle.ID, articles[0].ID)
+	assert.Equal(t, 1, articles[0].IndexedVersion)
+}
+
+func TestListUnsanitized(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+
+	mock.ExpectQuery("^SELECT \\* FROM \"articles\" WHERE sanitized_version < (.+) AND \"articles\".\"deleted_at\" IS NULL ORDER BY id LIMIT").
+		WithArgs(2, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "content", "version", "sanitized_version"}).
+			AddRow(test.TestArticle.ID, test.TestArticle.Content, 1, 1))
+
+	articles, err := r.ListUnsanitized(context.Background(), 2, 10)
+	assert.Nil(t, err)
+	assert.Len(t, articles, 1)
+	assert.Equal(t, test.TestArticle.ID, articles[0].ID)
+	assert.Equal(t, 1, articles[0].SanitizedVersion)
+}
+
+func TestMarkSanitized(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("^UPDATE \"articles\" SET \"sanitized_version\"=(.+) WHERE id = (.+) AND \"articles\".\"deleted_at\" IS NULL").
+		WithArgs(2, test.TestArticle.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := r.MarkSanitized(context.Background(), test.TestArticle.ID, 2)
+	assert.Nil(t, err)
+}
+
+func TestSaveIndex(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	indexQuery := "^UPDATE \"articles\" SET \"excerpt\"=(.+),\"fingerprint\"=(.+),\"indexed_version\"=(.+) WHERE \\(id = (.+) AND version = (.+)\\) AND \"articles\".\"deleted_at\" IS NULL"
+	deleteQuery := "^DELETE FROM \"article_terms\" WHERE article_id = "
+	insertQuery := "^INSERT INTO \"article_terms\""
+	terms := []*article.Term{
+		{ArticleID: test.TestArticle.ID, Term: "postgres", UserID: test.TestArticle.UserID, Weight: 0.8},
+		{ArticleID: test.TestArticle.ID, Term: "index", UserID: test.TestArticle.UserID, Weight: 0.6},
+	}
+
+	cases := []struct {
+		name          string
+		terms         []*article.Term
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		err           error
+	}{
+		{
+			name:  "should replace the article's term vector",
+			terms: terms,
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
+				mock.ExpectExec(indexQuery).
+					WithArgs(test.TestArticle.Excerpt, test.TestArticle.Fingerprint, test.TestArticle.Version, test.TestArticle.ID, test.TestArticle.Version).
+					WillReturnResult(sqlmock.NewResult(0, 1))
 				mock.ExpectExec(deleteQuery).
-					WithArgs(articleID, userID).
+					WithArgs(test.TestArticle.ID).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectExec(insertQuery).
+					WithArgs(test.TestArticle.ID, "postgres", test.TestArticle.UserID, 0.8,
+						test.TestArticle.ID, "index", test.TestArticle.UserID, 0.6).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+			err: nil,
+		},
+		{
+			name:  "should only clear the term vector when the article has no terms",
+			terms: nil,
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(indexQuery).
+					WithArgs(test.TestArticle.Excerpt, test.TestArticle.Fingerprint, test.TestArticle.Version, test.TestArticle.ID, test.TestArticle.Version).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(deleteQuery).
+					WithArgs(test.TestArticle.ID).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectCommit()
+			},
+			err: nil,
+		},
+		{
+			name:  "should keep the term vector when the article was edited since it was read",
+			terms: terms,
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(indexQuery).
+					WithArgs(test.TestArticle.Excerpt, test.TestArticle.Fingerprint, test.TestArticle.Version, test.TestArticle.ID, test.TestArticle.Version).
 					WillReturnResult(sqlmock.NewResult(0, 0))
 				mock.ExpectCommit()
 			},
-			userID:    test.TestArticle2.UserID,
-			articleID: test.TestArticle.ID,
-			err:       validation.NewError(validation.BadRequest, "fail to delete article"),
+			err: nil,
 		},
 		{
-			name: "should return err when fail to delete article record",
-			mockBehaviour: func(mock sqlmock.Sqlmock, userID, articleID string) {
+			name:  "should return err when fail to save terms",
+			terms: terms,
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
+				mock.ExpectExec(indexQuery).
+					WithArgs(test.TestArticle.Excerpt, test.TestArticle.Fingerprint, test.TestArticle.Version, test.TestArticle.ID, test.TestArticle.Version).
+					WillReturnResult(sqlmock.NewResult(0, 1))
 				mock.ExpectExec(deleteQuery).
-					WithArgs(articleID, userID).
+					WithArgs(test.TestArticle.ID).
 					WillReturnError(gorm.ErrInvalidDB)
 				mock.ExpectRollback()
 			},
-			userID:    test.TestArticle2.UserID,
-			articleID: test.TestArticle.ID,
-			err:       gorm.ErrInvalidDB,
+			err: gorm.ErrInvalidDB,
 		},
 	}
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
-			c.mockBehaviour(mock, c.userID, c.articleID)
-			err := r.Delete(context.Background(), c.userID, c.articleID)
+			c.mockBehaviour(mock)
+			err := r.SaveIndex(context.Background(), *test.TestArticle, c.terms)
+			assert.Equal(t, c.err, err)
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestListRelated(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	relatedQuery := "^SELECT id, title, article_link, language, domain, reading_time, excerpt, collection_id, created_at, updated_at, read_at, archived_at, favorited_at, snoozed_until, related.similarity FROM articles JOIN (.+) JOIN articles live ON live.id = candidate.article_id AND live.deleted_at IS NULL (.+) JOIN articles live ON live.id = terms.article_id AND live.deleted_at IS NULL (.+) related ON related.article_id = articles.id WHERE articles.deleted_at IS NULL ORDER BY related.similarity DESC, articles.id LIMIT"
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		expected      []*article.Article
+		err           error
+	}{
+		{
+			name: "should return articles ranked by similarity",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(relatedQuery).
+					WithArgs(test.TestArticle.UserID, test.TestArticle.UserID, test.TestArticle.UserID, test.TestArticle.ID, test.TestArticle.ID, 10).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "similarity"}).
+						AddRow(test.TestArticle2.ID, test.TestArticle2.Title, 0.42))
+			},
+			expected: []*article.Article{{ID: test.TestArticle2.ID, Title: test.TestArticle2.Title, Similarity: 0.42}},
+			err:      nil,
+		},
+		{
+			name: "should return err when fail to list related articles",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(relatedQuery).
+					WillReturnError(gorm.ErrInvalidDB)
+			},
+			expected: nil,
+			err:      gorm.ErrInvalidDB,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+			articles, err := r.ListRelated(context.Background(), test.TestArticle.UserID, test.TestArticle.ID, 10)
 			assert.Equal(t, c.err, err)
+			if err == nil {
+				assert.Equal(t, c.expected, articles)
+			}
+		})
+	}
+}
+
+func TestListFingerprinted(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+
+	mock.ExpectQuery("^SELECT id, title, article_link, language, domain, reading_time, excerpt, collection_id, created_at, updated_at, read_at, archived_at, favorited_at, snoozed_until, fingerprint FROM \"articles\" WHERE \\(user_id = (.+) AND fingerprint <> 0\\) AND \"articles\".\"deleted_at\" IS NULL ORDER BY created_at, id").
+		WithArgs(test.TestUser.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "fingerprint"}).
+			AddRow(test.TestArticle.ID, test.TestArticle.Title, int64(42)))
+
+	articles, err := r.ListFingerprinted(context.Background(), test.TestUser.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, []*article.Article{{ID: test.TestArticle.ID, Title: test.TestArticle.Title, Fingerprint: 42}}, articles)
+}
+
+func TestFindNearDuplicates(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+
+	mock.ExpectQuery("^SELECT id, title, article_link, language, domain, reading_time, excerpt, collection_id, created_at, updated_at, read_at, archived_at, favorited_at, snoozed_until FROM \"articles\" WHERE \\(user_id = (.+) AND id <> (.+) AND fingerprint <> 0 AND bit_count\\(\\(fingerprint # (.+)\\)::bit\\(64\\)\\) <= (.+)\\) AND \"articles\".\"deleted_at\" IS NULL ORDER BY created_at, id LIMIT").
+		WithArgs(test.TestUser.ID, test.TestArticle.ID, int64(42), article.NearDuplicateDistance, article.MaxNearDuplicates).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).
+			AddRow(test.TestArticle2.ID, test.TestArticle2.Title))
+
+	articles, err := r.FindNearDuplicates(context.Background(), test.TestUser.ID, test.TestArticle.ID, 42)
+	assert.Nil(t, err)
+	assert.Equal(t, []*article.Article{{ID: test.TestArticle2.ID, Title: test.TestArticle2.Title}}, articles)
+}
+
+func TestMerge(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	lockQuery := "^SELECT \\* FROM \"articles\" WHERE \\(id IN \\((.+),(.+)\\) AND user_id = (.+)\\) AND \"articles\".\"deleted_at\" IS NULL ORDER BY id FOR UPDATE"
+	tagQuery := "^INSERT INTO article_tags \\(article_id, tag_id\\) SELECT DISTINCT (.+), tag_id FROM article_tags WHERE article_id IN \\((.+)\\) ON CONFLICT DO NOTHING"
+	updateQuery := "^UPDATE \"articles\" SET \"collection_id\"=(.+),\"favorited_at\"=(.+),\"read_at\"=(.+) WHERE id = (.+) AND \"articles\".\"deleted_at\" IS NULL"
+	deleteQuery := "^UPDATE \"articles\" SET \"deleted_at\"=(.+) WHERE id IN \\((.+)\\) AND \"articles\".\"deleted_at\" IS NULL"
+	selectQuery := "^SELECT \\* FROM \"articles\" WHERE id = (.+) AND \"articles\".\"deleted_at\" IS NULL ORDER BY \"articles\".\"id\" LIMIT"
+	tagsQuery := "^SELECT \\* FROM \"article_tags\" WHERE \"article_tags\".\"article_id\" = "
+	readAt := time.Now().UTC().Add(-time.Hour)
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		expected      *article.Article
+		err           error
+	}{
+		{
+			name: "should merge duplicates into the kept article",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockQuery).
+					WithArgs(test.TestArticle.ID, test.TestArticle2.ID, test.TestUser.ID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "read_at"}).
+						AddRow(test.TestArticle.ID, test.TestUser.ID, nil).
+						AddRow(test.TestArticle2.ID, test.TestUser.ID, readAt))
+				mock.ExpectExec(tagQuery).
+					WithArgs(test.TestArticle.ID, test.TestArticle2.ID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(updateQuery).
+					WithArgs(nil, nil, readAt, test.TestArticle.ID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(deleteQuery).
+					WithArgs(test.AnyTime{}, test.TestArticle2.ID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(selectQuery).
+					WithArgs(test.TestArticle.ID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "read_at"}).
+						AddRow(test.TestArticle.ID, test.TestUser.ID, readAt))
+				mock.ExpectQuery(tagsQuery).
+					WithArgs(test.TestArticle.ID).
+					WillReturnRows(sqlmock.NewRows([]string{"article_id", "tag_id"}))
+				mock.ExpectCommit()
+			},
+			expected: &article.Article{ID: test.TestArticle.ID, UserID: test.TestUser.ID, ReadAt: &readAt},
+			err:      nil,
+		},
+		{
+			name: "should return err when an article isn't in the user's library",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockQuery).
+					WithArgs(test.TestArticle.ID, test.TestArticle2.ID, test.TestUser.ID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).
+						AddRow(test.TestArticle.ID, test.TestUser.ID))
+				mock.ExpectRollback()
+			},
+			expected: nil,
+			err:      validation.NewError(validation.NotFound, "no article found with given id"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+			merged, err := r.Merge(context.Background(), test.TestUser.ID, test.TestArticle.ID, []string{test.TestArticle2.ID})
+			assert.Equal(t, c.err, err)
+			if c.expected == nil {
+				assert.Nil(t, merged)
+				return
+			}
+			assert.Equal(t, c.expected.ID, merged.ID)
+			assert.Equal(t, c.expected.ReadAt.Unix(), merged.ReadAt.Unix())
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	deleteQuery := "^UPDATE \"articles\" SET \"deleted_at\"=(.+) WHERE \\(id = (.+) AND user_id = (.+)\\) AND \"articles\".\"deleted_at\" IS NULL"
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock, userID, articleID string)
+		userID        string
+		articleID     string
+		err           error
+	}{
+		{
+			name: "should move article to the trash",
+			mockBehaviour: func(mock sqlmock.Sqlmock, userID, articleID string) {
+				mock.ExpectBegin()
+				mock.ExpectExec(deleteQuery).
+					WithArgs(test.AnyTime{}, articleID, userID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			userID:    test.TestArticle.UserID,
+			articleID: test.TestArticle.ID,
+			err:       nil,
+		},
+		{
+			name: "should return err whene delete other user's article record",
+			mockBehaviour: func(mock sqlmock.Sqlmock, userID, articleID string) {
+				mock.ExpectBegin()
+				mock.ExpectExec(deleteQuery).
+					WithArgs(test.AnyTime{}, articleID, userID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			userID:    test.TestArticle2.UserID,
+			articleID: test.TestArticle.ID,
+			err:       validation.NewError(validation.BadRequest, "fail to delete article"),
+		},
+		{
+			name: "should return err when fail to delete article record",
+			mockBehaviour: func(mock sqlmock.Sqlmock, userID, articleID string) {
+				mock.ExpectBegin()
+				mock.ExpectExec(deleteQuery).
+					WithArgs(test.AnyTime{}, articleID, userID).
+					WillReturnError(gorm.ErrInvalidDB)
+				mock.ExpectRollback()
+			},
+			userID:    test.TestArticle2.UserID,
+			articleID: test.TestArticle.ID,
+			err:       gorm.ErrInvalidDB,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock, c.userID, c.articleID)
+			err := r.Delete(context.Background(), c.userID, c.articleID)
+			assert.Equal(t, c.err, err)
+		})
+	}
+}
+
+func TestUpdateState(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	readAt := time.Now().UTC()
+
+	cases := []struct {
+		name          string
+		userID        string
+		articleID     string
+		state         article.State
+		at            *time.Time
+		mockBehaviour func(mock sqlmock.Sqlmock, articleID string, at *time.Time)
+		err           error
+	}{
+		{
+			name:      "should mark article as read",
+			userID:    test.TestArticle.UserID,
+			articleID: test.TestArticle.ID,
+			state:     article.StateRead,
+			at:        &readAt,
+			mockBehaviour: func(mock sqlmock.Sqlmock, articleID string, at *time.Time) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectFromArticles).
+					WithArgs(articleID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "article_link", "user_id", "created_at", "updated_at"}).
+						AddRow(
+							test.TestArticle.ID, test.TestArticle.Title, test.TestArticle.Content, test.TestArticle.ArticleLink,
+							test.TestArticle.UserID, test.TestArticle.CreatedAt, test.TestArticle.UpdatedAt,
+						))
+				mock.ExpectExec("^UPDATE \"articles\" SET \"read_at\"").
+					WithArgs(at, articleID).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			err: nil,
+		},
+		{
+			name:      "should clear article favorite state",
+			userID:    test.TestArticle.UserID,
+			articleID: test.TestArticle.ID,
+			state:     article.StateFavorited,
+			at:        nil,
+			mockBehaviour: func(mock sqlmock.Sqlmock, articleID string, at *time.Time) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectFromArticles).
+					WithArgs(articleID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "favorited_at"}).
+						AddRow(test.TestArticle.ID, test.TestArticle.UserID, readAt))
+				mock.ExpectExec("^UPDATE \"articles\" SET \"favorited_at\"").
+					WithArgs(nil, articleID).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			err: nil,
+		},
+		{
+			name:      "should return err when updating non existing article",
+			userID:    test.TestArticle.UserID,
+			articleID: uuid.NewString(),
+			state:     article.StateArchived,
+			at:        &readAt,
+			mockBehaviour: func(mock sqlmock.Sqlmock, articleID string, at *time.Time) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectFromArticles).
+					WithArgs(articleID, 1).
+					WillReturnError(gorm.ErrRecordNotFound)
+				mock.ExpectRollback()
+			},
+			err: validation.NewError(validation.NotFound, "no article found with given id"),
+		},
+		{
+			name:      "should return err when updating another user's article state",
+			userID:    uuid.NewString(),
+			articleID: test.TestArticle.ID,
+			state:     article.StateArchived,
+			at:        &readAt,
+			mockBehaviour: func(mock sqlmock.Sqlmock, articleID string, at *time.Time) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectFromArticles).
+					WithArgs(articleID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).
+						AddRow(test.TestArticle.ID, test.TestArticle.UserID))
+				mock.ExpectRollback()
+			},
+			err: validation.NewError(validation.Forbidden, "forbidden access"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock, c.articleID, c.at)
+
+			updated, err := r.UpdateState(context.Background(), c.userID, c.articleID, c.state, c.at)
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				return
+			}
+
+			switch c.state {
+			case article.StateRead:
+				assert.Equal(t, c.at, updated.ReadAt)
+			case article.StateArchived:
+				assert.Equal(t, c.at, updated.ArchivedAt)
+			case article.StateFavorited:
+				assert.Equal(t, c.at, updated.FavoritedAt)
+			}
+		})
+	}
+}
+
+func TestUpdateSnooze(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	until := time.Now().Add(24 * time.Hour).UTC()
+
+	cases := []struct {
+		name          string
+		userID        string
+		articleID     string
+		until         *time.Time
+		mockBehaviour func(mock sqlmock.Sqlmock, articleID string, until *time.Time)
+		err           error
+	}{
+		{
+			name:      "should snooze article until the given time",
+			userID:    test.TestArticle.UserID,
+			articleID: test.TestArticle.ID,
+			until:     &until,
+			mockBehaviour: func(mock sqlmock.Sqlmock, articleID string, until *time.Time) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectFromArticles).
+					WithArgs(articleID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).
+						AddRow(test.TestArticle.ID, test.TestArticle.UserID))
+				mock.ExpectExec("^UPDATE \"articles\" SET \"snoozed_until\"").
+					WithArgs(until, articleID).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			err: nil,
+		},
+		{
+			name:      "should wake snoozed article",
+			userID:    test.TestArticle.UserID,
+			articleID: test.TestArticle.ID,
+			until:     nil,
+			mockBehaviour: func(mock sqlmock.Sqlmock, articleID string, until *time.Time) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectFromArticles).
+					WithArgs(articleID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "snoozed_until"}).
+						AddRow(test.TestArticle.ID, test.TestArticle.UserID, until))
+				mock.ExpectExec("^UPDATE \"articles\" SET \"snoozed_until\"").
+					WithArgs(nil, articleID).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			err: nil,
+		},
+		{
+			name:      "should return err when snoozing non existing article",
+			userID:    test.TestArticle.UserID,
+			articleID: uuid.NewString(),
+			until:     &until,
+			mockBehaviour: func(mock sqlmock.Sqlmock, articleID string, until *time.Time) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectFromArticles).
+					WithArgs(articleID, 1).
+					WillReturnError(gorm.ErrRecordNotFound)
+				mock.ExpectRollback()
+			},
+			err: validation.NewError(validation.NotFound, "no article found with given id"),
+		},
+		{
+			name:      "should return err when snoozing another user's article",
+			userID:    uuid.NewString(),
+			articleID: test.TestArticle.ID,
+			until:     &until,
+			mockBehaviour: func(mock sqlmock.Sqlmock, articleID string, until *time.Time) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectFromArticles).
+					WithArgs(articleID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).
+						AddRow(test.TestArticle.ID, test.TestArticle.UserID))
+				mock.ExpectRollback()
+			},
+			err: validation.NewError(validation.Forbidden, "forbidden access"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock, c.articleID, c.until)
+
+			updated, err := r.UpdateSnooze(context.Background(), c.userID, c.articleID, c.until)
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.until, updated.SnoozedUntil)
+		})
+	}
+}
+
+func TestCountStates(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		counts        *article.StateCounts
+		err           error
+	}{
+		{
+			name: "should return user's article counts per state",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT COUNT(.+) FROM \"articles\" WHERE user_id = ").
+					WithArgs(test.TestUser.ID).
+					WillReturnRows(sqlmock.NewRows([]string{"total", "inbox", "unread", "read", "archived", "favorites", "snoozed"}).
+						AddRow(10, 7, 4, 4, 2, 3, 1))
+			},
+			counts: &article.StateCounts{Total: 10, Inbox: 7, Unread: 4, Read: 4, Archived: 2, Favorites: 3, Snoozed: 1},
+			err:    nil,
+		},
+		{
+			name: "should return err when fail to count articles",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT COUNT(.+) FROM \"articles\" WHERE user_id = ").
+					WithArgs(test.TestUser.ID).
+					WillReturnError(gorm.ErrInvalidDB)
+			},
+			counts: nil,
+			err:    gorm.ErrInvalidDB,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+
+			counts, err := r.CountStates(context.Background(), test.TestUser.ID)
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.counts, counts)
+		})
+	}
+}
+
+func TestReadingStats(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	period := article.StatsPeriod{
+		First:    time.Date(2026, 9, 28, 0, 0, 0, 0, time.UTC),
+		Last:     time.Date(2026, 10, 11, 0, 0, 0, 0, time.UTC),
+		Location: time.UTC,
+	}
+	totalsQuery := "^SELECT COUNT(.+) AS saved, (.+) FROM articles WHERE user_id = (.+) AND deleted_at IS NULL"
+	weeksQuery := "^SELECT to_char\\(week, 'YYYY-MM-DD'\\) AS week, (.+) GROUP BY week ORDER BY week"
+	domainsQuery := "^SELECT domain, COUNT(.+) GROUP BY domain ORDER BY saved DESC, domain LIMIT"
+	runsQuery := "^SELECT MIN\\(day\\) AS first_day, MAX\\(day\\) AS last_day (.+) GROUP BY run HAVING MAX\\(day\\) >= (.+) ORDER BY first_day"
+	averageSeconds := int64(7200)
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		expected      *article.Stats
+		err           error
+	}{
+		{
+			name: "should return user's aggregated reading stats",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(totalsQuery).
+					WillReturnRows(sqlmock.NewRows([]string{"saved", "read", "reading_time", "average_seconds_to_read"}).
+						AddRow(5, 3, 24, 7200))
+				mock.ExpectQuery(weeksQuery).
+					WillReturnRows(sqlmock.NewRows([]string{"week", "saved", "read"}).
+						AddRow("2026-09-28", 5, 1).
+						AddRow("2026-10-05", 0, 2))
+				mock.ExpectQuery(domainsQuery).
+					WillReturnRows(sqlmock.NewRows([]string{"domain", "saved", "read"}).
+						AddRow("unclatter.com", 3, 2))
+				mock.ExpectQuery(runsQuery).
+					WillReturnRows(sqlmock.NewRows([]string{"first_day", "last_day"}).
+						AddRow(time.Date(2026, 10, 6, 0, 0, 0, 0, time.UTC), time.Date(2026, 10, 7, 0, 0, 0, 0, time.UTC)))
+			},
+			expected: &article.Stats{
+				Saved:                5,
+				Read:                 3,
+				ReadingTime:          24,
+				AverageSecondsToRead: &averageSeconds,
+				Weeks: []*article.WeekStats{
+					{Week: "2026-09-28", Saved: 5, Read: 1},
+					{Week: "2026-10-05", Saved: 0, Read: 2},
+				},
+				TopDomains: []*article.DomainStats{{Domain: "unclatter.com", Saved: 3, Read: 2}},
+				ReadRuns: []*article.ReadRun{
+					{FirstDay: time.Date(2026, 10, 6, 0, 0, 0, 0, time.UTC), LastDay: time.Date(2026, 10, 7, 0, 0, 0, 0, time.UTC)},
+				},
+			},
+			err: nil,
+		},
+		{
+			name: "should return err when fail to aggregate the weeks",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(totalsQuery).
+					WillReturnRows(sqlmock.NewRows([]string{"saved", "read", "reading_time", "average_seconds_to_read"}).
+						AddRow(0, 0, 0, nil))
+				mock.ExpectQuery(weeksQuery).
+					WillReturnError(gorm.ErrInvalidDB)
+			},
+			expected: nil,
+			err:      gorm.ErrInvalidDB,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+
+			stats, err := r.ReadingStats(context.Background(), test.TestUser.ID, period)
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.expected, stats)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestFindByLink(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	expectedQuery := "^SELECT (.+) FROM \"articles\" WHERE \\(user_id = (.+) AND normalized_link = (.+)\\) AND \"articles\".\"deleted_at\" IS NULL"
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		article       *article.Article
+		err           error
+	}{
+		{
+			name: "should return user's article with given normalized link",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(expectedQuery).
+					WithArgs(test.TestArticle.UserID, test.TestArticle.NormalizedLink, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "article_link", "normalized_link", "user_id"}).
+						AddRow(test.TestArticle.ID, test.TestArticle.Title, test.TestArticle.ArticleLink, test.TestArticle.NormalizedLink, test.TestArticle.UserID))
+			},
+			article: test.TestArticle,
+			err:     nil,
+		},
+		{
+			name: "should return not found err when link isn't bookmarked",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(expectedQuery).
+					WithArgs(test.TestArticle.UserID, test.TestArticle.NormalizedLink, 1).
+					WillReturnError(gorm.ErrRecordNotFound)
+			},
+			article: nil,
+			err:     validation.NewError(validation.NotFound, "no article found with given link"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+
+			article, err := r.FindByLink(context.Background(), test.TestArticle.UserID, test.TestArticle.NormalizedLink)
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.article.ID, article.ID)
+			assert.Equal(t, c.article.NormalizedLink, article.NormalizedLink)
+		})
+	}
+}
+
+func TestListTrashed(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	userID := test.TestArticle.UserID
+	page := pagination.Pagination{Limit: 2, Offset: 0}
+	deletedAt := time.Now().UTC()
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		expected      []*article.Article
+		total         int64
+		err           error
+	}{
+		{
+			name: "should return user's trashed articles",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT count(.+) FROM \"articles\" WHERE user_id = (.+) AND deleted_at IS NOT NULL$").
+					WithArgs(userID).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery("^SELECT (.+), deleted_at FROM \"articles\" WHERE user_id = (.+) AND deleted_at IS NOT NULL ORDER BY deleted_at DESC").
+					WithArgs(userID, page.Limit).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "article_link", "deleted_at"}).
+						AddRow(test.TestArticle.ID, test.TestArticle.Title, test.TestArticle.ArticleLink, deletedAt))
+			},
+			expected: []*article.Article{
+				{
+					ID:          test.TestArticle.ID,
+					Title:       test.TestArticle.Title,
+					ArticleLink: test.TestArticle.ArticleLink,
+					DeletedAt:   gorm.DeletedAt{Time: deletedAt, Valid: true},
+				},
+			},
+			total: 1,
+			err:   nil,
+		},
+		{
+			name: "should return err when fail to count trashed articles",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT count(.+) FROM \"articles\"").
+					WithArgs(userID).
+					WillReturnError(gorm.ErrInvalidDB)
+			},
+			expected: nil,
+			total:    0,
+			err:      gorm.ErrInvalidDB,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+
+			articles, total, err := r.ListTrashed(context.Background(), userID, page)
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.total, total)
+			assert.Equal(t, c.expected, articles)
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	selectTrashed := "^SELECT (.+) FROM \"articles\" WHERE id = (.+) AND deleted_at IS NOT NULL (.+) FOR UPDATE"
+	deletedAt := time.Now().UTC()
+
+	cases := []struct {
+		name          string
+		userID        string
+		articleID     string
+		mockBehaviour func(mock sqlmock.Sqlmock, articleID string)
+		err           error
+	}{
+		{
+			name:      "should restore trashed article",
+			userID:    test.TestArticle.UserID,
+			articleID: test.TestArticle.ID,
+			mockBehaviour: func(mock sqlmock.Sqlmock, articleID string) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectTrashed).
+					WithArgs(articleID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "user_id", "deleted_at"}).
+						AddRow(test.TestArticle.ID, test.TestArticle.Title, test.TestArticle.UserID, deletedAt))
+				mock.ExpectExec("^UPDATE \"articles\" SET \"deleted_at\"=(.+) WHERE \"id\" = ").
+					WithArgs(nil, articleID).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			err: nil,
+		},
+		{
+			name:      "should return err when article isn't in the trash",
+			userID:    test.TestArticle.UserID,
+			articleID: uuid.NewString(),
+			mockBehaviour: func(mock sqlmock.Sqlmock, articleID string) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectTrashed).
+					WithArgs(articleID, 1).
+					WillReturnError(gorm.ErrRecordNotFound)
+				mock.ExpectRollback()
+			},
+			err: validation.NewError(validation.NotFound, "no trashed article found with given id"),
+		},
+		{
+			name:      "should return err when restoring another user's article",
+			userID:    uuid.NewString(),
+			articleID: test.TestArticle.ID,
+			mockBehaviour: func(mock sqlmock.Sqlmock, articleID string) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectTrashed).
+					WithArgs(articleID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "deleted_at"}).
+						AddRow(test.TestArticle.ID, test.TestArticle.UserID, deletedAt))
+				mock.ExpectRollback()
+			},
+			err: validation.NewError(validation.Forbidden, "forbidden access"),
+		},
+		{
+			name:      "should return err when the url was bookmarked again",
+			userID:    test.TestArticle.UserID,
+			articleID: test.TestArticle.ID,
+			mockBehaviour: func(mock sqlmock.Sqlmock, articleID string) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectTrashed).
+					WithArgs(articleID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "deleted_at"}).
+						AddRow(test.TestArticle.ID, test.TestArticle.UserID, deletedAt))
+				mock.ExpectExec("^UPDATE \"articles\" SET \"deleted_at\"").
+					WithArgs(nil, articleID).
+					WillReturnError(gorm.ErrDuplicatedKey)
+				mock.ExpectRollback()
+			},
+			err: validation.NewError(validation.Conflict, "this url is already bookmarked"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock, c.articleID)
+
+			restored, err := r.Restore(context.Background(), c.userID, c.articleID)
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.articleID, restored.ID)
+			assert.False(t, restored.DeletedAt.Valid)
+		})
+	}
+}
+
+func TestPurge(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	purgeQuery := "^DELETE FROM \"articles\" WHERE id = (.+) AND user_id = (.+) AND deleted_at IS NOT NULL"
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock, userID, articleID string)
+		userID        string
+		articleID     string
+		err           error
+	}{
+		{
+			name: "should permanently delete trashed article",
+			mockBehaviour: func(mock sqlmock.Sqlmock, userID, articleID string) {
+				mock.ExpectBegin()
+				mock.ExpectExec(purgeQuery).
+					WithArgs(articleID, userID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			userID:    test.TestArticle.UserID,
+			articleID: test.TestArticle.ID,
+			err:       nil,
+		},
+		{
+			name: "should return err when article isn't in user's trash",
+			mockBehaviour: func(mock sqlmock.Sqlmock, userID, articleID string) {
+				mock.ExpectBegin()
+				mock.ExpectExec(purgeQuery).
+					WithArgs(articleID, userID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			userID:    test.TestArticle2.UserID,
+			articleID: test.TestArticle.ID,
+			err:       validation.NewError(validation.NotFound, "no trashed article found with given id"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock, c.userID, c.articleID)
+			err := r.Purge(context.Background(), c.userID, c.articleID)
+			assert.Equal(t, c.err, err)
+		})
+	}
+}
+
+func TestPurgeTrashedBefore(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	before := time.Now().UTC().Add(-30 * 24 * time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("^DELETE FROM \"articles\" WHERE deleted_at < ").
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	purged, err := r.PurgeTrashedBefore(context.Background(), before)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), purged)
+}
+
+func TestBulkUpdate(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	userID := test.TestArticle.UserID
+	otherUsersArticle := uuid.NewString()
+	missingArticle := uuid.NewString()
+	collectionID := uuid.NewString()
+	at := time.Now().UTC()
+	lockQuery := "^SELECT id, user_id FROM \"articles\" WHERE id IN (.+) FOR UPDATE$"
+
+	cases := []struct {
+		name          string
+		op            article.BulkOperation
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		results       []*article.BulkResult
+		err           error
+	}{
+		{
+			name: "should archive user's articles and report skipped ones",
+			op: article.BulkOperation{
+				Action: article.BulkArchive,
+				IDs:    []string{test.TestArticle.ID, otherUsersArticle, missingArticle},
+				At:     at,
+			},
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockQuery).
+					WithArgs(test.TestArticle.ID, otherUsersArticle, missingArticle).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).
+						AddRow(test.TestArticle.ID, userID).
+						AddRow(otherUsersArticle, uuid.NewString()))
+				mock.ExpectExec("^UPDATE \"articles\" SET \"archived_at\"=(.+) WHERE \\(id IN (.+) AND archived_at IS NULL\\)").
+					WithArgs(at, test.TestArticle.ID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			results: []*article.BulkResult{
+				{ID: test.TestArticle.ID, Status: article.BulkStatusOK},
+				{ID: otherUsersArticle, Status: article.BulkStatusForbidden},
+				{ID: missingArticle, Status: article.BulkStatusNotFound},
+			},
+			err: nil,
+		},
+		{
+			name: "should move articles matching the filter to the collection",
+			op: article.BulkOperation{
+				Action:       article.BulkMove,
+				Filter:       &article.ListFilter{Status: article.FilterUnread},
+				CollectionID: &collectionID,
+				At:           at,
+			},
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^SELECT count(.+) FROM \"collections\" WHERE id = (.+) AND \\(user_id = (.+) OR EXISTS \\(SELECT 1 FROM collection_members ").
+					WithArgs(collectionID, userID, userID, "editor", "owner").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery("^SELECT id, user_id FROM \"articles\" WHERE user_id = (.+) AND \\(read_at IS NULL AND archived_at IS NULL\\) (.+) FOR UPDATE$").
+					WithArgs(userID, article.MaxBulkSize+1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).
+						AddRow(test.TestArticle.ID, userID).
+						AddRow(test.TestArticle2.ID, userID))
+				mock.ExpectExec("^UPDATE \"articles\" SET \"collection_id\"=(.+) WHERE id IN ").
+					WithArgs(collectionID, test.TestArticle.ID, test.TestArticle2.ID).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+			results: []*article.BulkResult{
+				{ID: test.TestArticle.ID, Status: article.BulkStatusOK},
+				{ID: test.TestArticle2.ID, Status: article.BulkStatusOK},
+			},
+			err: nil,
+		},
+		{
+			name: "should return err when moving to another user's collection",
+			op: article.BulkOperation{
+				Action:       article.BulkMove,
+				IDs:          []string{test.TestArticle.ID},
+				CollectionID: &collectionID,
+				At:           at,
+			},
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^SELECT count(.+) FROM \"collections\"").
+					WithArgs(collectionID, userID, userID, "editor", "owner").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectRollback()
+			},
+			results: nil,
+			err:     validation.NewError(validation.NotFound, "no collection found with given id"),
+		},
+		{
+			name: "should add tags to user's articles",
+			op: article.BulkOperation{
+				Action: article.BulkTag,
+				IDs:    []string{test.TestArticle.ID},
+				Tags:   tag.NewTags(userID, []string{"Go"}),
+				At:     at,
+			},
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				tagID := uuid.NewString()
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockQuery).
+					WithArgs(test.TestArticle.ID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(test.TestArticle.ID, userID))
+				mock.ExpectExec("^INSERT INTO \"tags\" (.+) ON CONFLICT \\(\"user_id\",\"name\"\\) DO NOTHING").
+					WithArgs(sqlmock.AnyArg(), userID, "go", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("^SELECT \"id\" FROM \"tags\" WHERE user_id = (.+) AND name IN ").
+					WithArgs(userID, "go").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(tagID))
+				mock.ExpectExec("^INSERT INTO \"article_tags\" (.+) ON CONFLICT DO NOTHING").
+					WithArgs(test.TestArticle.ID, tagID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			results: []*article.BulkResult{
+				{ID: test.TestArticle.ID, Status: article.BulkStatusOK},
+			},
+			err: nil,
+		},
+		{
+			name: "should return err when filter matches too many articles",
+			op: article.BulkOperation{
+				Action: article.BulkDelete,
+				Filter: &article.ListFilter{},
+				At:     at,
+			},
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "user_id"})
+				for i := 0; i <= article.MaxBulkSize; i++ {
+					rows.AddRow(uuid.NewString(), userID)
+				}
+				mock.ExpectBegin()
+				mock.ExpectQuery("^SELECT id, user_id FROM \"articles\"").
+					WithArgs(userID, article.MaxBulkSize+1).
+					WillReturnRows(rows)
+				mock.ExpectRollback()
+			},
+			results: nil,
+			err:     validation.NewError(validation.BadRequest, "filter matches more than 500 articles"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+
+			results, err := r.BulkUpdate(context.Background(), userID, c.op)
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.results, results)
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestListRevisions(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	revision := article.NewRevision(*test.TestArticle, article.RevisionUserEdit)
+	page := pagination.Pagination{Limit: 2, Offset: 0}
+
+	mock.ExpectQuery("^SELECT count(.*) FROM \"article_revisions\" WHERE article_id = ").
+		WithArgs(test.TestArticle.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery("^SELECT id, article_id, version, title, article_link, language, source, created_at FROM \"article_revisions\" "+
+		"WHERE article_id = (.+) ORDER BY version DESC LIMIT (.+)$").
+		WithArgs(test.TestArticle.ID, page.Limit).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "article_id", "version", "title", "source"}).
+			AddRow(revision.ID, revision.ArticleID, revision.Version, revision.Title, revision.Source))
+
+	revisions, total, err := r.ListRevisions(context.Background(), test.TestArticle.ID, page)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, []*article.Revision{{
+		ID:        revision.ID,
+		ArticleID: revision.ArticleID,
+		Version:   revision.Version,
+		Title:     revision.Title,
+		Source:    revision.Source,
+	}}, revisions)
+}
+
+func TestFindRevision(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	revisionID := uuid.NewString()
+	expectedQuery := "^SELECT \\* FROM \"article_revisions\" WHERE id = (.+) AND article_id = (.+) LIMIT (.+)$"
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		err           error
+	}{
+		{
+			name: "should return article revision",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(expectedQuery).
+					WithArgs(revisionID, test.TestArticle.ID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "article_id", "version"}).AddRow(revisionID, test.TestArticle.ID, 1))
+			},
+			err: nil,
+		},
+		{
+			name: "should return not found err when the article has no revision with given id",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(expectedQuery).
+					WithArgs(revisionID, test.TestArticle.ID, 1).
+					WillReturnError(gorm.ErrRecordNotFound)
+			},
+			err: validation.NewError(validation.NotFound, "no revision found with given id"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+
+			revision, err := r.FindRevision(context.Background(), test.TestArticle.ID, revisionID)
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, revisionID, revision.ID)
 		})
 	}
 }
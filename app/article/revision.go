@@ -0,0 +1,83 @@
+package article
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ryanadiputraa/unclatter/pkg/diff"
+	"github.com/ryanadiputraa/unclatter/pkg/plaintext"
+)
+
+// RevisionSource tells what replaced the version a revision holds.
+type RevisionSource string
+
+const (
+	RevisionUserEdit           RevisionSource = "user_edit"
+	RevisionRescrape           RevisionSource = "rescrape"
+	RevisionSanitizerMigration RevisionSource = "sanitizer_migration"
+	RevisionRestore            RevisionSource = "restore"
+)
+
+// Revision is a snapshot of an article version taken right before it was updated.
+type Revision struct {
+	ID          string         `json:"id" gorm:"type:varchar"`
+	ArticleID   string         `json:"article_id" gorm:"type:varchar;not null;index:idx_article_revisions_article_version,priority:1"`
+	Version     int            `json:"version" gorm:"type:integer;not null;index:idx_article_revisions_article_version,priority:2"`
+	Title       string         `json:"title" gorm:"type:varchar;not null"`
+	Content     string         `json:"content,omitempty" gorm:"type:text;not null"`
+	ArticleLink string         `json:"article_link" gorm:"type:varchar;not null"`
+	Language    string         `json:"language" gorm:"type:regconfig;not null;default:'english'"`
+	Source      RevisionSource `json:"source" gorm:"type:varchar;not null"`
+	CreatedAt   time.Time      `json:"created_at" gorm:"type:timestamptz;not null"`
+
+	Article *Article `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+func (Revision) TableName() string {
+	return "article_revisions"
+}
+
+func NewRevision(previous Article, source RevisionSource) *Revision {
+	return &Revision{
+		ID:          uuid.NewString(),
+		ArticleID:   previous.ID,
+		Version:     previous.Version,
+		Title:       previous.Title,
+		Content:     previous.Content,
+		ArticleLink: previous.ArticleLink,
+		Language:    previous.Language,
+		Source:      source,
+		CreatedAt:   time.Now().UTC(),
+	}
+}
+
+// RevisionDiff compares readable text with a line per block element, so markup changes don't show up.
+type RevisionDiff struct {
+	FromVersion int         `json:"from_version"`
+	ToVersion   int         `json:"to_version"`
+	Title       []diff.Line `json:"title"`
+	ArticleLink []diff.Line `json:"article_link"`
+	Content     []diff.Line `json:"content"`
+}
+
+func NewRevisionDiff(from, to Revision) *RevisionDiff {
+	return &RevisionDiff{
+		FromVersion: from.Version,
+		ToVersion:   to.Version,
+		Title:       diff.Lines(from.Title, to.Title),
+		ArticleLink: diff.Lines(from.ArticleLink, to.ArticleLink),
+		Content:     diff.Lines(plaintext.FromHTML(from.Content), plaintext.FromHTML(to.Content)),
+	}
+}
+
+func CurrentRevision(current Article) *Revision {
+	return &Revision{
+		ArticleID:   current.ID,
+		Version:     current.Version,
+		Title:       current.Title,
+		Content:     current.Content,
+		ArticleLink: current.ArticleLink,
+		Language:    current.Language,
+		CreatedAt:   current.UpdatedAt,
+	}
+}
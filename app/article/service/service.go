@@ -2,11 +2,12 @@ package service
 
 import (
 	"context"
-	"math"
 	"time"
 
 	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/collection"
 	"github.com/ryanadiputraa/unclatter/app/pagination"
+	"github.com/ryanadiputraa/unclatter/app/tag"
 	"github.com/ryanadiputraa/unclatter/app/validation"
 	"github.com/ryanadiputraa/unclatter/pkg/logger"
 	"github.com/ryanadiputraa/unclatter/pkg/sanitizer"
@@ -14,28 +15,33 @@ import (
 )
 
 type service struct {
-	log        logger.Logger
-	scrapper   scrapper.Scrapper
-	sanitizer  sanitizer.Sanitizer
-	repository article.ArticleRepository
+	log                  logger.Logger
+	scrapper             scrapper.Scrapper
+	sanitizer            sanitizer.Sanitizer
+	repository           article.ArticleRepository
+	collectionRepository collection.CollectionRepository
+	tagRepository        tag.TagRepository
 }
 
-func NewService(log logger.Logger, scrapper scrapper.Scrapper, sanitizer sanitizer.Sanitizer, repository article.ArticleRepository) article.ArticleService {
+func NewService(log logger.Logger, scrapper scrapper.Scrapper, sanitizer sanitizer.Sanitizer, repository article.ArticleRepository, collectionRepository collection.CollectionRepository, tagRepository tag.TagRepository) article.ArticleService {
 	return &service{
-		log:        log,
-		scrapper:   scrapper,
-		sanitizer:  sanitizer,
-		repository: repository,
+		log:                  log,
+		scrapper:             scrapper,
+		sanitizer:            sanitizer,
+		repository:           repository,
+		collectionRepository: collectionRepository,
+		tagRepository:        tagRepository,
 	}
 }
 
 func (s *service) ScrapeContent(ctx context.Context, url string) (content string, err error) {
-	content, err = s.scrapper.ScrapeTextContent(url)
+	page, err := s.scrapper.ScrapePage(url)
 	if err != nil {
 		s.log.Warn("article service: fail to scrape page", err)
 		return
 	}
 
+	content = page.Content
 	if content == "" {
 		err = validation.NewError(validation.BadRequest, "fail to scrape any article content")
 	}
@@ -44,71 +50,378 @@ func (s *service) ScrapeContent(ctx context.Context, url string) (content string
 }
 
 func (s *service) BookmarkArticle(ctx context.Context, arg article.BookmarkPayload, userID string) (bookmarked *article.Article, err error) {
+	if arg.Language != "" && !article.IsSupportedLanguage(arg.Language) {
+		err = validation.NewError(validation.BadRequest, "unsupported article language")
+		return
+	}
+
 	bookmarked = article.NewArticle(article.NewArticleArg{
 		Title:       arg.Title,
 		Content:     s.sanitizer.Sanitize(arg.Content),
 		ArticleLink: arg.ArticleLink,
+		Language:    arg.Language,
+		Keywords:    arg.Keywords,
 		UserID:      userID,
 	})
 
+	if existing, ok := s.findBookmarkedLink(ctx, userID, bookmarked.NormalizedLink); ok {
+		return existing, validation.NewError(validation.Conflict, "this url is already bookmarked")
+	}
+
 	if err = s.repository.Save(ctx, *bookmarked); err != nil {
-		return
+		// the same link may have been bookmarked concurrently after the lookup
+		if vErr, ok := err.(*validation.Error); ok && vErr.Err == validation.Conflict {
+			if existing, ok := s.findBookmarkedLink(ctx, userID, bookmarked.NormalizedLink); ok {
+				return existing, err
+			}
+		}
+		return nil, err
 	}
-	return
+
+	if bookmarked.Fingerprint != 0 {
+		// the bookmark is saved either way
+		duplicates, err := s.repository.FindNearDuplicates(ctx, userID, bookmarked.ID, bookmarked.Fingerprint)
+		if err != nil {
+			s.log.Error("article service: fail to fetch near-duplicate articles", err)
+		}
+		bookmarked.NearDuplicates = duplicates
+	}
+
+	// suggestions fall back to the article's keywords
+	existing, err := s.tagRepository.List(ctx, userID)
+	if err != nil {
+		s.log.Error("article service: fail to fetch user's tags", err)
+	}
+	bookmarked.SuggestedTags = article.SuggestTags(*bookmarked, existing)
+	return bookmarked, nil
 }
 
-func (s *service) ListBookmarkedArticles(ctx context.Context, userID string, page pagination.Pagination) (articles []*article.Article, meta *pagination.Meta, err error) {
-	articles, total, err := s.repository.List(ctx, userID, page)
+// findBookmarkedLink treats lookup failures as not bookmarked, the unique index still guards the insert.
+func (s *service) findBookmarkedLink(ctx context.Context, userID, normalizedLink string) (*article.Article, bool) {
+	existing, err := s.repository.FindByLink(ctx, userID, normalizedLink)
 	if err != nil {
-		s.log.Error("article service: fail to fetch user's bookmarked articles", err)
-		return
+		if _, ok := err.(*validation.Error); !ok {
+			s.log.Error("article service: fail to fetch article by link", err)
+		}
+		return nil, false
 	}
+	return existing, true
+}
 
-	totalPages := 0
-	if total > 0 {
-		totalPages = int(math.Ceil(float64(total) / float64(page.Limit)))
+func (s *service) ListBookmarkedArticles(ctx context.Context, userID string, filter article.ListFilter, page pagination.Pagination) (articles []*article.Article, meta *pagination.Meta, err error) {
+	if err = validateFilter(filter); err != nil {
+		return
+	}
+	if page.Cursor != nil && filter.Query != "" {
+		err = validation.NewError(validation.BadRequest, "cursor can't be used with search query, use page instead")
+		return
+	}
+	if page.Cursor != nil && !filter.IsKeysetOrdered() {
+		err = validation.NewError(validation.BadRequest, "cursor can only be used when sorting by updated_at descending, use page instead")
+		return
+	}
+	if filter.CollectionID != "" {
+		// a collection is open to anyone it's shared with
+		if err = s.authorizeCollection(ctx, userID, filter.CollectionID, collection.RoleViewer); err != nil {
+			return
+		}
+		filter.SharedCollection = true
 	}
 
-	meta = &pagination.Meta{
-		CurrentPage: page.Offset/page.Limit + 1,
-		TotalPages:  totalPages,
-		Size:        page.Limit,
-		TotalData:   total,
+	articles, total, err := s.repository.List(ctx, userID, filter, page)
+	if err != nil {
+		s.log.Error("article service: fail to fetch user's bookmarked articles", err)
+		return
 	}
 
+	meta = pagination.NewMeta(page, total)
+	meta.NextCursor = nextCursor(filter, page, articles, total)
 	return
 }
 
-func (s *service) GetBookmarkedArticle(ctx context.Context, userID, articleID string) (article *article.Article, err error) {
-	article, err = s.repository.FindByID(ctx, articleID)
+// nextCursor is empty for lists not ordered by (updated_at, id).
+func nextCursor(filter article.ListFilter, page pagination.Pagination, articles []*article.Article, total int64) string {
+	if !filter.IsKeysetOrdered() || len(articles) == 0 {
+		return ""
+	}
+	if page.Cursor == nil && int64(page.Offset+len(articles)) >= total {
+		return ""
+	}
+	if page.Cursor != nil && len(articles) < page.Limit {
+		return ""
+	}
+
+	last := articles[len(articles)-1]
+	return pagination.Cursor{UpdatedAt: last.UpdatedAt, ID: last.ID}.Encode()
+}
+
+func (s *service) GetBookmarkedArticle(ctx context.Context, userID, articleID string) (*article.Article, error) {
+	return s.findArticle(ctx, userID, articleID, collection.RoleViewer)
+}
+
+// findArticle checks the user owns the article or has the role in its shared collection.
+func (s *service) findArticle(ctx context.Context, userID, articleID string, required collection.Role) (a *article.Article, err error) {
+	a, err = s.repository.FindByID(ctx, articleID)
 	if err != nil {
 		s.log.Warn("article service: fail to fetch article ", articleID, " ", err)
 		return
 	}
-
-	if article.UserID != userID {
-		err = validation.NewError(validation.Forbidden, "forbidden access")
+	if a.UserID == userID {
 		return
 	}
+
+	if a.CollectionID == nil {
+		return nil, validation.NewError(validation.Forbidden, "forbidden access")
+	}
+	if err = s.authorizeCollection(ctx, userID, *a.CollectionID, required); err != nil {
+		if vErr, ok := err.(*validation.Error); ok && vErr.Err == validation.NotFound {
+			err = validation.NewError(validation.Forbidden, "forbidden access")
+		}
+		return nil, err
+	}
 	return
 }
 
-func (s *service) UpdateArticle(ctx context.Context, userID, articleID string, arg article.BookmarkPayload) (updated *article.Article, err error) {
-	update := article.Article{
+func (s *service) authorizeCollection(ctx context.Context, userID, collectionID string, required collection.Role) error {
+	role, err := s.collectionRepository.FindRole(ctx, collectionID, userID)
+	if err != nil {
+		if _, ok := err.(*validation.Error); !ok {
+			s.log.Error("article service: fail to fetch collection role", err)
+		}
+		return err
+	}
+
+	if !role.Can(required) {
+		return validation.NewError(validation.Forbidden, "forbidden access")
+	}
+	return nil
+}
+
+func (s *service) UpdateArticle(ctx context.Context, userID, articleID string, arg article.BookmarkPayload, version int) (updated *article.Article, err error) {
+	existing, err := s.findArticle(ctx, userID, articleID, collection.RoleEditor)
+	if err != nil {
+		return
+	}
+	language := arg.Language
+	if language == "" {
+		language = existing.Language
+	}
+
+	return s.updateArticle(ctx, article.Article{
 		ID:          articleID,
 		Title:       arg.Title,
 		Content:     arg.Content,
 		ArticleLink: arg.ArticleLink,
-		UserID:      userID,
-		UpdatedAt:   time.Now().UTC(),
+		Language:    language,
+		UserID:      existing.UserID,
+		Version:     version,
+	}, article.RevisionUserEdit)
+}
+
+func (s *service) PatchArticle(ctx context.Context, userID, articleID string, arg article.PatchPayload, version int) (updated *article.Article, err error) {
+	existing, err := s.findArticle(ctx, userID, articleID, collection.RoleEditor)
+	if err != nil {
+		return
+	}
+
+	update := article.Article{
+		ID:          articleID,
+		Title:       existing.Title,
+		Content:     existing.Content,
+		ArticleLink: existing.ArticleLink,
+		Language:    existing.Language,
+		UserID:      existing.UserID,
+		Version:     version,
+	}
+	if arg.Title != nil {
+		update.Title = *arg.Title
 	}
-	updated, err = s.repository.Update(ctx, update)
+	if arg.Content != nil {
+		update.Content = *arg.Content
+	}
+	if arg.ArticleLink != nil {
+		update.ArticleLink = *arg.ArticleLink
+	}
+	if arg.Language != nil {
+		update.Language = *arg.Language
+		if update.Language == "" {
+			update.Language = article.DefaultLanguage
+		}
+	}
+	return s.updateArticle(ctx, update, article.RevisionUserEdit)
+}
+
+// updateArticle keeps the owner's ID, editors of a shared collection update articles of other users.
+func (s *service) updateArticle(ctx context.Context, update article.Article, source article.RevisionSource) (updated *article.Article, err error) {
+	if update.Language != "" && !article.IsSupportedLanguage(update.Language) {
+		err = validation.NewError(validation.BadRequest, "unsupported article language")
+		return
+	}
+
+	update.NormalizedLink, err = article.NormalizeLink(update.ArticleLink)
+	if err != nil {
+		err = validation.NewError(validation.BadRequest, "invalid article link")
+		return
+	}
+	update.Domain = article.LinkDomain(update.ArticleLink)
+	update.Content = s.sanitizer.Sanitize(update.Content)
+	update.ReadingTime = article.ReadingTime(update.Content)
+	update.Excerpt = article.Summarize(update.Content, update.Language)
+	update.Fingerprint = article.Fingerprint(update.Content)
+	update.UpdatedAt = time.Now().UTC()
+
+	updated, err = s.repository.Update(ctx, update, source)
 	if err != nil {
 		s.log.Warn("article service: fail to update bookmarked article", err)
 	}
 	return
 }
 
+func (s *service) ListArticleRevisions(ctx context.Context, userID, articleID string, page pagination.Pagination) (revisions []*article.Revision, meta *pagination.Meta, err error) {
+	if _, err = s.GetBookmarkedArticle(ctx, userID, articleID); err != nil {
+		return
+	}
+
+	revisions, total, err := s.repository.ListRevisions(ctx, articleID, page)
+	if err != nil {
+		s.log.Error("article service: fail to fetch article revisions", err)
+		return
+	}
+
+	meta = pagination.NewMeta(page, total)
+	return
+}
+
+func (s *service) GetArticleRevision(ctx context.Context, userID, articleID, revisionID string) (revision *article.Revision, err error) {
+	if _, err = s.GetBookmarkedArticle(ctx, userID, articleID); err != nil {
+		return
+	}
+	return s.findRevision(ctx, articleID, revisionID)
+}
+
+func (s *service) DiffArticleRevisions(ctx context.Context, userID, articleID, fromID, toID string) (diff *article.RevisionDiff, err error) {
+	current, err := s.GetBookmarkedArticle(ctx, userID, articleID)
+	if err != nil {
+		return
+	}
+
+	from, err := s.findRevision(ctx, articleID, fromID)
+	if err != nil {
+		return
+	}
+	to := article.CurrentRevision(*current)
+	if toID != "" {
+		if to, err = s.findRevision(ctx, articleID, toID); err != nil {
+			return
+		}
+	}
+
+	diff = article.NewRevisionDiff(*from, *to)
+	return
+}
+
+func (s *service) RestoreArticleRevision(ctx context.Context, userID, articleID, revisionID string, version int) (restored *article.Article, err error) {
+	current, err := s.findArticle(ctx, userID, articleID, collection.RoleEditor)
+	if err != nil {
+		return
+	}
+
+	revision, err := s.findRevision(ctx, articleID, revisionID)
+	if err != nil {
+		return
+	}
+
+	return s.updateArticle(ctx, article.Article{
+		ID:          articleID,
+		Title:       revision.Title,
+		Content:     revision.Content,
+		ArticleLink: revision.ArticleLink,
+		Language:    revision.Language,
+		UserID:      current.UserID,
+		Version:     version,
+	}, article.RevisionRestore)
+}
+
+func (s *service) RescrapeArticle(ctx context.Context, userID, articleID string, version int) (updated *article.Article, err error) {
+	existing, err := s.findArticle(ctx, userID, articleID, collection.RoleEditor)
+	if err != nil {
+		return
+	}
+
+	page, err := s.scrapper.ScrapePage(existing.ArticleLink)
+	if err != nil || page.Content == "" {
+		s.log.Warn("article service: fail to rescrape page", err)
+		err = validation.NewError(validation.BadRequest, "fail to scrape any article content")
+		return
+	}
+
+	return s.updateArticle(ctx, article.Article{
+		ID:          articleID,
+		Title:       existing.Title,
+		Content:     page.Content,
+		ArticleLink: existing.ArticleLink,
+		Language:    existing.Language,
+		UserID:      existing.UserID,
+		Version:     version,
+	}, article.RevisionRescrape)
+}
+
+func (s *service) MigrateSanitizedContent(ctx context.Context, limit int) (migrated int, err error) {
+	articles, err := s.repository.ListUnsanitized(ctx, sanitizer.Version, limit)
+	if err != nil {
+		s.log.Error("article service: fail to list unsanitized articles", err)
+		return
+	}
+
+	for _, a := range articles {
+		if content := s.sanitizer.Sanitize(a.Content); content != a.Content {
+			_, err = s.updateArticle(ctx, article.Article{
+				ID:          a.ID,
+				Title:       a.Title,
+				Content:     content,
+				ArticleLink: a.ArticleLink,
+				Language:    a.Language,
+				UserID:      a.UserID,
+				Version:     a.Version,
+			}, article.RevisionSanitizerMigration)
+			vErr, ok := err.(*validation.Error)
+			switch {
+			case ok && vErr.Err == validation.PreconditionFailed:
+				// edited since it was listed, it's migrated on the next run
+				err = nil
+				continue
+			case ok:
+				// a rejected update keeps the content
+				err = nil
+			case err != nil:
+				return
+			default:
+				migrated++
+			}
+		}
+
+		if err = s.repository.MarkSanitized(ctx, a.ID, sanitizer.Version); err != nil {
+			s.log.Error("article service: fail to mark article sanitized", err)
+			return
+		}
+	}
+
+	if migrated > 0 {
+		s.log.Info("article service: migrated", migrated, "articles to the current sanitizer")
+	}
+	return
+}
+
+func (s *service) findRevision(ctx context.Context, articleID, revisionID string) (revision *article.Revision, err error) {
+	revision, err = s.repository.FindRevision(ctx, articleID, revisionID)
+	if err != nil {
+		if _, ok := err.(*validation.Error); !ok {
+			s.log.Error("article service: fail to fetch article revision", err)
+		}
+	}
+	return
+}
+
 func (s *service) DeleteArticle(ctx context.Context, userID, articleID string) error {
 	if err := s.repository.Delete(ctx, userID, articleID); err != nil {
 		s.log.Error("article service: fail to delete article", err)
@@ -117,3 +430,253 @@ func (s *service) DeleteArticle(ctx context.Context, userID, articleID string) e
 
 	return nil
 }
+
+func (s *service) ListTrashedArticles(ctx context.Context, userID string, page pagination.Pagination) (articles []*article.Article, meta *pagination.Meta, err error) {
+	articles, total, err := s.repository.ListTrashed(ctx, userID, page)
+	if err != nil {
+		s.log.Error("article service: fail to fetch user's trashed articles", err)
+		return
+	}
+
+	meta = pagination.NewMeta(page, total)
+	return
+}
+
+func (s *service) RestoreArticle(ctx context.Context, userID, articleID string) (restored *article.Article, err error) {
+	restored, err = s.repository.Restore(ctx, userID, articleID)
+	if err != nil {
+		s.log.Warn("article service: fail to restore article ", articleID, " ", err)
+	}
+	return
+}
+
+func (s *service) PurgeArticle(ctx context.Context, userID, articleID string) error {
+	if err := s.repository.Purge(ctx, userID, articleID); err != nil {
+		s.log.Warn("article service: fail to purge article ", articleID, " ", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) PurgeExpiredTrash(ctx context.Context, retention time.Duration) (purged int64, err error) {
+	purged, err = s.repository.PurgeTrashedBefore(ctx, time.Now().UTC().Add(-retention))
+	if err != nil {
+		s.log.Error("article service: fail to purge expired trash", err)
+		return
+	}
+
+	if purged > 0 {
+		s.log.Info("article service: purged", purged, "expired articles from trash")
+	}
+	return
+}
+
+func (s *service) BulkUpdateArticles(ctx context.Context, userID string, arg article.BulkPayload) (results []*article.BulkResult, err error) {
+	op := article.BulkOperation{
+		Action:       arg.Action,
+		IDs:          uniqueIDs(arg.IDs),
+		CollectionID: arg.CollectionID,
+		At:           time.Now().UTC(),
+	}
+
+	switch {
+	case len(op.IDs) > 0 && arg.Filter != nil:
+		return nil, validation.NewError(validation.BadRequest, "ids and filter can't be combined")
+	case len(op.IDs) == 0 && arg.Filter == nil:
+		return nil, validation.NewError(validation.BadRequest, "either ids or filter is required")
+	case arg.Filter != nil:
+		op.Filter = &article.ListFilter{
+			Query:        arg.Filter.Query,
+			Language:     arg.Filter.Language,
+			Status:       arg.Filter.Status,
+			CollectionID: arg.Filter.Collection,
+			Tag:          arg.Filter.Tag,
+		}
+		if err = validateFilter(*op.Filter); err != nil {
+			return
+		}
+	}
+
+	if op.Action == article.BulkTag {
+		op.Tags = tag.NewTags(userID, arg.Tags)
+		if len(op.Tags) == 0 {
+			return nil, validation.NewError(validation.BadRequest, "tags are required to tag articles")
+		}
+	}
+
+	results, err = s.repository.BulkUpdate(ctx, userID, op)
+	if err != nil {
+		s.log.Warn("article service: fail to apply bulk ", op.Action, " ", err)
+	}
+	return
+}
+
+func (s *service) SetArticleState(ctx context.Context, userID, articleID string, state article.State, enabled bool) (updated *article.Article, err error) {
+	var at *time.Time
+	if enabled {
+		now := time.Now().UTC()
+		at = &now
+	}
+
+	updated, err = s.repository.UpdateState(ctx, userID, articleID, state, at)
+	if err != nil {
+		s.log.Warn("article service: fail to update article ", state, " state ", err)
+	}
+	return
+}
+
+func (s *service) SnoozeArticle(ctx context.Context, userID, articleID string, until *time.Time) (updated *article.Article, err error) {
+	if until != nil && !until.After(time.Now()) {
+		err = validation.NewError(validation.BadRequest, "until should be in the future")
+		return
+	}
+
+	updated, err = s.repository.UpdateSnooze(ctx, userID, articleID, until)
+	if err != nil {
+		s.log.Warn("article service: fail to snooze article ", articleID, " ", err)
+	}
+	return
+}
+
+func (s *service) CountArticleStates(ctx context.Context, userID string) (counts *article.StateCounts, err error) {
+	counts, err = s.repository.CountStates(ctx, userID)
+	if err != nil {
+		s.log.Error("article service: fail to count user's articles", err)
+	}
+	return
+}
+
+func (s *service) GetReadingStats(ctx context.Context, userID string, period article.StatsPeriod) (stats *article.Stats, err error) {
+	stats, err = s.repository.ReadingStats(ctx, userID, period)
+	if err != nil {
+		s.log.Error("article service: fail to aggregate user's reading stats", err)
+		return
+	}
+	stats.Complete(period)
+	return
+}
+
+func (s *service) SummarizeArticle(ctx context.Context, userID, articleID string) (summarized *article.Article, err error) {
+	summarized, err = s.findArticle(ctx, userID, articleID, collection.RoleEditor)
+	if err != nil {
+		return
+	}
+
+	summarized.Excerpt = article.Summarize(summarized.Content, summarized.Language)
+	if err = s.repository.UpdateExcerpt(ctx, articleID, summarized.Excerpt); err != nil {
+		if _, ok := err.(*validation.Error); !ok {
+			s.log.Error("article service: fail to save article excerpt", err)
+		}
+		return nil, err
+	}
+	return
+}
+
+func (s *service) ListRelatedArticles(ctx context.Context, userID, articleID string, limit int) (related []*article.Article, err error) {
+	a, err := s.findArticle(ctx, userID, articleID, collection.RoleViewer)
+	if err != nil {
+		return
+	}
+
+	// new and edited articles are indexed right away instead of waiting for the background job
+	if a.IndexedVersion != a.Version {
+		if err = s.index(ctx, *a); err != nil {
+			return
+		}
+	}
+
+	related, err = s.repository.ListRelated(ctx, userID, articleID, limit)
+	if err != nil {
+		s.log.Error("article service: fail to list related articles", err)
+	}
+	return
+}
+
+func (s *service) IndexArticles(ctx context.Context, limit int) (indexed int, err error) {
+	articles, err := s.repository.ListUnindexed(ctx, limit)
+	if err != nil {
+		s.log.Error("article service: fail to list unindexed articles", err)
+		return
+	}
+
+	for _, a := range articles {
+		if err = s.index(ctx, *a); err != nil {
+			return
+		}
+		indexed++
+	}
+
+	if indexed > 0 {
+		s.log.Info("article service: indexed", indexed, "articles")
+	}
+	return
+}
+
+// index also fills the excerpt of articles saved before excerpts existed.
+func (s *service) index(ctx context.Context, a article.Article) error {
+	a.Fingerprint = article.Fingerprint(a.Content)
+	if a.Excerpt == "" {
+		a.Excerpt = article.Summarize(a.Content, a.Language)
+	}
+	err := s.repository.SaveIndex(ctx, a, article.NewTerms(a))
+	if err != nil {
+		s.log.Error("article service: fail to index article", err)
+	}
+	return err
+}
+
+func (s *service) ListDuplicateClusters(ctx context.Context, userID string) ([]*article.DuplicateCluster, error) {
+	articles, err := s.repository.ListFingerprinted(ctx, userID)
+	if err != nil {
+		s.log.Error("article service: fail to fetch fingerprinted articles", err)
+		return nil, err
+	}
+	return article.Clusters(articles), nil
+}
+
+func (s *service) MergeDuplicates(ctx context.Context, userID string, arg article.MergePayload) (merged *article.Article, err error) {
+	duplicateIDs := make([]string, 0, len(arg.DuplicateIDs))
+	for _, id := range uniqueIDs(arg.DuplicateIDs) {
+		if id != arg.KeepID {
+			duplicateIDs = append(duplicateIDs, id)
+		}
+	}
+	if len(duplicateIDs) == 0 {
+		err = validation.NewError(validation.BadRequest, "no duplicates to merge into the kept article")
+		return
+	}
+
+	merged, err = s.repository.Merge(ctx, userID, arg.KeepID, duplicateIDs)
+	if err != nil {
+		if _, ok := err.(*validation.Error); !ok {
+			s.log.Error("article service: fail to merge duplicate articles", err)
+		}
+	}
+	return
+}
+
+func validateFilter(filter article.ListFilter) error {
+	if filter.Language != "" && !article.IsSupportedLanguage(filter.Language) {
+		return validation.NewError(validation.BadRequest, "unsupported search language")
+	}
+	switch filter.Status {
+	case "", article.FilterUnread, article.FilterArchived, article.FilterFavorites, article.FilterSnoozed:
+		return nil
+	default:
+		return validation.NewError(validation.BadRequest, "unsupported article filter")
+	}
+}
+
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	return unique
+}
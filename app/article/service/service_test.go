@@ -2,15 +2,20 @@ package service
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gocolly/colly/v2"
 	"github.com/google/uuid"
 	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/collection"
 	"github.com/ryanadiputraa/unclatter/app/mocks"
 	"github.com/ryanadiputraa/unclatter/app/pagination"
+	"github.com/ryanadiputraa/unclatter/app/tag"
 	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/pkg/diff"
 	"github.com/ryanadiputraa/unclatter/pkg/logger"
 	"github.com/ryanadiputraa/unclatter/pkg/sanitizer"
 	"github.com/ryanadiputraa/unclatter/pkg/scrapper"
@@ -21,7 +26,9 @@ import (
 )
 
 const (
-	forbiddenAccess = "forbidden access"
+	forbiddenAccess  = "forbidden access"
+	unsafeContent    = `<p onclick="steal()">Updated Content</p><script>steal()</script><img src="x" onerror="steal()">`
+	sanitizedContent = `<p>Updated Content</p><img src="x">`
 )
 
 func TestScrapeContent(t *testing.T) {
@@ -38,7 +45,7 @@ func TestScrapeContent(t *testing.T) {
 			expected: test.TestArticle.Content,
 			err:      nil,
 			mockScrapperBehaviour: func(mockScrapper *mocks.Scrapper, url string) {
-				mockScrapper.On("ScrapeTextContent", url).Return(test.TestArticle.Content, nil)
+				mockScrapper.On("ScrapePage", url).Return(&scrapper.Page{Content: test.TestArticle.Content}, nil)
 			},
 		},
 		{
@@ -47,7 +54,7 @@ func TestScrapeContent(t *testing.T) {
 			expected: "",
 			err:      validation.NewError(validation.BadRequest, "fail to scrape any article content"),
 			mockScrapperBehaviour: func(mockScrapper *mocks.Scrapper, url string) {
-				mockScrapper.On("ScrapeTextContent", url).Return("", nil)
+				mockScrapper.On("ScrapePage", url).Return(&scrapper.Page{}, nil)
 			},
 		},
 		{
@@ -56,7 +63,7 @@ func TestScrapeContent(t *testing.T) {
 			expected: "",
 			err:      colly.ErrForbiddenURL,
 			mockScrapperBehaviour: func(mockScrapper *mocks.Scrapper, url string) {
-				mockScrapper.On("ScrapeTextContent", url).Return("", colly.ErrForbiddenURL)
+				mockScrapper.On("ScrapePage", url).Return(nil, colly.ErrForbiddenURL)
 			},
 		},
 	}
@@ -67,7 +74,7 @@ func TestScrapeContent(t *testing.T) {
 			c.mockScrapperBehaviour(scrapperPkg, c.url)
 
 			r := new(mocks.ArticleRepository)
-			s := NewService(logger.NewLogger(), scrapperPkg, sanitizer.NewSanitizer(), r)
+			s := NewService(logger.NewLogger(), scrapperPkg, sanitizer.NewSanitizer(), r, new(mocks.CollectionRepository), new(mocks.TagRepository))
 			content, err := s.ScrapeContent(context.Background(), c.url)
 
 			assert.Equal(t, c.err, err)
@@ -85,6 +92,8 @@ func TestBookmark(t *testing.T) {
 		arg               article.BookmarkPayload
 		expected          *article.Article
 		err               error
+		existing          *article.Article
+		tags              []*tag.Tag
 		mockRepoBehaviour func(mockRepo *mocks.ArticleRepository)
 	}{
 		{
@@ -105,6 +114,8 @@ func TestBookmark(t *testing.T) {
 			},
 			err: nil,
 			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByLink", context.Background(), userID, "https://unclatter.com").
+					Return(nil, validation.NewError(validation.NotFound, "no article found with given link"))
 				mockRepo.On("Save", context.Background(), mock.Anything).Return(nil)
 			},
 		},
@@ -124,9 +135,81 @@ func TestBookmark(t *testing.T) {
 				CreatedAt:   time.Now().UTC(),
 				UpdatedAt:   time.Now().UTC(),
 			},
-			err: validation.NewError(validation.BadRequest, "title is already in use"),
+			err: validation.NewError(validation.ServerErr, "fail to bookmark article"),
 			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
-				mockRepo.On("Save", context.Background(), mock.Anything).Return(validation.NewError(validation.BadRequest, "title is already in use"))
+				mockRepo.On("FindByLink", context.Background(), userID, "https://unclatter.com").
+					Return(nil, validation.NewError(validation.NotFound, "no article found with given link"))
+				mockRepo.On("Save", context.Background(), mock.Anything).Return(validation.NewError(validation.ServerErr, "fail to bookmark article"))
+			},
+		},
+		{
+			name: "should warn about near-duplicate articles of the user's library",
+			arg: article.BookmarkPayload{
+				Title:       test.TestArticle.Title,
+				Content:     "<p>" + strings.Repeat("The same story syndicated across outlets clutters the library. ", 8) + "</p>",
+				ArticleLink: test.TestArticle.ArticleLink,
+			},
+			expected: &article.Article{
+				ID:             articleID,
+				Title:          test.TestArticle.Title,
+				Content:        "<p>" + strings.Repeat("The same story syndicated across outlets clutters the library. ", 8) + "</p>",
+				ArticleLink:    test.TestArticle.ArticleLink,
+				UserID:         userID,
+				NearDuplicates: []*article.Article{test.TestArticle2},
+				CreatedAt:      time.Now().UTC(),
+				UpdatedAt:      time.Now().UTC(),
+			},
+			err: nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByLink", context.Background(), userID, "https://unclatter.com").
+					Return(nil, validation.NewError(validation.NotFound, "no article found with given link"))
+				mockRepo.On("Save", context.Background(), mock.Anything).Return(nil)
+				mockRepo.On("FindNearDuplicates", context.Background(), userID, mock.Anything, mock.Anything).
+					Return([]*article.Article{test.TestArticle2}, nil)
+			},
+		},
+		{
+			name: "should suggest tags from the article's keywords and the user's existing tags",
+			arg: article.BookmarkPayload{
+				Title:       "Tuning Postgres indexes",
+				Content:     "<p>Postgres indexes speed up queries, but every index slows down writes. A partial index only covers the rows a query needs, and Postgres can combine several indexes with bitmap scans.</p>",
+				ArticleLink: test.TestArticle.ArticleLink,
+				Keywords:    []string{"databases, Performance"},
+			},
+			expected: &article.Article{
+				ID:            articleID,
+				Title:         "Tuning Postgres indexes",
+				Content:       "<p>Postgres indexes speed up queries, but every index slows down writes. A partial index only covers the rows a query needs, and Postgres can combine several indexes with bitmap scans.</p>",
+				ArticleLink:   test.TestArticle.ArticleLink,
+				UserID:        userID,
+				SuggestedTags: []string{"postgres", "databases", "indexes", "performance", "index"},
+				CreatedAt:     time.Now().UTC(),
+				UpdatedAt:     time.Now().UTC(),
+			},
+			tags: []*tag.Tag{
+				{Name: "postgres", UserID: userID, ArticleCount: 12},
+				{Name: "cooking", UserID: userID, ArticleCount: 3},
+			},
+			err: nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByLink", context.Background(), userID, "https://unclatter.com").
+					Return(nil, validation.NewError(validation.NotFound, "no article found with given link"))
+				mockRepo.On("Save", context.Background(), mock.MatchedBy(func(a article.Article) bool {
+					return a.Keywords == "databases, Performance"
+				})).Return(nil)
+			},
+		},
+		{
+			name: "should return existing bookmark with conflict error when url is already bookmarked",
+			arg: article.BookmarkPayload{
+				Title:       "Another Title",
+				Content:     "<p>article content</p>",
+				ArticleLink: "http://www.unclatter.com/?utm_source=newsletter#top",
+			},
+			existing: test.TestArticle,
+			err:      validation.NewError(validation.Conflict, "this url is already bookmarked"),
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByLink", context.Background(), userID, "https://unclatter.com").Return(test.TestArticle, nil)
 			},
 		},
 	}
@@ -135,12 +218,15 @@ func TestBookmark(t *testing.T) {
 		t.Run(c.name, func(t *testing.T) {
 			r := new(mocks.ArticleRepository)
 			c.mockRepoBehaviour(r)
+			tagRepo := new(mocks.TagRepository)
+			tagRepo.On("List", context.Background(), userID).Return(c.tags, nil).Maybe()
 
-			s := NewService(logger.NewLogger(), scrapper.NewScrapper(), sanitizer.NewSanitizer(), r)
+			s := NewService(logger.NewLogger(), scrapper.NewScrapper(), sanitizer.NewSanitizer(), r, new(mocks.CollectionRepository), tagRepo)
 			article, err := s.BookmarkArticle(context.Background(), c.arg, userID)
 
 			assert.Equal(t, c.err, err)
 			if err != nil {
+				assert.Equal(t, c.existing, article)
 				return
 			}
 
@@ -149,6 +235,10 @@ func TestBookmark(t *testing.T) {
 			assert.Equal(t, c.expected.Content, article.Content)
 			assert.Equal(t, c.expected.ArticleLink, article.ArticleLink)
 			assert.Equal(t, c.expected.UserID, article.UserID)
+			assert.Equal(t, c.expected.NearDuplicates, article.NearDuplicates)
+			if c.expected.SuggestedTags != nil {
+				assert.Equal(t, c.expected.SuggestedTags, article.SuggestedTags)
+			}
 			assert.NotEmpty(t, article.CreatedAt)
 			assert.NotEmpty(t, article.UpdatedAt)
 		})
@@ -159,11 +249,12 @@ func TestListArticle(t *testing.T) {
 	cases := []struct {
 		name              string
 		userID            string
+		filter            article.ListFilter
 		page              pagination.Pagination
 		expected          []*article.Article
 		meta              *pagination.Meta
 		err               error
-		mockRepoBehaviour func(mockRepo *mocks.ArticleRepository, userID string, page pagination.Pagination)
+		mockRepoBehaviour func(mockRepo *mocks.ArticleRepository, userID string, filter article.ListFilter, page pagination.Pagination)
 	}{
 		{
 			name:   "should return list of user's bookmarked articles",
@@ -184,8 +275,8 @@ func TestListArticle(t *testing.T) {
 				TotalData:   3,
 			},
 			err: nil,
-			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository, userID string, page pagination.Pagination) {
-				mockRepo.On("List", context.Background(), userID, page).
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository, userID string, filter article.ListFilter, page pagination.Pagination) {
+				mockRepo.On("List", context.Background(), userID, filter, page).
 					Return(
 						[]*article.Article{test.TestArticle, test.TestArticle2, test.TestArticle3},
 						int64(3),
@@ -193,6 +284,74 @@ func TestListArticle(t *testing.T) {
 					)
 			},
 		},
+		{
+			name:   "should return next cursor when there are more bookmarked articles",
+			userID: test.TestUser.ID,
+			page: pagination.Pagination{
+				Limit: 2,
+				Cursor: &pagination.Cursor{
+					UpdatedAt: test.TestArticle.UpdatedAt,
+					ID:        test.TestArticle.ID,
+				},
+			},
+			expected: []*article.Article{
+				test.TestArticle2,
+				test.TestArticle3,
+			},
+			meta: &pagination.Meta{
+				TotalPages: 2,
+				Size:       2,
+				TotalData:  3,
+				NextCursor: pagination.Cursor{UpdatedAt: test.TestArticle3.UpdatedAt, ID: test.TestArticle3.ID}.Encode(),
+			},
+			err: nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository, userID string, filter article.ListFilter, page pagination.Pagination) {
+				mockRepo.On("List", context.Background(), userID, filter, page).
+					Return(
+						[]*article.Article{test.TestArticle2, test.TestArticle3},
+						int64(3),
+						nil,
+					)
+			},
+		},
+		{
+			name:   "should return err when using cursor with search query",
+			userID: test.TestUser.ID,
+			filter: article.ListFilter{
+				Query: "title",
+			},
+			page: pagination.Pagination{
+				Limit: 2,
+				Cursor: &pagination.Cursor{
+					UpdatedAt: test.TestArticle.UpdatedAt,
+					ID:        test.TestArticle.ID,
+				},
+			},
+			expected: []*article.Article{},
+			meta:     nil,
+			err:      validation.NewError(validation.BadRequest, "cursor can't be used with search query, use page instead"),
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository, userID string, filter article.ListFilter, page pagination.Pagination) {
+			},
+		},
+		{
+			name:   "should return err when using cursor with another sort",
+			userID: test.TestUser.ID,
+			filter: article.ListFilter{
+				Sort: article.SortTitle,
+			},
+			page: pagination.Pagination{
+				Limit: 2,
+				Cursor: &pagination.Cursor{
+					UpdatedAt: test.TestArticle.UpdatedAt,
+					ID:        test.TestArticle.ID,
+				},
+			},
+			expected: []*article.Article{},
+			meta:     nil,
+			err:      validation.NewError(validation.BadRequest, "cursor can only be used when sorting by updated_at descending, use page instead"),
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository, userID string, filter article.ListFilter, page pagination.Pagination) {
+			},
+		},
 		{
 			name:   "should return empty list of user's bookmarked articles",
 			userID: test.TestUser.ID,
@@ -208,8 +367,8 @@ func TestListArticle(t *testing.T) {
 				TotalData:   0,
 			},
 			err: nil,
-			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository, userID string, page pagination.Pagination) {
-				mockRepo.On("List", context.Background(), userID, page).
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository, userID string, filter article.ListFilter, page pagination.Pagination) {
+				mockRepo.On("List", context.Background(), userID, filter, page).
 					Return(
 						[]*article.Article{},
 						int64(0),
@@ -217,6 +376,23 @@ func TestListArticle(t *testing.T) {
 					)
 			},
 		},
+		{
+			name:   "should return err when searching with unsupported language",
+			userID: test.TestUser.ID,
+			filter: article.ListFilter{
+				Query:    "title",
+				Language: "klingon",
+			},
+			page: pagination.Pagination{
+				Limit:  2,
+				Offset: 0,
+			},
+			expected: []*article.Article{},
+			meta:     nil,
+			err:      validation.NewError(validation.BadRequest, "unsupported search language"),
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository, userID string, filter article.ListFilter, page pagination.Pagination) {
+			},
+		},
 		{
 			name:   "should return err when fail to fetch user's bookmarked articles",
 			userID: test.TestUser.ID,
@@ -227,8 +403,8 @@ func TestListArticle(t *testing.T) {
 			expected: []*article.Article{},
 			meta:     nil,
 			err:      gorm.ErrInvalidDB,
-			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository, userID string, page pagination.Pagination) {
-				mockRepo.On("List", context.Background(), userID, page).Return(nil, int64(0), gorm.ErrInvalidDB)
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository, userID string, filter article.ListFilter, page pagination.Pagination) {
+				mockRepo.On("List", context.Background(), userID, filter, page).Return(nil, int64(0), gorm.ErrInvalidDB)
 			},
 		},
 	}
@@ -236,10 +412,10 @@ func TestListArticle(t *testing.T) {
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
 			r := new(mocks.ArticleRepository)
-			c.mockRepoBehaviour(r, c.userID, c.page)
+			c.mockRepoBehaviour(r, c.userID, c.filter, c.page)
 
-			s := NewService(logger.NewLogger(), scrapper.NewScrapper(), sanitizer.NewSanitizer(), r)
-			articles, meta, err := s.ListBookmarkedArticles(context.Background(), c.userID, c.page)
+			s := NewService(logger.NewLogger(), scrapper.NewScrapper(), sanitizer.NewSanitizer(), r, new(mocks.CollectionRepository), new(mocks.TagRepository))
+			articles, meta, err := s.ListBookmarkedArticles(context.Background(), c.userID, c.filter, c.page)
 
 			assert.Equal(t, c.err, err)
 			if err != nil {
@@ -261,6 +437,7 @@ func TestListArticle(t *testing.T) {
 			assert.Equal(t, c.meta.TotalPages, meta.TotalPages)
 			assert.Equal(t, c.meta.Size, meta.Size)
 			assert.Equal(t, c.meta.TotalData, meta.TotalData)
+			assert.Equal(t, c.meta.NextCursor, meta.NextCursor)
 		})
 	}
 }
@@ -314,7 +491,7 @@ func TestGetArticle(t *testing.T) {
 			r := new(mocks.ArticleRepository)
 			c.mockRepoBehaviour(r, c.articleID)
 
-			s := NewService(logger.NewLogger(), scrapper.NewScrapper(), sanitizer.NewSanitizer(), r)
+			s := NewService(logger.NewLogger(), scrapper.NewScrapper(), sanitizer.NewSanitizer(), r, new(mocks.CollectionRepository), new(mocks.TagRepository))
 			article, err := s.GetBookmarkedArticle(context.Background(), c.userID, c.articleID)
 
 			assert.Equal(t, c.err, err)
@@ -334,6 +511,112 @@ func TestGetArticle(t *testing.T) {
 	}
 }
 
+func TestGetArticleInSharedCollection(t *testing.T) {
+	collectionID := uuid.NewString()
+	memberID := uuid.NewString()
+	shared := *test.TestArticle
+	shared.CollectionID = &collectionID
+
+	cases := []struct {
+		name                        string
+		err                         error
+		mockCollectionRepoBehaviour func(mockRepo *mocks.CollectionRepository)
+	}{
+		{
+			name: "should return article to viewer of the shared collection",
+			err:  nil,
+			mockCollectionRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {
+				mockRepo.On("FindRole", context.Background(), collectionID, memberID).Return(collection.RoleViewer, nil)
+			},
+		},
+		{
+			name: "should return err when the collection isn't shared with the user",
+			err:  validation.NewError(validation.Forbidden, forbiddenAccess),
+			mockCollectionRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {
+				mockRepo.On("FindRole", context.Background(), collectionID, memberID).Return(collection.Role(""), nil)
+			},
+		},
+		{
+			name: "should return err when the collection no longer exists",
+			err:  validation.NewError(validation.Forbidden, forbiddenAccess),
+			mockCollectionRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {
+				mockRepo.On("FindRole", context.Background(), collectionID, memberID).
+					Return(collection.Role(""), validation.NewError(validation.NotFound, "no collection found with given id"))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.ArticleRepository)
+			r.On("FindByID", context.Background(), shared.ID).Return(&shared, nil)
+			collectionRepo := new(mocks.CollectionRepository)
+			c.mockCollectionRepoBehaviour(collectionRepo)
+
+			s := NewService(logger.NewLogger(), scrapper.NewScrapper(), sanitizer.NewSanitizer(), r, collectionRepo, new(mocks.TagRepository))
+			article, err := s.GetBookmarkedArticle(context.Background(), memberID, shared.ID)
+
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				assert.Nil(t, article)
+				return
+			}
+			assert.Equal(t, shared.ID, article.ID)
+		})
+	}
+}
+
+func TestListArticleInCollection(t *testing.T) {
+	collectionID := uuid.NewString()
+	page := pagination.Pagination{Limit: 10, Offset: 0}
+
+	cases := []struct {
+		name                        string
+		err                         error
+		mockRepoBehaviour           func(mockRepo *mocks.ArticleRepository)
+		mockCollectionRepoBehaviour func(mockRepo *mocks.CollectionRepository)
+	}{
+		{
+			name: "should list articles of every member of the shared collection",
+			err:  nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				filter := article.ListFilter{CollectionID: collectionID, SharedCollection: true}
+				mockRepo.On("List", context.Background(), test.TestUser.ID, filter, page).
+					Return([]*article.Article{test.TestArticle}, int64(1), nil)
+			},
+			mockCollectionRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {
+				mockRepo.On("FindRole", context.Background(), collectionID, test.TestUser.ID).Return(collection.RoleViewer, nil)
+			},
+		},
+		{
+			name:              "should return err when the collection isn't shared with the user",
+			err:               validation.NewError(validation.Forbidden, forbiddenAccess),
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {},
+			mockCollectionRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {
+				mockRepo.On("FindRole", context.Background(), collectionID, test.TestUser.ID).Return(collection.Role(""), nil)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.ArticleRepository)
+			c.mockRepoBehaviour(r)
+			collectionRepo := new(mocks.CollectionRepository)
+			c.mockCollectionRepoBehaviour(collectionRepo)
+
+			s := NewService(logger.NewLogger(), scrapper.NewScrapper(), sanitizer.NewSanitizer(), r, collectionRepo, new(mocks.TagRepository))
+			articles, _, err := s.ListBookmarkedArticles(context.Background(), test.TestUser.ID, article.ListFilter{CollectionID: collectionID}, page)
+
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				return
+			}
+			assert.Len(t, articles, 1)
+		})
+	}
+}
+
 func TestUpdateArticle(t *testing.T) {
 	updatePayload := article.BookmarkPayload{
 		Title:       "Updated Title",
@@ -341,58 +624,118 @@ func TestUpdateArticle(t *testing.T) {
 		ArticleLink: "https://newlink.com",
 	}
 	updatedTime := time.Now().UTC()
+	collectionID := uuid.NewString()
+	memberID := uuid.NewString()
+	shared := *test.TestArticle
+	shared.CollectionID = &collectionID
+	updated := &article.Article{
+		ID:          test.TestArticle.ID,
+		Title:       updatePayload.Title,
+		Content:     updatePayload.Content,
+		ArticleLink: updatePayload.ArticleLink,
+		UserID:      test.TestArticle.UserID,
+		CreatedAt:   test.TestArticle.CreatedAt,
+		UpdatedAt:   updatedTime,
+	}
 
 	cases := []struct {
-		name              string
-		userID            string
-		articleID         string
-		arg               article.BookmarkPayload
-		expected          *article.Article
-		err               error
-		mockRepoBehaviour func(mockRepo *mocks.ArticleRepository)
+		name                        string
+		userID                      string
+		articleID                   string
+		arg                         article.BookmarkPayload
+		expected                    *article.Article
+		err                         error
+		mockRepoBehaviour           func(mockRepo *mocks.ArticleRepository)
+		mockCollectionRepoBehaviour func(mockRepo *mocks.CollectionRepository)
 	}{
 		{
 			name:      "should return updated article",
 			userID:    test.TestArticle.UserID,
 			articleID: test.TestArticle.ID,
 			arg:       updatePayload,
-			expected: &article.Article{
-				ID:          test.TestArticle.ID,
+			expected:  updated,
+			err:       nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+				mockRepo.On("Update", context.Background(), mock.Anything, article.RevisionUserEdit).Return(updated, nil)
+			},
+			mockCollectionRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {},
+		},
+		{
+			name:      "should sanitize updated content",
+			userID:    test.TestArticle.UserID,
+			articleID: test.TestArticle.ID,
+			arg: article.BookmarkPayload{
 				Title:       updatePayload.Title,
-				Content:     updatePayload.Content,
+				Content:     unsafeContent,
 				ArticleLink: updatePayload.ArticleLink,
-				UserID:      test.TestArticle.UserID,
-				CreatedAt:   test.TestArticle.CreatedAt,
-				UpdatedAt:   updatedTime,
 			},
-			err: nil,
+			expected: updated,
+			err:      nil,
 			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
-				mockRepo.On("Update", context.Background(), mock.Anything).
-					Return(
-						&article.Article{
-							ID:          test.TestArticle.ID,
-							Title:       updatePayload.Title,
-							Content:     updatePayload.Content,
-							ArticleLink: updatePayload.ArticleLink,
-							UserID:      test.TestArticle.UserID,
-							CreatedAt:   test.TestArticle.CreatedAt,
-							UpdatedAt:   updatedTime,
-						},
-						nil,
-					)
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+				mockRepo.On("Update", context.Background(), mock.MatchedBy(func(a article.Article) bool {
+					return a.Content == sanitizedContent
+				}), article.RevisionUserEdit).Return(updated, nil)
+			},
+			mockCollectionRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {},
+		},
+		{
+			name:      "should let editor of the shared collection update other user's article",
+			userID:    memberID,
+			articleID: test.TestArticle.ID,
+			arg:       updatePayload,
+			expected:  updated,
+			err:       nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(&shared, nil)
+				mockRepo.On("Update", context.Background(), mock.MatchedBy(func(a article.Article) bool {
+					return a.UserID == test.TestArticle.UserID
+				}), article.RevisionUserEdit).Return(updated, nil)
+			},
+			mockCollectionRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {
+				mockRepo.On("FindRole", context.Background(), collectionID, memberID).Return(collection.RoleEditor, nil)
 			},
 		},
+		{
+			name:      "should return err when viewer of the shared collection updates other user's article",
+			userID:    memberID,
+			articleID: test.TestArticle.ID,
+			arg:       updatePayload,
+			expected:  nil,
+			err:       validation.NewError(validation.Forbidden, forbiddenAccess),
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(&shared, nil)
+			},
+			mockCollectionRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {
+				mockRepo.On("FindRole", context.Background(), collectionID, memberID).Return(collection.RoleViewer, nil)
+			},
+		},
+		{
+			name:      "should return err when updating other user's article",
+			userID:    memberID,
+			articleID: test.TestArticle.ID,
+			arg:       updatePayload,
+			expected:  nil,
+			err:       validation.NewError(validation.Forbidden, forbiddenAccess),
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+			},
+			mockCollectionRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {},
+		},
 		{
 			name:      "should return err when fail to update article",
 			userID:    test.TestArticle.UserID,
 			articleID: test.TestArticle.ID,
 			arg:       updatePayload,
 			expected:  nil,
-			err:       validation.NewError(validation.Forbidden, forbiddenAccess),
+			err:       validation.NewError(validation.PreconditionFailed, "article has been modified, fetch the latest version and retry"),
 			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
-				mockRepo.On("Update", context.Background(), mock.Anything).
-					Return(nil, validation.NewError(validation.Forbidden, forbiddenAccess))
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+				mockRepo.On("Update", context.Background(), mock.Anything, article.RevisionUserEdit).
+					Return(nil, validation.NewError(validation.PreconditionFailed, "article has been modified, fetch the latest version and retry"))
 			},
+			mockCollectionRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {},
 		},
 	}
 
@@ -400,11 +743,14 @@ func TestUpdateArticle(t *testing.T) {
 		t.Run(c.name, func(t *testing.T) {
 			r := new(mocks.ArticleRepository)
 			c.mockRepoBehaviour(r)
+			collectionRepo := new(mocks.CollectionRepository)
+			c.mockCollectionRepoBehaviour(collectionRepo)
 
-			s := NewService(logger.NewLogger(), scrapper.NewScrapper(), sanitizer.NewSanitizer(), r)
-			article, err := s.UpdateArticle(context.Background(), c.userID, c.articleID, c.arg)
+			s := NewService(logger.NewLogger(), scrapper.NewScrapper(), sanitizer.NewSanitizer(), r, collectionRepo, new(mocks.TagRepository))
+			article, err := s.UpdateArticle(context.Background(), c.userID, c.articleID, c.arg, 0)
 
 			assert.Equal(t, c.err, err)
+			r.AssertExpectations(t)
 			if err != nil {
 				return
 			}
@@ -420,6 +766,99 @@ func TestUpdateArticle(t *testing.T) {
 	}
 }
 
+func TestPatchArticle(t *testing.T) {
+	title := "Patched Title"
+	unsupported := "klingon"
+	content := unsafeContent
+
+	cases := []struct {
+		name              string
+		userID            string
+		arg               article.PatchPayload
+		version           int
+		err               error
+		mockRepoBehaviour func(mockRepo *mocks.ArticleRepository)
+	}{
+		{
+			name:    "should merge the patch into the stored article",
+			userID:  test.TestArticle.UserID,
+			arg:     article.PatchPayload{Title: &title},
+			version: test.TestArticle.Version,
+			err:     nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+				mockRepo.On("Update", context.Background(), mock.MatchedBy(func(arg article.Article) bool {
+					return arg.Title == title && arg.Content == sanitizer.NewSanitizer().Sanitize(test.TestArticle.Content) &&
+						arg.ArticleLink == test.TestArticle.ArticleLink && arg.NormalizedLink == test.TestArticle.NormalizedLink &&
+						arg.Version == test.TestArticle.Version
+				}), article.RevisionUserEdit).Return(&article.Article{ID: test.TestArticle.ID, Title: title, Version: test.TestArticle.Version + 1}, nil)
+			},
+		},
+		{
+			name:    "should sanitize patched content",
+			userID:  test.TestArticle.UserID,
+			arg:     article.PatchPayload{Title: &title, Content: &content},
+			version: test.TestArticle.Version,
+			err:     nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+				mockRepo.On("Update", context.Background(), mock.MatchedBy(func(arg article.Article) bool {
+					return arg.Content == sanitizedContent
+				}), article.RevisionUserEdit).Return(&article.Article{ID: test.TestArticle.ID, Title: title, Version: test.TestArticle.Version + 1}, nil)
+			},
+		},
+		{
+			name:    "should return err when patching another user's article",
+			userID:  uuid.NewString(),
+			arg:     article.PatchPayload{Title: &title},
+			version: test.TestArticle.Version,
+			err:     validation.NewError(validation.Forbidden, forbiddenAccess),
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+			},
+		},
+		{
+			name:    "should return err when patching unsupported language",
+			userID:  test.TestArticle.UserID,
+			arg:     article.PatchPayload{Language: &unsupported},
+			version: test.TestArticle.Version,
+			err:     validation.NewError(validation.BadRequest, "unsupported article language"),
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+			},
+		},
+		{
+			name:    "should return err when article has been modified",
+			userID:  test.TestArticle.UserID,
+			arg:     article.PatchPayload{Title: &title},
+			version: test.TestArticle.Version + 1,
+			err:     validation.NewError(validation.PreconditionFailed, "article has been modified, fetch the latest version and retry"),
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+				mockRepo.On("Update", context.Background(), mock.Anything, article.RevisionUserEdit).
+					Return(nil, validation.NewError(validation.PreconditionFailed, "article has been modified, fetch the latest version and retry"))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.ArticleRepository)
+			c.mockRepoBehaviour(r)
+
+			s := NewService(logger.NewLogger(), scrapper.NewScrapper(), sanitizer.NewSanitizer(), r, new(mocks.CollectionRepository), new(mocks.TagRepository))
+			patched, err := s.PatchArticle(context.Background(), c.userID, test.TestArticle.ID, c.arg, c.version)
+			assert.Equal(t, c.err, err)
+			r.AssertExpectations(t)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, title, patched.Title)
+			assert.Equal(t, test.TestArticle.Version+1, patched.Version)
+		})
+	}
+}
+
 func TestDeleteArticle(t *testing.T) {
 	cases := []struct {
 		name              string
@@ -455,9 +894,933 @@ func TestDeleteArticle(t *testing.T) {
 			r := new(mocks.ArticleRepository)
 			c.mockRepoBehaviour(r, c.userID, c.articleID)
 
-			s := NewService(logger.NewLogger(), scrapper.NewScrapper(), sanitizer.NewSanitizer(), r)
+			s := NewService(logger.NewLogger(), scrapper.NewScrapper(), sanitizer.NewSanitizer(), r, new(mocks.CollectionRepository), new(mocks.TagRepository))
 			err := s.DeleteArticle(context.Background(), c.userID, c.articleID)
 			assert.Equal(t, c.err, err)
 		})
 	}
 }
+
+func TestGetReadingStats(t *testing.T) {
+	period := article.StatsPeriod{
+		First:    time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC),
+		Last:     time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+		Location: time.UTC,
+	}
+
+	cases := []struct {
+		name              string
+		expected          *article.Stats
+		err               error
+		mockRepoBehaviour func(mockRepo *mocks.ArticleRepository)
+	}{
+		{
+			name: "should return reading stats completed for the period",
+			expected: &article.Stats{
+				From:        "2026-10-05",
+				To:          "2026-10-18",
+				Timezone:    "UTC",
+				Saved:       3,
+				Read:        2,
+				ReadingTime: 12,
+				Weeks: []*article.WeekStats{
+					{Week: "2026-10-05"},
+					{Week: "2026-10-12", Saved: 3, Read: 2},
+				},
+				TopDomains: []*article.DomainStats{{Domain: "unclatter.com", Saved: 3, Read: 2}},
+				Streak:     article.Streak{Current: 2, Longest: 2},
+				ReadRuns: []*article.ReadRun{
+					{FirstDay: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), LastDay: time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)},
+				},
+			},
+			err: nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("ReadingStats", context.Background(), test.TestUser.ID, period).Return(&article.Stats{
+					Saved:       3,
+					Read:        2,
+					ReadingTime: 12,
+					Weeks:       []*article.WeekStats{{Week: "2026-10-12", Saved: 3, Read: 2}},
+					TopDomains:  []*article.DomainStats{{Domain: "unclatter.com", Saved: 3, Read: 2}},
+					ReadRuns: []*article.ReadRun{
+						{FirstDay: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), LastDay: time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)},
+					},
+				}, nil)
+			},
+		},
+		{
+			name:     "should return err when fail to aggregate reading stats",
+			expected: nil,
+			err:      gorm.ErrInvalidDB,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("ReadingStats", context.Background(), test.TestUser.ID, period).Return(nil, gorm.ErrInvalidDB)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.ArticleRepository)
+			c.mockRepoBehaviour(r)
+
+			s := NewService(logger.NewLogger(), scrapper.NewScrapper(), sanitizer.NewSanitizer(), r, new(mocks.CollectionRepository), new(mocks.TagRepository))
+			stats, err := s.GetReadingStats(context.Background(), test.TestUser.ID, period)
+
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.expected, stats)
+		})
+	}
+}
+
+func TestSummarizeArticle(t *testing.T) {
+	stored := *test.TestArticle
+	stored.Content = "<p>Go is a programming language designed at Google for building reliable software.</p>"
+	excerpt := "Go is a programming language designed at Google for building reliable software."
+
+	cases := []struct {
+		name              string
+		userID            string
+		expected          string
+		err               error
+		mockRepoBehaviour func(mockRepo *mocks.ArticleRepository)
+	}{
+		{
+			name:     "should recompute and save article excerpt",
+			userID:   stored.UserID,
+			expected: excerpt,
+			err:      nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				a := stored
+				mockRepo.On("FindByID", context.Background(), stored.ID).Return(&a, nil)
+				mockRepo.On("UpdateExcerpt", context.Background(), stored.ID, excerpt).Return(nil)
+			},
+		},
+		{
+			name:   "should return err when summarizing other user's article",
+			userID: uuid.NewString(),
+			err:    validation.NewError(validation.Forbidden, forbiddenAccess),
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				a := stored
+				mockRepo.On("FindByID", context.Background(), stored.ID).Return(&a, nil)
+			},
+		},
+		{
+			name:   "should return err when fail to save article excerpt",
+			userID: stored.UserID,
+			err:    gorm.ErrInvalidDB,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				a := stored
+				mockRepo.On("FindByID", context.Background(), stored.ID).Return(&a, nil)
+				mockRepo.On("UpdateExcerpt", context.Background(), stored.ID, excerpt).Return(gorm.ErrInvalidDB)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.ArticleRepository)
+			c.mockRepoBehaviour(r)
+
+			s := NewService(logger.NewLogger(), scrapper.NewScrapper(), sanitizer.NewSanitizer(), r, new(mocks.CollectionRepository), new(mocks.TagRepository))
+			summarized, err := s.SummarizeArticle(context.Background(), c.userID, stored.ID)
+
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				assert.Nil(t, summarized)
+				return
+			}
+			assert.Equal(t, c.expected, summarized.Excerpt)
+			r.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListRelatedArticles(t *testing.T) {
+	indexed := *test.TestArticle
+	indexed.IndexedVersion = indexed.Version
+	related := []*article.Article{{ID: test.TestArticle2.ID, Title: test.TestArticle2.Title, Similarity: 0.42}}
+
+	cases := []struct {
+		name              string
+		userID            string
+		expected          []*article.Article
+		err               error
+		mockRepoBehaviour func(mockRepo *mocks.ArticleRepository)
+	}{
+		{
+			name:     "should return related articles of an indexed article",
+			userID:   indexed.UserID,
+			expected: related,
+			err:      nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				a := indexed
+				mockRepo.On("FindByID", context.Background(), indexed.ID).Return(&a, nil)
+				mockRepo.On("ListRelated", context.Background(), indexed.UserID, indexed.ID, 10).Return(related, nil)
+			},
+		},
+		{
+			name:     "should index a stale article before listing related articles",
+			userID:   indexed.UserID,
+			expected: related,
+			err:      nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				a := *test.TestArticle
+				a.Excerpt = "An excerpt summarized before"
+				mockRepo.On("FindByID", context.Background(), indexed.ID).Return(&a, nil)
+				mockRepo.On("SaveIndex", context.Background(), a, article.NewTerms(a)).Return(nil)
+				mockRepo.On("ListRelated", context.Background(), indexed.UserID, indexed.ID, 10).Return(related, nil)
+			},
+		},
+		{
+			name:   "should return err when listing related articles of other user's article",
+			userID: uuid.NewString(),
+			err:    validation.NewError(validation.Forbidden, forbiddenAccess),
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				a := indexed
+				mockRepo.On("FindByID", context.Background(), indexed.ID).Return(&a, nil)
+			},
+		},
+		{
+			name:   "should return err when fail to list related articles",
+			userID: indexed.UserID,
+			err:    gorm.ErrInvalidDB,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				a := indexed
+				mockRepo.On("FindByID", context.Background(), indexed.ID).Return(&a, nil)
+				mockRepo.On("ListRelated", context.Background(), indexed.UserID, indexed.ID, 10).Return(nil, gorm.ErrInvalidDB)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.ArticleRepository)
+			c.mockRepoBehaviour(r)
+
+			s := NewService(logger.NewLogger(), scrapper.NewScrapper(), sanitizer.NewSanitizer(), r, new(mocks.CollectionRepository), new(mocks.TagRepository))
+			articles, err := s.ListRelatedArticles(context.Background(), c.userID, indexed.ID, 10)
+
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.expected, articles)
+			r.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIndexArticles(t *testing.T) {
+	stale := *test.TestArticle
+	stale.Excerpt = "An excerpt summarized before"
+	stale2 := *test.TestArticle2
+	// articles saved before excerpts existed get theirs when they're indexed
+	summarized2 := stale2
+	summarized2.Excerpt = article.Summarize(stale2.Content, stale2.Language)
+
+	cases := []struct {
+		name              string
+		expected          int
+		err               error
+		mockRepoBehaviour func(mockRepo *mocks.ArticleRepository)
+	}{
+		{
+			name:     "should index the term vectors of stale articles",
+			expected: 2,
+			err:      nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("ListUnindexed", context.Background(), 50).Return([]*article.Article{&stale, &stale2}, nil)
+				mockRepo.On("SaveIndex", context.Background(), stale, article.NewTerms(stale)).Return(nil)
+				mockRepo.On("SaveIndex", context.Background(), summarized2, article.NewTerms(stale2)).Return(nil)
+			},
+		},
+		{
+			name:     "should stop at the first article that fails to be indexed",
+			expected: 1,
+			err:      gorm.ErrInvalidDB,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("ListUnindexed", context.Background(), 50).Return([]*article.Article{&stale, &stale2}, nil)
+				mockRepo.On("SaveIndex", context.Background(), stale, article.NewTerms(stale)).Return(nil)
+				mockRepo.On("SaveIndex", context.Background(), summarized2, article.NewTerms(stale2)).Return(gorm.ErrInvalidDB)
+			},
+		},
+		{
+			name:     "should return err when fail to list stale articles",
+			expected: 0,
+			err:      gorm.ErrInvalidDB,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("ListUnindexed", context.Background(), 50).Return(nil, gorm.ErrInvalidDB)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.ArticleRepository)
+			c.mockRepoBehaviour(r)
+
+			s := NewService(logger.NewLogger(), scrapper.NewScrapper(), sanitizer.NewSanitizer(), r, new(mocks.CollectionRepository), new(mocks.TagRepository))
+			indexed, err := s.IndexArticles(context.Background(), 50)
+
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.expected, indexed)
+			r.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListDuplicateClusters(t *testing.T) {
+	original := &article.Article{ID: test.TestArticle.ID, Fingerprint: 0x0f0f, CreatedAt: time.Now().UTC().Add(-time.Hour)}
+	copied := &article.Article{ID: test.TestArticle2.ID, Fingerprint: 0x0f0e, CreatedAt: time.Now().UTC()}
+
+	cases := []struct {
+		name              string
+		expected          []*article.DuplicateCluster
+		err               error
+		mockRepoBehaviour func(mockRepo *mocks.ArticleRepository)
+	}{
+		{
+			name:     "should return clusters of near-duplicate articles",
+			expected: []*article.DuplicateCluster{{Articles: []*article.Article{original, copied}}},
+			err:      nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("ListFingerprinted", context.Background(), test.TestUser.ID).
+					Return([]*article.Article{original, copied}, nil)
+			},
+		},
+		{
+			name:     "should return err when fail to fetch fingerprinted articles",
+			expected: nil,
+			err:      gorm.ErrInvalidDB,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("ListFingerprinted", context.Background(), test.TestUser.ID).Return(nil, gorm.ErrInvalidDB)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.ArticleRepository)
+			c.mockRepoBehaviour(r)
+
+			s := NewService(logger.NewLogger(), scrapper.NewScrapper(), sanitizer.NewSanitizer(), r, new(mocks.CollectionRepository), new(mocks.TagRepository))
+			clusters, err := s.ListDuplicateClusters(context.Background(), test.TestUser.ID)
+
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.expected, clusters)
+		})
+	}
+}
+
+func TestMergeDuplicates(t *testing.T) {
+	cases := []struct {
+		name              string
+		arg               article.MergePayload
+		expected          *article.Article
+		err               error
+		mockRepoBehaviour func(mockRepo *mocks.ArticleRepository)
+	}{
+		{
+			name: "should merge the unique duplicates into the kept article",
+			arg: article.MergePayload{
+				KeepID:       test.TestArticle.ID,
+				DuplicateIDs: []string{test.TestArticle2.ID, test.TestArticle.ID, test.TestArticle2.ID},
+			},
+			expected: test.TestArticle,
+			err:      nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("Merge", context.Background(), test.TestUser.ID, test.TestArticle.ID, []string{test.TestArticle2.ID}).
+					Return(test.TestArticle, nil)
+			},
+		},
+		{
+			name: "should return err when there is nothing to merge",
+			arg: article.MergePayload{
+				KeepID:       test.TestArticle.ID,
+				DuplicateIDs: []string{test.TestArticle.ID},
+			},
+			expected:          nil,
+			err:               validation.NewError(validation.BadRequest, "no duplicates to merge into the kept article"),
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {},
+		},
+		{
+			name: "should return err when an article isn't in the user's library",
+			arg: article.MergePayload{
+				KeepID:       test.TestArticle.ID,
+				DuplicateIDs: []string{test.TestArticle2.ID},
+			},
+			expected: nil,
+			err:      validation.NewError(validation.NotFound, "no article found with given id"),
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("Merge", context.Background(), test.TestUser.ID, test.TestArticle.ID, []string{test.TestArticle2.ID}).
+					Return(nil, validation.NewError(validation.NotFound, "no article found with given id"))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.ArticleRepository)
+			c.mockRepoBehaviour(r)
+
+			s := NewService(logger.NewLogger(), scrapper.NewScrapper(), sanitizer.NewSanitizer(), r, new(mocks.CollectionRepository), new(mocks.TagRepository))
+			merged, err := s.MergeDuplicates(context.Background(), test.TestUser.ID, c.arg)
+
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.expected, merged)
+			r.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSetArticleState(t *testing.T) {
+	readAt := time.Now().UTC()
+
+	cases := []struct {
+		name              string
+		userID            string
+		articleID         string
+		state             article.State
+		enabled           bool
+		err               error
+		mockRepoBehaviour func(mockRepo *mocks.ArticleRepository, userID, articleID string, state article.State)
+	}{
+		{
+			name:      "should set article state timestamp when enabled",
+			userID:    test.TestArticle.UserID,
+			articleID: test.TestArticle.ID,
+			state:     article.StateRead,
+			enabled:   true,
+			err:       nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository, userID, articleID string, state article.State) {
+				mockRepo.On("UpdateState", context.Background(), userID, articleID, state, mock.MatchedBy(func(at *time.Time) bool {
+					return at != nil
+				})).Return(&article.Article{ID: articleID, UserID: userID, ReadAt: &readAt}, nil)
+			},
+		},
+		{
+			name:      "should clear article state timestamp when disabled",
+			userID:    test.TestArticle.UserID,
+			articleID: test.TestArticle.ID,
+			state:     article.StateArchived,
+			enabled:   false,
+			err:       nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository, userID, articleID string, state article.State) {
+				mockRepo.On("UpdateState", context.Background(), userID, articleID, state, (*time.Time)(nil)).
+					Return(&article.Article{ID: articleID, UserID: userID}, nil)
+			},
+		},
+		{
+			name:      "should return err when updating other user's article state",
+			userID:    uuid.NewString(),
+			articleID: test.TestArticle.ID,
+			state:     article.StateFavorited,
+			enabled:   true,
+			err:       validation.NewError(validation.Forbidden, forbiddenAccess),
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository, userID, articleID string, state article.State) {
+				mockRepo.On("UpdateState", context.Background(), userID, articleID, state, mock.Anything).
+					Return(nil, validation.NewError(validation.Forbidden, forbiddenAccess))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.ArticleRepository)
+			c.mockRepoBehaviour(r, c.userID, c.articleID, c.state)
+
+			s := NewService(logger.NewLogger(), scrapper.NewScrapper(), sanitizer.NewSanitizer(), r, new(mocks.CollectionRepository), new(mocks.TagRepository))
+			updated, err := s.SetArticleState(context.Background(), c.userID, c.articleID, c.state, c.enabled)
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.articleID, updated.ID)
+			r.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSnoozeArticle(t *testing.T) {
+	until := time.Now().Add(24 * time.Hour).UTC()
+	past := time.Now().Add(-time.Hour).UTC()
+
+	cases := []struct {
+		name              string
+		userID            string
+		articleID         string
+		until             *time.Time
+		err               error
+		mockRepoBehaviour func(mockRepo *mocks.ArticleRepository, userID, articleID string, until *time.Time)
+	}{
+		{
+			name:      "should snooze article until the given time",
+			userID:    test.TestArticle.UserID,
+			articleID: test.TestArticle.ID,
+			until:     &until,
+			err:       nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository, userID, articleID string, until *time.Time) {
+				mockRepo.On("UpdateSnooze", context.Background(), userID, articleID, until).
+					Return(&article.Article{ID: articleID, UserID: userID, SnoozedUntil: until}, nil)
+			},
+		},
+		{
+			name:      "should wake snoozed article",
+			userID:    test.TestArticle.UserID,
+			articleID: test.TestArticle.ID,
+			until:     nil,
+			err:       nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository, userID, articleID string, until *time.Time) {
+				mockRepo.On("UpdateSnooze", context.Background(), userID, articleID, until).
+					Return(&article.Article{ID: articleID, UserID: userID}, nil)
+			},
+		},
+		{
+			name:              "should return err when snoozing until a past time",
+			userID:            test.TestArticle.UserID,
+			articleID:         test.TestArticle.ID,
+			until:             &past,
+			err:               validation.NewError(validation.BadRequest, "until should be in the future"),
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository, userID, articleID string, until *time.Time) {},
+		},
+		{
+			name:      "should return err when snoozing other user's article",
+			userID:    uuid.NewString(),
+			articleID: test.TestArticle.ID,
+			until:     &until,
+			err:       validation.NewError(validation.Forbidden, forbiddenAccess),
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository, userID, articleID string, until *time.Time) {
+				mockRepo.On("UpdateSnooze", context.Background(), userID, articleID, until).
+					Return(nil, validation.NewError(validation.Forbidden, forbiddenAccess))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.ArticleRepository)
+			c.mockRepoBehaviour(r, c.userID, c.articleID, c.until)
+
+			s := NewService(logger.NewLogger(), scrapper.NewScrapper(), sanitizer.NewSanitizer(), r, new(mocks.CollectionRepository), new(mocks.TagRepository))
+			updated, err := s.SnoozeArticle(context.Background(), c.userID, c.articleID, c.until)
+			assert.Equal(t, c.err, err)
+			r.AssertExpectations(t)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.until, updated.SnoozedUntil)
+		})
+	}
+}
+
+func TestRestoreArticle(t *testing.T) {
+	cases := []struct {
+		name              string
+		userID            string
+		articleID         string
+		expected          *article.Article
+		err               error
+		mockRepoBehaviour func(mockRepo *mocks.ArticleRepository, userID, articleID string)
+	}{
+		{
+			name:      "should return restored article",
+			userID:    test.TestArticle.UserID,
+			articleID: test.TestArticle.ID,
+			expected:  test.TestArticle,
+			err:       nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository, userID, articleID string) {
+				mockRepo.On("Restore", context.Background(), userID, articleID).Return(test.TestArticle, nil)
+			},
+		},
+		{
+			name:      "should return conflict err when the url was bookmarked again",
+			userID:    test.TestArticle.UserID,
+			articleID: test.TestArticle.ID,
+			expected:  nil,
+			err:       validation.NewError(validation.Conflict, "this url is already bookmarked"),
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository, userID, articleID string) {
+				mockRepo.On("Restore", context.Background(), userID, articleID).Return(
+					nil, validation.NewError(validation.Conflict, "this url is already bookmarked"),
+				)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.ArticleRepository)
+			c.mockRepoBehaviour(r, c.userID, c.articleID)
+
+			s := NewService(logger.NewLogger(), scrapper.NewScrapper(), sanitizer.NewSanitizer(), r, new(mocks.CollectionRepository), new(mocks.TagRepository))
+			restored, err := s.RestoreArticle(context.Background(), c.userID, c.articleID)
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.expected, restored)
+		})
+	}
+}
+
+func TestPurgeExpiredTrash(t *testing.T) {
+	retention := 30 * 24 * time.Hour
+
+	cases := []struct {
+		name              string
+		purged            int64
+		err               error
+		mockRepoBehaviour func(mockRepo *mocks.ArticleRepository)
+	}{
+		{
+			name:   "should purge articles trashed before the retention period",
+			purged: 2,
+			err:    nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("PurgeTrashedBefore", context.Background(), mock.MatchedBy(func(before time.Time) bool {
+					return time.Since(before) >= retention && time.Since(before) < retention+time.Minute
+				})).Return(int64(2), nil)
+			},
+		},
+		{
+			name:   "should return err when fail to purge trash",
+			purged: 0,
+			err:    errors.New("db error"),
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("PurgeTrashedBefore", context.Background(), mock.Anything).Return(int64(0), errors.New("db error"))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.ArticleRepository)
+			c.mockRepoBehaviour(r)
+
+			s := NewService(logger.NewLogger(), scrapper.NewScrapper(), sanitizer.NewSanitizer(), r, new(mocks.CollectionRepository), new(mocks.TagRepository))
+			purged, err := s.PurgeExpiredTrash(context.Background(), retention)
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.purged, purged)
+		})
+	}
+}
+
+func TestBulkUpdateArticles(t *testing.T) {
+	userID := test.TestArticle.UserID
+
+	cases := []struct {
+		name              string
+		arg               article.BulkPayload
+		expected          []*article.BulkResult
+		err               error
+		mockRepoBehaviour func(mockRepo *mocks.ArticleRepository)
+	}{
+		{
+			name: "should apply action once per article id",
+			arg: article.BulkPayload{
+				Action: article.BulkMarkRead,
+				IDs:    []string{test.TestArticle.ID, test.TestArticle.ID, test.TestArticle2.ID},
+			},
+			expected: []*article.BulkResult{
+				{ID: test.TestArticle.ID, Status: article.BulkStatusOK},
+				{ID: test.TestArticle2.ID, Status: article.BulkStatusOK},
+			},
+			err: nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("BulkUpdate", context.Background(), userID, mock.MatchedBy(func(op article.BulkOperation) bool {
+					return op.Action == article.BulkMarkRead && len(op.IDs) == 2 && op.Filter == nil
+				})).Return([]*article.BulkResult{
+					{ID: test.TestArticle.ID, Status: article.BulkStatusOK},
+					{ID: test.TestArticle2.ID, Status: article.BulkStatusOK},
+				}, nil)
+			},
+		},
+		{
+			name: "should tag articles matching the filter",
+			arg: article.BulkPayload{
+				Action: article.BulkTag,
+				Filter: &article.BulkFilter{Query: "golang", Status: article.FilterFavorites},
+				Tags:   []string{"Go", "go"},
+			},
+			expected: []*article.BulkResult{
+				{ID: test.TestArticle.ID, Status: article.BulkStatusOK},
+			},
+			err: nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("BulkUpdate", context.Background(), userID, mock.MatchedBy(func(op article.BulkOperation) bool {
+					return op.Filter.Query == "golang" && op.Filter.Status == article.FilterFavorites && len(op.Tags) == 1
+				})).Return([]*article.BulkResult{{ID: test.TestArticle.ID, Status: article.BulkStatusOK}}, nil)
+			},
+		},
+		{
+			name: "should return err when both ids and filter are given",
+			arg: article.BulkPayload{
+				Action: article.BulkDelete,
+				IDs:    []string{test.TestArticle.ID},
+				Filter: &article.BulkFilter{},
+			},
+			expected:          nil,
+			err:               validation.NewError(validation.BadRequest, "ids and filter can't be combined"),
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {},
+		},
+		{
+			name: "should return err when neither ids nor filter are given",
+			arg: article.BulkPayload{
+				Action: article.BulkDelete,
+			},
+			expected:          nil,
+			err:               validation.NewError(validation.BadRequest, "either ids or filter is required"),
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {},
+		},
+		{
+			name: "should return err when tagging without tags",
+			arg: article.BulkPayload{
+				Action: article.BulkTag,
+				IDs:    []string{test.TestArticle.ID},
+				Tags:   []string{"  "},
+			},
+			expected:          nil,
+			err:               validation.NewError(validation.BadRequest, "tags are required to tag articles"),
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {},
+		},
+		{
+			name: "should return err when filter status is unsupported",
+			arg: article.BulkPayload{
+				Action: article.BulkArchive,
+				Filter: &article.BulkFilter{Status: "pinned"},
+			},
+			expected:          nil,
+			err:               validation.NewError(validation.BadRequest, "unsupported article filter"),
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.ArticleRepository)
+			c.mockRepoBehaviour(r)
+
+			s := NewService(logger.NewLogger(), scrapper.NewScrapper(), sanitizer.NewSanitizer(), r, new(mocks.CollectionRepository), new(mocks.TagRepository))
+			results, err := s.BulkUpdateArticles(context.Background(), userID, c.arg)
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.expected, results)
+			r.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDiffArticleRevisions(t *testing.T) {
+	previous := *test.TestArticle
+	previous.Title = "Old Title"
+	previous.Content = "<p>first paragraph</p><p>old paragraph</p>"
+	revision := article.NewRevision(previous, article.RevisionUserEdit)
+
+	current := *test.TestArticle
+	current.Version = previous.Version + 1
+	current.Content = "<p>first paragraph</p><p>new paragraph</p>"
+
+	cases := []struct {
+		name              string
+		userID            string
+		expected          *article.RevisionDiff
+		err               error
+		mockRepoBehaviour func(mockRepo *mocks.ArticleRepository)
+	}{
+		{
+			name:   "should diff the revision against the current article",
+			userID: current.UserID,
+			expected: &article.RevisionDiff{
+				FromVersion: previous.Version,
+				ToVersion:   current.Version,
+				Title: []diff.Line{
+					{Op: diff.Delete, Text: "Old Title"},
+					{Op: diff.Insert, Text: current.Title},
+				},
+				ArticleLink: []diff.Line{{Op: diff.Equal, Text: current.ArticleLink}},
+				Content: []diff.Line{
+					{Op: diff.Equal, Text: "first paragraph"},
+					{Op: diff.Delete, Text: "old paragraph"},
+					{Op: diff.Insert, Text: "new paragraph"},
+				},
+			},
+			err: nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), current.ID).Return(&current, nil)
+				mockRepo.On("FindRevision", context.Background(), current.ID, revision.ID).Return(revision, nil)
+			},
+		},
+		{
+			name:     "should return err when diffing another user's article",
+			userID:   uuid.NewString(),
+			expected: nil,
+			err:      validation.NewError(validation.Forbidden, forbiddenAccess),
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), current.ID).Return(&current, nil)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.ArticleRepository)
+			c.mockRepoBehaviour(r)
+
+			s := NewService(logger.NewLogger(), scrapper.NewScrapper(), sanitizer.NewSanitizer(), r, new(mocks.CollectionRepository), new(mocks.TagRepository))
+			revisionDiff, err := s.DiffArticleRevisions(context.Background(), c.userID, current.ID, revision.ID, "")
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.expected, revisionDiff)
+		})
+	}
+}
+
+func TestRestoreArticleRevision(t *testing.T) {
+	previous := *test.TestArticle
+	previous.Title = "Old Title"
+	revision := article.NewRevision(previous, article.RevisionUserEdit)
+
+	cases := []struct {
+		name              string
+		err               error
+		mockRepoBehaviour func(mockRepo *mocks.ArticleRepository)
+	}{
+		{
+			name: "should restore the revision as the current article",
+			err:  nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+				mockRepo.On("FindRevision", context.Background(), test.TestArticle.ID, revision.ID).Return(revision, nil)
+				mockRepo.On("Update", context.Background(), mock.MatchedBy(func(arg article.Article) bool {
+					return arg.Title == revision.Title && arg.Content == sanitizer.NewSanitizer().Sanitize(revision.Content) &&
+						arg.Version == test.TestArticle.Version
+				}), article.RevisionRestore).Return(&article.Article{ID: test.TestArticle.ID, Title: revision.Title}, nil)
+			},
+		},
+		{
+			name: "should return err when revision doesn't exist",
+			err:  validation.NewError(validation.NotFound, "no revision found with given id"),
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+				mockRepo.On("FindRevision", context.Background(), test.TestArticle.ID, revision.ID).
+					Return(nil, validation.NewError(validation.NotFound, "no revision found with given id"))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.ArticleRepository)
+			c.mockRepoBehaviour(r)
+
+			s := NewService(logger.NewLogger(), scrapper.NewScrapper(), sanitizer.NewSanitizer(), r, new(mocks.CollectionRepository), new(mocks.TagRepository))
+			restored, err := s.RestoreArticleRevision(context.Background(), test.TestArticle.UserID, test.TestArticle.ID, revision.ID, test.TestArticle.Version)
+			assert.Equal(t, c.err, err)
+			r.AssertExpectations(t)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, revision.Title, restored.Title)
+		})
+	}
+}
+
+func TestRescrapeArticle(t *testing.T) {
+	cases := []struct {
+		name                  string
+		err                   error
+		mockScrapperBehaviour func(mockScrapper *mocks.Scrapper)
+		mockRepoBehaviour     func(mockRepo *mocks.ArticleRepository)
+	}{
+		{
+			name: "should replace the content with the re-scraped page",
+			err:  nil,
+			mockScrapperBehaviour: func(mockScrapper *mocks.Scrapper) {
+				mockScrapper.On("ScrapePage", test.TestArticle.ArticleLink).Return(&scrapper.Page{Content: unsafeContent}, nil)
+			},
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+				mockRepo.On("Update", context.Background(), mock.MatchedBy(func(arg article.Article) bool {
+					return arg.Title == test.TestArticle.Title && arg.Content == sanitizedContent &&
+						arg.Version == test.TestArticle.Version
+				}), article.RevisionRescrape).Return(&article.Article{ID: test.TestArticle.ID, Content: sanitizedContent}, nil)
+			},
+		},
+		{
+			name: "should return err when fail to scrape article content",
+			err:  validation.NewError(validation.BadRequest, "fail to scrape any article content"),
+			mockScrapperBehaviour: func(mockScrapper *mocks.Scrapper) {
+				mockScrapper.On("ScrapePage", test.TestArticle.ArticleLink).Return(nil, colly.ErrForbiddenURL)
+			},
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			scrapperPkg := new(mocks.Scrapper)
+			c.mockScrapperBehaviour(scrapperPkg)
+			r := new(mocks.ArticleRepository)
+			c.mockRepoBehaviour(r)
+
+			s := NewService(logger.NewLogger(), scrapperPkg, sanitizer.NewSanitizer(), r, new(mocks.CollectionRepository), new(mocks.TagRepository))
+			updated, err := s.RescrapeArticle(context.Background(), test.TestArticle.UserID, test.TestArticle.ID, test.TestArticle.Version)
+			assert.Equal(t, c.err, err)
+			r.AssertExpectations(t)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, sanitizedContent, updated.Content)
+		})
+	}
+}
+
+func TestMigrateSanitizedContent(t *testing.T) {
+	unsafe := article.Article{ID: uuid.NewString(), Title: "Unsafe", Content: unsafeContent, ArticleLink: test.TestArticle.ArticleLink,
+		Language: test.TestArticle.Language, UserID: test.TestArticle.UserID, Version: 2}
+	clean := article.Article{ID: uuid.NewString(), Content: sanitizedContent, Version: 1}
+
+	cases := []struct {
+		name              string
+		expected          int
+		err               error
+		mockRepoBehaviour func(mockRepo *mocks.ArticleRepository)
+	}{
+		{
+			name:     "should record a sanitizer migration revision when the content changes",
+			expected: 1,
+			err:      nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("ListUnsanitized", context.Background(), sanitizer.Version, 10).Return([]*article.Article{&unsafe, &clean}, nil)
+				mockRepo.On("Update", context.Background(), mock.MatchedBy(func(arg article.Article) bool {
+					return arg.ID == unsafe.ID && arg.Content == sanitizedContent && arg.Version == unsafe.Version
+				}), article.RevisionSanitizerMigration).Return(&article.Article{ID: unsafe.ID}, nil)
+				mockRepo.On("MarkSanitized", context.Background(), unsafe.ID, sanitizer.Version).Return(nil)
+				mockRepo.On("MarkSanitized", context.Background(), clean.ID, sanitizer.Version).Return(nil)
+			},
+		},
+		{
+			name:     "should leave articles edited since they were listed for the next run",
+			expected: 0,
+			err:      nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("ListUnsanitized", context.Background(), sanitizer.Version, 10).Return([]*article.Article{&unsafe}, nil)
+				mockRepo.On("Update", context.Background(), mock.Anything, article.RevisionSanitizerMigration).
+					Return(nil, validation.NewError(validation.PreconditionFailed, "article has been modified"))
+			},
+		},
+		{
+			name:     "should return err when fail to list unsanitized articles",
+			expected: 0,
+			err:      gorm.ErrInvalidDB,
+			mockRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("ListUnsanitized", context.Background(), sanitizer.Version, 10).Return(nil, gorm.ErrInvalidDB)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.ArticleRepository)
+			c.mockRepoBehaviour(r)
+
+			s := NewService(logger.NewLogger(), scrapper.NewScrapper(), sanitizer.NewSanitizer(), r, new(mocks.CollectionRepository), new(mocks.TagRepository))
+			migrated, err := s.MigrateSanitizedContent(context.Background(), 10)
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.expected, migrated)
+			r.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package article
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultStatsDays is the period covered when no date range is given.
+	DefaultStatsDays = 84
+	MaxStatsDays     = 366
+	MaxTopDomains    = 10
+
+	dateLayout = "2006-01-02"
+)
+
+// StatsParams cover whole calendar days in the timezone.
+type StatsParams struct {
+	From     string `validate:"omitempty,iso8601date"`
+	To       string `validate:"omitempty,iso8601date"`
+	Timezone string `validate:"omitempty,max=64"`
+}
+
+type StatsPeriod struct {
+	// First and Last are midnights in the user's timezone.
+	First    time.Time
+	Last     time.Time
+	Location *time.Location
+}
+
+func (p StatsParams) Period(now time.Time) (period StatsPeriod, errDetail map[string]string) {
+	errDetail = make(map[string]string)
+	period.Location = time.UTC
+	if p.Timezone != "" {
+		// an empty name loads UTC and Local is the server's zone, neither is what the user picked
+		loc, err := time.LoadLocation(p.Timezone)
+		if err != nil || p.Timezone == "Local" {
+			errDetail["timezone"] = "timezone should be a valid IANA timezone"
+			return
+		}
+		period.Location = loc
+	}
+
+	today := now.In(period.Location)
+	period.Last = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, period.Location)
+	if p.To != "" {
+		period.Last = dayOf(p.To, period.Location)
+	}
+	period.First = period.Last.AddDate(0, 0, -DefaultStatsDays+1)
+	if p.From != "" {
+		period.First = dayOf(p.From, period.Location)
+		if p.To == "" && period.First.AddDate(0, 0, DefaultStatsDays-1).Before(period.Last) {
+			period.Last = period.First.AddDate(0, 0, DefaultStatsDays-1)
+		}
+	}
+
+	if period.Last.Before(period.First) {
+		errDetail["to"] = "to should be greater than or equal to from"
+	} else if period.Days() > MaxStatsDays {
+		errDetail["to"] = fmt.Sprintf("the date range should span at most %d days", MaxStatsDays)
+	}
+	return
+}
+
+func dayOf(date string, loc *time.Location) time.Time {
+	t, _ := time.Parse(time.RFC3339Nano, date)
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// End is the exclusive upper bound of the period.
+func (p StatsPeriod) End() time.Time {
+	return p.Last.AddDate(0, 0, 1)
+}
+
+func (p StatsPeriod) Days() int {
+	return daysBetween(p.First, p.Last) + 1
+}
+
+// Stats leave out trashed articles.
+type Stats struct {
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Timezone    string `json:"timezone"`
+	Saved       int64  `json:"saved"`
+	Read        int64  `json:"read"`
+	ReadingTime int64  `json:"reading_time"`
+	// AverageSecondsToRead is nil when nothing was read.
+	AverageSecondsToRead *int64         `json:"average_seconds_to_read"`
+	Weeks                []*WeekStats   `json:"weeks" gorm:"-"`
+	TopDomains           []*DomainStats `json:"top_domains" gorm:"-"`
+	Streak               Streak         `json:"streak" gorm:"-"`
+
+	// ReadRuns reach into the period, oldest first.
+	ReadRuns []*ReadRun `json:"-" gorm:"-"`
+}
+
+type WeekStats struct {
+	Week  string `json:"week"`
+	Saved int64  `json:"saved"`
+	Read  int64  `json:"read"`
+}
+
+type DomainStats struct {
+	Domain string `json:"domain"`
+	Saved  int64  `json:"saved"`
+	Read   int64  `json:"read"`
+}
+
+// Streak is still current on the day before the last, which may not be over yet.
+type Streak struct {
+	Current int `json:"current"`
+	Longest int `json:"longest"`
+}
+
+type ReadRun struct {
+	FirstDay time.Time
+	LastDay  time.Time
+}
+
+func (s *Stats) Complete(period StatsPeriod) {
+	s.From = period.First.Format(dateLayout)
+	s.To = period.Last.Format(dateLayout)
+	s.Timezone = period.Location.String()
+
+	counted := make(map[string]*WeekStats, len(s.Weeks))
+	for _, w := range s.Weeks {
+		counted[w.Week] = w
+	}
+	weeks := make([]*WeekStats, 0, period.Days()/7+2)
+	for week := startOfWeek(period.First); !week.After(period.Last); week = week.AddDate(0, 0, 7) {
+		w, ok := counted[week.Format(dateLayout)]
+		if !ok {
+			w = &WeekStats{Week: week.Format(dateLayout)}
+		}
+		weeks = append(weeks, w)
+	}
+	s.Weeks = weeks
+	if s.TopDomains == nil {
+		s.TopDomains = []*DomainStats{}
+	}
+
+	s.Streak = Streak{}
+	last := period.Last.Format(dateLayout)
+	dayBefore := period.Last.AddDate(0, 0, -1).Format(dateLayout)
+	for _, r := range s.ReadRuns {
+		days := daysBetween(r.FirstDay, r.LastDay) + 1
+		s.Streak.Longest = max(s.Streak.Longest, days)
+		if d := r.LastDay.Format(dateLayout); d == last || d == dayBefore {
+			s.Streak.Current = days
+		}
+	}
+}
+
+// startOfWeek starts weeks on Monday like Postgres' date_trunc.
+func startOfWeek(day time.Time) time.Time {
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+func daysBetween(a, b time.Time) int {
+	a = time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	b = time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(b.Sub(a).Hours() / 24)
+}
@@ -0,0 +1,136 @@
+package article
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsParamsPeriod(t *testing.T) {
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+	// 2026-10-18 20:00 UTC is already the 19th in Jakarta
+	now := time.Date(2026, 10, 18, 20, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		arg      StatsParams
+		expected StatsPeriod
+		err      map[string]string
+	}{
+		{
+			name: "should default to the twelve weeks up to today in the timezone",
+			arg:  StatsParams{Timezone: "Asia/Jakarta"},
+			expected: StatsPeriod{
+				First:    time.Date(2026, 7, 28, 0, 0, 0, 0, jakarta),
+				Last:     time.Date(2026, 10, 19, 0, 0, 0, 0, jakarta),
+				Location: jakarta,
+			},
+			err: map[string]string{},
+		},
+		{
+			name: "should cover the given days",
+			arg:  StatsParams{From: "2026-01-01T00:00:00Z", To: "2026-03-31T23:59:59Z"},
+			expected: StatsPeriod{
+				First:    time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+				Last:     time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+				Location: time.UTC,
+			},
+			err: map[string]string{},
+		},
+		{
+			name: "should cover the days the dates fall on in the timezone",
+			arg:  StatsParams{From: "2026-01-01T18:00:00Z", To: "2026-03-31T16:59:59Z", Timezone: "Asia/Jakarta"},
+			expected: StatsPeriod{
+				First:    time.Date(2026, 1, 2, 0, 0, 0, 0, jakarta),
+				Last:     time.Date(2026, 3, 31, 0, 0, 0, 0, jakarta),
+				Location: jakarta,
+			},
+			err: map[string]string{},
+		},
+		{
+			name: "should cover the default days from the given day",
+			arg:  StatsParams{From: "2026-01-01T00:00:00Z"},
+			expected: StatsPeriod{
+				First:    time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+				Last:     time.Date(2026, 3, 25, 0, 0, 0, 0, time.UTC),
+				Location: time.UTC,
+			},
+			err: map[string]string{},
+		},
+		{
+			name: "should return err when the range is reversed",
+			arg:  StatsParams{From: "2026-03-01T00:00:00Z", To: "2026-02-01T00:00:00Z"},
+			err:  map[string]string{"to": "to should be greater than or equal to from"},
+		},
+		{
+			name: "should return err when the range is too long",
+			arg:  StatsParams{From: "2025-01-01T00:00:00Z", To: "2026-06-30T00:00:00Z"},
+			err:  map[string]string{"to": "the date range should span at most 366 days"},
+		},
+		{
+			name: "should return err when the timezone doesn't exist",
+			arg:  StatsParams{Timezone: "Mars/Olympus_Mons"},
+			err:  map[string]string{"timezone": "timezone should be a valid IANA timezone"},
+		},
+		{
+			name: "should return err when the timezone is the server's local one",
+			arg:  StatsParams{Timezone: "Local"},
+			err:  map[string]string{"timezone": "timezone should be a valid IANA timezone"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			period, err := c.arg.Period(now)
+			assert.Equal(t, c.err, err)
+			if len(err) > 0 {
+				return
+			}
+			assert.Equal(t, c.expected, period)
+		})
+	}
+}
+
+func TestStatsComplete(t *testing.T) {
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+	period := StatsPeriod{
+		First:    time.Date(2026, 9, 30, 0, 0, 0, 0, jakarta),
+		Last:     time.Date(2026, 10, 19, 0, 0, 0, 0, jakarta),
+		Location: jakarta,
+	}
+	day := func(d int) time.Time {
+		return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	stats := &Stats{
+		Saved: 6,
+		Read:  4,
+		Weeks: []*WeekStats{
+			{Week: "2026-09-28", Saved: 4, Read: 1},
+			{Week: "2026-10-12", Saved: 2, Read: 3},
+		},
+		ReadRuns: []*ReadRun{
+			{FirstDay: time.Date(2026, 9, 26, 0, 0, 0, 0, time.UTC), LastDay: day(2)},
+			{FirstDay: day(16), LastDay: day(18)},
+		},
+	}
+	stats.Complete(period)
+
+	assert.Equal(t, "2026-09-30", stats.From)
+	assert.Equal(t, "2026-10-19", stats.To)
+	assert.Equal(t, "Asia/Jakarta", stats.Timezone)
+	assert.Equal(t, []*WeekStats{
+		{Week: "2026-09-28", Saved: 4, Read: 1},
+		{Week: "2026-10-05"},
+		{Week: "2026-10-12", Saved: 2, Read: 3},
+		{Week: "2026-10-19"},
+	}, stats.Weeks)
+	assert.Equal(t, []*DomainStats{}, stats.TopDomains)
+	// the run reaching into the period counts in full, the last day of the period may not be over yet
+	assert.Equal(t, Streak{Current: 3, Longest: 7}, stats.Streak)
+
+	stats.ReadRuns = []*ReadRun{{FirstDay: day(10), LastDay: day(15)}}
+	stats.Complete(period)
+	assert.Equal(t, Streak{Current: 0, Longest: 6}, stats.Streak)
+}
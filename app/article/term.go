@@ -0,0 +1,75 @@
+package article
+
+import (
+	"math"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/ryanadiputraa/unclatter/pkg/nlp"
+	"github.com/ryanadiputraa/unclatter/pkg/plaintext"
+)
+
+const (
+	MaxTerms      = 100
+	titleBoost    = 3
+	maxTermLength = 40
+
+	DefaultRelatedLimit = 10
+	MaxRelatedLimit     = 50
+)
+
+// Term weights are unit scaled term frequencies, the idf is applied when articles are compared.
+type Term struct {
+	ArticleID string  `gorm:"type:varchar;primaryKey"`
+	Term      string  `gorm:"type:varchar;primaryKey;index:idx_article_terms_user_term,priority:2"`
+	UserID    string  `gorm:"type:varchar;not null;index:idx_article_terms_user_term,priority:1"`
+	Weight    float64 `gorm:"type:double precision;not null"`
+
+	Article *Article `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+func (Term) TableName() string {
+	return "article_terms"
+}
+
+func NewTerms(a Article) []*Term {
+	counts := make(map[string]int)
+	for _, w := range nlp.ContentWords(a.Title, a.Language) {
+		counts[w] += titleBoost
+	}
+	for _, w := range nlp.ContentWords(plaintext.FromHTML(a.Content), a.Language) {
+		counts[w]++
+	}
+
+	terms := make([]*Term, 0, len(counts))
+	for w, n := range counts {
+		if utf8.RuneCountInString(w) > maxTermLength {
+			continue
+		}
+		terms = append(terms, &Term{
+			ArticleID: a.ID,
+			Term:      w,
+			UserID:    a.UserID,
+			Weight:    1 + math.Log(float64(n)),
+		})
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].Weight != terms[j].Weight {
+			return terms[i].Weight > terms[j].Weight
+		}
+		return terms[i].Term < terms[j].Term
+	})
+	if len(terms) > MaxTerms {
+		terms = terms[:MaxTerms]
+	}
+
+	norm := 0.0
+	for _, t := range terms {
+		norm += t.Weight * t.Weight
+	}
+	norm = math.Sqrt(norm)
+	for _, t := range terms {
+		t.Weight /= norm
+	}
+	return terms
+}
@@ -0,0 +1,54 @@
+package article
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTerms(t *testing.T) {
+	t.Run("should weigh title and content words into a unit vector", func(t *testing.T) {
+		a := Article{
+			ID:       "article",
+			UserID:   "user",
+			Title:    "Postgres indexing",
+			Content:  "<p>The indexing of the tables in postgres and the planner.</p>",
+			Language: DefaultLanguage,
+		}
+
+		terms := NewTerms(a)
+
+		weights := make(map[string]float64)
+		norm := 0.0
+		for _, term := range terms {
+			assert.Equal(t, a.ID, term.ArticleID)
+			assert.Equal(t, a.UserID, term.UserID)
+			weights[term.Term] = term.Weight
+			norm += term.Weight * term.Weight
+		}
+		assert.InDelta(t, 1, norm, 1e-9)
+		assert.NotContains(t, weights, "the")
+		assert.NotContains(t, weights, "of")
+		assert.Greater(t, weights["postgres"], weights["tables"])
+		assert.Equal(t, weights["postgres"], weights["indexing"])
+		assert.Equal(t, weights["tables"], weights["planner"])
+	})
+
+	t.Run("should keep only the heaviest terms", func(t *testing.T) {
+		var words []string
+		for i := 0; i < MaxTerms+20; i++ {
+			words = append(words, fmt.Sprintf("word%c%c", 'a'+i/26, 'a'+i%26))
+		}
+		terms := NewTerms(Article{Title: "frequent", Content: strings.Join(words, " "), Language: DefaultLanguage})
+
+		assert.Len(t, terms, MaxTerms)
+		assert.Equal(t, "frequent", terms[0].Term)
+		assert.Greater(t, terms[0].Weight, terms[1].Weight)
+	})
+
+	t.Run("should return no terms for an empty article", func(t *testing.T) {
+		assert.Empty(t, NewTerms(Article{Language: DefaultLanguage}))
+	})
+}
@@ -0,0 +1,128 @@
+package collection
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Collection struct {
+	ID        string    `json:"id" gorm:"type:varchar"`
+	UserID    string    `json:"-" gorm:"type:varchar;not null;uniqueIndex:idx_collections_user_name,priority:1"`
+	Name      string    `json:"name" gorm:"type:varchar(100);not null;uniqueIndex:idx_collections_user_name,priority:2"`
+	CreatedAt time.Time `json:"created_at" gorm:"type:timestamptz;not null"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"type:timestamptz;not null"`
+
+	// ArticleCount and Role are only populated when listing the user's collections.
+	ArticleCount int64 `json:"article_count" gorm:"->;-:migration"`
+	Role         Role  `json:"role,omitempty" gorm:"->;-:migration"`
+}
+
+type CollectionPayload struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+func NewCollection(userID, name string) *Collection {
+	return &Collection{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		Name:      strings.TrimSpace(name),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+}
+
+// Role is the access a user has to a collection, the collection's creator is always its owner.
+type Role string
+
+const (
+	// RoleViewer can read the collection's articles.
+	RoleViewer Role = "viewer"
+	// RoleEditor can also edit the collection's articles and move their own articles into it.
+	RoleEditor Role = "editor"
+	// RoleOwner can also manage the collection's members, only the creator can rename or delete it.
+	RoleOwner Role = "owner"
+)
+
+var roleRank = map[Role]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleOwner:  3,
+}
+
+// Can reports whether the role grants at least the required access.
+func (r Role) Can(required Role) bool {
+	return roleRank[r] > 0 && roleRank[r] >= roleRank[required]
+}
+
+// Member gives another user access to a shared collection.
+type Member struct {
+	CollectionID string    `json:"collection_id" gorm:"type:varchar;primaryKey"`
+	UserID       string    `json:"user_id" gorm:"type:varchar;primaryKey;index"`
+	Role         Role      `json:"role" gorm:"type:varchar;not null"`
+	InvitedBy    string    `json:"invited_by" gorm:"type:varchar;not null"`
+	CreatedAt    time.Time `json:"created_at" gorm:"type:timestamptz;not null"`
+	UpdatedAt    time.Time `json:"updated_at" gorm:"type:timestamptz;not null"`
+
+	Collection *Collection `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+
+	// Email, FirstName and LastName are only populated when listing the collection's members.
+	Email     string `json:"email,omitempty" gorm:"->;-:migration"`
+	FirstName string `json:"first_name,omitempty" gorm:"->;-:migration"`
+	LastName  string `json:"last_name,omitempty" gorm:"->;-:migration"`
+}
+
+func (Member) TableName() string {
+	return "collection_members"
+}
+
+type MemberPayload struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  Role   `json:"role" validate:"required,oneof=viewer editor owner"`
+}
+
+type MemberRolePayload struct {
+	Role Role `json:"role" validate:"required,oneof=viewer editor owner"`
+}
+
+func NewMember(collectionID, userID string, role Role, invitedBy string) *Member {
+	return &Member{
+		CollectionID: collectionID,
+		UserID:       userID,
+		Role:         role,
+		InvitedBy:    invitedBy,
+		CreatedAt:    time.Now().UTC(),
+		UpdatedAt:    time.Now().UTC(),
+	}
+}
+
+type CollectionService interface {
+	CreateCollection(ctx context.Context, userID string, arg CollectionPayload) (*Collection, error)
+	ListCollections(ctx context.Context, userID string) ([]*Collection, error)
+	RenameCollection(ctx context.Context, userID, collectionID string, arg CollectionPayload) (*Collection, error)
+	// DeleteCollection removes the collection, its articles are kept outside of any collection.
+	DeleteCollection(ctx context.Context, userID, collectionID string) error
+	ListMembers(ctx context.Context, userID, collectionID string) ([]*Member, error)
+	// InviteMember shares the collection with the user registered with the payload's email.
+	InviteMember(ctx context.Context, userID, collectionID string, arg MemberPayload) (*Member, error)
+	UpdateMemberRole(ctx context.Context, userID, collectionID, memberID string, arg MemberRolePayload) (*Member, error)
+	// RemoveMember is allowed to owners and to members leaving the collection.
+	RemoveMember(ctx context.Context, userID, collectionID, memberID string) error
+}
+
+type CollectionRepository interface {
+	Save(ctx context.Context, arg Collection) error
+	// List returns the user's collections along with the ones shared with them.
+	List(ctx context.Context, userID string) ([]*Collection, error)
+	FindByID(ctx context.Context, collectionID string) (*Collection, error)
+	Update(ctx context.Context, arg Collection) (*Collection, error)
+	Delete(ctx context.Context, userID, collectionID string) error
+	// FindRole returns the user's role in the collection, it's empty when the user has no access.
+	FindRole(ctx context.Context, collectionID, userID string) (Role, error)
+	ListMembers(ctx context.Context, collectionID string) ([]*Member, error)
+	SaveMember(ctx context.Context, arg Member) error
+	UpdateMember(ctx context.Context, arg Member) (*Member, error)
+	DeleteMember(ctx context.Context, collectionID, userID string) error
+}
@@ -0,0 +1,44 @@
+package collection
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCollection(t *testing.T) {
+	userID := uuid.NewString()
+
+	c := NewCollection(userID, "  Reading List ")
+
+	assert.NotEmpty(t, c.ID)
+	assert.Equal(t, userID, c.UserID)
+	assert.Equal(t, "Reading List", c.Name)
+	assert.NotEmpty(t, c.CreatedAt)
+	assert.NotEmpty(t, c.UpdatedAt)
+}
+
+func TestNewMember(t *testing.T) {
+	collectionID := uuid.NewString()
+	userID := uuid.NewString()
+	invitedBy := uuid.NewString()
+
+	m := NewMember(collectionID, userID, RoleEditor, invitedBy)
+
+	assert.Equal(t, collectionID, m.CollectionID)
+	assert.Equal(t, userID, m.UserID)
+	assert.Equal(t, RoleEditor, m.Role)
+	assert.Equal(t, invitedBy, m.InvitedBy)
+	assert.NotEmpty(t, m.CreatedAt)
+	assert.NotEmpty(t, m.UpdatedAt)
+}
+
+func TestRoleCan(t *testing.T) {
+	assert.True(t, RoleOwner.Can(RoleEditor))
+	assert.True(t, RoleEditor.Can(RoleEditor))
+	assert.True(t, RoleViewer.Can(RoleViewer))
+	assert.False(t, RoleViewer.Can(RoleEditor))
+	assert.False(t, RoleEditor.Can(RoleOwner))
+	assert.False(t, Role("").Can(RoleViewer))
+}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ryanadiputraa/unclatter/app/collection"
+	"github.com/ryanadiputraa/unclatter/app/middleware"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	_http "github.com/ryanadiputraa/unclatter/pkg/http"
+	"github.com/ryanadiputraa/unclatter/pkg/validator"
+)
+
+type handler struct {
+	rw                _http.ResponseWriter
+	collectionService collection.CollectionService
+	validator         validator.Validator
+}
+
+func NewHandler(web *http.ServeMux, rw _http.ResponseWriter, collectionService collection.CollectionService, authMiddleware middleware.AuthMiddleware, validator validator.Validator) {
+	h := &handler{
+		rw:                rw,
+		collectionService: collectionService,
+		validator:         validator,
+	}
+
+	web.Handle("POST /api/collections", authMiddleware.ParseJWTToken(h.CreateCollection()))
+	web.Handle("GET /api/collections", authMiddleware.ParseJWTToken(h.ListCollections()))
+	web.Handle("PUT /api/collections/{id}", authMiddleware.ParseJWTToken(h.RenameCollection()))
+	web.Handle("DELETE /api/collections/{id}", authMiddleware.ParseJWTToken(h.DeleteCollection()))
+	web.Handle("GET /api/collections/{id}/members", authMiddleware.ParseJWTToken(h.ListMembers()))
+	web.Handle("POST /api/collections/{id}/members", authMiddleware.ParseJWTToken(h.InviteMember()))
+	web.Handle("PUT /api/collections/{id}/members/{userID}", authMiddleware.ParseJWTToken(h.UpdateMemberRole()))
+	web.Handle("DELETE /api/collections/{id}/members/{userID}", authMiddleware.ParseJWTToken(h.RemoveMember()))
+}
+
+func (h *handler) CreateCollection() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		var payload collection.CollectionPayload
+
+		json.NewDecoder(r.Body).Decode(&payload)
+		if err, errMap := h.validator.Validate(payload); err != nil {
+			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+			return
+		}
+
+		created, err := h.collectionService.CreateCollection(ac.Context, ac.UserID, payload)
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusCreated, created)
+	}
+}
+
+func (h *handler) ListCollections() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		collections, err := h.collectionService.ListCollections(ac.Context, ac.UserID)
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, collections)
+	}
+}
+
+func (h *handler) RenameCollection() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		var payload collection.CollectionPayload
+
+		json.NewDecoder(r.Body).Decode(&payload)
+		if err, errMap := h.validator.Validate(payload); err != nil {
+			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+			return
+		}
+
+		updated, err := h.collectionService.RenameCollection(ac.Context, ac.UserID, r.PathValue("id"), payload)
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, updated)
+	}
+}
+
+func (h *handler) DeleteCollection() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		err := h.collectionService.DeleteCollection(ac.Context, ac.UserID, r.PathValue("id"))
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, nil)
+	}
+}
+
+func (h *handler) ListMembers() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		members, err := h.collectionService.ListMembers(ac.Context, ac.UserID, r.PathValue("id"))
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, members)
+	}
+}
+
+func (h *handler) InviteMember() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		var payload collection.MemberPayload
+
+		json.NewDecoder(r.Body).Decode(&payload)
+		if err, errMap := h.validator.Validate(payload); err != nil {
+			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+			return
+		}
+
+		member, err := h.collectionService.InviteMember(ac.Context, ac.UserID, r.PathValue("id"), payload)
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusCreated, member)
+	}
+}
+
+func (h *handler) UpdateMemberRole() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		var payload collection.MemberRolePayload
+
+		json.NewDecoder(r.Body).Decode(&payload)
+		if err, errMap := h.validator.Validate(payload); err != nil {
+			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+			return
+		}
+
+		member, err := h.collectionService.UpdateMemberRole(ac.Context, ac.UserID, r.PathValue("id"), r.PathValue("userID"), payload)
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, member)
+	}
+}
+
+func (h *handler) RemoveMember() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		err := h.collectionService.RemoveMember(ac.Context, ac.UserID, r.PathValue("id"), r.PathValue("userID"))
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, nil)
+	}
+}
+
+func (h *handler) writeErr(w http.ResponseWriter, err error) {
+	if vErr, ok := err.(*validation.Error); ok {
+		h.rw.WriteErrMessage(w, validation.HttpErrMap[vErr.Err], vErr.Message)
+		return
+	}
+	h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+}
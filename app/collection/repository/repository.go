@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ryanadiputraa/unclatter/app/collection"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) collection.CollectionRepository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Save(ctx context.Context, arg collection.Collection) error {
+	err := r.db.Create(&arg).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		err = validation.NewError(validation.Conflict, "collection name is already in use")
+	}
+	return err
+}
+
+func (r *repository) List(ctx context.Context, userID string) (collections []*collection.Collection, err error) {
+	err = r.db.
+		Select("collections.*, COALESCE(collection_members.role, ?) AS role, COUNT(articles.id) AS article_count", collection.RoleOwner).
+		Joins("LEFT JOIN collection_members ON collection_members.collection_id = collections.id AND collection_members.user_id = ?", userID).
+		Joins("LEFT JOIN articles ON articles.collection_id = collections.id AND articles.deleted_at IS NULL").
+		Where("collections.user_id = ? OR collection_members.user_id IS NOT NULL", userID).
+		Group("collections.id, collection_members.role").
+		Order("collections.name ASC").
+		Find(&collections).Error
+	return
+}
+
+func (r *repository) FindByID(ctx context.Context, collectionID string) (c *collection.Collection, err error) {
+	err = r.db.First(&c, "id = ?", collectionID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, validation.NewError(validation.NotFound, "no collection found with given id")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (r *repository) Update(ctx context.Context, arg collection.Collection) (updated *collection.Collection, err error) {
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&updated, "id = ?", arg.ID).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				err = validation.NewError(validation.NotFound, "no collection found with given id")
+			}
+			return err
+		}
+
+		if updated.UserID != arg.UserID {
+			return validation.NewError(validation.Forbidden, "forbidden access")
+		}
+
+		updated.Name = arg.Name
+		updated.UpdatedAt = arg.UpdatedAt
+		return tx.Model(&updated).Updates(collection.Collection{
+			Name:      arg.Name,
+			UpdatedAt: arg.UpdatedAt,
+		}).Error
+	})
+
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		err = validation.NewError(validation.Conflict, "collection name is already in use")
+	}
+	return
+}
+
+func (r *repository) Delete(ctx context.Context, userID, collectionID string) error {
+	res := r.db.Where("id = ? AND user_id = ?", collectionID, userID).Delete(&collection.Collection{})
+	if res.RowsAffected == 0 && res.Error == nil {
+		return validation.NewError(validation.NotFound, "no collection found with given id")
+	}
+	return res.Error
+}
+
+func (r *repository) FindRole(ctx context.Context, collectionID, userID string) (collection.Role, error) {
+	var access struct {
+		UserID string
+		Role   collection.Role
+	}
+	err := r.db.Table("collections").
+		Select("collections.user_id, collection_members.role").
+		Joins("LEFT JOIN collection_members ON collection_members.collection_id = collections.id AND collection_members.user_id = ?", userID).
+		Where("collections.id = ?", collectionID).
+		Take(&access).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", validation.NewError(validation.NotFound, "no collection found with given id")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if access.UserID == userID {
+		return collection.RoleOwner, nil
+	}
+	return access.Role, nil
+}
+
+func (r *repository) ListMembers(ctx context.Context, collectionID string) (members []*collection.Member, err error) {
+	err = r.db.
+		Select("collection_members.*, users.email, users.first_name, users.last_name").
+		Joins("JOIN users ON users.id = collection_members.user_id").
+		Where("collection_members.collection_id = ?", collectionID).
+		Order("collection_members.created_at ASC").
+		Find(&members).Error
+	return
+}
+
+func (r *repository) SaveMember(ctx context.Context, arg collection.Member) error {
+	err := r.db.Create(&arg).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		err = validation.NewError(validation.Conflict, "user is already a member of this collection")
+	}
+	return err
+}
+
+func (r *repository) UpdateMember(ctx context.Context, arg collection.Member) (updated *collection.Member, err error) {
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&updated, "collection_id = ? AND user_id = ?", arg.CollectionID, arg.UserID).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				err = validation.NewError(validation.NotFound, "no member found with given id")
+			}
+			return err
+		}
+
+		updated.Role = arg.Role
+		updated.UpdatedAt = arg.UpdatedAt
+		return tx.Model(&updated).Updates(collection.Member{
+			Role:      arg.Role,
+			UpdatedAt: arg.UpdatedAt,
+		}).Error
+	})
+	return
+}
+
+func (r *repository) DeleteMember(ctx context.Context, collectionID, userID string) error {
+	res := r.db.Where("collection_id = ? AND user_id = ?", collectionID, userID).Delete(&collection.Member{})
+	if res.RowsAffected == 0 && res.Error == nil {
+		return validation.NewError(validation.NotFound, "no member found with given id")
+	}
+	return res.Error
+}
@@ -0,0 +1,482 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/ryanadiputraa/unclatter/app/collection"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/test"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+var testCollection = collection.NewCollection(test.TestUser.ID, "Reading List")
+
+func TestSave(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	expectedExec := "^INSERT INTO \"collections\""
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		err           error
+	}{
+		{
+			name: "should insert new collection",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(expectedExec).
+					WithArgs(testCollection.ID, testCollection.UserID, testCollection.Name, testCollection.CreatedAt, testCollection.UpdatedAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			err: nil,
+		},
+		{
+			name: "should return err when collection name is already used",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(expectedExec).
+					WithArgs(testCollection.ID, testCollection.UserID, testCollection.Name, testCollection.CreatedAt, testCollection.UpdatedAt).
+					WillReturnError(gorm.ErrDuplicatedKey)
+				mock.ExpectRollback()
+			},
+			err: validation.NewError(validation.Conflict, "collection name is already in use"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+
+			err := r.Save(context.Background(), *testCollection)
+			assert.Equal(t, c.err, err)
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	sharedID := uuid.NewString()
+	expectedQuery := "^SELECT collections.\\*, COALESCE\\(collection_members.role, (.+)\\) AS role, COUNT\\(articles.id\\) AS article_count " +
+		"FROM \"collections\" LEFT JOIN collection_members (.+) AND collection_members.user_id = (.+) " +
+		"LEFT JOIN articles (.+) AND articles.deleted_at IS NULL WHERE collections.user_id = (.+) OR collection_members.user_id IS NOT NULL " +
+		"GROUP BY collections.id, collection_members.role"
+
+	mock.ExpectQuery(expectedQuery).
+		WithArgs(collection.RoleOwner, test.TestUser.ID, test.TestUser.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "role", "article_count"}).
+			AddRow(testCollection.ID, testCollection.Name, "owner", 4).
+			AddRow(sharedID, "Team Reading", "viewer", 2))
+
+	collections, err := r.List(context.Background(), test.TestUser.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, []*collection.Collection{
+		{ID: testCollection.ID, Name: testCollection.Name, Role: collection.RoleOwner, ArticleCount: 4},
+		{ID: sharedID, Name: "Team Reading", Role: collection.RoleViewer, ArticleCount: 2},
+	}, collections)
+}
+
+func TestFindByID(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	expectedQuery := "^SELECT \\* FROM \"collections\" WHERE id = (.+) ORDER BY \"collections\".\"id\" LIMIT (.+)$"
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		expected      *collection.Collection
+		err           error
+	}{
+		{
+			name: "should return collection",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(expectedQuery).
+					WithArgs(testCollection.ID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name"}).
+						AddRow(testCollection.ID, testCollection.UserID, testCollection.Name))
+			},
+			expected: &collection.Collection{ID: testCollection.ID, UserID: testCollection.UserID, Name: testCollection.Name},
+			err:      nil,
+		},
+		{
+			name: "should return not found err when collection doesn't exist",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(expectedQuery).
+					WithArgs(testCollection.ID, 1).
+					WillReturnError(gorm.ErrRecordNotFound)
+			},
+			expected: nil,
+			err:      validation.NewError(validation.NotFound, "no collection found with given id"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+
+			found, err := r.FindByID(context.Background(), testCollection.ID)
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.expected, found)
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	selectQuery := "^SELECT (.+) FROM \"collections\" WHERE id = (.+) FOR UPDATE"
+	updatedAt := time.Now().UTC()
+
+	cases := []struct {
+		name          string
+		arg           collection.Collection
+		mockBehaviour func(mock sqlmock.Sqlmock, arg collection.Collection)
+		err           error
+	}{
+		{
+			name: "should rename user's collection",
+			arg:  collection.Collection{ID: testCollection.ID, UserID: testCollection.UserID, Name: "Later", UpdatedAt: updatedAt},
+			mockBehaviour: func(mock sqlmock.Sqlmock, arg collection.Collection) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectQuery).
+					WithArgs(arg.ID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name"}).
+						AddRow(testCollection.ID, testCollection.UserID, testCollection.Name))
+				mock.ExpectExec("^UPDATE \"collections\" SET").
+					WithArgs(arg.Name, test.AnyTime{}, arg.ID).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			err: nil,
+		},
+		{
+			name: "should return err when renaming another user's collection",
+			arg:  collection.Collection{ID: testCollection.ID, UserID: uuid.NewString(), Name: "Later", UpdatedAt: updatedAt},
+			mockBehaviour: func(mock sqlmock.Sqlmock, arg collection.Collection) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectQuery).
+					WithArgs(arg.ID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name"}).
+						AddRow(testCollection.ID, testCollection.UserID, testCollection.Name))
+				mock.ExpectRollback()
+			},
+			err: validation.NewError(validation.Forbidden, "forbidden access"),
+		},
+		{
+			name: "should return err when collection doesn't exist",
+			arg:  collection.Collection{ID: uuid.NewString(), UserID: testCollection.UserID, Name: "Later", UpdatedAt: updatedAt},
+			mockBehaviour: func(mock sqlmock.Sqlmock, arg collection.Collection) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectQuery).
+					WithArgs(arg.ID, 1).
+					WillReturnError(gorm.ErrRecordNotFound)
+				mock.ExpectRollback()
+			},
+			err: validation.NewError(validation.NotFound, "no collection found with given id"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock, c.arg)
+
+			updated, err := r.Update(context.Background(), c.arg)
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.arg.Name, updated.Name)
+			assert.NotEmpty(t, updated.UpdatedAt)
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	deleteQuery := "^DELETE FROM \"collections\" WHERE id = (.+) AND user_id = "
+
+	cases := []struct {
+		name         string
+		rowsAffected int64
+		err          error
+	}{
+		{
+			name:         "should delete user's collection",
+			rowsAffected: 1,
+			err:          nil,
+		},
+		{
+			name:         "should return not found err when user has no collection with given id",
+			rowsAffected: 0,
+			err:          validation.NewError(validation.NotFound, "no collection found with given id"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectExec(deleteQuery).
+				WithArgs(testCollection.ID, testCollection.UserID).
+				WillReturnResult(sqlmock.NewResult(0, c.rowsAffected))
+			mock.ExpectCommit()
+
+			err := r.Delete(context.Background(), testCollection.UserID, testCollection.ID)
+			assert.Equal(t, c.err, err)
+		})
+	}
+}
+
+func TestFindRole(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	memberID := uuid.NewString()
+	expectedQuery := "^SELECT collections.user_id, collection_members.role FROM \"collections\" LEFT JOIN collection_members " +
+		"(.+) AND collection_members.user_id = (.+) WHERE collections.id = (.+) LIMIT (.+)$"
+
+	cases := []struct {
+		name          string
+		userID        string
+		mockBehaviour func(mock sqlmock.Sqlmock, userID string)
+		role          collection.Role
+		err           error
+	}{
+		{
+			name:   "should return owner role for the collection's creator",
+			userID: test.TestUser.ID,
+			mockBehaviour: func(mock sqlmock.Sqlmock, userID string) {
+				mock.ExpectQuery(expectedQuery).
+					WithArgs(userID, testCollection.ID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id", "role"}).AddRow(test.TestUser.ID, nil))
+			},
+			role: collection.RoleOwner,
+			err:  nil,
+		},
+		{
+			name:   "should return member's role",
+			userID: memberID,
+			mockBehaviour: func(mock sqlmock.Sqlmock, userID string) {
+				mock.ExpectQuery(expectedQuery).
+					WithArgs(userID, testCollection.ID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id", "role"}).AddRow(test.TestUser.ID, "editor"))
+			},
+			role: collection.RoleEditor,
+			err:  nil,
+		},
+		{
+			name:   "should return empty role when the collection isn't shared with the user",
+			userID: memberID,
+			mockBehaviour: func(mock sqlmock.Sqlmock, userID string) {
+				mock.ExpectQuery(expectedQuery).
+					WithArgs(userID, testCollection.ID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id", "role"}).AddRow(test.TestUser.ID, nil))
+			},
+			role: "",
+			err:  nil,
+		},
+		{
+			name:   "should return not found err when collection doesn't exist",
+			userID: memberID,
+			mockBehaviour: func(mock sqlmock.Sqlmock, userID string) {
+				mock.ExpectQuery(expectedQuery).
+					WithArgs(userID, testCollection.ID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id", "role"}))
+			},
+			role: "",
+			err:  validation.NewError(validation.NotFound, "no collection found with given id"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock, c.userID)
+
+			role, err := r.FindRole(context.Background(), testCollection.ID, c.userID)
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.role, role)
+		})
+	}
+}
+
+func TestListMembers(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	memberID := uuid.NewString()
+	expectedQuery := "^SELECT collection_members.\\*, users.email, users.first_name, users.last_name FROM \"collection_members\" " +
+		"JOIN users ON users.id = collection_members.user_id WHERE collection_members.collection_id = (.+) ORDER BY collection_members.created_at ASC"
+
+	mock.ExpectQuery(expectedQuery).
+		WithArgs(testCollection.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"collection_id", "user_id", "role", "email"}).
+			AddRow(testCollection.ID, memberID, "viewer", "member@mail.com"))
+
+	members, err := r.ListMembers(context.Background(), testCollection.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, []*collection.Member{
+		{CollectionID: testCollection.ID, UserID: memberID, Role: collection.RoleViewer, Email: "member@mail.com"},
+	}, members)
+}
+
+func TestSaveMember(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	member := collection.NewMember(testCollection.ID, uuid.NewString(), collection.RoleEditor, test.TestUser.ID)
+	expectedExec := "^INSERT INTO \"collection_members\""
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		err           error
+	}{
+		{
+			name: "should insert new member",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(expectedExec).
+					WithArgs(member.CollectionID, member.UserID, member.Role, member.InvitedBy, member.CreatedAt, member.UpdatedAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			err: nil,
+		},
+		{
+			name: "should return err when user is already a member",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(expectedExec).
+					WithArgs(member.CollectionID, member.UserID, member.Role, member.InvitedBy, member.CreatedAt, member.UpdatedAt).
+					WillReturnError(gorm.ErrDuplicatedKey)
+				mock.ExpectRollback()
+			},
+			err: validation.NewError(validation.Conflict, "user is already a member of this collection"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+
+			err := r.SaveMember(context.Background(), *member)
+			assert.Equal(t, c.err, err)
+		})
+	}
+}
+
+func TestUpdateMember(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	memberID := uuid.NewString()
+	selectQuery := "^SELECT (.+) FROM \"collection_members\" WHERE collection_id = (.+) AND user_id = (.+) FOR UPDATE"
+	updatedAt := time.Now().UTC()
+	arg := collection.Member{CollectionID: testCollection.ID, UserID: memberID, Role: collection.RoleEditor, UpdatedAt: updatedAt}
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		err           error
+	}{
+		{
+			name: "should update member's role",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectQuery).
+					WithArgs(testCollection.ID, memberID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"collection_id", "user_id", "role"}).AddRow(testCollection.ID, memberID, "viewer"))
+				mock.ExpectExec("^UPDATE \"collection_members\" SET \"role\"=(.+),\"updated_at\"=(.+) WHERE \"collection_id\" = (.+) AND \"user_id\" = ").
+					WithArgs(collection.RoleEditor, test.AnyTime{}, testCollection.ID, memberID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			err: nil,
+		},
+		{
+			name: "should return not found err when user isn't a member",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectQuery).
+					WithArgs(testCollection.ID, memberID, 1).
+					WillReturnError(gorm.ErrRecordNotFound)
+				mock.ExpectRollback()
+			},
+			err: validation.NewError(validation.NotFound, "no member found with given id"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+
+			updated, err := r.UpdateMember(context.Background(), arg)
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, collection.RoleEditor, updated.Role)
+			assert.NotEmpty(t, updated.UpdatedAt)
+		})
+	}
+}
+
+func TestDeleteMember(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	memberID := uuid.NewString()
+	deleteQuery := "^DELETE FROM \"collection_members\" WHERE collection_id = (.+) AND user_id = (.+)"
+
+	cases := []struct {
+		name         string
+		rowsAffected int64
+		err          error
+	}{
+		{
+			name:         "should remove member",
+			rowsAffected: 1,
+			err:          nil,
+		},
+		{
+			name:         "should return not found err when user isn't a member",
+			rowsAffected: 0,
+			err:          validation.NewError(validation.NotFound, "no member found with given id"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectExec(deleteQuery).
+				WithArgs(testCollection.ID, memberID).
+				WillReturnResult(sqlmock.NewResult(0, c.rowsAffected))
+			mock.ExpectCommit()
+
+			err := r.DeleteMember(context.Background(), testCollection.ID, memberID)
+			assert.Equal(t, c.err, err)
+		})
+	}
+}
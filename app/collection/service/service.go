@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ryanadiputraa/unclatter/app/collection"
+	"github.com/ryanadiputraa/unclatter/app/user"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/pkg/logger"
+)
+
+type service struct {
+	log            logger.Logger
+	repository     collection.CollectionRepository
+	userRepository user.UserRepository
+}
+
+func NewService(log logger.Logger, repository collection.CollectionRepository, userRepository user.UserRepository) collection.CollectionService {
+	return &service{
+		log:            log,
+		repository:     repository,
+		userRepository: userRepository,
+	}
+}
+
+func (s *service) CreateCollection(ctx context.Context, userID string, arg collection.CollectionPayload) (*collection.Collection, error) {
+	c := collection.NewCollection(userID, arg.Name)
+	if err := s.repository.Save(ctx, *c); err != nil {
+		s.log.Warn("collection service: fail to save collection", err)
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *service) ListCollections(ctx context.Context, userID string) (collections []*collection.Collection, err error) {
+	collections, err = s.repository.List(ctx, userID)
+	if err != nil {
+		s.log.Error("collection service: fail to fetch user's collections", err)
+	}
+	return
+}
+
+func (s *service) RenameCollection(ctx context.Context, userID, collectionID string, arg collection.CollectionPayload) (updated *collection.Collection, err error) {
+	c := collection.NewCollection(userID, arg.Name)
+	c.ID = collectionID
+	c.UpdatedAt = time.Now().UTC()
+
+	updated, err = s.repository.Update(ctx, *c)
+	if err != nil {
+		s.log.Warn("collection service: fail to rename collection ", collectionID, " ", err)
+	}
+	return
+}
+
+func (s *service) DeleteCollection(ctx context.Context, userID, collectionID string) error {
+	if err := s.repository.Delete(ctx, userID, collectionID); err != nil {
+		s.log.Warn("collection service: fail to delete collection ", collectionID, " ", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) ListMembers(ctx context.Context, userID, collectionID string) (members []*collection.Member, err error) {
+	if err = s.authorize(ctx, userID, collectionID, collection.RoleViewer); err != nil {
+		return
+	}
+
+	members, err = s.repository.ListMembers(ctx, collectionID)
+	if err != nil {
+		s.log.Error("collection service: fail to fetch collection members", err)
+	}
+	return
+}
+
+func (s *service) InviteMember(ctx context.Context, userID, collectionID string, arg collection.MemberPayload) (*collection.Member, error) {
+	if err := s.authorize(ctx, userID, collectionID, collection.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	invitee, err := s.userRepository.FindByEmail(ctx, arg.Email)
+	if err != nil {
+		if _, ok := err.(*validation.Error); ok {
+			return nil, validation.NewError(validation.NotFound, "no user found with given email")
+		}
+		s.log.Error("collection service: fail to fetch invited user", err)
+		return nil, err
+	}
+
+	// the creator isn't stored as a member, so the role lookup also covers inviting them
+	role, err := s.repository.FindRole(ctx, collectionID, invitee.ID)
+	if err != nil {
+		s.log.Error("collection service: fail to fetch collection role", err)
+		return nil, err
+	}
+	if role != "" {
+		return nil, validation.NewError(validation.Conflict, "user is already a member of this collection")
+	}
+
+	m := collection.NewMember(collectionID, invitee.ID, arg.Role, userID)
+	if err = s.repository.SaveMember(ctx, *m); err != nil {
+		s.log.Warn("collection service: fail to save collection member", err)
+		return nil, err
+	}
+
+	m.Email = invitee.Email
+	m.FirstName = invitee.FirstName
+	m.LastName = invitee.LastName
+	return m, nil
+}
+
+func (s *service) UpdateMemberRole(ctx context.Context, userID, collectionID, memberID string, arg collection.MemberRolePayload) (updated *collection.Member, err error) {
+	if err = s.authorize(ctx, userID, collectionID, collection.RoleOwner); err != nil {
+		return
+	}
+
+	updated, err = s.repository.UpdateMember(ctx, collection.Member{
+		CollectionID: collectionID,
+		UserID:       memberID,
+		Role:         arg.Role,
+		UpdatedAt:    time.Now().UTC(),
+	})
+	if err != nil {
+		s.log.Warn("collection service: fail to update member ", memberID, " ", err)
+	}
+	return
+}
+
+func (s *service) RemoveMember(ctx context.Context, userID, collectionID, memberID string) error {
+	if memberID != userID {
+		if err := s.authorize(ctx, userID, collectionID, collection.RoleOwner); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repository.DeleteMember(ctx, collectionID, memberID); err != nil {
+		s.log.Warn("collection service: fail to remove member ", memberID, " ", err)
+		return err
+	}
+	return nil
+}
+
+// authorize checks the user has at least the required role in the collection.
+func (s *service) authorize(ctx context.Context, userID, collectionID string, required collection.Role) error {
+	role, err := s.repository.FindRole(ctx, collectionID, userID)
+	if err != nil {
+		if _, ok := err.(*validation.Error); !ok {
+			s.log.Error("collection service: fail to fetch collection role", err)
+		}
+		return err
+	}
+
+	if !role.Can(required) {
+		return validation.NewError(validation.Forbidden, "forbidden access")
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/ryanadiputraa/unclatter/app/collection"
+	"github.com/ryanadiputraa/unclatter/app/mocks"
+	"github.com/ryanadiputraa/unclatter/app/user"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/pkg/logger"
+	"github.com/ryanadiputraa/unclatter/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateCollection(t *testing.T) {
+	cases := []struct {
+		name              string
+		arg               collection.CollectionPayload
+		err               error
+		mockRepoBehaviour func(mockRepo *mocks.CollectionRepository)
+	}{
+		{
+			name: "should return created collection",
+			arg:  collection.CollectionPayload{Name: " Reading List "},
+			err:  nil,
+			mockRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {
+				mockRepo.On("Save", context.Background(), mock.Anything).Return(nil)
+			},
+		},
+		{
+			name: "should return err when collection name is already in use",
+			arg:  collection.CollectionPayload{Name: "Reading List"},
+			err:  validation.NewError(validation.Conflict, "collection name is already in use"),
+			mockRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {
+				mockRepo.On("Save", context.Background(), mock.Anything).
+					Return(validation.NewError(validation.Conflict, "collection name is already in use"))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.CollectionRepository)
+			c.mockRepoBehaviour(r)
+
+			s := NewService(logger.NewLogger(), r, new(mocks.UserRepository))
+			created, err := s.CreateCollection(context.Background(), test.TestUser.ID, c.arg)
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				assert.Nil(t, created)
+				return
+			}
+			assert.NotEmpty(t, created.ID)
+			assert.Equal(t, test.TestUser.ID, created.UserID)
+			assert.Equal(t, "Reading List", created.Name)
+		})
+	}
+}
+
+func TestRenameCollection(t *testing.T) {
+	collectionID := uuid.NewString()
+	renamed := &collection.Collection{ID: collectionID, UserID: test.TestUser.ID, Name: "Later"}
+
+	cases := []struct {
+		name              string
+		expected          *collection.Collection
+		err               error
+		mockRepoBehaviour func(mockRepo *mocks.CollectionRepository)
+	}{
+		{
+			name:     "should return renamed collection",
+			expected: renamed,
+			err:      nil,
+			mockRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {
+				mockRepo.On("Update", context.Background(), mock.MatchedBy(func(arg collection.Collection) bool {
+					return arg.ID == collectionID && arg.UserID == test.TestUser.ID && arg.Name == "Later"
+				})).Return(renamed, nil)
+			},
+		},
+		{
+			name:     "should return err when renaming another user's collection",
+			expected: nil,
+			err:      validation.NewError(validation.Forbidden, "forbidden access"),
+			mockRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {
+				mockRepo.On("Update", context.Background(), mock.Anything).
+					Return(nil, validation.NewError(validation.Forbidden, "forbidden access"))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.CollectionRepository)
+			c.mockRepoBehaviour(r)
+
+			s := NewService(logger.NewLogger(), r, new(mocks.UserRepository))
+			updated, err := s.RenameCollection(context.Background(), test.TestUser.ID, collectionID, collection.CollectionPayload{Name: "Later"})
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.expected, updated)
+		})
+	}
+}
+
+func TestInviteMember(t *testing.T) {
+	collectionID := uuid.NewString()
+	invitee := &user.User{ID: uuid.NewString(), Email: "member@mail.com", FirstName: "Member"}
+	arg := collection.MemberPayload{Email: invitee.Email, Role: collection.RoleEditor}
+
+	cases := []struct {
+		name                  string
+		err                   error
+		mockRepoBehaviour     func(mockRepo *mocks.CollectionRepository)
+		mockUserRepoBehaviour func(mockRepo *mocks.UserRepository)
+	}{
+		{
+			name: "should share collection with the invited user",
+			err:  nil,
+			mockRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {
+				mockRepo.On("FindRole", context.Background(), collectionID, test.TestUser.ID).Return(collection.RoleOwner, nil)
+				mockRepo.On("FindRole", context.Background(), collectionID, invitee.ID).Return(collection.Role(""), nil)
+				mockRepo.On("SaveMember", context.Background(), mock.Anything).Return(nil)
+			},
+			mockUserRepoBehaviour: func(mockRepo *mocks.UserRepository) {
+				mockRepo.On("FindByEmail", context.Background(), invitee.Email).Return(invitee, nil)
+			},
+		},
+		{
+			name: "should return err when editor invites a user",
+			err:  validation.NewError(validation.Forbidden, "forbidden access"),
+			mockRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {
+				mockRepo.On("FindRole", context.Background(), collectionID, test.TestUser.ID).Return(collection.RoleEditor, nil)
+			},
+			mockUserRepoBehaviour: func(mockRepo *mocks.UserRepository) {},
+		},
+		{
+			name: "should return err when no user is registered with the email",
+			err:  validation.NewError(validation.NotFound, "no user found with given email"),
+			mockRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {
+				mockRepo.On("FindRole", context.Background(), collectionID, test.TestUser.ID).Return(collection.RoleOwner, nil)
+			},
+			mockUserRepoBehaviour: func(mockRepo *mocks.UserRepository) {
+				mockRepo.On("FindByEmail", context.Background(), invitee.Email).
+					Return(nil, validation.NewError(validation.BadRequest, "missing user data"))
+			},
+		},
+		{
+			name: "should return err when user already has access to the collection",
+			err:  validation.NewError(validation.Conflict, "user is already a member of this collection"),
+			mockRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {
+				mockRepo.On("FindRole", context.Background(), collectionID, test.TestUser.ID).Return(collection.RoleOwner, nil)
+				mockRepo.On("FindRole", context.Background(), collectionID, invitee.ID).Return(collection.RoleViewer, nil)
+			},
+			mockUserRepoBehaviour: func(mockRepo *mocks.UserRepository) {
+				mockRepo.On("FindByEmail", context.Background(), invitee.Email).Return(invitee, nil)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.CollectionRepository)
+			c.mockRepoBehaviour(r)
+			userRepo := new(mocks.UserRepository)
+			c.mockUserRepoBehaviour(userRepo)
+
+			s := NewService(logger.NewLogger(), r, userRepo)
+			member, err := s.InviteMember(context.Background(), test.TestUser.ID, collectionID, arg)
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				assert.Nil(t, member)
+				return
+			}
+			assert.Equal(t, invitee.ID, member.UserID)
+			assert.Equal(t, collection.RoleEditor, member.Role)
+			assert.Equal(t, test.TestUser.ID, member.InvitedBy)
+			assert.Equal(t, invitee.Email, member.Email)
+		})
+	}
+}
+
+func TestRemoveMember(t *testing.T) {
+	collectionID := uuid.NewString()
+	memberID := uuid.NewString()
+
+	cases := []struct {
+		name              string
+		userID            string
+		err               error
+		mockRepoBehaviour func(mockRepo *mocks.CollectionRepository)
+	}{
+		{
+			name:   "should let owner remove a member",
+			userID: test.TestUser.ID,
+			err:    nil,
+			mockRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {
+				mockRepo.On("FindRole", context.Background(), collectionID, test.TestUser.ID).Return(collection.RoleOwner, nil)
+				mockRepo.On("DeleteMember", context.Background(), collectionID, memberID).Return(nil)
+			},
+		},
+		{
+			name:   "should let member leave the collection",
+			userID: memberID,
+			err:    nil,
+			mockRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {
+				mockRepo.On("DeleteMember", context.Background(), collectionID, memberID).Return(nil)
+			},
+		},
+		{
+			name:   "should return err when viewer removes another member",
+			userID: test.TestUser.ID,
+			err:    validation.NewError(validation.Forbidden, "forbidden access"),
+			mockRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {
+				mockRepo.On("FindRole", context.Background(), collectionID, test.TestUser.ID).Return(collection.RoleViewer, nil)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.CollectionRepository)
+			c.mockRepoBehaviour(r)
+
+			s := NewService(logger.NewLogger(), r, new(mocks.UserRepository))
+			err := s.RemoveMember(context.Background(), c.userID, collectionID, memberID)
+			assert.Equal(t, c.err, err)
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package delivery
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/pagination"
+)
+
+const (
+	// codeAlphabet leaves out characters that are easily mistaken for each other on an e-reader screen.
+	codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	codeLength   = 8
+	// CodeTTL is how long a verification code can be used.
+	CodeTTL = 24 * time.Hour
+
+	// MaxAttachmentSize keeps deliveries under the message size most smtp servers and e-reader inboxes accept.
+	MaxAttachmentSize = 20 << 20
+)
+
+// Address is an inbox articles can be delivered to, like an e-reader's email address. It has to be verified
+// with the code sent to it before anything else is delivered.
+type Address struct {
+	ID     string `json:"id" gorm:"type:varchar"`
+	UserID string `json:"-" gorm:"type:varchar;not null;uniqueIndex:idx_delivery_addresses_user_email,priority:1"`
+	Email  string `json:"email" gorm:"type:varchar;not null;uniqueIndex:idx_delivery_addresses_user_email,priority:2"`
+	Label  string `json:"label" gorm:"type:varchar(100);not null;default:''"`
+	// CodeHash is the sha256 of the pending verification code, the code itself is never stored.
+	CodeHash      string     `json:"-" gorm:"type:varchar(64);not null;default:''"`
+	CodeExpiresAt *time.Time `json:"-" gorm:"type:timestamptz"`
+	VerifiedAt    *time.Time `json:"verified_at" gorm:"type:timestamptz"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"type:timestamptz;not null"`
+}
+
+func (Address) TableName() string {
+	return "delivery_addresses"
+}
+
+type AddressPayload struct {
+	Email string `json:"email" validate:"required,email"`
+	Label string `json:"label" validate:"max=100"`
+}
+
+type VerifyPayload struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// NewAddress creates an unverified address along with its verification code.
+func NewAddress(userID string, arg AddressPayload) (*Address, string, error) {
+	a := &Address{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		Email:     strings.ToLower(strings.TrimSpace(arg.Email)),
+		Label:     strings.TrimSpace(arg.Label),
+		CreatedAt: time.Now().UTC(),
+	}
+	code, err := a.ResetCode()
+	if err != nil {
+		return nil, "", err
+	}
+	return a, code, nil
+}
+
+// ResetCode replaces the pending verification code, the previous one can't be used anymore.
+func (a *Address) ResetCode() (string, error) {
+	b := make([]byte, codeLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	for i := range b {
+		b[i] = codeAlphabet[int(b[i])%len(codeAlphabet)]
+	}
+
+	code := string(b)
+	expiresAt := time.Now().UTC().Add(CodeTTL)
+	a.CodeHash = HashCode(code)
+	a.CodeExpiresAt = &expiresAt
+	return code, nil
+}
+
+// Verify marks the address as verified when the code matches the pending one.
+func (a *Address) Verify(code string, now time.Time) bool {
+	if a.CodeHash == "" || a.CodeExpiresAt == nil || !now.Before(*a.CodeExpiresAt) {
+		return false
+	}
+	if subtle.ConstantTimeCompare([]byte(HashCode(code)), []byte(a.CodeHash)) != 1 {
+		return false
+	}
+
+	verifiedAt := now.UTC()
+	a.VerifiedAt = &verifiedAt
+	a.CodeHash = ""
+	a.CodeExpiresAt = nil
+	return true
+}
+
+func (a *Address) IsVerified() bool {
+	return a.VerifiedAt != nil
+}
+
+// HashCode hashes the code as it's typed, ignoring case, spaces and dashes.
+func HashCode(code string) string {
+	normalized := strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+type Format string
+
+const (
+	FormatEPUB Format = "epub"
+	// FormatHTML sends the article inline in the email body.
+	FormatHTML Format = "html"
+)
+
+type Status string
+
+const (
+	// StatusQueued deliveries are waiting for the delivery job to build and send them.
+	StatusQueued Status = "queued"
+	StatusSent   Status = "sent"
+	StatusFailed Status = "failed"
+)
+
+// Delivery is an entry of the delivery log, the address and article are kept as they were when it was queued.
+type Delivery struct {
+	ID           string  `json:"id" gorm:"type:varchar"`
+	UserID       string  `json:"-" gorm:"type:varchar;not null;index"`
+	AddressID    *string `json:"address_id" gorm:"type:varchar;index"`
+	ArticleID    *string `json:"article_id" gorm:"type:varchar;index"`
+	Email        string  `json:"email" gorm:"type:varchar;not null"`
+	ArticleTitle string  `json:"article_title" gorm:"type:varchar;not null"`
+	Format       Format  `json:"format" gorm:"type:varchar;not null"`
+	Status       Status  `json:"status" gorm:"type:varchar;not null;index"`
+	// Error is why the delivery failed, smtp errors are kept out of it.
+	Error     string    `json:"error,omitempty" gorm:"type:varchar;not null;default:''"`
+	CreatedAt time.Time `json:"created_at" gorm:"type:timestamptz;not null"`
+
+	Address *Address         `json:"-" gorm:"constraint:OnDelete:SET NULL"`
+	Article *article.Article `json:"-" gorm:"constraint:OnDelete:SET NULL"`
+}
+
+func (Delivery) TableName() string {
+	return "deliveries"
+}
+
+type DeliveryPayload struct {
+	AddressID string `json:"address_id" validate:"required"`
+	Format    Format `json:"format" validate:"required,oneof=epub html"`
+}
+
+func NewDelivery(userID string, address *Address, a *article.Article, format Format) *Delivery {
+	return &Delivery{
+		ID:           uuid.NewString(),
+		UserID:       userID,
+		AddressID:    &address.ID,
+		ArticleID:    &a.ID,
+		Email:        address.Email,
+		ArticleTitle: a.Title,
+		Format:       format,
+		Status:       StatusQueued,
+		CreatedAt:    time.Now().UTC(),
+	}
+}
+
+func (d *Delivery) Sent() {
+	d.Status = StatusSent
+	d.Error = ""
+}
+
+func (d *Delivery) Fail(reason string) {
+	d.Status = StatusFailed
+	d.Error = reason
+}
+
+type DeliveryService interface {
+	ListAddresses(ctx context.Context, userID string) ([]*Address, error)
+	// AddAddress saves the address and sends its verification code to it.
+	AddAddress(ctx context.Context, userID string, arg AddressPayload) (*Address, error)
+	ResendVerification(ctx context.Context, userID, addressID string) error
+	VerifyAddress(ctx context.Context, userID, addressID string, arg VerifyPayload) (*Address, error)
+	DeleteAddress(ctx context.Context, userID, addressID string) error
+	// SendArticle queues the article to be emailed to a verified address, the delivery job builds and sends it.
+	SendArticle(ctx context.Context, userID, articleID string, arg DeliveryPayload) (*Delivery, error)
+	ListDeliveries(ctx context.Context, userID string, page pagination.Pagination) ([]*Delivery, *pagination.Meta, error)
+	// SendQueuedDeliveries sends up to limit queued deliveries, oldest first, and returns how many were sent.
+	SendQueuedDeliveries(ctx context.Context, limit int) (int, error)
+}
+
+type DeliveryRepository interface {
+	SaveAddress(ctx context.Context, arg Address) error
+	ListAddresses(ctx context.Context, userID string) ([]*Address, error)
+	FindAddress(ctx context.Context, userID, addressID string) (*Address, error)
+	// UpdateAddress saves the address' verification state.
+	UpdateAddress(ctx context.Context, arg Address) error
+	DeleteAddress(ctx context.Context, userID, addressID string) error
+	SaveDelivery(ctx context.Context, arg Delivery) error
+	// ListDeliveries returns the user's delivery log newest first.
+	ListDeliveries(ctx context.Context, userID string, page pagination.Pagination) (deliveries []*Delivery, total int64, err error)
+	// ListQueued returns the oldest queued deliveries along with their address and article, either is nil when
+	// it was deleted after the delivery was queued.
+	ListQueued(ctx context.Context, limit int) ([]*Delivery, error)
+	// UpdateDelivery saves the delivery's status and error.
+	UpdateDelivery(ctx context.Context, arg Delivery) error
+}
@@ -0,0 +1,100 @@
+package delivery
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAddress(t *testing.T) {
+	userID := uuid.NewString()
+
+	a, code, err := NewAddress(userID, AddressPayload{Email: " Reader@Kindle.com ", Label: " Kindle "})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, a.ID)
+	assert.Equal(t, userID, a.UserID)
+	assert.Equal(t, "reader@kindle.com", a.Email)
+	assert.Equal(t, "Kindle", a.Label)
+	assert.Len(t, code, codeLength)
+	for _, r := range code {
+		assert.True(t, strings.ContainsRune(codeAlphabet, r))
+	}
+	assert.Equal(t, HashCode(code), a.CodeHash)
+	assert.NotNil(t, a.CodeExpiresAt)
+	assert.False(t, a.IsVerified())
+	assert.NotEmpty(t, a.CreatedAt)
+}
+
+func TestVerify(t *testing.T) {
+	a, code, _ := NewAddress(uuid.NewString(), AddressPayload{Email: "reader@kindle.com"})
+	now := time.Now()
+	typed := strings.ToLower(code[:4] + "- " + code[4:])
+
+	cases := []struct {
+		name     string
+		code     string
+		now      time.Time
+		expected bool
+	}{
+		{
+			name:     "should not verify with wrong code",
+			code:     "AAAAAAAA",
+			now:      now,
+			expected: false,
+		},
+		{
+			name:     "should not verify with expired code",
+			code:     code,
+			now:      now.Add(CodeTTL + time.Minute),
+			expected: false,
+		},
+		{
+			name:     "should verify code as it's typed",
+			code:     typed,
+			now:      now,
+			expected: true,
+		},
+		{
+			name:     "should not verify with used code",
+			code:     code,
+			now:      now,
+			expected: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, a.Verify(c.code, c.now))
+		})
+	}
+	assert.True(t, a.IsVerified())
+	assert.Empty(t, a.CodeHash)
+	assert.Nil(t, a.CodeExpiresAt)
+}
+
+func TestNewDelivery(t *testing.T) {
+	address := &Address{ID: uuid.NewString(), Email: "reader@kindle.com"}
+	a := &article.Article{ID: uuid.NewString(), Title: "Title"}
+
+	d := NewDelivery(address.UserID, address, a, FormatEPUB)
+	assert.NotEmpty(t, d.ID)
+	assert.Equal(t, address.ID, *d.AddressID)
+	assert.Equal(t, a.ID, *d.ArticleID)
+	assert.Equal(t, address.Email, d.Email)
+	assert.Equal(t, a.Title, d.ArticleTitle)
+	assert.Equal(t, FormatEPUB, d.Format)
+	assert.Equal(t, StatusQueued, d.Status)
+	assert.NotEmpty(t, d.CreatedAt)
+
+	d.Fail("fail to send email")
+	assert.Equal(t, StatusFailed, d.Status)
+	assert.Equal(t, "fail to send email", d.Error)
+
+	d.Sent()
+	assert.Equal(t, StatusSent, d.Status)
+	assert.Empty(t, d.Error)
+}
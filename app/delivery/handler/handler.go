@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ryanadiputraa/unclatter/app/delivery"
+	"github.com/ryanadiputraa/unclatter/app/middleware"
+	"github.com/ryanadiputraa/unclatter/app/pagination"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	_http "github.com/ryanadiputraa/unclatter/pkg/http"
+	"github.com/ryanadiputraa/unclatter/pkg/validator"
+)
+
+type handler struct {
+	rw              _http.ResponseWriter
+	deliveryService delivery.DeliveryService
+	validator       validator.Validator
+}
+
+func NewHandler(web *http.ServeMux, rw _http.ResponseWriter, deliveryService delivery.DeliveryService, authMiddleware middleware.AuthMiddleware, validator validator.Validator) {
+	h := &handler{
+		rw:              rw,
+		deliveryService: deliveryService,
+		validator:       validator,
+	}
+
+	web.Handle("GET /api/delivery/addresses", authMiddleware.ParseJWTToken(h.ListAddresses()))
+	web.Handle("POST /api/delivery/addresses", authMiddleware.ParseJWTToken(h.AddAddress()))
+	web.Handle("POST /api/delivery/addresses/{id}/verify", authMiddleware.ParseJWTToken(h.VerifyAddress()))
+	web.Handle("POST /api/delivery/addresses/{id}/resend", authMiddleware.ParseJWTToken(h.ResendVerification()))
+	web.Handle("DELETE /api/delivery/addresses/{id}", authMiddleware.ParseJWTToken(h.DeleteAddress()))
+	web.Handle("POST /api/articles/bookmarks/{id}/deliveries", authMiddleware.ParseJWTToken(h.SendArticle()))
+	web.Handle("GET /api/deliveries", authMiddleware.ParseJWTToken(h.ListDeliveries()))
+}
+
+func (h *handler) ListAddresses() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		addresses, err := h.deliveryService.ListAddresses(ac.Context, ac.UserID)
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, addresses)
+	}
+}
+
+func (h *handler) AddAddress() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		var payload delivery.AddressPayload
+
+		json.NewDecoder(r.Body).Decode(&payload)
+		if err, errMap := h.validator.Validate(payload); err != nil {
+			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+			return
+		}
+
+		address, err := h.deliveryService.AddAddress(ac.Context, ac.UserID, payload)
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusCreated, address)
+	}
+}
+
+func (h *handler) VerifyAddress() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		var payload delivery.VerifyPayload
+
+		json.NewDecoder(r.Body).Decode(&payload)
+		if err, errMap := h.validator.Validate(payload); err != nil {
+			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+			return
+		}
+
+		address, err := h.deliveryService.VerifyAddress(ac.Context, ac.UserID, r.PathValue("id"), payload)
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, address)
+	}
+}
+
+func (h *handler) ResendVerification() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		if err := h.deliveryService.ResendVerification(ac.Context, ac.UserID, r.PathValue("id")); err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, nil)
+	}
+}
+
+func (h *handler) DeleteAddress() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		if err := h.deliveryService.DeleteAddress(ac.Context, ac.UserID, r.PathValue("id")); err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, nil)
+	}
+}
+
+func (h *handler) SendArticle() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		var payload delivery.DeliveryPayload
+
+		json.NewDecoder(r.Body).Decode(&payload)
+		if err, errMap := h.validator.Validate(payload); err != nil {
+			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+			return
+		}
+
+		d, err := h.deliveryService.SendArticle(ac.Context, ac.UserID, r.PathValue("id"), payload)
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		// the delivery is sent in the background, its status shows up in the delivery log
+		h.rw.WriteResponseData(w, http.StatusAccepted, d)
+	}
+}
+
+func (h *handler) ListDeliveries() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		query := r.URL.Query()
+
+		pagination, errMap, err := pagination.ValidateParam(query.Get("page"), query.Get("size"))
+		if err != nil {
+			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+			return
+		}
+
+		deliveries, meta, err := h.deliveryService.ListDeliveries(ac.Context, ac.UserID, *pagination)
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseDataWithPagination(w, http.StatusOK, deliveries, *meta)
+	}
+}
+
+func (h *handler) writeErr(w http.ResponseWriter, err error) {
+	if vErr, ok := err.(*validation.Error); ok {
+		h.rw.WriteErrMessage(w, validation.HttpErrMap[vErr.Err], vErr.Message)
+		return
+	}
+	h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+}
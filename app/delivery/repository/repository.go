@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ryanadiputraa/unclatter/app/delivery"
+	"github.com/ryanadiputraa/unclatter/app/pagination"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) delivery.DeliveryRepository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) SaveAddress(ctx context.Context, arg delivery.Address) error {
+	err := r.db.Create(&arg).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		err = validation.NewError(validation.Conflict, "this address is already added")
+	}
+	return err
+}
+
+func (r *repository) ListAddresses(ctx context.Context, userID string) (addresses []*delivery.Address, err error) {
+	err = r.db.Where("user_id = ?", userID).Order("created_at").Find(&addresses).Error
+	return
+}
+
+func (r *repository) FindAddress(ctx context.Context, userID, addressID string) (address *delivery.Address, err error) {
+	err = r.db.First(&address, "id = ? AND user_id = ?", addressID, userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, validation.NewError(validation.NotFound, "no delivery address found with given id")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (r *repository) UpdateAddress(ctx context.Context, arg delivery.Address) error {
+	res := r.db.Model(&delivery.Address{}).
+		Where("id = ? AND user_id = ?", arg.ID, arg.UserID).
+		Select("code_hash", "code_expires_at", "verified_at").
+		Updates(arg)
+	if res.RowsAffected == 0 && res.Error == nil {
+		return validation.NewError(validation.NotFound, "no delivery address found with given id")
+	}
+	return res.Error
+}
+
+func (r *repository) DeleteAddress(ctx context.Context, userID, addressID string) error {
+	res := r.db.Where("id = ? AND user_id = ?", addressID, userID).Delete(&delivery.Address{})
+	if res.RowsAffected == 0 && res.Error == nil {
+		return validation.NewError(validation.NotFound, "no delivery address found with given id")
+	}
+	return res.Error
+}
+
+func (r *repository) SaveDelivery(ctx context.Context, arg delivery.Delivery) error {
+	return r.db.Create(&arg).Error
+}
+
+func (r *repository) ListDeliveries(ctx context.Context, userID string, page pagination.Pagination) (deliveries []*delivery.Delivery, total int64, err error) {
+	err = r.db.Model(&delivery.Delivery{}).Where("user_id = ?", userID).Count(&total).Error
+	if err != nil {
+		return
+	}
+
+	err = r.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(page.Limit).
+		Offset(page.Offset).
+		Find(&deliveries).Error
+	return
+}
+
+func (r *repository) ListQueued(ctx context.Context, limit int) (deliveries []*delivery.Delivery, err error) {
+	err = r.db.Preload("Address").
+		Preload("Article").
+		Where("status = ?", delivery.StatusQueued).
+		Order("created_at").
+		Limit(limit).
+		Find(&deliveries).Error
+	return
+}
+
+func (r *repository) UpdateDelivery(ctx context.Context, arg delivery.Delivery) error {
+	return r.db.Model(&delivery.Delivery{}).
+		Where("id = ?", arg.ID).
+		Select("status", "error").
+		Updates(arg).Error
+}
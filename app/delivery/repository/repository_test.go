@@ -0,0 +1,269 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/ryanadiputraa/unclatter/app/delivery"
+	"github.com/ryanadiputraa/unclatter/app/pagination"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/test"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestSaveAddress(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	a, _, _ := delivery.NewAddress(test.TestUser.ID, delivery.AddressPayload{Email: "reader@kindle.com"})
+	expectedQuery := "^INSERT INTO \"delivery_addresses\""
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		err           error
+	}{
+		{
+			name: "should save address",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(expectedQuery).
+					WithArgs(a.ID, a.UserID, a.Email, a.Label, a.CodeHash, a.CodeExpiresAt, nil, a.CreatedAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			err: nil,
+		},
+		{
+			name: "should return conflict err when address is already added",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(expectedQuery).WillReturnError(gorm.ErrDuplicatedKey)
+				mock.ExpectRollback()
+			},
+			err: validation.NewError(validation.Conflict, "this address is already added"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+
+			err := r.SaveAddress(context.Background(), *a)
+			assert.Equal(t, c.err, err)
+		})
+	}
+}
+
+func TestFindAddress(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	addressID := uuid.NewString()
+	expectedQuery := "^SELECT \\* FROM \"delivery_addresses\" WHERE id = (.+) AND user_id = (.+) ORDER BY (.+) LIMIT (.+)$"
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		err           error
+	}{
+		{
+			name: "should return user's address",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(expectedQuery).
+					WithArgs(addressID, test.TestUser.ID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "email"}).AddRow(addressID, test.TestUser.ID, "reader@kindle.com"))
+			},
+			err: nil,
+		},
+		{
+			name: "should return not found err when address doesn't exist",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(expectedQuery).
+					WithArgs(addressID, test.TestUser.ID, 1).
+					WillReturnError(gorm.ErrRecordNotFound)
+			},
+			err: validation.NewError(validation.NotFound, "no delivery address found with given id"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+
+			a, err := r.FindAddress(context.Background(), test.TestUser.ID, addressID)
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				assert.Nil(t, a)
+				return
+			}
+			assert.Equal(t, addressID, a.ID)
+			assert.Equal(t, "reader@kindle.com", a.Email)
+		})
+	}
+}
+
+func TestUpdateAddress(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	a, _, _ := delivery.NewAddress(test.TestUser.ID, delivery.AddressPayload{Email: "reader@kindle.com"})
+	expectedQuery := "^UPDATE \"delivery_addresses\" SET \"code_hash\"=\\$1,\"code_expires_at\"=\\$2,\"verified_at\"=\\$3 WHERE id = \\$4 AND user_id = \\$5"
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		err           error
+	}{
+		{
+			name: "should update address verification state",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(expectedQuery).
+					WithArgs(a.CodeHash, a.CodeExpiresAt, nil, a.ID, a.UserID).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			err: nil,
+		},
+		{
+			name: "should return not found err when address doesn't exist",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(expectedQuery).
+					WithArgs(a.CodeHash, a.CodeExpiresAt, nil, a.ID, a.UserID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			err: validation.NewError(validation.NotFound, "no delivery address found with given id"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+
+			err := r.UpdateAddress(context.Background(), *a)
+			assert.Equal(t, c.err, err)
+		})
+	}
+}
+
+func TestDeleteAddress(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	addressID := uuid.NewString()
+	expectedQuery := "^DELETE FROM \"delivery_addresses\" WHERE id = (.+) AND user_id = (.+)"
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		err           error
+	}{
+		{
+			name: "should delete address",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(expectedQuery).
+					WithArgs(addressID, test.TestUser.ID).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			err: nil,
+		},
+		{
+			name: "should return not found err when address doesn't exist",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(expectedQuery).
+					WithArgs(addressID, test.TestUser.ID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			err: validation.NewError(validation.NotFound, "no delivery address found with given id"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+
+			err := r.DeleteAddress(context.Background(), test.TestUser.ID, addressID)
+			assert.Equal(t, c.err, err)
+		})
+	}
+}
+
+func TestListDeliveries(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	page := pagination.Pagination{Limit: 10, Offset: 10}
+	deliveryID := uuid.NewString()
+
+	mock.ExpectQuery("^SELECT count\\(\\*\\) FROM \"deliveries\" WHERE user_id = (.+)").
+		WithArgs(test.TestUser.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(11))
+	mock.ExpectQuery("^SELECT \\* FROM \"deliveries\" WHERE user_id = (.+) ORDER BY created_at DESC LIMIT (.+) OFFSET (.+)").
+		WithArgs(test.TestUser.ID, page.Limit, page.Offset).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "status"}).AddRow(deliveryID, "reader@kindle.com", delivery.StatusSent))
+
+	deliveries, total, err := r.ListDeliveries(context.Background(), test.TestUser.ID, page)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(11), total)
+	assert.Equal(t, []*delivery.Delivery{
+		{ID: deliveryID, Email: "reader@kindle.com", Status: delivery.StatusSent},
+	}, deliveries)
+}
+
+func TestListQueued(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	deliveryID := uuid.NewString()
+	addressID := uuid.NewString()
+
+	mock.ExpectQuery("^SELECT \\* FROM \"deliveries\" WHERE status = (.+) ORDER BY created_at LIMIT (.+)").
+		WithArgs(delivery.StatusQueued, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "address_id", "article_id", "status"}).
+			AddRow(deliveryID, addressID, test.TestArticle.ID, delivery.StatusQueued))
+	mock.ExpectQuery("^SELECT \\* FROM \"delivery_addresses\" WHERE \"delivery_addresses\".\"id\" = (.+)").
+		WithArgs(addressID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(addressID, "reader@kindle.com"))
+	mock.ExpectQuery("^SELECT \\* FROM \"articles\" WHERE \"articles\".\"id\" = (.+) AND \"articles\".\"deleted_at\" IS NULL").
+		WithArgs(test.TestArticle.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(test.TestArticle.ID, test.TestArticle.Title))
+
+	deliveries, err := r.ListQueued(context.Background(), 10)
+	assert.Nil(t, err)
+	assert.Len(t, deliveries, 1)
+	assert.Equal(t, "reader@kindle.com", deliveries[0].Address.Email)
+	assert.Equal(t, test.TestArticle.Title, deliveries[0].Article.Title)
+}
+
+func TestUpdateDelivery(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	d := delivery.Delivery{ID: uuid.NewString(), Status: delivery.StatusFailed, Error: "fail to send email"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("^UPDATE \"deliveries\" SET \"status\"=\\$1,\"error\"=\\$2 WHERE id = \\$3").
+		WithArgs(d.Status, d.Error, d.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := r.UpdateDelivery(context.Background(), d)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"time"
+
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/collection"
+	"github.com/ryanadiputraa/unclatter/app/delivery"
+	"github.com/ryanadiputraa/unclatter/app/export"
+	"github.com/ryanadiputraa/unclatter/app/pagination"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/pkg/epub"
+	"github.com/ryanadiputraa/unclatter/pkg/logger"
+	"github.com/ryanadiputraa/unclatter/pkg/mailer"
+	"github.com/ryanadiputraa/unclatter/pkg/sanitizer"
+)
+
+type service struct {
+	log                  logger.Logger
+	mailer               mailer.Mailer
+	sanitizer            sanitizer.Sanitizer
+	images               epub.ImageFetcher
+	repository           delivery.DeliveryRepository
+	articleRepository    article.ArticleRepository
+	collectionRepository collection.CollectionRepository
+}
+
+func NewService(log logger.Logger, mailer mailer.Mailer, sanitizer sanitizer.Sanitizer, images epub.ImageFetcher, repository delivery.DeliveryRepository, articleRepository article.ArticleRepository, collectionRepository collection.CollectionRepository) delivery.DeliveryService {
+	return &service{
+		log:                  log,
+		mailer:               mailer,
+		sanitizer:            sanitizer,
+		images:               images,
+		repository:           repository,
+		articleRepository:    articleRepository,
+		collectionRepository: collectionRepository,
+	}
+}
+
+func (s *service) ListAddresses(ctx context.Context, userID string) (addresses []*delivery.Address, err error) {
+	addresses, err = s.repository.ListAddresses(ctx, userID)
+	if err != nil {
+		s.log.Error("delivery service: fail to fetch user's addresses", err)
+	}
+	return
+}
+
+func (s *service) AddAddress(ctx context.Context, userID string, arg delivery.AddressPayload) (*delivery.Address, error) {
+	address, code, err := delivery.NewAddress(userID, arg)
+	if err != nil {
+		s.log.Error("delivery service: fail to generate verification code", err)
+		return nil, err
+	}
+	if err = s.repository.SaveAddress(ctx, *address); err != nil {
+		if _, ok := err.(*validation.Error); !ok {
+			s.log.Error("delivery service: fail to save address", err)
+		}
+		return nil, err
+	}
+
+	// an address whose code never arrived is removed so it can be added again
+	if err = s.sendCode(ctx, address, code); err != nil {
+		if dErr := s.repository.DeleteAddress(ctx, userID, address.ID); dErr != nil {
+			s.log.Error("delivery service: fail to delete unverifiable address", dErr)
+		}
+		return nil, err
+	}
+	return address, nil
+}
+
+func (s *service) ResendVerification(ctx context.Context, userID, addressID string) error {
+	address, err := s.findAddress(ctx, userID, addressID)
+	if err != nil {
+		return err
+	}
+	if address.IsVerified() {
+		return validation.NewError(validation.BadRequest, "address is already verified")
+	}
+
+	code, err := address.ResetCode()
+	if err != nil {
+		s.log.Error("delivery service: fail to generate verification code", err)
+		return err
+	}
+	if err = s.repository.UpdateAddress(ctx, *address); err != nil {
+		s.log.Error("delivery service: fail to update address", err)
+		return err
+	}
+	return s.sendCode(ctx, address, code)
+}
+
+func (s *service) VerifyAddress(ctx context.Context, userID, addressID string, arg delivery.VerifyPayload) (*delivery.Address, error) {
+	address, err := s.findAddress(ctx, userID, addressID)
+	if err != nil {
+		return nil, err
+	}
+	if address.IsVerified() {
+		return address, nil
+	}
+
+	if !address.Verify(arg.Code, time.Now()) {
+		return nil, validation.NewError(validation.BadRequest, "invalid or expired verification code")
+	}
+	if err = s.repository.UpdateAddress(ctx, *address); err != nil {
+		s.log.Error("delivery service: fail to verify address", err)
+		return nil, err
+	}
+	return address, nil
+}
+
+func (s *service) DeleteAddress(ctx context.Context, userID, addressID string) error {
+	if err := s.repository.DeleteAddress(ctx, userID, addressID); err != nil {
+		s.log.Warn("delivery service: fail to delete address ", addressID, " ", err)
+		return err
+	}
+	return nil
+}
+
+func (s *service) SendArticle(ctx context.Context, userID, articleID string, arg delivery.DeliveryPayload) (*delivery.Delivery, error) {
+	address, err := s.findAddress(ctx, userID, arg.AddressID)
+	if err != nil {
+		return nil, err
+	}
+	if !address.IsVerified() {
+		return nil, validation.NewError(validation.BadRequest, "delivery address isn't verified")
+	}
+	a, err := s.findArticle(ctx, userID, articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	d := delivery.NewDelivery(userID, address, a, arg.Format)
+	if err = s.repository.SaveDelivery(ctx, *d); err != nil {
+		s.log.Error("delivery service: fail to save delivery", err)
+		return nil, err
+	}
+	return d, nil
+}
+
+func (s *service) SendQueuedDeliveries(ctx context.Context, limit int) (int, error) {
+	deliveries, err := s.repository.ListQueued(ctx, limit)
+	if err != nil {
+		s.log.Error("delivery service: fail to fetch queued deliveries", err)
+		return 0, err
+	}
+
+	sent := 0
+	for _, d := range deliveries {
+		if ctx.Err() != nil {
+			return sent, ctx.Err()
+		}
+
+		s.send(ctx, d)
+		if err = s.repository.UpdateDelivery(ctx, *d); err != nil {
+			s.log.Error("delivery service: fail to update delivery", err)
+			return sent, err
+		}
+		if d.Status == delivery.StatusSent {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// send builds and emails the queued delivery, why it failed is kept on the delivery.
+func (s *service) send(ctx context.Context, d *delivery.Delivery) {
+	switch {
+	case d.Address == nil:
+		d.Fail("delivery address was deleted")
+		return
+	case d.Article == nil:
+		d.Fail("article was deleted")
+		return
+	}
+
+	msg, err := s.articleMessage(ctx, d.Address, d.Article, d.Format)
+	switch {
+	case errors.Is(err, errTooLarge):
+		d.Fail(err.Error())
+	case err != nil:
+		s.log.Error("delivery service: fail to build article email", err)
+		d.Fail("fail to build article")
+	default:
+		if err = s.mailer.Send(ctx, *msg); err != nil {
+			s.log.Error("delivery service: fail to send article email", err)
+			d.Fail("fail to send email")
+			return
+		}
+		d.Sent()
+	}
+}
+
+func (s *service) ListDeliveries(ctx context.Context, userID string, page pagination.Pagination) (deliveries []*delivery.Delivery, meta *pagination.Meta, err error) {
+	deliveries, total, err := s.repository.ListDeliveries(ctx, userID, page)
+	if err != nil {
+		s.log.Error("delivery service: fail to fetch user's deliveries", err)
+		return
+	}
+
+	meta = pagination.NewMeta(page, total)
+	return
+}
+
+var errTooLarge = errors.New("article is too large to be delivered")
+
+// articleMessage builds the email of the article in the given format.
+func (s *service) articleMessage(ctx context.Context, address *delivery.Address, a *article.Article, format delivery.Format) (*mailer.Message, error) {
+	content := s.sanitizer.Sanitize(a.Content)
+	msg := &mailer.Message{
+		To:      address.Email,
+		Subject: a.Title,
+		Text:    fmt.Sprintf("%s\n%s\n\nSent from Unclatter.\n", a.Title, a.ArticleLink),
+	}
+
+	switch format {
+	case delivery.FormatEPUB:
+		var buf bytes.Buffer
+		if err := export.WriteArticleBook(ctx, &buf, a, content, s.images); err != nil {
+			return nil, err
+		}
+		if buf.Len() > delivery.MaxAttachmentSize {
+			return nil, errTooLarge
+		}
+		msg.Attachments = []mailer.Attachment{{Filename: export.BookFile(a), ContentType: "application/epub+zip", Data: buf.Bytes()}}
+	default:
+		msg.HTML = export.HTMLDocument(a, content)
+		if len(msg.HTML) > delivery.MaxAttachmentSize {
+			return nil, errTooLarge
+		}
+	}
+	return msg, nil
+}
+
+// sendCode emails the verification code, it's attached as a document too since e-readers only show attachments.
+func (s *service) sendCode(ctx context.Context, address *delivery.Address, code string) error {
+	readable := code[:len(code)/2] + "-" + code[len(code)/2:]
+	text := fmt.Sprintf("Your Unclatter verification code is %s\n\nEnter it in Unclatter to start delivering articles to %s. "+
+		"The code expires in %d hours.\n", readable, address.Email, int(delivery.CodeTTL.Hours()))
+	document := fmt.Sprintf("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>Unclatter verification code</title>\n</head>\n"+
+		"<body>\n<h1>%s</h1>\n<p>%s</p>\n</body>\n</html>\n", readable, html.EscapeString(text))
+
+	err := s.mailer.Send(ctx, mailer.Message{
+		To:          address.Email,
+		Subject:     "Unclatter verification code " + readable,
+		Text:        text,
+		HTML:        document,
+		Attachments: []mailer.Attachment{{Filename: "unclatter-verification.html", ContentType: "text/html; charset=utf-8", Data: []byte(document)}},
+	})
+	if err != nil {
+		s.log.Error("delivery service: fail to send verification code", err)
+	}
+	return err
+}
+
+func (s *service) findAddress(ctx context.Context, userID, addressID string) (*delivery.Address, error) {
+	address, err := s.repository.FindAddress(ctx, userID, addressID)
+	if err != nil {
+		if _, ok := err.(*validation.Error); !ok {
+			s.log.Error("delivery service: fail to fetch address", err)
+		}
+		return nil, err
+	}
+	return address, nil
+}
+
+// findArticle returns the article when it's the user's or in a collection the user can view.
+func (s *service) findArticle(ctx context.Context, userID, articleID string) (*article.Article, error) {
+	a, err := s.articleRepository.FindByID(ctx, articleID)
+	if err != nil {
+		if _, ok := err.(*validation.Error); !ok {
+			s.log.Error("delivery service: fail to fetch article", err)
+		}
+		return nil, err
+	}
+	if a.UserID == userID {
+		return a, nil
+	}
+
+	forbidden := validation.NewError(validation.Forbidden, "forbidden access")
+	if a.CollectionID == nil {
+		return nil, forbidden
+	}
+	role, err := s.collectionRepository.FindRole(ctx, *a.CollectionID, userID)
+	if err != nil {
+		if vErr, ok := err.(*validation.Error); ok && vErr.Err == validation.NotFound {
+			return nil, forbidden
+		}
+		s.log.Error("delivery service: fail to fetch collection role", err)
+		return nil, err
+	}
+	if !role.Can(collection.RoleViewer) {
+		return nil, forbidden
+	}
+	return a, nil
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ryanadiputraa/unclatter/app/delivery"
+	"github.com/ryanadiputraa/unclatter/app/mocks"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/pkg/logger"
+	"github.com/ryanadiputraa/unclatter/pkg/mailer"
+	"github.com/ryanadiputraa/unclatter/pkg/sanitizer"
+	"github.com/ryanadiputraa/unclatter/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newService(m *mocks.Mailer, r *mocks.DeliveryRepository, articleRepo *mocks.ArticleRepository) delivery.DeliveryService {
+	return NewService(logger.NewLogger(), m, sanitizer.NewSanitizer(), new(mocks.ImageFetcher), r, articleRepo, new(mocks.CollectionRepository))
+}
+
+func TestAddAddress(t *testing.T) {
+	arg := delivery.AddressPayload{Email: "reader@kindle.com", Label: "Kindle"}
+
+	cases := []struct {
+		name                string
+		err                 error
+		mockRepoBehaviour   func(mockRepo *mocks.DeliveryRepository)
+		mockMailerBehaviour func(mockMailer *mocks.Mailer)
+	}{
+		{
+			name: "should add address and send its verification code",
+			err:  nil,
+			mockRepoBehaviour: func(mockRepo *mocks.DeliveryRepository) {
+				mockRepo.On("SaveAddress", context.Background(), mock.Anything).Return(nil)
+			},
+			mockMailerBehaviour: func(mockMailer *mocks.Mailer) {
+				mockMailer.On("Send", context.Background(), mock.MatchedBy(func(msg mailer.Message) bool {
+					return msg.To == arg.Email && len(msg.Attachments) == 1
+				})).Return(nil)
+			},
+		},
+		{
+			name: "should return conflict err when address is already added",
+			err:  validation.NewError(validation.Conflict, "this address is already added"),
+			mockRepoBehaviour: func(mockRepo *mocks.DeliveryRepository) {
+				mockRepo.On("SaveAddress", context.Background(), mock.Anything).
+					Return(validation.NewError(validation.Conflict, "this address is already added"))
+			},
+			mockMailerBehaviour: func(mockMailer *mocks.Mailer) {},
+		},
+		{
+			name: "should remove address when verification code can't be sent",
+			err:  errors.New("smtp: connection refused"),
+			mockRepoBehaviour: func(mockRepo *mocks.DeliveryRepository) {
+				mockRepo.On("SaveAddress", context.Background(), mock.Anything).Return(nil)
+				mockRepo.On("DeleteAddress", context.Background(), test.TestUser.ID, mock.Anything).Return(nil)
+			},
+			mockMailerBehaviour: func(mockMailer *mocks.Mailer) {
+				mockMailer.On("Send", context.Background(), mock.Anything).Return(errors.New("smtp: connection refused"))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.DeliveryRepository)
+			c.mockRepoBehaviour(r)
+			m := new(mocks.Mailer)
+			c.mockMailerBehaviour(m)
+
+			s := newService(m, r, new(mocks.ArticleRepository))
+			address, err := s.AddAddress(context.Background(), test.TestUser.ID, arg)
+			assert.Equal(t, c.err, err)
+			r.AssertExpectations(t)
+			m.AssertExpectations(t)
+			if err != nil {
+				assert.Nil(t, address)
+				return
+			}
+			assert.Equal(t, arg.Email, address.Email)
+			assert.False(t, address.IsVerified())
+		})
+	}
+}
+
+func TestVerifyAddress(t *testing.T) {
+	verifiedAt := time.Now().UTC()
+
+	cases := []struct {
+		name     string
+		code     string
+		verified bool
+		err      error
+	}{
+		{
+			name: "should verify address",
+			err:  nil,
+		},
+		{
+			name:     "should return verified address as it is",
+			code:     "AAAAAAAA",
+			verified: true,
+			err:      nil,
+		},
+		{
+			name: "should return err when code is wrong",
+			code: "AAAAAAAA",
+			err:  validation.NewError(validation.BadRequest, "invalid or expired verification code"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			address, code, _ := delivery.NewAddress(test.TestUser.ID, delivery.AddressPayload{Email: "reader@kindle.com"})
+			if c.verified {
+				address.VerifiedAt = &verifiedAt
+			}
+			if c.code != "" {
+				code = c.code
+			}
+			r := new(mocks.DeliveryRepository)
+			r.On("FindAddress", context.Background(), test.TestUser.ID, address.ID).Return(address, nil)
+			r.On("UpdateAddress", context.Background(), mock.Anything).Return(nil)
+
+			s := newService(new(mocks.Mailer), r, new(mocks.ArticleRepository))
+			verified, err := s.VerifyAddress(context.Background(), test.TestUser.ID, address.ID, delivery.VerifyPayload{Code: code})
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				assert.Nil(t, verified)
+				r.AssertNotCalled(t, "UpdateAddress", context.Background(), mock.Anything)
+				return
+			}
+			assert.True(t, verified.IsVerified())
+		})
+	}
+}
+
+func TestSendArticle(t *testing.T) {
+	verifiedAt := time.Now().UTC()
+	address := &delivery.Address{ID: uuid.NewString(), UserID: test.TestUser.ID, Email: "reader@kindle.com", VerifiedAt: &verifiedAt}
+	unverified := &delivery.Address{ID: uuid.NewString(), UserID: test.TestUser.ID, Email: "reader@kindle.com"}
+
+	cases := []struct {
+		name    string
+		address *delivery.Address
+		err     error
+	}{
+		{
+			name:    "should queue article delivery",
+			address: address,
+			err:     nil,
+		},
+		{
+			name:    "should return err when address isn't verified",
+			address: unverified,
+			err:     validation.NewError(validation.BadRequest, "delivery address isn't verified"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.DeliveryRepository)
+			r.On("FindAddress", context.Background(), test.TestUser.ID, c.address.ID).Return(c.address, nil)
+			r.On("SaveDelivery", context.Background(), mock.Anything).Return(nil)
+			articleRepo := new(mocks.ArticleRepository)
+			articleRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+			m := new(mocks.Mailer)
+
+			s := newService(m, r, articleRepo)
+			d, err := s.SendArticle(context.Background(), test.TestUser.ID, test.TestArticle.ID, delivery.DeliveryPayload{AddressID: c.address.ID, Format: delivery.FormatEPUB})
+			assert.Equal(t, c.err, err)
+			m.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+			if err != nil {
+				assert.Nil(t, d)
+				r.AssertNotCalled(t, "SaveDelivery", context.Background(), mock.Anything)
+				return
+			}
+			assert.Equal(t, delivery.StatusQueued, d.Status)
+			assert.Equal(t, test.TestArticle.Title, d.ArticleTitle)
+			r.AssertCalled(t, "SaveDelivery", context.Background(), *d)
+		})
+	}
+}
+
+func TestSendQueuedDeliveries(t *testing.T) {
+	verifiedAt := time.Now().UTC()
+	address := &delivery.Address{ID: uuid.NewString(), UserID: test.TestUser.ID, Email: "reader@kindle.com", VerifiedAt: &verifiedAt}
+
+	cases := []struct {
+		name                string
+		format              delivery.Format
+		address             *delivery.Address
+		status              delivery.Status
+		reason              string
+		mockMailerBehaviour func(mockMailer *mocks.Mailer)
+	}{
+		{
+			name:    "should send article as epub",
+			format:  delivery.FormatEPUB,
+			address: address,
+			status:  delivery.StatusSent,
+			mockMailerBehaviour: func(mockMailer *mocks.Mailer) {
+				mockMailer.On("Send", context.Background(), mock.MatchedBy(func(msg mailer.Message) bool {
+					return msg.To == address.Email && len(msg.Attachments) == 1 && isBook(msg.Attachments[0].Data)
+				})).Return(nil)
+			},
+		},
+		{
+			name:    "should send article as inline html",
+			format:  delivery.FormatHTML,
+			address: address,
+			status:  delivery.StatusSent,
+			mockMailerBehaviour: func(mockMailer *mocks.Mailer) {
+				mockMailer.On("Send", context.Background(), mock.MatchedBy(func(msg mailer.Message) bool {
+					return len(msg.Attachments) == 0 && strings.Contains(msg.HTML, "article content") && !strings.Contains(msg.HTML, "onblur")
+				})).Return(nil)
+			},
+		},
+		{
+			name:    "should mark delivery as failed when email can't be sent",
+			format:  delivery.FormatEPUB,
+			address: address,
+			status:  delivery.StatusFailed,
+			reason:  "fail to send email",
+			mockMailerBehaviour: func(mockMailer *mocks.Mailer) {
+				mockMailer.On("Send", context.Background(), mock.Anything).Return(errors.New("smtp: connection refused"))
+			},
+		},
+		{
+			name:                "should mark delivery as failed when address was deleted",
+			format:              delivery.FormatEPUB,
+			address:             nil,
+			status:              delivery.StatusFailed,
+			reason:              "delivery address was deleted",
+			mockMailerBehaviour: func(mockMailer *mocks.Mailer) {},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			queued := delivery.NewDelivery(test.TestUser.ID, address, test.TestArticle, c.format)
+			queued.Address = c.address
+			queued.Article = test.TestArticle
+
+			r := new(mocks.DeliveryRepository)
+			r.On("ListQueued", context.Background(), 10).Return([]*delivery.Delivery{queued}, nil)
+			r.On("UpdateDelivery", context.Background(), mock.MatchedBy(func(d delivery.Delivery) bool {
+				return d.ID == queued.ID && d.Status == c.status && d.Error == c.reason
+			})).Return(nil)
+			m := new(mocks.Mailer)
+			c.mockMailerBehaviour(m)
+
+			s := newService(m, r, new(mocks.ArticleRepository))
+			sent, err := s.SendQueuedDeliveries(context.Background(), 10)
+			assert.Nil(t, err)
+			assert.Equal(t, c.status == delivery.StatusSent, sent == 1)
+			m.AssertExpectations(t)
+			r.AssertExpectations(t)
+		})
+	}
+}
+
+func isBook(data []byte) bool {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil || len(zr.File) == 0 || zr.File[0].Name != "mimetype" {
+		return false
+	}
+	f, err := zr.File[0].Open()
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	b, _ := io.ReadAll(f)
+	return string(b) == "application/epub+zip"
+}
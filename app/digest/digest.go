@@ -0,0 +1,193 @@
+package digest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/pkg/plaintext"
+)
+
+const (
+	// MaxArticles is how many unread articles are listed in a single digest.
+	MaxArticles = 10
+	// excerptLength is the longest excerpt in runes.
+	excerptLength = 240
+)
+
+type Frequency string
+
+const (
+	FrequencyOff    Frequency = "off"
+	FrequencyDaily  Frequency = "daily"
+	FrequencyWeekly Frequency = "weekly"
+)
+
+// Preference is the user's digest schedule, digests are off until the user opts in.
+type Preference struct {
+	UserID    string    `json:"-" gorm:"type:varchar;primaryKey"`
+	Frequency Frequency `json:"frequency" gorm:"type:varchar;not null;default:'off'"`
+	// Hour is the hour of the day digests are sent at in the user's timezone.
+	Hour int `json:"hour" gorm:"type:smallint;not null"`
+	// Weekday is the day weekly digests are sent on, sunday is 0.
+	Weekday  int    `json:"weekday" gorm:"type:smallint;not null"`
+	Timezone string `json:"timezone" gorm:"type:varchar;not null;default:'UTC'"`
+	// UnsubscribeToken is linked from every digest so it's stored as it is, it can only turn digests off.
+	UnsubscribeToken string     `json:"-" gorm:"type:varchar;not null;uniqueIndex"`
+	NextSendAt       *time.Time `json:"next_send_at" gorm:"type:timestamptz;index"`
+	LastSentAt       *time.Time `json:"last_sent_at" gorm:"type:timestamptz"`
+	UpdatedAt        time.Time  `json:"updated_at" gorm:"type:timestamptz;not null"`
+}
+
+func (Preference) TableName() string {
+	return "digest_preferences"
+}
+
+type PreferencePayload struct {
+	Frequency Frequency `json:"frequency" validate:"required,oneof=off daily weekly"`
+	Hour      int       `json:"hour" validate:"min=0,max=23"`
+	Weekday   int       `json:"weekday" validate:"min=0,max=6"`
+	Timezone  string    `json:"timezone" validate:"required,max=64"`
+}
+
+// NewPreference returns the default preference of a user who hasn't opted in yet.
+func NewPreference(userID string) (*Preference, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	return &Preference{
+		UserID:           userID,
+		Frequency:        FrequencyOff,
+		Hour:             8,
+		Weekday:          int(time.Monday),
+		Timezone:         "UTC",
+		UnsubscribeToken: token,
+		UpdatedAt:        time.Now().UTC(),
+	}, nil
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Apply updates the schedule and reschedules the next digest.
+func (p *Preference) Apply(arg PreferencePayload, now time.Time) error {
+	// an empty name loads UTC and Local is the server's own zone, neither is what the user picked
+	if arg.Timezone == "Local" {
+		return validation.NewError(validation.BadRequest, "invalid timezone")
+	}
+	if _, err := time.LoadLocation(arg.Timezone); err != nil {
+		return validation.NewError(validation.BadRequest, "invalid timezone")
+	}
+
+	p.Frequency = arg.Frequency
+	p.Hour = arg.Hour
+	p.Weekday = arg.Weekday
+	p.Timezone = arg.Timezone
+	p.NextSendAt = p.Next(now)
+	p.UpdatedAt = now.UTC()
+	return nil
+}
+
+// Next returns when the digest after the given time is due, it's nil when digests are off.
+func (p *Preference) Next(after time.Time) *time.Time {
+	if p.Frequency != FrequencyDaily && p.Frequency != FrequencyWeekly {
+		return nil
+	}
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	local := after.In(loc)
+	days, step := 0, 1
+	if p.Frequency == FrequencyWeekly {
+		days, step = (p.Weekday-int(local.Weekday())+7)%7, 7
+	}
+	// time.Date normalizes the day overflow and daylight saving changes in the user's timezone
+	next := time.Date(local.Year(), local.Month(), local.Day()+days, p.Hour, 0, 0, 0, loc)
+	if !next.After(after) {
+		next = time.Date(local.Year(), local.Month(), local.Day()+days+step, p.Hour, 0, 0, 0, loc)
+	}
+	next = next.UTC()
+	return &next
+}
+
+// Article is an unread article as it's listed in the digest.
+type Article struct {
+	Title       string
+	Link        string
+	Domain      string
+	ReadingTime int
+	Excerpt     string
+}
+
+func NewArticle(a *article.Article) Article {
+	return Article{
+		Title:       a.Title,
+		Link:        a.ArticleLink,
+		Domain:      a.Domain,
+		ReadingTime: a.ReadingTime,
+		Excerpt:     Excerpt(a.Content),
+	}
+}
+
+// Excerpt returns the beginning of the sanitized html content as a single line of text, cut at a word boundary.
+func Excerpt(content string) string {
+	text := strings.Join(strings.Fields(plaintext.FromHTML(content)), " ")
+	runes := []rune(text)
+	if len(runes) <= excerptLength {
+		return text
+	}
+
+	cut := excerptLength
+	for i := excerptLength; i > excerptLength/2; i-- {
+		if unicode.IsSpace(runes[i]) {
+			cut = i
+			break
+		}
+	}
+	return strings.TrimRightFunc(string(runes[:cut]), func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	}) + "…"
+}
+
+// Digest is the content of a single digest email.
+type Digest struct {
+	Frequency Frequency
+	Articles  []Article
+	// Unread is the count of every unread article, not only the listed ones.
+	Unread         int64
+	AppURL         string
+	UnsubscribeURL string
+}
+
+type DigestService interface {
+	GetPreference(ctx context.Context, userID string) (*Preference, error)
+	UpdatePreference(ctx context.Context, userID string, arg PreferencePayload) (*Preference, error)
+	// Unsubscribe turns the digest of the token's owner off.
+	Unsubscribe(ctx context.Context, token string) error
+	// SendDueDigests sends up to limit digests that are due and returns how many were sent.
+	SendDueDigests(ctx context.Context, now time.Time, limit int) (int, error)
+}
+
+type DigestRepository interface {
+	FindPreference(ctx context.Context, userID string) (*Preference, error)
+	SavePreference(ctx context.Context, arg Preference) error
+	// Unsubscribe turns off the digest of the preference with the token.
+	Unsubscribe(ctx context.Context, token string, at time.Time) error
+	// ListDue returns the enabled preferences whose next digest is due, the most overdue first.
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*Preference, error)
+	// UpdateSchedule saves when the digest was last sent and when the next one is due.
+	UpdateSchedule(ctx context.Context, arg Preference) error
+}
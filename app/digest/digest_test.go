@@ -0,0 +1,153 @@
+package digest
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPreference(t *testing.T) {
+	userID := uuid.NewString()
+
+	p, err := NewPreference(userID)
+	assert.Nil(t, err)
+	assert.Equal(t, userID, p.UserID)
+	assert.Equal(t, FrequencyOff, p.Frequency)
+	assert.Equal(t, "UTC", p.Timezone)
+	assert.Len(t, p.UnsubscribeToken, 43)
+	assert.Nil(t, p.NextSendAt)
+
+	other, _ := NewPreference(userID)
+	assert.NotEqual(t, p.UnsubscribeToken, other.UnsubscribeToken)
+}
+
+func TestApply(t *testing.T) {
+	now := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name string
+		arg  PreferencePayload
+		next *time.Time
+		err  error
+	}{
+		{
+			name: "should schedule daily digest",
+			arg:  PreferencePayload{Frequency: FrequencyDaily, Hour: 8, Timezone: "Asia/Jakarta"},
+			next: test.Ptr(time.Date(2024, 3, 5, 1, 0, 0, 0, time.UTC)),
+			err:  nil,
+		},
+		{
+			name: "should not schedule when digest is off",
+			arg:  PreferencePayload{Frequency: FrequencyOff, Hour: 8, Timezone: "UTC"},
+			next: nil,
+			err:  nil,
+		},
+		{
+			name: "should return err when timezone is unknown",
+			arg:  PreferencePayload{Frequency: FrequencyDaily, Hour: 8, Timezone: "Mars/Olympus"},
+			err:  validation.NewError(validation.BadRequest, "invalid timezone"),
+		},
+		{
+			name: "should return err when timezone is the server's",
+			arg:  PreferencePayload{Frequency: FrequencyDaily, Hour: 8, Timezone: "Local"},
+			err:  validation.NewError(validation.BadRequest, "invalid timezone"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p, _ := NewPreference(uuid.NewString())
+
+			err := p.Apply(c.arg, now)
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.arg.Frequency, p.Frequency)
+			assert.Equal(t, c.arg.Timezone, p.Timezone)
+			assert.Equal(t, c.next, p.NextSendAt)
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	cases := []struct {
+		name       string
+		preference Preference
+		after      time.Time
+		expected   time.Time
+	}{
+		{
+			name:       "should be later today before the hour",
+			preference: Preference{Frequency: FrequencyDaily, Hour: 18, Timezone: "UTC"},
+			after:      time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC),
+			expected:   time.Date(2024, 3, 4, 18, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "should be tomorrow at the hour",
+			preference: Preference{Frequency: FrequencyDaily, Hour: 10, Timezone: "UTC"},
+			after:      time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC),
+			expected:   time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "should be on the weekday of this week",
+			preference: Preference{Frequency: FrequencyWeekly, Hour: 8, Weekday: int(time.Friday), Timezone: "UTC"},
+			after:      time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC),
+			expected:   time.Date(2024, 3, 8, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "should be on the weekday of next week once it passed",
+			preference: Preference{Frequency: FrequencyWeekly, Hour: 8, Weekday: int(time.Monday), Timezone: "UTC"},
+			after:      time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC),
+			expected:   time.Date(2024, 3, 11, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "should keep the local hour across daylight saving",
+			preference: Preference{Frequency: FrequencyDaily, Hour: 8, Timezone: "America/New_York"},
+			after:      time.Date(2024, 3, 9, 14, 0, 0, 0, time.UTC),
+			expected:   time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, *c.preference.Next(c.after))
+		})
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	assert.Equal(t, "Title text and a link.", Excerpt("<h1>Title</h1>\n<p>text and <a href=\"#\">a link</a>.</p>"))
+
+	long := Excerpt("<p>" + strings.Repeat("word, ", 100) + "</p>")
+	assert.True(t, strings.HasSuffix(long, "word…"))
+	assert.LessOrEqual(t, utf8.RuneCountInString(long), excerptLength+1)
+}
+
+func TestRender(t *testing.T) {
+	d := Digest{
+		Frequency: FrequencyWeekly,
+		Articles: []Article{
+			{Title: "<Title>", Link: "https://unclatter.com/a", Domain: "unclatter.com", ReadingTime: 1, Excerpt: "excerpt"},
+		},
+		Unread:         3,
+		AppURL:         "https://app.unclatter.com",
+		UnsubscribeURL: "https://api.unclatter.com/api/public/digest/unsubscribe/token",
+	}
+
+	text, html, err := Render(d)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(text, "You have 3 unread articles waiting in Unclatter.\n"))
+	assert.Contains(t, text, "<Title>\nunclatter.com · 1 min read\nexcerpt\nhttps://unclatter.com/a\n")
+	assert.Contains(t, text, "See the rest of your unread articles at https://app.unclatter.com")
+	assert.Contains(t, text, "Unsubscribe: "+d.UnsubscribeURL)
+	assert.Contains(t, html, "&lt;Title&gt;")
+	assert.Contains(t, html, `href="`+d.UnsubscribeURL+`"`)
+	assert.Equal(t, "Your weekly reading digest: 3 unread articles", Subject(d))
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/ryanadiputraa/unclatter/app/digest"
+	"github.com/ryanadiputraa/unclatter/app/middleware"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	_http "github.com/ryanadiputraa/unclatter/pkg/http"
+	"github.com/ryanadiputraa/unclatter/pkg/validator"
+)
+
+type handler struct {
+	rw            _http.ResponseWriter
+	digestService digest.DigestService
+	validator     validator.Validator
+}
+
+func NewHandler(web *http.ServeMux, rw _http.ResponseWriter, digestService digest.DigestService, authMiddleware middleware.AuthMiddleware, validator validator.Validator) {
+	h := &handler{
+		rw:            rw,
+		digestService: digestService,
+		validator:     validator,
+	}
+
+	web.Handle("GET /api/digest", authMiddleware.ParseJWTToken(h.GetPreference()))
+	web.Handle("PUT /api/digest", authMiddleware.ParseJWTToken(h.UpdatePreference()))
+	web.Handle("GET /api/public/digest/unsubscribe/{token}", h.UnsubscribePage())
+	web.Handle("POST /api/public/digest/unsubscribe/{token}", h.Unsubscribe())
+}
+
+func (h *handler) GetPreference() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		preference, err := h.digestService.GetPreference(ac.Context, ac.UserID)
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, preference)
+	}
+}
+
+func (h *handler) UpdatePreference() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		var payload digest.PreferencePayload
+
+		json.NewDecoder(r.Body).Decode(&payload)
+		if err, errMap := h.validator.Validate(payload); err != nil {
+			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+			return
+		}
+
+		preference, err := h.digestService.UpdatePreference(ac.Context, ac.UserID, payload)
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, preference)
+	}
+}
+
+// UnsubscribePage is opened from the digest's unsubscribe link, it asks the user to confirm.
+func (h *handler) UnsubscribePage() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.writePage(w, false)
+	}
+}
+
+// Unsubscribe is posted by the confirmation page and by mail clients supporting one-click unsubscribe.
+func (h *handler) Unsubscribe() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := h.digestService.Unsubscribe(r.Context(), r.PathValue("token")); err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.writePage(w, true)
+	}
+}
+
+func (h *handler) writePage(w http.ResponseWriter, done bool) {
+	page, err := digest.UnsubscribePage(done)
+	if err != nil {
+		h.writeErr(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, page)
+}
+
+func (h *handler) writeErr(w http.ResponseWriter, err error) {
+	if vErr, ok := err.(*validation.Error); ok {
+		h.rw.WriteErrMessage(w, validation.HttpErrMap[vErr.Err], vErr.Message)
+		return
+	}
+	h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+}
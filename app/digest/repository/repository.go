@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ryanadiputraa/unclatter/app/digest"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) digest.DigestRepository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) FindPreference(ctx context.Context, userID string) (preference *digest.Preference, err error) {
+	err = r.db.First(&preference, "user_id = ?", userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, validation.NewError(validation.NotFound, "no digest preference found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (r *repository) SavePreference(ctx context.Context, arg digest.Preference) error {
+	// the unsubscribe token and the last sent time are kept when the preference already exists
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"frequency", "hour", "weekday", "timezone", "next_send_at", "updated_at"}),
+	}).Create(&arg).Error
+}
+
+func (r *repository) Unsubscribe(ctx context.Context, token string, at time.Time) error {
+	res := r.db.Model(&digest.Preference{}).
+		Where("unsubscribe_token = ?", token).
+		Updates(map[string]any{"frequency": digest.FrequencyOff, "next_send_at": nil, "updated_at": at})
+	if res.RowsAffected == 0 && res.Error == nil {
+		return validation.NewError(validation.NotFound, "no digest subscription found with given token")
+	}
+	return res.Error
+}
+
+func (r *repository) ListDue(ctx context.Context, now time.Time, limit int) (preferences []*digest.Preference, err error) {
+	err = r.db.Where("frequency <> ? AND next_send_at <= ?", digest.FrequencyOff, now).
+		Order("next_send_at").
+		Limit(limit).
+		Find(&preferences).Error
+	return
+}
+
+func (r *repository) UpdateSchedule(ctx context.Context, arg digest.Preference) error {
+	// the schedule is only moved forward, a preference updated in the meantime already has its own
+	return r.db.Model(&digest.Preference{}).
+		Where("user_id = ? AND updated_at <= ?", arg.UserID, arg.UpdatedAt).
+		UpdateColumns(map[string]any{"last_sent_at": arg.LastSentAt, "next_send_at": arg.NextSendAt}).Error
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ryanadiputraa/unclatter/app/digest"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/test"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestFindPreference(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	expectedQuery := "^SELECT \\* FROM \"digest_preferences\" WHERE user_id = (.+) ORDER BY (.+) LIMIT (.+)$"
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		err           error
+	}{
+		{
+			name: "should return user's preference",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(expectedQuery).
+					WithArgs(test.TestUser.ID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id", "frequency", "timezone"}).AddRow(test.TestUser.ID, digest.FrequencyDaily, "UTC"))
+			},
+			err: nil,
+		},
+		{
+			name: "should return not found err when user has no preference",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(expectedQuery).
+					WithArgs(test.TestUser.ID, 1).
+					WillReturnError(gorm.ErrRecordNotFound)
+			},
+			err: validation.NewError(validation.NotFound, "no digest preference found"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+
+			p, err := r.FindPreference(context.Background(), test.TestUser.ID)
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				assert.Nil(t, p)
+				return
+			}
+			assert.Equal(t, digest.FrequencyDaily, p.Frequency)
+		})
+	}
+}
+
+func TestSavePreference(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	p, _ := digest.NewPreference(test.TestUser.ID)
+	p.Apply(digest.PreferencePayload{Frequency: digest.FrequencyDaily, Hour: 8, Timezone: "UTC"}, time.Now())
+
+	mock.ExpectBegin()
+	mock.ExpectExec("^INSERT INTO \"digest_preferences\" (.+) ON CONFLICT \\(\"user_id\"\\) DO UPDATE SET \"frequency\"=\"excluded\".\"frequency\",\"hour\"=\"excluded\".\"hour\",\"weekday\"=\"excluded\".\"weekday\",\"timezone\"=\"excluded\".\"timezone\",\"next_send_at\"=\"excluded\".\"next_send_at\",\"updated_at\"=\"excluded\".\"updated_at\"").
+		WithArgs(p.UserID, p.Frequency, p.Hour, p.Weekday, p.Timezone, p.UnsubscribeToken, p.NextSendAt, nil, p.UpdatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := r.SavePreference(context.Background(), *p)
+	assert.Nil(t, err)
+}
+
+func TestUnsubscribe(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	now := time.Now().UTC()
+	expectedQuery := "^UPDATE \"digest_preferences\" SET \"frequency\"=\\$1,\"next_send_at\"=\\$2,\"updated_at\"=\\$3 WHERE unsubscribe_token = \\$4"
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		err           error
+	}{
+		{
+			name: "should turn digest off",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(expectedQuery).
+					WithArgs(digest.FrequencyOff, nil, now, "token").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			err: nil,
+		},
+		{
+			name: "should return not found err when token doesn't exist",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(expectedQuery).
+					WithArgs(digest.FrequencyOff, nil, now, "token").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			err: validation.NewError(validation.NotFound, "no digest subscription found with given token"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+
+			err := r.Unsubscribe(context.Background(), "token", now)
+			assert.Equal(t, c.err, err)
+		})
+	}
+}
+
+func TestListDue(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	now := time.Now().UTC()
+
+	mock.ExpectQuery("^SELECT \\* FROM \"digest_preferences\" WHERE frequency <> (.+) AND next_send_at <= (.+) ORDER BY next_send_at LIMIT (.+)").
+		WithArgs(digest.FrequencyOff, now, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "frequency"}).AddRow(test.TestUser.ID, digest.FrequencyWeekly))
+
+	preferences, err := r.ListDue(context.Background(), now, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []*digest.Preference{{UserID: test.TestUser.ID, Frequency: digest.FrequencyWeekly}}, preferences)
+}
+
+func TestUpdateSchedule(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	now := time.Now().UTC()
+	next := now.Add(24 * time.Hour)
+	p := digest.Preference{UserID: test.TestUser.ID, LastSentAt: &now, NextSendAt: &next, UpdatedAt: now.Add(-time.Hour)}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("^UPDATE \"digest_preferences\" SET \"last_sent_at\"=\\$1,\"next_send_at\"=\\$2 WHERE user_id = \\$3 AND updated_at <= \\$4").
+		WithArgs(p.LastSentAt, p.NextSendAt, p.UserID, p.UpdatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := r.UpdateSchedule(context.Background(), p)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/digest"
+	"github.com/ryanadiputraa/unclatter/app/pagination"
+	"github.com/ryanadiputraa/unclatter/app/user"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/pkg/logger"
+	"github.com/ryanadiputraa/unclatter/pkg/mailer"
+)
+
+type service struct {
+	log               logger.Logger
+	mailer            mailer.Mailer
+	repository        digest.DigestRepository
+	articleRepository article.ArticleRepository
+	userRepository    user.UserRepository
+	// apiURL is where unsubscribe links point to and appURL is the frontend digests link to.
+	apiURL string
+	appURL string
+}
+
+func NewService(log logger.Logger, mailer mailer.Mailer, repository digest.DigestRepository, articleRepository article.ArticleRepository, userRepository user.UserRepository, apiURL, appURL string) digest.DigestService {
+	return &service{
+		log:               log,
+		mailer:            mailer,
+		repository:        repository,
+		articleRepository: articleRepository,
+		userRepository:    userRepository,
+		apiURL:            apiURL,
+		appURL:            appURL,
+	}
+}
+
+func (s *service) GetPreference(ctx context.Context, userID string) (*digest.Preference, error) {
+	return s.findPreference(ctx, userID)
+}
+
+func (s *service) UpdatePreference(ctx context.Context, userID string, arg digest.PreferencePayload) (*digest.Preference, error) {
+	preference, err := s.findPreference(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err = preference.Apply(arg, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err = s.repository.SavePreference(ctx, *preference); err != nil {
+		s.log.Error("digest service: fail to save preference", err)
+		return nil, err
+	}
+	return preference, nil
+}
+
+func (s *service) Unsubscribe(ctx context.Context, token string) error {
+	if err := s.repository.Unsubscribe(ctx, token, time.Now().UTC()); err != nil {
+		if _, ok := err.(*validation.Error); !ok {
+			s.log.Error("digest service: fail to unsubscribe", err)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *service) SendDueDigests(ctx context.Context, now time.Time, limit int) (int, error) {
+	preferences, err := s.repository.ListDue(ctx, now, limit)
+	if err != nil {
+		s.log.Error("digest service: fail to fetch due digests", err)
+		return 0, err
+	}
+
+	sent := 0
+	for _, p := range preferences {
+		if ctx.Err() != nil {
+			return sent, ctx.Err()
+		}
+
+		// a digest that fails isn't retried, the next one is scheduled either way so a bad address or an empty
+		// inbox doesn't get picked up on every run
+		ok, err := s.sendDigest(ctx, p)
+		if err != nil {
+			s.log.Warn("digest service: fail to send digest to user ", p.UserID, " ", err)
+		}
+		if ok {
+			sentAt := now.UTC()
+			p.LastSentAt = &sentAt
+			sent++
+		}
+		p.NextSendAt = p.Next(now)
+		if err = s.repository.UpdateSchedule(ctx, *p); err != nil {
+			s.log.Error("digest service: fail to schedule next digest", err)
+			return sent, err
+		}
+	}
+	return sent, nil
+}
+
+// sendDigest emails the user's unread articles, nothing is sent when there's nothing left to read.
+func (s *service) sendDigest(ctx context.Context, p *digest.Preference) (bool, error) {
+	u, err := s.userRepository.FindByID(ctx, p.UserID)
+	if err != nil {
+		return false, err
+	}
+	articles, total, err := s.articleRepository.List(ctx, p.UserID, article.ListFilter{
+		Status: article.FilterUnread,
+		Sort:   article.SortCreatedAt,
+	}, pagination.Pagination{Limit: digest.MaxArticles})
+	if err != nil || total == 0 {
+		return false, err
+	}
+
+	d := digest.Digest{
+		Frequency:      p.Frequency,
+		Unread:         total,
+		AppURL:         s.appURL,
+		UnsubscribeURL: fmt.Sprintf("%s/api/public/digest/unsubscribe/%s", s.apiURL, p.UnsubscribeToken),
+	}
+	for _, a := range articles {
+		// the list leaves the content out, it's only needed for the excerpt
+		full, err := s.articleRepository.FindByID(ctx, a.ID)
+		if err != nil {
+			if _, ok := err.(*validation.Error); ok {
+				continue
+			}
+			return false, err
+		}
+		d.Articles = append(d.Articles, digest.NewArticle(full))
+	}
+
+	text, html, err := digest.Render(d)
+	if err != nil {
+		return false, err
+	}
+	err = s.mailer.Send(ctx, mailer.Message{
+		To:      u.Email,
+		Subject: digest.Subject(d),
+		Text:    text,
+		HTML:    html,
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + d.UnsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// findPreference returns the stored preference or the default one when the user hasn't set it yet.
+func (s *service) findPreference(ctx context.Context, userID string) (*digest.Preference, error) {
+	preference, err := s.repository.FindPreference(ctx, userID)
+	if err == nil {
+		return preference, nil
+	}
+	if vErr, ok := err.(*validation.Error); !ok || vErr.Err != validation.NotFound {
+		s.log.Error("digest service: fail to fetch preference", err)
+		return nil, err
+	}
+
+	preference, err = digest.NewPreference(userID)
+	if err != nil {
+		s.log.Error("digest service: fail to generate unsubscribe token", err)
+		return nil, err
+	}
+	return preference, nil
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/digest"
+	"github.com/ryanadiputraa/unclatter/app/mocks"
+	"github.com/ryanadiputraa/unclatter/app/pagination"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/pkg/logger"
+	"github.com/ryanadiputraa/unclatter/pkg/mailer"
+	"github.com/ryanadiputraa/unclatter/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	apiURL = "https://api.unclatter.com"
+	appURL = "https://unclatter.com"
+)
+
+func TestUpdatePreference(t *testing.T) {
+	arg := digest.PreferencePayload{Frequency: digest.FrequencyDaily, Hour: 7, Timezone: "Europe/Berlin"}
+	stored, _ := digest.NewPreference(test.TestUser.ID)
+
+	cases := []struct {
+		name              string
+		arg               digest.PreferencePayload
+		err               error
+		mockRepoBehaviour func(mockRepo *mocks.DigestRepository)
+	}{
+		{
+			name: "should opt in with the default preference",
+			arg:  arg,
+			err:  nil,
+			mockRepoBehaviour: func(mockRepo *mocks.DigestRepository) {
+				mockRepo.On("FindPreference", context.Background(), test.TestUser.ID).
+					Return(nil, validation.NewError(validation.NotFound, "no digest preference found"))
+				mockRepo.On("SavePreference", context.Background(), mock.MatchedBy(func(p digest.Preference) bool {
+					return p.UnsubscribeToken != "" && p.NextSendAt != nil
+				})).Return(nil)
+			},
+		},
+		{
+			name: "should keep unsubscribe token of stored preference",
+			arg:  arg,
+			err:  nil,
+			mockRepoBehaviour: func(mockRepo *mocks.DigestRepository) {
+				mockRepo.On("FindPreference", context.Background(), test.TestUser.ID).Return(stored, nil)
+				mockRepo.On("SavePreference", context.Background(), mock.MatchedBy(func(p digest.Preference) bool {
+					return p.UnsubscribeToken == stored.UnsubscribeToken
+				})).Return(nil)
+			},
+		},
+		{
+			name: "should return err when timezone is unknown",
+			arg:  digest.PreferencePayload{Frequency: digest.FrequencyDaily, Timezone: "Mars/Olympus"},
+			err:  validation.NewError(validation.BadRequest, "invalid timezone"),
+			mockRepoBehaviour: func(mockRepo *mocks.DigestRepository) {
+				mockRepo.On("FindPreference", context.Background(), test.TestUser.ID).Return(stored, nil)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.DigestRepository)
+			c.mockRepoBehaviour(r)
+
+			s := NewService(logger.NewLogger(), new(mocks.Mailer), r, new(mocks.ArticleRepository), new(mocks.UserRepository), apiURL, appURL)
+			p, err := s.UpdatePreference(context.Background(), test.TestUser.ID, c.arg)
+			assert.Equal(t, c.err, err)
+			r.AssertExpectations(t)
+			if err != nil {
+				assert.Nil(t, p)
+				return
+			}
+			assert.Equal(t, digest.FrequencyDaily, p.Frequency)
+			assert.Equal(t, 7, p.Hour)
+		})
+	}
+}
+
+func TestSendDueDigests(t *testing.T) {
+	now := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+	filter := article.ListFilter{Status: article.FilterUnread, Sort: article.SortCreatedAt}
+	page := pagination.Pagination{Limit: digest.MaxArticles}
+
+	cases := []struct {
+		name                 string
+		sent                 int
+		mockArticleBehaviour func(mockRepo *mocks.ArticleRepository)
+		mockMailerBehaviour  func(mockMailer *mocks.Mailer)
+	}{
+		{
+			name: "should send digest of unread articles",
+			sent: 1,
+			mockArticleBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("List", context.Background(), test.TestUser.ID, filter, page).
+					Return([]*article.Article{{ID: test.TestArticle.ID, Title: test.TestArticle.Title}}, int64(3), nil)
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+			},
+			mockMailerBehaviour: func(mockMailer *mocks.Mailer) {
+				mockMailer.On("Send", context.Background(), mock.MatchedBy(func(msg mailer.Message) bool {
+					return msg.To == test.TestUser.Email &&
+						msg.Subject == "Your daily reading digest: 3 unread articles" &&
+						strings.Contains(msg.Text, "Google article content") &&
+						msg.Headers["List-Unsubscribe"] == "<"+apiURL+"/api/public/digest/unsubscribe/token>" &&
+						msg.Headers["List-Unsubscribe-Post"] == "List-Unsubscribe=One-Click"
+				})).Return(nil)
+			},
+		},
+		{
+			name: "should skip digest when there's nothing unread",
+			sent: 0,
+			mockArticleBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("List", context.Background(), test.TestUser.ID, filter, page).Return([]*article.Article{}, int64(0), nil)
+			},
+			mockMailerBehaviour: func(mockMailer *mocks.Mailer) {},
+		},
+		{
+			name: "should schedule next digest when email can't be sent",
+			sent: 0,
+			mockArticleBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("List", context.Background(), test.TestUser.ID, filter, page).
+					Return([]*article.Article{{ID: test.TestArticle.ID}}, int64(1), nil)
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+			},
+			mockMailerBehaviour: func(mockMailer *mocks.Mailer) {
+				mockMailer.On("Send", context.Background(), mock.Anything).Return(errors.New("smtp: connection refused"))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			due := now.Add(-time.Minute)
+			p := &digest.Preference{UserID: test.TestUser.ID, Frequency: digest.FrequencyDaily, Hour: 8, Timezone: "UTC", UnsubscribeToken: "token", NextSendAt: &due}
+			next := time.Date(2024, 3, 5, 8, 0, 0, 0, time.UTC)
+
+			r := new(mocks.DigestRepository)
+			r.On("ListDue", context.Background(), now, 10).Return([]*digest.Preference{p}, nil)
+			r.On("UpdateSchedule", context.Background(), mock.MatchedBy(func(p digest.Preference) bool {
+				return p.NextSendAt.Equal(next) && (p.LastSentAt != nil) == (c.sent > 0)
+			})).Return(nil)
+			userRepo := new(mocks.UserRepository)
+			userRepo.On("FindByID", context.Background(), test.TestUser.ID).Return(test.TestUser, nil)
+			articleRepo := new(mocks.ArticleRepository)
+			c.mockArticleBehaviour(articleRepo)
+			m := new(mocks.Mailer)
+			c.mockMailerBehaviour(m)
+
+			s := NewService(logger.NewLogger(), m, r, articleRepo, userRepo, apiURL, appURL)
+			sent, err := s.SendDueDigests(context.Background(), now, 10)
+			assert.Nil(t, err)
+			assert.Equal(t, c.sent, sent)
+			r.AssertExpectations(t)
+			m.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package digest
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+func plural(n int64, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
+
+var funcs = map[string]any{
+	"plural": plural,
+	"int64": func(n int) int64 {
+		return int64(n)
+	},
+}
+
+var textTemplate = texttemplate.Must(texttemplate.New("digest").Funcs(funcs).Parse(strings.TrimLeft(`
+You have {{.Unread}} unread {{plural .Unread "article"}} waiting in Unclatter.
+{{range .Articles}}
+{{.Title}}
+{{.Domain}} · {{.ReadingTime}} min read
+{{- if .Excerpt}}
+{{.Excerpt}}
+{{- end}}
+{{.Link}}
+{{end}}
+{{- if gt .Unread (int64 (len .Articles))}}
+See the rest of your unread articles at {{.AppURL}}
+{{end}}
+--
+You're receiving this {{.Frequency}} digest because you turned it on in Unclatter.
+Unsubscribe: {{.UnsubscribeURL}}
+`, "\n")))
+
+var htmlTemplate = htmltemplate.Must(htmltemplate.New("digest").Funcs(funcs).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Your reading digest</title>
+</head>
+<body style="margin:0;padding:24px;background:#f6f6f4;font-family:Georgia,serif;color:#1f1f1f">
+<div style="max-width:600px;margin:0 auto;background:#ffffff;padding:24px">
+<p style="font-size:18px;margin:0 0 24px">You have <strong>{{.Unread}}</strong> unread {{plural .Unread "article"}} waiting in Unclatter.</p>
+{{- range .Articles}}
+<div style="margin:0 0 24px">
+<a href="{{.Link}}" style="font-size:20px;color:#1f1f1f;text-decoration:none;font-weight:bold">{{.Title}}</a>
+<p style="margin:4px 0;font-family:Helvetica,Arial,sans-serif;font-size:13px;color:#6b6b6b">{{.Domain}} · {{.ReadingTime}} min read</p>
+{{- if .Excerpt}}
+<p style="margin:4px 0;font-size:15px;line-height:1.5">{{.Excerpt}}</p>
+{{- end}}
+</div>
+{{- end}}
+{{- if gt .Unread (int64 (len .Articles))}}
+<p style="margin:0 0 24px"><a href="{{.AppURL}}" style="color:#1f1f1f">See the rest of your unread articles</a></p>
+{{- end}}
+<p style="margin:24px 0 0;font-family:Helvetica,Arial,sans-serif;font-size:12px;color:#6b6b6b">You're receiving this {{.Frequency}} digest because you turned it on in Unclatter. <a href="{{.UnsubscribeURL}}" style="color:#6b6b6b">Unsubscribe</a></p>
+</div>
+</body>
+</html>
+`))
+
+// Render returns the plain text and html bodies of the digest email.
+func Render(d Digest) (text, html string, err error) {
+	var buf bytes.Buffer
+	if err = textTemplate.Execute(&buf, d); err != nil {
+		return
+	}
+	text = buf.String()
+
+	buf.Reset()
+	if err = htmlTemplate.Execute(&buf, d); err != nil {
+		return
+	}
+	html = buf.String()
+	return
+}
+
+// Subject is the subject of the digest email.
+func Subject(d Digest) string {
+	return fmt.Sprintf("Your %s reading digest: %d unread %s", d.Frequency, d.Unread, plural(d.Unread, "article"))
+}
+
+var unsubscribeTemplate = htmltemplate.Must(htmltemplate.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Unsubscribe from reading digests</title>
+</head>
+<body style="padding:24px;font-family:Helvetica,Arial,sans-serif;color:#1f1f1f">
+{{- if .Done}}
+<p>You're unsubscribed, Unclatter won't send you reading digests anymore.</p>
+{{- else}}
+<form method="post">
+<p>Stop receiving reading digests from Unclatter?</p>
+<button type="submit">Unsubscribe</button>
+</form>
+{{- end}}
+</body>
+</html>
+`))
+
+// UnsubscribePage renders the page unsubscribe links open, done is whether the user is already unsubscribed.
+// Links only show a confirmation form so mail scanners opening them don't unsubscribe the user.
+func UnsubscribePage(done bool) (string, error) {
+	var buf bytes.Buffer
+	err := unsubscribeTemplate.Execute(&buf, struct{ Done bool }{done})
+	return buf.String(), err
+}
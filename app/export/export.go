@@ -0,0 +1,329 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/collection"
+	"github.com/ryanadiputraa/unclatter/pkg/epub"
+)
+
+const (
+	ManifestFile  = "manifest.json"
+	BookmarksFile = "bookmarks.html"
+	articlesDir   = "articles/"
+	maxSlugLength = 60
+
+	// MaxBookArticles caps the chapters of a collection's book, larger collections are better exported
+	// as an archive.
+	MaxBookArticles = 200
+)
+
+// Scope selects the articles to export, either the user's whole library or a single collection.
+type Scope struct {
+	UserID string
+	// CollectionID exports every article of the collection, including the ones added by its other members.
+	CollectionID string
+}
+
+// Manifest describes the archive, its articles are written one at a time so the manifest is never held
+// in memory as a whole.
+type Manifest struct {
+	ExportedAt   time.Time `json:"exported_at"`
+	CollectionID string    `json:"collection_id,omitempty"`
+}
+
+// ManifestArticle is the manifest record of an article, content is left out since it's in the article files.
+type ManifestArticle struct {
+	*article.Article
+	Files Files `json:"files"`
+}
+
+// Files are the paths of the article's documents inside the archive.
+type Files struct {
+	HTML     string `json:"html"`
+	Markdown string `json:"markdown"`
+}
+
+func NewFiles(a *article.Article) Files {
+	name := articlesDir + slug(a.Title) + "-" + shortID(a.ID)
+	return Files{
+		HTML:     name + ".html",
+		Markdown: name + ".md",
+	}
+}
+
+// BookFile names the article's EPUB after its title.
+func BookFile(a *article.Article) string {
+	return slug(a.Title) + "-" + shortID(a.ID) + ".epub"
+}
+
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+// slug makes a file name out of the title, anything but letters and digits is collapsed into a dash.
+func slug(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteRune('-')
+			}
+			dash = false
+			b.WriteRune(r)
+		} else {
+			dash = true
+		}
+		if b.Len() >= maxSlugLength {
+			break
+		}
+	}
+	if b.Len() == 0 {
+		return "article"
+	}
+	return b.String()
+}
+
+// HTMLDocument wraps the sanitized article content in a standalone page.
+func HTMLDocument(a *article.Article, content string) string {
+	title := html.EscapeString(a.Title)
+	return "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>" + title + "</title>\n</head>\n<body>\n" +
+		"<h1>" + title + "</h1>\n<p><a href=\"" + html.EscapeString(a.ArticleLink) + "\">" + html.EscapeString(a.ArticleLink) + "</a></p>\n" +
+		content + "\n</body>\n</html>\n"
+}
+
+// MarkdownDocument prefixes the converted content with a yaml front matter holding the article's metadata.
+// Strings are written as json strings, which are valid double quoted yaml scalars.
+func MarkdownDocument(a *article.Article, content string) string {
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "id: %s\n", strconv.Quote(a.ID))
+	fmt.Fprintf(&b, "title: %s\n", strconv.Quote(a.Title))
+	fmt.Fprintf(&b, "url: %s\n", strconv.Quote(a.ArticleLink))
+	fmt.Fprintf(&b, "domain: %s\n", strconv.Quote(a.Domain))
+	fmt.Fprintf(&b, "language: %s\n", strconv.Quote(a.Language))
+	fmt.Fprintf(&b, "reading_time: %d\n", a.ReadingTime)
+	if a.CollectionID != nil {
+		fmt.Fprintf(&b, "collection_id: %s\n", strconv.Quote(*a.CollectionID))
+	}
+	if tags := TagNames(a); len(tags) > 0 {
+		quoted := make([]string, len(tags))
+		for i, t := range tags {
+			quoted[i] = strconv.Quote(t)
+		}
+		fmt.Fprintf(&b, "tags: [%s]\n", strings.Join(quoted, ", "))
+	}
+	fmt.Fprintf(&b, "created_at: %s\n", a.CreatedAt.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "updated_at: %s\n", a.UpdatedAt.UTC().Format(time.RFC3339))
+	writeTime(&b, "read_at", a.ReadAt)
+	writeTime(&b, "archived_at", a.ArchivedAt)
+	writeTime(&b, "favorited_at", a.FavoritedAt)
+	b.WriteString("---\n\n")
+
+	b.WriteString(content)
+	b.WriteString("\n")
+	return b.String()
+}
+
+func writeTime(b *strings.Builder, key string, t *time.Time) {
+	if t != nil {
+		fmt.Fprintf(b, "%s: %s\n", key, t.UTC().Format(time.RFC3339))
+	}
+}
+
+func TagNames(a *article.Article) []string {
+	names := make([]string, len(a.Tags))
+	for i, t := range a.Tags {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// languageTags maps the articles' text search configurations to the language tags e-readers understand.
+var languageTags = map[string]string{
+	"arabic": "ar", "armenian": "hy", "basque": "eu", "catalan": "ca", "danish": "da", "dutch": "nl",
+	"english": "en", "finnish": "fi", "french": "fr", "german": "de", "greek": "el", "hindi": "hi",
+	"hungarian": "hu", "indonesian": "id", "irish": "ga", "italian": "it", "lithuanian": "lt", "nepali": "ne",
+	"norwegian": "no", "portuguese": "pt", "romanian": "ro", "russian": "ru", "serbian": "sr", "spanish": "es",
+	"swedish": "sv", "tamil": "ta", "turkish": "tr", "yiddish": "yi",
+}
+
+// LanguageTag is the language tag of the article's language, it's undetermined for the simple configuration.
+func LanguageTag(language string) string {
+	if tag, ok := languageTags[language]; ok {
+		return tag
+	}
+	return "und"
+}
+
+// ArticleBook describes the book of a single article, it's credited to the article's site.
+func ArticleBook(a *article.Article) epub.Metadata {
+	return epub.Metadata{
+		ID:          a.ID,
+		Title:       a.Title,
+		Language:    LanguageTag(a.Language),
+		Creator:     a.Domain,
+		Source:      a.ArticleLink,
+		Description: fmt.Sprintf("%d min read", a.ReadingTime),
+		Subjects:    TagNames(a),
+		Date:        a.CreatedAt,
+	}
+}
+
+// CollectionBook describes the book of a collection, it's written in the language of its first article.
+func CollectionBook(c *collection.Collection, first *article.Article) epub.Metadata {
+	return epub.Metadata{
+		ID:       c.ID,
+		Title:    c.Name,
+		Language: LanguageTag(first.Language),
+		Creator:  "unclatter",
+		Date:     c.CreatedAt,
+	}
+}
+
+// NewChapter makes the article's chapter out of its sanitized content.
+func NewChapter(a *article.Article, content string) epub.Chapter {
+	byline := fmt.Sprintf("%s · %d min read · saved %s", a.Domain, a.ReadingTime, a.CreatedAt.UTC().Format("January 2, 2006"))
+	return epub.Chapter{
+		Title:    a.Title,
+		Byline:   byline,
+		Source:   a.ArticleLink,
+		Language: LanguageTag(a.Language),
+		Content:  content,
+	}
+}
+
+// WriteArticleBook writes an EPUB of the article with its sanitized content into w.
+func WriteArticleBook(ctx context.Context, w io.Writer, a *article.Article, content string, images epub.ImageFetcher) error {
+	book, err := epub.NewWriter(w, ArticleBook(a), images)
+	if err != nil {
+		return err
+	}
+	if err = book.AddChapter(ctx, NewChapter(a, content)); err != nil {
+		return err
+	}
+	return book.Close()
+}
+
+type Status string
+
+const (
+	// StatusQueued exports are waiting for the export job to build their archive.
+	StatusQueued Status = "queued"
+	StatusDone   Status = "done"
+	StatusFailed Status = "failed"
+)
+
+// Job is an archive of the scope built in the background, it can be downloaded until it expires.
+type Job struct {
+	ID           string `json:"id" gorm:"type:varchar"`
+	UserID       string `json:"-" gorm:"type:varchar;not null;index"`
+	CollectionID string `json:"collection_id,omitempty" gorm:"type:varchar;not null;default:''"`
+	Status       Status `json:"status" gorm:"type:varchar;not null;index"`
+	// Error is why the archive couldn't be built, database errors are kept out of it.
+	Error       string     `json:"error,omitempty" gorm:"type:varchar;not null;default:''"`
+	Size        int64      `json:"size" gorm:"not null;default:0"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"type:timestamptz;not null"`
+	CompletedAt *time.Time `json:"completed_at" gorm:"type:timestamptz"`
+	ExpiresAt   *time.Time `json:"expires_at" gorm:"type:timestamptz;index"`
+}
+
+func (Job) TableName() string {
+	return "export_jobs"
+}
+
+func NewJob(userID, collectionID string) *Job {
+	return &Job{
+		ID:           uuid.NewString(),
+		UserID:       userID,
+		CollectionID: collectionID,
+		Status:       StatusQueued,
+		CreatedAt:    time.Now().UTC(),
+	}
+}
+
+func (j *Job) Scope() Scope {
+	return Scope{UserID: j.UserID, CollectionID: j.CollectionID}
+}
+
+// File names the job's archive in the file store.
+func (j *Job) File() string {
+	return j.ID + ".zip"
+}
+
+// Filename is what the archive is saved as when it's downloaded.
+func (j *Job) Filename() string {
+	return fmt.Sprintf("unclatter-export-%s.zip", j.CreatedAt.UTC().Format("20060102-150405"))
+}
+
+func (j *Job) Done(size int64, now time.Time, retention time.Duration) {
+	completedAt := now.UTC()
+	expiresAt := completedAt.Add(retention)
+	j.Status = StatusDone
+	j.Error = ""
+	j.Size = size
+	j.CompletedAt = &completedAt
+	j.ExpiresAt = &expiresAt
+}
+
+// Fail keeps the failed job around as long as a built one, so the user can see why it failed.
+func (j *Job) Fail(reason string, now time.Time, retention time.Duration) {
+	completedAt := now.UTC()
+	expiresAt := completedAt.Add(retention)
+	j.Status = StatusFailed
+	j.Error = reason
+	j.CompletedAt = &completedAt
+	j.ExpiresAt = &expiresAt
+}
+
+type ExportService interface {
+	// ExportLibrary streams a zip archive of the scope's articles into w. Nothing is written to w when the
+	// user can't export the scope, so the caller can still answer with an error.
+	ExportLibrary(ctx context.Context, userID, collectionID string, w io.Writer) error
+	// StartExport queues the scope's archive to be built by the export job, large libraries are better
+	// downloaded this way than streamed by ExportLibrary.
+	StartExport(ctx context.Context, userID, collectionID string) (*Job, error)
+	GetExport(ctx context.Context, userID, jobID string) (*Job, error)
+	// OpenExport opens the archive of a built export that hasn't expired, the caller closes it.
+	OpenExport(ctx context.Context, userID, jobID string) (*Job, *os.File, error)
+	// BuildQueuedExports builds up to limit queued exports, oldest first, and returns how many were built.
+	BuildQueuedExports(ctx context.Context, limit int) (int, error)
+	// PurgeExpiredExports removes the exports that expired by now along with their archives and returns
+	// how many were removed.
+	PurgeExpiredExports(ctx context.Context, now time.Time) (int, error)
+	// ExportArticleBook streams an EPUB of the article into w, nothing is written when the user can't read it.
+	ExportArticleBook(ctx context.Context, userID, articleID string, w io.Writer) error
+	// ExportCollectionBook streams an EPUB with a chapter per article of the collection, oldest first.
+	ExportCollectionBook(ctx context.Context, userID, collectionID string, w io.Writer) error
+}
+
+type ExportRepository interface {
+	// Snapshot runs fn in a read only transaction, every read made through the repository passed to fn sees
+	// the library as it was when fn started.
+	Snapshot(ctx context.Context, fn func(r ExportRepository) error) error
+	// EachBatch calls fn with the scope's articles and their tags in batches, content is only fetched
+	// when withContent is set.
+	EachBatch(ctx context.Context, scope Scope, withContent bool, fn func(articles []*article.Article) error) error
+	// ListArticleIDs returns the ids of the scope's articles, oldest first.
+	ListArticleIDs(ctx context.Context, scope Scope) ([]string, error)
+	SaveJob(ctx context.Context, job Job) error
+	FindJob(ctx context.Context, userID, jobID string) (*Job, error)
+	ListQueuedJobs(ctx context.Context, limit int) ([]*Job, error)
+	UpdateJob(ctx context.Context, job Job) error
+	ListExpiredJobs(ctx context.Context, now time.Time) ([]*Job, error)
+	DeleteJob(ctx context.Context, jobID string) error
+}
@@ -0,0 +1,105 @@
+package export
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/tag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFiles(t *testing.T) {
+	cases := []struct {
+		name     string
+		title    string
+		expected string
+	}{
+		{
+			name:     "should name files after the title",
+			title:    "Hello, World: Go 1.22!",
+			expected: "articles/hello-world-go-1-22-0a1b2c3d",
+		},
+		{
+			name:     "should fallback to article when the title has no letters",
+			title:    "!!!",
+			expected: "articles/article-0a1b2c3d",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			files := NewFiles(&article.Article{ID: "0a1b2c3d-1111-2222-3333-444455556666", Title: c.title})
+			assert.Equal(t, c.expected+".html", files.HTML)
+			assert.Equal(t, c.expected+".md", files.Markdown)
+		})
+	}
+}
+
+func TestMarkdownDocument(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	readAt := createdAt.Add(time.Hour)
+	a := &article.Article{
+		ID:          "id",
+		Title:       `Quote "this"`,
+		ArticleLink: "https://unclatter.com",
+		Domain:      "unclatter.com",
+		Language:    "english",
+		ReadingTime: 2,
+		CreatedAt:   createdAt,
+		UpdatedAt:   createdAt,
+		ReadAt:      &readAt,
+		Tags:        []*tag.Tag{{Name: "go"}, {Name: "web"}},
+	}
+
+	expected := "---\n" +
+		"id: \"id\"\n" +
+		"title: \"Quote \\\"this\\\"\"\n" +
+		"url: \"https://unclatter.com\"\n" +
+		"domain: \"unclatter.com\"\n" +
+		"language: \"english\"\n" +
+		"reading_time: 2\n" +
+		"tags: [\"go\", \"web\"]\n" +
+		"created_at: 2024-03-01T10:00:00Z\n" +
+		"updated_at: 2024-03-01T10:00:00Z\n" +
+		"read_at: 2024-03-01T11:00:00Z\n" +
+		"---\n\n" +
+		"content\n"
+	assert.Equal(t, expected, MarkdownDocument(a, "content"))
+}
+
+func TestHTMLDocument(t *testing.T) {
+	a := &article.Article{Title: "<b>Title</b>", ArticleLink: "https://unclatter.com?a=1&b=2"}
+
+	doc := HTMLDocument(a, "<p>content</p>")
+	assert.Contains(t, doc, "<title>&lt;b&gt;Title&lt;/b&gt;</title>")
+	assert.Contains(t, doc, `<a href="https://unclatter.com?a=1&amp;b=2">`)
+	assert.Contains(t, doc, "<p>content</p>")
+}
+
+func TestArticleBook(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	a := &article.Article{
+		ID:          "0a1b2c3d-1111-2222-3333-444455556666",
+		Title:       "Title",
+		ArticleLink: "https://unclatter.com",
+		Domain:      "unclatter.com",
+		Language:    "german",
+		ReadingTime: 4,
+		CreatedAt:   createdAt,
+		Tags:        []*tag.Tag{{Name: "go"}},
+	}
+
+	meta := ArticleBook(a)
+	assert.Equal(t, a.ID, meta.ID)
+	assert.Equal(t, "de", meta.Language)
+	assert.Equal(t, "unclatter.com", meta.Creator)
+	assert.Equal(t, []string{"go"}, meta.Subjects)
+	assert.Equal(t, createdAt, meta.Date)
+
+	chapter := NewChapter(a, "<p>content</p>")
+	assert.Equal(t, "unclatter.com · 4 min read · saved March 1, 2024", chapter.Byline)
+	assert.Equal(t, a.ArticleLink, chapter.Source)
+
+	assert.Equal(t, "und", LanguageTag("simple"))
+}
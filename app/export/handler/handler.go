@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ryanadiputraa/unclatter/app/export"
+	"github.com/ryanadiputraa/unclatter/app/middleware"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	_http "github.com/ryanadiputraa/unclatter/pkg/http"
+)
+
+// writeTimeout bounds every write of the archive instead of the whole response, so large exports aren't cut
+// by the server's write timeout while a stalled download still is.
+const writeTimeout = 30 * time.Second
+
+const epubContentType = "application/epub+zip"
+
+type handler struct {
+	rw            _http.ResponseWriter
+	exportService export.ExportService
+}
+
+func NewHandler(web *http.ServeMux, rw _http.ResponseWriter, exportService export.ExportService, authMiddleware middleware.AuthMiddleware) {
+	h := &handler{
+		rw:            rw,
+		exportService: exportService,
+	}
+
+	web.Handle("GET /api/exports", authMiddleware.ParseJWTToken(h.ExportLibrary()))
+	web.Handle("POST /api/exports", authMiddleware.ParseJWTToken(h.StartExport()))
+	web.Handle("GET /api/exports/{id}", authMiddleware.ParseJWTToken(h.GetExport()))
+	web.Handle("GET /api/exports/{id}/download", authMiddleware.ParseJWTToken(h.DownloadExport()))
+	web.Handle("GET /api/articles/bookmarks/{id}/epub", authMiddleware.ParseJWTToken(h.ExportArticleBook()))
+	web.Handle("GET /api/collections/{id}/epub", authMiddleware.ParseJWTToken(h.ExportCollectionBook()))
+}
+
+func (h *handler) ExportLibrary() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		archive := newArchiveWriter(w, "application/zip", fmt.Sprintf("unclatter-export-%s.zip", time.Now().UTC().Format("20060102-150405")))
+
+		err := h.exportService.ExportLibrary(ac.Context, ac.UserID, r.URL.Query().Get("collection"), archive)
+		h.finish(w, archive, err)
+	}
+}
+
+func (h *handler) StartExport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		job, err := h.exportService.StartExport(ac.Context, ac.UserID, r.URL.Query().Get("collection"))
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusAccepted, job)
+	}
+}
+
+func (h *handler) GetExport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		job, err := h.exportService.GetExport(ac.Context, ac.UserID, r.PathValue("id"))
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, job)
+	}
+}
+
+func (h *handler) DownloadExport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		job, f, err := h.exportService.OpenExport(ac.Context, ac.UserID, r.PathValue("id"))
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+		defer f.Close()
+
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", job.Filename()))
+		// ServeContent answers range requests, so an interrupted download of a large archive can be resumed
+		http.ServeContent(&deadlineWriter{ResponseWriter: w, rc: http.NewResponseController(w)}, r, job.Filename(), *job.CompletedAt, f)
+	}
+}
+
+func (h *handler) ExportArticleBook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		id := r.PathValue("id")
+		book := newArchiveWriter(w, epubContentType, fmt.Sprintf("unclatter-article-%s.epub", shortID(id)))
+
+		err := h.exportService.ExportArticleBook(ac.Context, ac.UserID, id, book)
+		h.finish(w, book, err)
+	}
+}
+
+func (h *handler) ExportCollectionBook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		id := r.PathValue("id")
+		book := newArchiveWriter(w, epubContentType, fmt.Sprintf("unclatter-collection-%s.epub", shortID(id)))
+
+		err := h.exportService.ExportCollectionBook(ac.Context, ac.UserID, id, book)
+		h.finish(w, book, err)
+	}
+}
+
+// finish answers errors found before the archive was started, once it's partially sent the client sees
+// the broken download.
+func (h *handler) finish(w http.ResponseWriter, archive *archiveWriter, err error) {
+	if err == nil || archive.started {
+		return
+	}
+	h.writeErr(w, err)
+}
+
+func (h *handler) writeErr(w http.ResponseWriter, err error) {
+	if vErr, ok := err.(*validation.Error); ok {
+		h.rw.WriteErrMessage(w, validation.HttpErrMap[vErr.Err], vErr.Message)
+		return
+	}
+	h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+}
+
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+// archiveWriter sends the download headers along with the first chunk of the archive, errors found before
+// anything is written can still be answered with a json error.
+type archiveWriter struct {
+	w           http.ResponseWriter
+	rc          *http.ResponseController
+	contentType string
+	filename    string
+	started     bool
+}
+
+func newArchiveWriter(w http.ResponseWriter, contentType, filename string) *archiveWriter {
+	return &archiveWriter{
+		w:           w,
+		rc:          http.NewResponseController(w),
+		contentType: contentType,
+		filename:    filename,
+	}
+}
+
+func (a *archiveWriter) Write(p []byte) (int, error) {
+	if !a.started {
+		a.started = true
+		a.w.Header().Set("Content-Type", a.contentType)
+		a.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", a.filename))
+		a.w.WriteHeader(http.StatusOK)
+	}
+
+	a.rc.SetWriteDeadline(time.Now().Add(writeTimeout))
+	return a.w.Write(p)
+}
+
+// deadlineWriter bounds every write of a download like archiveWriter does.
+type deadlineWriter struct {
+	http.ResponseWriter
+	rc *http.ResponseController
+}
+
+func (d *deadlineWriter) Write(p []byte) (int, error) {
+	d.rc.SetWriteDeadline(time.Now().Add(writeTimeout))
+	return d.ResponseWriter.Write(p)
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/export"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"gorm.io/gorm"
+)
+
+const (
+	batchSize       = 100
+	metadataColumns = "id, title, article_link, language, domain, reading_time, collection_id, version, created_at, updated_at, read_at, archived_at, favorited_at"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) export.ExportRepository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Snapshot(ctx context.Context, fn func(r export.ExportRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&repository{db: tx})
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+}
+
+func (r *repository) EachBatch(ctx context.Context, scope export.Scope, withContent bool, fn func(articles []*article.Article) error) error {
+	columns := metadataColumns
+	if withContent {
+		columns += ", content"
+	}
+
+	db := r.db.Select(columns).Preload("Tags")
+	if scope.CollectionID != "" {
+		db = db.Where("collection_id = ?", scope.CollectionID)
+	} else {
+		db = db.Where("user_id = ?", scope.UserID)
+	}
+
+	var articles []*article.Article
+	return db.FindInBatches(&articles, batchSize, func(tx *gorm.DB, batch int) error {
+		return fn(articles)
+	}).Error
+}
+
+func (r *repository) ListArticleIDs(ctx context.Context, scope export.Scope) (ids []string, err error) {
+	db := r.db.Model(&article.Article{})
+	if scope.CollectionID != "" {
+		db = db.Where("collection_id = ?", scope.CollectionID)
+	} else {
+		db = db.Where("user_id = ?", scope.UserID)
+	}
+	err = db.Order("created_at, id").Pluck("id", &ids).Error
+	return
+}
+
+func (r *repository) SaveJob(ctx context.Context, job export.Job) error {
+	return r.db.Create(&job).Error
+}
+
+func (r *repository) FindJob(ctx context.Context, userID, jobID string) (job *export.Job, err error) {
+	err = r.db.First(&job, "id = ? AND user_id = ?", jobID, userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, validation.NewError(validation.NotFound, "no export found with given id")
+	}
+	return
+}
+
+func (r *repository) ListQueuedJobs(ctx context.Context, limit int) (jobs []*export.Job, err error) {
+	err = r.db.Where("status = ?", export.StatusQueued).
+		Order("created_at").
+		Limit(limit).
+		Find(&jobs).Error
+	return
+}
+
+func (r *repository) UpdateJob(ctx context.Context, job export.Job) error {
+	return r.db.Model(&export.Job{}).
+		Where("id = ?", job.ID).
+		Select("status", "error", "size", "completed_at", "expires_at").
+		Updates(job).Error
+}
+
+func (r *repository) ListExpiredJobs(ctx context.Context, now time.Time) (jobs []*export.Job, err error) {
+	err = r.db.Where("expires_at <= ?", now).
+		Order("expires_at").
+		Find(&jobs).Error
+	return
+}
+
+func (r *repository) DeleteJob(ctx context.Context, jobID string) error {
+	return r.db.Delete(&export.Job{}, "id = ?", jobID).Error
+}
@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/export"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEachBatch(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	collectionID := uuid.NewString()
+	preloadTags := "^SELECT \\* FROM \"article_tags\" WHERE \"article_tags\".\"article_id\" = "
+
+	cases := []struct {
+		name          string
+		scope         export.Scope
+		withContent   bool
+		mockBehaviour func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name:  "should read user's library without content",
+			scope: export.Scope{UserID: test.TestUser.ID},
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT id, title, article_link, language, domain, reading_time, collection_id, version, created_at, updated_at, read_at, archived_at, favorited_at "+
+					"FROM \"articles\" WHERE user_id = (.+) AND \"articles\".\"deleted_at\" IS NULL ORDER BY \"articles\".\"id\" LIMIT (.+)$").
+					WithArgs(test.TestUser.ID, batchSize).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(test.TestArticle.ID, test.TestArticle.Title))
+				mock.ExpectQuery(preloadTags).
+					WithArgs(test.TestArticle.ID).
+					WillReturnRows(sqlmock.NewRows([]string{"article_id", "tag_id"}))
+			},
+		},
+		{
+			name:        "should read collection's articles with content",
+			scope:       export.Scope{UserID: test.TestUser.ID, CollectionID: collectionID},
+			withContent: true,
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT (.+), favorited_at, content FROM \"articles\" WHERE collection_id = (.+) AND \"articles\".\"deleted_at\" IS NULL").
+					WithArgs(collectionID, batchSize).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content"}).AddRow(test.TestArticle.ID, test.TestArticle.Title, test.TestArticle.Content))
+				mock.ExpectQuery(preloadTags).
+					WithArgs(test.TestArticle.ID).
+					WillReturnRows(sqlmock.NewRows([]string{"article_id", "tag_id"}))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+
+			var read []*article.Article
+			err := r.EachBatch(context.Background(), c.scope, c.withContent, func(articles []*article.Article) error {
+				read = append(read, articles...)
+				return nil
+			})
+			assert.Nil(t, err)
+			assert.Len(t, read, 1)
+			assert.Equal(t, test.TestArticle.ID, read[0].ID)
+			if c.withContent {
+				assert.Equal(t, test.TestArticle.Content, read[0].Content)
+			}
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	called := false
+	err := r.Snapshot(context.Background(), func(r export.ExportRepository) error {
+		called = true
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.True(t, called)
+}
+
+func TestListArticleIDs(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	collectionID := uuid.NewString()
+
+	mock.ExpectQuery("^SELECT \"id\" FROM \"articles\" WHERE collection_id = (.+) AND \"articles\".\"deleted_at\" IS NULL ORDER BY created_at, id$").
+		WithArgs(collectionID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(test.TestArticle.ID).AddRow(test.TestArticle2.ID))
+
+	ids, err := r.ListArticleIDs(context.Background(), export.Scope{UserID: test.TestUser.ID, CollectionID: collectionID})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{test.TestArticle.ID, test.TestArticle2.ID}, ids)
+}
+
+func TestFindJob(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	jobID := uuid.NewString()
+	query := "^SELECT \\* FROM \"export_jobs\" WHERE id = (.+) AND user_id = (.+) ORDER BY \"export_jobs\".\"id\" LIMIT (.+)"
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		expected      *export.Job
+		err           error
+	}{
+		{
+			name: "should return user's export",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(jobID, test.TestUser.ID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "status"}).AddRow(jobID, test.TestUser.ID, export.StatusQueued))
+			},
+			expected: &export.Job{ID: jobID, UserID: test.TestUser.ID, Status: export.StatusQueued},
+			err:      nil,
+		},
+		{
+			name: "should return not found when the export isn't user's",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(jobID, test.TestUser.ID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			expected: nil,
+			err:      validation.NewError(validation.NotFound, "no export found with given id"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+
+			job, err := r.FindJob(context.Background(), test.TestUser.ID, jobID)
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.expected, job)
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestListQueuedJobs(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	jobID := uuid.NewString()
+
+	mock.ExpectQuery("^SELECT \\* FROM \"export_jobs\" WHERE status = (.+) ORDER BY created_at LIMIT (.+)").
+		WithArgs(export.StatusQueued, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(jobID, export.StatusQueued))
+
+	jobs, err := r.ListQueuedJobs(context.Background(), 2)
+	assert.Nil(t, err)
+	assert.Len(t, jobs, 1)
+	assert.Equal(t, jobID, jobs[0].ID)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateJob(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	job := export.NewJob(test.TestUser.ID, "")
+	job.Done(2048, time.Now(), time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("^UPDATE \"export_jobs\" SET \"status\"=\\$1,\"error\"=\\$2,\"size\"=\\$3,\"completed_at\"=\\$4,\"expires_at\"=\\$5 WHERE id = \\$6").
+		WithArgs(export.StatusDone, "", int64(2048), test.AnyTime{}, test.AnyTime{}, job.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := r.UpdateJob(context.Background(), *job)
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestListExpiredJobs(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	jobID := uuid.NewString()
+	now := time.Now()
+
+	mock.ExpectQuery("^SELECT \\* FROM \"export_jobs\" WHERE expires_at <= (.+) ORDER BY expires_at").
+		WithArgs(now).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(jobID, export.StatusDone))
+
+	jobs, err := r.ListExpiredJobs(context.Background(), now)
+	assert.Nil(t, err)
+	assert.Len(t, jobs, 1)
+	assert.Equal(t, jobID, jobs[0].ID)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteJob(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	jobID := uuid.NewString()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("^DELETE FROM \"export_jobs\" WHERE id = (.+)").
+		WithArgs(jobID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := r.DeleteJob(context.Background(), jobID)
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
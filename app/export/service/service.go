@@ -0,0 +1,403 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/collection"
+	"github.com/ryanadiputraa/unclatter/app/export"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/pkg/epub"
+	"github.com/ryanadiputraa/unclatter/pkg/filestore"
+	"github.com/ryanadiputraa/unclatter/pkg/logger"
+	"github.com/ryanadiputraa/unclatter/pkg/markdown"
+	"github.com/ryanadiputraa/unclatter/pkg/netscape"
+	"github.com/ryanadiputraa/unclatter/pkg/sanitizer"
+)
+
+type service struct {
+	log                  logger.Logger
+	sanitizer            sanitizer.Sanitizer
+	images               epub.ImageFetcher
+	store                filestore.Store
+	repository           export.ExportRepository
+	articleRepository    article.ArticleRepository
+	collectionRepository collection.CollectionRepository
+	// retention is how long a built export can be downloaded.
+	retention time.Duration
+}
+
+func NewService(log logger.Logger, sanitizer sanitizer.Sanitizer, images epub.ImageFetcher, store filestore.Store, repository export.ExportRepository, articleRepository article.ArticleRepository, collectionRepository collection.CollectionRepository, retention time.Duration) export.ExportService {
+	return &service{
+		log:                  log,
+		sanitizer:            sanitizer,
+		images:               images,
+		store:                store,
+		repository:           repository,
+		articleRepository:    articleRepository,
+		collectionRepository: collectionRepository,
+		retention:            retention,
+	}
+}
+
+func (s *service) ExportLibrary(ctx context.Context, userID, collectionID string, w io.Writer) error {
+	scope := export.Scope{UserID: userID, CollectionID: collectionID}
+	if collectionID != "" {
+		if err := s.authorize(ctx, userID, collectionID); err != nil {
+			return err
+		}
+	}
+
+	err := s.writeLibrary(ctx, scope, w)
+	if err != nil {
+		s.log.Error("export service: fail to export library", err)
+	}
+	return err
+}
+
+// writeLibrary writes the scope's archive into w. It's built in a few passes over the library, each one
+// reading a batch of articles at a time so the export never holds the whole library in memory.
+func (s *service) writeLibrary(ctx context.Context, scope export.Scope, w io.Writer) error {
+	return s.repository.Snapshot(ctx, func(r export.ExportRepository) error {
+		zw := zip.NewWriter(w)
+		if err := writeManifest(ctx, zw, r, scope); err != nil {
+			return err
+		}
+		if err := writeBookmarks(ctx, zw, r, scope); err != nil {
+			return err
+		}
+		if err := s.writeArticles(ctx, zw, r, scope); err != nil {
+			return err
+		}
+		return zw.Close()
+	})
+}
+
+func (s *service) StartExport(ctx context.Context, userID, collectionID string) (*export.Job, error) {
+	if collectionID != "" {
+		if err := s.authorize(ctx, userID, collectionID); err != nil {
+			return nil, err
+		}
+	}
+
+	job := export.NewJob(userID, collectionID)
+	if err := s.repository.SaveJob(ctx, *job); err != nil {
+		s.log.Error("export service: fail to save export", err)
+		return nil, err
+	}
+	return job, nil
+}
+
+func (s *service) GetExport(ctx context.Context, userID, jobID string) (*export.Job, error) {
+	job, err := s.repository.FindJob(ctx, userID, jobID)
+	if err != nil {
+		if _, ok := err.(*validation.Error); !ok {
+			s.log.Error("export service: fail to fetch export", err)
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+func (s *service) OpenExport(ctx context.Context, userID, jobID string) (*export.Job, *os.File, error) {
+	job, err := s.GetExport(ctx, userID, jobID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if job.Status != export.StatusDone {
+		return nil, nil, validation.NewError(validation.Conflict, "export isn't ready to download")
+	}
+	if job.ExpiresAt != nil && !time.Now().Before(*job.ExpiresAt) {
+		return nil, nil, validation.NewError(validation.NotFound, "export has expired")
+	}
+
+	f, err := s.store.Open(job.File())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, validation.NewError(validation.NotFound, "export has expired")
+	}
+	if err != nil {
+		s.log.Error("export service: fail to open export archive", err)
+		return nil, nil, err
+	}
+	return job, f, nil
+}
+
+func (s *service) BuildQueuedExports(ctx context.Context, limit int) (int, error) {
+	jobs, err := s.repository.ListQueuedJobs(ctx, limit)
+	if err != nil {
+		s.log.Error("export service: fail to fetch queued exports", err)
+		return 0, err
+	}
+
+	built := 0
+	for _, job := range jobs {
+		if ctx.Err() != nil {
+			return built, ctx.Err()
+		}
+
+		s.build(ctx, job)
+		if err = s.repository.UpdateJob(ctx, *job); err != nil {
+			s.log.Error("export service: fail to update export", err)
+			return built, err
+		}
+		if job.Status == export.StatusDone {
+			built++
+		}
+	}
+	return built, nil
+}
+
+// build writes the job's archive into the store, why it failed is kept on the job.
+func (s *service) build(ctx context.Context, job *export.Job) {
+	// the user may have left the collection since the export was queued
+	if job.CollectionID != "" {
+		if err := s.authorize(ctx, job.UserID, job.CollectionID); err != nil {
+			job.Fail("collection can't be exported anymore", time.Now(), s.retention)
+			return
+		}
+	}
+
+	size, err := s.store.Write(job.File(), func(w io.Writer) error {
+		return s.writeLibrary(ctx, job.Scope(), w)
+	})
+	if err != nil {
+		s.log.Error("export service: fail to build export archive", err)
+		job.Fail("fail to build archive", time.Now(), s.retention)
+		return
+	}
+	job.Done(size, time.Now(), s.retention)
+}
+
+func (s *service) PurgeExpiredExports(ctx context.Context, now time.Time) (int, error) {
+	jobs, err := s.repository.ListExpiredJobs(ctx, now)
+	if err != nil {
+		s.log.Error("export service: fail to fetch expired exports", err)
+		return 0, err
+	}
+
+	purged := 0
+	for _, job := range jobs {
+		// the archive goes first so a failure leaves the job around to be purged again on the next run
+		if err = s.store.Remove(job.File()); err != nil {
+			s.log.Error("export service: fail to remove export archive", err)
+			return purged, err
+		}
+		if err = s.repository.DeleteJob(ctx, job.ID); err != nil {
+			s.log.Error("export service: fail to delete export", err)
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+func (s *service) authorize(ctx context.Context, userID, collectionID string) error {
+	role, err := s.collectionRepository.FindRole(ctx, collectionID, userID)
+	if err != nil {
+		if _, ok := err.(*validation.Error); !ok {
+			s.log.Error("export service: fail to fetch collection role", err)
+		}
+		return err
+	}
+
+	if !role.Can(collection.RoleViewer) {
+		return validation.NewError(validation.Forbidden, "forbidden access")
+	}
+	return nil
+}
+
+func (s *service) ExportArticleBook(ctx context.Context, userID, articleID string, w io.Writer) error {
+	a, err := s.findArticle(ctx, userID, articleID)
+	if err != nil {
+		return err
+	}
+
+	if err = export.WriteArticleBook(ctx, w, a, s.sanitizer.Sanitize(a.Content), s.images); err != nil {
+		s.log.Error("export service: fail to export article book", err)
+	}
+	return err
+}
+
+func (s *service) ExportCollectionBook(ctx context.Context, userID, collectionID string, w io.Writer) error {
+	if err := s.authorize(ctx, userID, collectionID); err != nil {
+		return err
+	}
+	c, err := s.collectionRepository.FindByID(ctx, collectionID)
+	if err != nil {
+		if _, ok := err.(*validation.Error); !ok {
+			s.log.Error("export service: fail to fetch collection", err)
+		}
+		return err
+	}
+
+	ids, err := s.repository.ListArticleIDs(ctx, export.Scope{UserID: userID, CollectionID: collectionID})
+	if err != nil {
+		s.log.Error("export service: fail to list collection's articles", err)
+		return err
+	}
+	if len(ids) > export.MaxBookArticles {
+		return validation.NewError(validation.BadRequest, fmt.Sprintf("collection has more than %d articles, export it as an archive instead", export.MaxBookArticles))
+	}
+
+	// articles are read one at a time so the book never holds more than a chapter's content in memory
+	var book *epub.Writer
+	for _, id := range ids {
+		a, err := s.articleRepository.FindByID(ctx, id)
+		if vErr, ok := err.(*validation.Error); ok && vErr.Err == validation.NotFound {
+			// the article was trashed since the ids were listed
+			continue
+		}
+		if err == nil && book == nil {
+			book, err = epub.NewWriter(w, export.CollectionBook(c, a), s.images)
+		}
+		if err == nil {
+			err = book.AddChapter(ctx, export.NewChapter(a, s.sanitizer.Sanitize(a.Content)))
+		}
+		if err != nil {
+			s.log.Error("export service: fail to export collection book", err)
+			return err
+		}
+	}
+
+	if book == nil {
+		return validation.NewError(validation.BadRequest, "collection has no articles")
+	}
+	if err = book.Close(); err != nil {
+		s.log.Error("export service: fail to export collection book", err)
+	}
+	return err
+}
+
+// findArticle returns the article when it's the user's or in a collection the user can view.
+func (s *service) findArticle(ctx context.Context, userID, articleID string) (*article.Article, error) {
+	a, err := s.articleRepository.FindByID(ctx, articleID)
+	if err != nil {
+		if _, ok := err.(*validation.Error); !ok {
+			s.log.Error("export service: fail to fetch article", err)
+		}
+		return nil, err
+	}
+	if a.UserID == userID {
+		return a, nil
+	}
+
+	if a.CollectionID == nil {
+		return nil, validation.NewError(validation.Forbidden, "forbidden access")
+	}
+	if err = s.authorize(ctx, userID, *a.CollectionID); err != nil {
+		if vErr, ok := err.(*validation.Error); ok && vErr.Err == validation.NotFound {
+			err = validation.NewError(validation.Forbidden, "forbidden access")
+		}
+		return nil, err
+	}
+	return a, nil
+}
+
+func writeManifest(ctx context.Context, zw *zip.Writer, r export.ExportRepository, scope export.Scope) error {
+	f, err := create(zw, export.ManifestFile, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	header, err := json.Marshal(export.Manifest{ExportedAt: time.Now().UTC(), CollectionID: scope.CollectionID})
+	if err != nil {
+		return err
+	}
+	// the articles are appended to the manifest object as they're read
+	if _, err = f.Write(append(header[:len(header)-1], `,"articles":[`...)); err != nil {
+		return err
+	}
+
+	first := true
+	err = r.EachBatch(ctx, scope, false, func(articles []*article.Article) error {
+		for _, a := range articles {
+			record, err := json.Marshal(export.ManifestArticle{Article: a, Files: export.NewFiles(a)})
+			if err != nil {
+				return err
+			}
+			if !first {
+				record = append([]byte{','}, record...)
+			}
+			first = false
+			if _, err = f.Write(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write([]byte("]}"))
+	return err
+}
+
+func writeBookmarks(ctx context.Context, zw *zip.Writer, r export.ExportRepository, scope export.Scope) error {
+	f, err := create(zw, export.BookmarksFile, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	bookmarks := netscape.NewWriter(f)
+	err = r.EachBatch(ctx, scope, false, func(articles []*article.Article) error {
+		for _, a := range articles {
+			err := bookmarks.Write(netscape.Bookmark{
+				Title:        a.Title,
+				URL:          a.ArticleLink,
+				AddDate:      a.CreatedAt,
+				LastModified: a.UpdatedAt,
+				Tags:         export.TagNames(a),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return bookmarks.Close()
+}
+
+func (s *service) writeArticles(ctx context.Context, zw *zip.Writer, r export.ExportRepository, scope export.Scope) error {
+	return r.EachBatch(ctx, scope, true, func(articles []*article.Article) error {
+		for _, a := range articles {
+			content := s.sanitizer.Sanitize(a.Content)
+			files := export.NewFiles(a)
+
+			f, err := create(zw, files.HTML, a.UpdatedAt)
+			if err != nil {
+				return err
+			}
+			if _, err = io.WriteString(f, export.HTMLDocument(a, content)); err != nil {
+				return err
+			}
+
+			f, err = create(zw, files.Markdown, a.UpdatedAt)
+			if err != nil {
+				return err
+			}
+			if _, err = io.WriteString(f, export.MarkdownDocument(a, markdown.FromHTML(content))); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func create(zw *zip.Writer, name string, modified time.Time) (io.Writer, error) {
+	return zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: modified,
+	})
+}
@@ -0,0 +1,497 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/collection"
+	"github.com/ryanadiputraa/unclatter/app/export"
+	"github.com/ryanadiputraa/unclatter/app/mocks"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/pkg/filestore"
+	"github.com/ryanadiputraa/unclatter/pkg/logger"
+	"github.com/ryanadiputraa/unclatter/pkg/sanitizer"
+	"github.com/ryanadiputraa/unclatter/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestExportLibrary(t *testing.T) {
+	collectionID := uuid.NewString()
+
+	cases := []struct {
+		name                        string
+		collectionID                string
+		err                         error
+		mockRepoBehaviour           func(mockRepo *mocks.ExportRepository)
+		mockCollectionRepoBehaviour func(mockRepo *mocks.CollectionRepository)
+	}{
+		{
+			name: "should export user's library",
+			err:  nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ExportRepository) {
+				mockRepo.On("Snapshot", context.Background(), mock.Anything).
+					Return(func(ctx context.Context, fn func(r export.ExportRepository) error) error {
+						return fn(mockRepo)
+					})
+				mockRepo.On("EachBatch", context.Background(), export.Scope{UserID: test.TestUser.ID}, mock.Anything, mock.Anything).
+					Return(func(ctx context.Context, scope export.Scope, withContent bool, fn func(articles []*article.Article) error) error {
+						a := *test.TestArticle
+						if !withContent {
+							a.Content = ""
+						}
+						return fn([]*article.Article{&a})
+					})
+			},
+			mockCollectionRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {},
+		},
+		{
+			name:              "should return err when the collection isn't shared with the user",
+			collectionID:      collectionID,
+			err:               validation.NewError(validation.Forbidden, "forbidden access"),
+			mockRepoBehaviour: func(mockRepo *mocks.ExportRepository) {},
+			mockCollectionRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {
+				mockRepo.On("FindRole", context.Background(), collectionID, test.TestUser.ID).Return(collection.Role(""), nil)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.ExportRepository)
+			c.mockRepoBehaviour(r)
+			collectionRepo := new(mocks.CollectionRepository)
+			c.mockCollectionRepoBehaviour(collectionRepo)
+
+			var buf bytes.Buffer
+			s := NewService(logger.NewLogger(), sanitizer.NewSanitizer(), new(mocks.ImageFetcher), filestore.NewStore(t.TempDir()), r, new(mocks.ArticleRepository), collectionRepo, time.Hour)
+			err := s.ExportLibrary(context.Background(), test.TestUser.ID, c.collectionID, &buf)
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				assert.Zero(t, buf.Len())
+				return
+			}
+
+			zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			assert.Nil(t, err)
+			files := map[string]string{}
+			for _, f := range zr.File {
+				rc, _ := f.Open()
+				content, _ := io.ReadAll(rc)
+				rc.Close()
+				files[f.Name] = string(content)
+			}
+
+			paths := export.NewFiles(test.TestArticle)
+			assert.Len(t, files, 4)
+
+			var manifest struct {
+				Articles []export.ManifestArticle `json:"articles"`
+			}
+			assert.Nil(t, json.Unmarshal([]byte(files[export.ManifestFile]), &manifest))
+			assert.Len(t, manifest.Articles, 1)
+			assert.Equal(t, test.TestArticle.ID, manifest.Articles[0].ID)
+			assert.Empty(t, manifest.Articles[0].Content)
+			assert.Equal(t, paths, manifest.Articles[0].Files)
+
+			assert.Contains(t, files[export.BookmarksFile], `<A HREF="https://unclatter.com"`)
+			assert.NotContains(t, files[paths.HTML], "onblur")
+			assert.Contains(t, files[paths.HTML], "<p>article content</p>")
+			assert.Contains(t, files[paths.Markdown], "[Google](http://www.google.com)")
+			assert.Contains(t, files[paths.Markdown], "title: \"Title\"")
+		})
+	}
+}
+
+// png is the smallest valid png header, enough for the content type to be sniffed.
+var png = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestExportArticleBook(t *testing.T) {
+	collectionID := uuid.NewString()
+	withImages := *test.TestArticle
+	withImages.Content = `<p>intro</p><img src="/cover.png" srcset="/cover-2x.png 2x"><img src="https://cdn.unclatter.com/gone.png" alt="a chart">`
+	shared := *test.TestArticle
+	shared.UserID = uuid.NewString()
+	shared.CollectionID = &collectionID
+	private := *test.TestArticle
+	private.UserID = uuid.NewString()
+
+	cases := []struct {
+		name                        string
+		article                     *article.Article
+		err                         error
+		mockCollectionRepoBehaviour func(mockRepo *mocks.CollectionRepository)
+	}{
+		{
+			name:                        "should export user's article with its images",
+			article:                     &withImages,
+			err:                         nil,
+			mockCollectionRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {},
+		},
+		{
+			name:    "should export article of a collection shared with the user",
+			article: &shared,
+			err:     nil,
+			mockCollectionRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {
+				mockRepo.On("FindRole", context.Background(), collectionID, test.TestUser.ID).Return(collection.RoleViewer, nil)
+			},
+		},
+		{
+			name:                        "should return err when exporting other user's article",
+			article:                     &private,
+			err:                         validation.NewError(validation.Forbidden, "forbidden access"),
+			mockCollectionRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			articleRepo := new(mocks.ArticleRepository)
+			articleRepo.On("FindByID", context.Background(), c.article.ID).Return(c.article, nil)
+			collectionRepo := new(mocks.CollectionRepository)
+			c.mockCollectionRepoBehaviour(collectionRepo)
+			images := new(mocks.ImageFetcher)
+			images.On("Fetch", mock.Anything, "https://unclatter.com/cover.png").Return(png, nil)
+			images.On("Fetch", mock.Anything, "https://cdn.unclatter.com/gone.png").Return(nil, errors.New("not found"))
+
+			var buf bytes.Buffer
+			s := NewService(logger.NewLogger(), sanitizer.NewSanitizer(), images, filestore.NewStore(t.TempDir()), new(mocks.ExportRepository), articleRepo, collectionRepo, time.Hour)
+			err := s.ExportArticleBook(context.Background(), test.TestUser.ID, c.article.ID, &buf)
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				assert.Zero(t, buf.Len())
+				return
+			}
+
+			files := readBook(t, buf.Bytes())
+			assert.Contains(t, files["OEBPS/content.opf"], "<dc:title>Title</dc:title>")
+			assert.Contains(t, files["OEBPS/content.opf"], "<dc:language>en</dc:language>")
+			assert.Contains(t, files["OEBPS/content.opf"], "urn:uuid:"+c.article.ID)
+			assert.Contains(t, files["OEBPS/nav.xhtml"], `<a href="chapters/chapter-1.xhtml">Title</a>`)
+			assert.NotContains(t, files["OEBPS/chapters/chapter-1.xhtml"], "onblur")
+
+			if c.article == &withImages {
+				chapter := files["OEBPS/chapters/chapter-1.xhtml"]
+				assert.Contains(t, chapter, `<img src="../images/image-1.png" alt=""/>`)
+				assert.NotContains(t, chapter, "srcset")
+				assert.Contains(t, chapter, "a chart")
+				assert.Equal(t, string(png), files["OEBPS/images/image-1.png"])
+				assert.Contains(t, files["OEBPS/content.opf"], `<item id="image-1" href="images/image-1.png" media-type="image/png"/>`)
+			}
+		})
+	}
+}
+
+func TestExportCollectionBook(t *testing.T) {
+	c := collection.NewCollection(test.TestUser.ID, "Weekend reads")
+	scope := export.Scope{UserID: test.TestUser.ID, CollectionID: c.ID}
+	trashedID := uuid.NewString()
+
+	collectionRepo := new(mocks.CollectionRepository)
+	collectionRepo.On("FindRole", context.Background(), c.ID, test.TestUser.ID).Return(collection.RoleOwner, nil)
+	collectionRepo.On("FindByID", context.Background(), c.ID).Return(c, nil)
+	r := new(mocks.ExportRepository)
+	r.On("ListArticleIDs", context.Background(), scope).Return([]string{test.TestArticle.ID, trashedID, test.TestArticle2.ID}, nil)
+	articleRepo := new(mocks.ArticleRepository)
+	articleRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+	articleRepo.On("FindByID", context.Background(), trashedID).Return(nil, validation.NewError(validation.NotFound, "no article found with given id"))
+	articleRepo.On("FindByID", context.Background(), test.TestArticle2.ID).Return(test.TestArticle2, nil)
+
+	var buf bytes.Buffer
+	s := NewService(logger.NewLogger(), sanitizer.NewSanitizer(), new(mocks.ImageFetcher), filestore.NewStore(t.TempDir()), r, articleRepo, collectionRepo, time.Hour)
+	err := s.ExportCollectionBook(context.Background(), test.TestUser.ID, c.ID, &buf)
+	assert.Nil(t, err)
+
+	files := readBook(t, buf.Bytes())
+	assert.Contains(t, files["OEBPS/content.opf"], "<dc:title>Weekend reads</dc:title>")
+	assert.Contains(t, files["OEBPS/chapters/chapter-1.xhtml"], "<p>article content</p>")
+	assert.Contains(t, files["OEBPS/chapters/chapter-2.xhtml"], "<p>article content 2</p>")
+	assert.NotContains(t, files, "OEBPS/chapters/chapter-3.xhtml")
+	assert.Equal(t, 2, strings.Count(files["OEBPS/toc.ncx"], "<navPoint"))
+}
+
+func readBook(t *testing.T, book []byte) map[string]string {
+	zr, err := zip.NewReader(bytes.NewReader(book), int64(len(book)))
+	assert.Nil(t, err)
+	assert.Equal(t, "mimetype", zr.File[0].Name)
+	assert.Equal(t, zip.Store, zr.File[0].Method)
+
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, _ := f.Open()
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(content)
+	}
+	assert.Equal(t, "application/epub+zip", files["mimetype"])
+	return files
+}
+
+func TestStartExport(t *testing.T) {
+	collectionID := uuid.NewString()
+
+	cases := []struct {
+		name                        string
+		collectionID                string
+		err                         error
+		mockRepoBehaviour           func(mockRepo *mocks.ExportRepository)
+		mockCollectionRepoBehaviour func(mockRepo *mocks.CollectionRepository)
+	}{
+		{
+			name: "should queue user's library export",
+			err:  nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ExportRepository) {
+				mockRepo.On("SaveJob", context.Background(), mock.MatchedBy(func(job export.Job) bool {
+					return job.UserID == test.TestUser.ID && job.CollectionID == "" && job.Status == export.StatusQueued
+				})).Return(nil)
+			},
+			mockCollectionRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {},
+		},
+		{
+			name:         "should queue a shared collection's export",
+			collectionID: collectionID,
+			err:          nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ExportRepository) {
+				mockRepo.On("SaveJob", context.Background(), mock.MatchedBy(func(job export.Job) bool {
+					return job.CollectionID == collectionID
+				})).Return(nil)
+			},
+			mockCollectionRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {
+				mockRepo.On("FindRole", context.Background(), collectionID, test.TestUser.ID).Return(collection.RoleViewer, nil)
+			},
+		},
+		{
+			name:              "should return err when the collection isn't shared with the user",
+			collectionID:      collectionID,
+			err:               validation.NewError(validation.Forbidden, "forbidden access"),
+			mockRepoBehaviour: func(mockRepo *mocks.ExportRepository) {},
+			mockCollectionRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {
+				mockRepo.On("FindRole", context.Background(), collectionID, test.TestUser.ID).Return(collection.Role(""), nil)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.ExportRepository)
+			c.mockRepoBehaviour(r)
+			collectionRepo := new(mocks.CollectionRepository)
+			c.mockCollectionRepoBehaviour(collectionRepo)
+
+			s := NewService(logger.NewLogger(), sanitizer.NewSanitizer(), new(mocks.ImageFetcher), filestore.NewStore(t.TempDir()), r, new(mocks.ArticleRepository), collectionRepo, time.Hour)
+			job, err := s.StartExport(context.Background(), test.TestUser.ID, c.collectionID)
+			assert.Equal(t, c.err, err)
+			if err == nil {
+				assert.Equal(t, export.StatusQueued, job.Status)
+			}
+			r.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBuildQueuedExports(t *testing.T) {
+	collectionID := uuid.NewString()
+
+	cases := []struct {
+		name                        string
+		job                         *export.Job
+		built                       int
+		status                      export.Status
+		reason                      string
+		mockRepoBehaviour           func(mockRepo *mocks.ExportRepository)
+		mockCollectionRepoBehaviour func(mockRepo *mocks.CollectionRepository)
+	}{
+		{
+			name:   "should build the archive into the store",
+			job:    export.NewJob(test.TestUser.ID, ""),
+			built:  1,
+			status: export.StatusDone,
+			mockRepoBehaviour: func(mockRepo *mocks.ExportRepository) {
+				mockRepo.On("Snapshot", mock.Anything, mock.Anything).
+					Return(func(ctx context.Context, fn func(r export.ExportRepository) error) error {
+						return fn(mockRepo)
+					})
+				mockRepo.On("EachBatch", mock.Anything, export.Scope{UserID: test.TestUser.ID}, mock.Anything, mock.Anything).
+					Return(func(ctx context.Context, scope export.Scope, withContent bool, fn func(articles []*article.Article) error) error {
+						a := *test.TestArticle
+						return fn([]*article.Article{&a})
+					})
+			},
+			mockCollectionRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {},
+		},
+		{
+			name:   "should fail the export when the archive can't be built",
+			job:    export.NewJob(test.TestUser.ID, ""),
+			built:  0,
+			status: export.StatusFailed,
+			reason: "fail to build archive",
+			mockRepoBehaviour: func(mockRepo *mocks.ExportRepository) {
+				mockRepo.On("Snapshot", mock.Anything, mock.Anything).Return(errors.New("db error"))
+			},
+			mockCollectionRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {},
+		},
+		{
+			name:              "should fail the export when the user left the collection",
+			job:               export.NewJob(test.TestUser.ID, collectionID),
+			built:             0,
+			status:            export.StatusFailed,
+			reason:            "collection can't be exported anymore",
+			mockRepoBehaviour: func(mockRepo *mocks.ExportRepository) {},
+			mockCollectionRepoBehaviour: func(mockRepo *mocks.CollectionRepository) {
+				mockRepo.On("FindRole", context.Background(), collectionID, test.TestUser.ID).Return(collection.Role(""), nil)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.ExportRepository)
+			r.On("ListQueuedJobs", context.Background(), 2).Return([]*export.Job{c.job}, nil)
+			r.On("UpdateJob", context.Background(), mock.Anything).Return(nil)
+			c.mockRepoBehaviour(r)
+			collectionRepo := new(mocks.CollectionRepository)
+			c.mockCollectionRepoBehaviour(collectionRepo)
+			store := filestore.NewStore(t.TempDir())
+
+			s := NewService(logger.NewLogger(), sanitizer.NewSanitizer(), new(mocks.ImageFetcher), store, r, new(mocks.ArticleRepository), collectionRepo, time.Hour)
+			built, err := s.BuildQueuedExports(context.Background(), 2)
+			assert.Nil(t, err)
+			assert.Equal(t, c.built, built)
+			assert.Equal(t, c.status, c.job.Status)
+			assert.Equal(t, c.reason, c.job.Error)
+			assert.NotNil(t, c.job.ExpiresAt)
+			r.AssertCalled(t, "UpdateJob", context.Background(), *c.job)
+
+			f, err := store.Open(c.job.File())
+			if c.status != export.StatusDone {
+				assert.True(t, errors.Is(err, os.ErrNotExist))
+				return
+			}
+			defer f.Close()
+			info, _ := f.Stat()
+			assert.Equal(t, info.Size(), c.job.Size)
+			_, err = zip.NewReader(f, info.Size())
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestOpenExport(t *testing.T) {
+	done := export.NewJob(test.TestUser.ID, "")
+	done.Done(7, time.Now(), time.Hour)
+	missing := export.NewJob(test.TestUser.ID, "")
+	missing.Done(7, time.Now(), time.Hour)
+	expired := export.NewJob(test.TestUser.ID, "")
+	expired.Done(7, time.Now().Add(-2*time.Hour), time.Hour)
+	queued := export.NewJob(test.TestUser.ID, "")
+
+	cases := []struct {
+		name string
+		job  *export.Job
+		err  error
+	}{
+		{
+			name: "should open a built export",
+			job:  done,
+			err:  nil,
+		},
+		{
+			name: "should return err when the export isn't built yet",
+			job:  queued,
+			err:  validation.NewError(validation.Conflict, "export isn't ready to download"),
+		},
+		{
+			name: "should return err when the export expired",
+			job:  expired,
+			err:  validation.NewError(validation.NotFound, "export has expired"),
+		},
+		{
+			name: "should return err when the archive was removed",
+			job:  missing,
+			err:  validation.NewError(validation.NotFound, "export has expired"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.ExportRepository)
+			r.On("FindJob", context.Background(), test.TestUser.ID, c.job.ID).Return(c.job, nil)
+			store := filestore.NewStore(t.TempDir())
+			for _, job := range []*export.Job{done, expired} {
+				store.Write(job.File(), func(w io.Writer) error {
+					_, err := io.WriteString(w, "archive")
+					return err
+				})
+			}
+
+			s := NewService(logger.NewLogger(), sanitizer.NewSanitizer(), new(mocks.ImageFetcher), store, r, new(mocks.ArticleRepository), new(mocks.CollectionRepository), time.Hour)
+			job, f, err := s.OpenExport(context.Background(), test.TestUser.ID, c.job.ID)
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				assert.Nil(t, f)
+				return
+			}
+			defer f.Close()
+			assert.Equal(t, c.job, job)
+			content, _ := io.ReadAll(f)
+			assert.Equal(t, "archive", string(content))
+		})
+	}
+}
+
+func TestPurgeExpiredExports(t *testing.T) {
+	now := time.Now()
+	job := export.NewJob(test.TestUser.ID, "")
+	job.Done(7, now.Add(-2*time.Hour), time.Hour)
+
+	cases := []struct {
+		name              string
+		purged            int
+		err               error
+		mockRepoBehaviour func(mockRepo *mocks.ExportRepository)
+	}{
+		{
+			name:   "should remove expired exports and their archives",
+			purged: 1,
+			err:    nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ExportRepository) {
+				mockRepo.On("DeleteJob", context.Background(), job.ID).Return(nil)
+			},
+		},
+		{
+			name:   "should return err when an export can't be deleted",
+			purged: 0,
+			err:    errors.New("db error"),
+			mockRepoBehaviour: func(mockRepo *mocks.ExportRepository) {
+				mockRepo.On("DeleteJob", context.Background(), job.ID).Return(errors.New("db error"))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.ExportRepository)
+			r.On("ListExpiredJobs", context.Background(), now).Return([]*export.Job{job}, nil)
+			c.mockRepoBehaviour(r)
+			store := filestore.NewStore(t.TempDir())
+			store.Write(job.File(), func(w io.Writer) error { return nil })
+
+			s := NewService(logger.NewLogger(), sanitizer.NewSanitizer(), new(mocks.ImageFetcher), store, r, new(mocks.ArticleRepository), new(mocks.CollectionRepository), time.Hour)
+			purged, err := s.PurgeExpiredExports(context.Background(), now)
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.purged, purged)
+			_, err = store.Open(job.File())
+			assert.True(t, errors.Is(err, os.ErrNotExist))
+		})
+	}
+}
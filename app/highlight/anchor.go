@@ -0,0 +1,111 @@
+package highlight
+
+import (
+	"unicode"
+)
+
+// Anchor resolves the selector against an article's plain text and returns the rune range of the quote.
+// Every occurrence of the quote is scored by how much of its prefix and suffix context still matches,
+// using the position hint only to break ties, and when the quote is gone it's searched again ignoring
+// whitespace changes so highlights survive re-sanitization and edits elsewhere in the article.
+func Anchor(text string, s Selector) (start, end int, ok bool) {
+	runes := []rune(text)
+	exact := []rune(s.Exact)
+	if len(exact) == 0 {
+		return 0, 0, false
+	}
+
+	if start, ok = bestMatch(runes, exact, []rune(s.Prefix), []rune(s.Suffix), s.Start); ok {
+		return start, start + len(exact), true
+	}
+
+	normText, positions := collapseSpace(runes)
+	normExact, _ := collapseSpace(exact)
+	normPrefix, _ := collapseSpace([]rune(s.Prefix))
+	normSuffix, _ := collapseSpace([]rune(s.Suffix))
+	if len(normExact) == 0 {
+		return 0, 0, false
+	}
+
+	hint := len(normText)
+	for i, p := range positions {
+		if p >= s.Start {
+			hint = i
+			break
+		}
+	}
+	normStart, ok := bestMatch(normText, normExact, normPrefix, normSuffix, hint)
+	if !ok {
+		return 0, 0, false
+	}
+	return positions[normStart], positions[normStart+len(normExact)-1] + 1, true
+}
+
+// bestMatch returns the occurrence of exact whose surrounding text matches the most context,
+// ties are broken by the distance to the position hint.
+func bestMatch(text, exact, prefix, suffix []rune, hint int) (int, bool) {
+	best, bestScore, bestDistance := -1, -1, 0
+	for i := 0; i+len(exact) <= len(text); i++ {
+		if !equalRunes(text[i:i+len(exact)], exact) {
+			continue
+		}
+
+		score := commonSuffix(text[:i], prefix) + commonPrefix(text[i+len(exact):], suffix)
+		distance := i - hint
+		if distance < 0 {
+			distance = -distance
+		}
+		if score > bestScore || (score == bestScore && distance < bestDistance) {
+			best, bestScore, bestDistance = i, score, distance
+		}
+	}
+	return best, best >= 0
+}
+
+// collapseSpace replaces every run of whitespace with a single space and returns the
+// original index of each rune kept.
+func collapseSpace(runes []rune) ([]rune, []int) {
+	out := make([]rune, 0, len(runes))
+	positions := make([]int, 0, len(runes))
+	inSpace := false
+	for i, r := range runes {
+		if unicode.IsSpace(r) {
+			if inSpace {
+				continue
+			}
+			inSpace = true
+			r = ' '
+		} else {
+			inSpace = false
+		}
+		out = append(out, r)
+		positions = append(positions, i)
+	}
+	return out, positions
+}
+
+func equalRunes(a, b []rune) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func commonSuffix(text, prefix []rune) (n int) {
+	for n < len(text) && n < len(prefix) && text[len(text)-1-n] == prefix[len(prefix)-1-n] {
+		n++
+	}
+	return
+}
+
+func commonPrefix(text, suffix []rune) (n int) {
+	for n < len(text) && n < len(suffix) && text[n] == suffix[n] {
+		n++
+	}
+	return
+}
@@ -0,0 +1,87 @@
+package highlight
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnchor(t *testing.T) {
+	text := "Go is fun. Rust is fun too. Go is fast and Go is simple."
+
+	cases := []struct {
+		name     string
+		text     string
+		selector Selector
+		start    int
+		end      int
+		ok       bool
+	}{
+		{
+			name:     "should anchor on the position hint",
+			text:     text,
+			selector: Selector{Exact: "Rust is fun", Start: 11, End: 22},
+			start:    11,
+			end:      22,
+			ok:       true,
+		},
+		{
+			name:     "should pick the occurrence matching prefix and suffix context",
+			text:     text,
+			selector: Selector{Exact: "Go is", Prefix: "fast and ", Suffix: " simple", Start: 0, End: 5},
+			start:    43,
+			end:      48,
+			ok:       true,
+		},
+		{
+			name:     "should re-anchor after content is inserted before the quote",
+			text:     "Preface. " + text,
+			selector: Selector{Exact: "Rust is fun", Prefix: "fun. ", Suffix: " too", Start: 11, End: 22},
+			start:    20,
+			end:      31,
+			ok:       true,
+		},
+		{
+			name:     "should prefer the occurrence closest to the hint without context",
+			text:     text,
+			selector: Selector{Exact: "Go is", Start: 30, End: 35},
+			start:    28,
+			end:      33,
+			ok:       true,
+		},
+		{
+			name:     "should anchor when whitespace changed after re-sanitization",
+			text:     "Go is fun.\nRust  is\tfun too.",
+			selector: Selector{Exact: "Rust is fun", Prefix: "fun. ", Start: 11, End: 22},
+			start:    11,
+			end:      23,
+			ok:       true,
+		},
+		{
+			name:     "should count positions in runes",
+			text:     "Kopi ☕ enak. Teh 🍵 juga enak.",
+			selector: Selector{Exact: "juga enak", Start: 0, End: 9},
+			start:    19,
+			end:      28,
+			ok:       true,
+		},
+		{
+			name:     "should be orphaned when the quote was removed",
+			text:     text,
+			selector: Selector{Exact: "Zig is fun", Start: 11, End: 21},
+			ok:       false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			start, end, ok := Anchor(c.text, c.selector)
+			assert.Equal(t, c.ok, ok)
+			if !ok {
+				return
+			}
+			assert.Equal(t, c.start, start)
+			assert.Equal(t, c.end, end)
+		})
+	}
+}
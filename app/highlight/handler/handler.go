@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ryanadiputraa/unclatter/app/highlight"
+	"github.com/ryanadiputraa/unclatter/app/middleware"
+	"github.com/ryanadiputraa/unclatter/app/pagination"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	_http "github.com/ryanadiputraa/unclatter/pkg/http"
+	"github.com/ryanadiputraa/unclatter/pkg/validator"
+)
+
+type handler struct {
+	rw               _http.ResponseWriter
+	highlightService highlight.HighlightService
+	validator        validator.Validator
+}
+
+func NewHandler(web *http.ServeMux, rw _http.ResponseWriter, highlightService highlight.HighlightService, authMiddleware middleware.AuthMiddleware, validator validator.Validator) {
+	h := &handler{
+		rw:               rw,
+		highlightService: highlightService,
+		validator:        validator,
+	}
+
+	web.Handle("POST /api/articles/bookmarks/{id}/highlights", authMiddleware.ParseJWTToken(h.CreateHighlight()))
+	web.Handle("GET /api/articles/bookmarks/{id}/highlights", authMiddleware.ParseJWTToken(h.ListArticleHighlights()))
+	web.Handle("PUT /api/articles/bookmarks/{id}/highlights/{highlightID}", authMiddleware.ParseJWTToken(h.UpdateHighlight()))
+	web.Handle("DELETE /api/articles/bookmarks/{id}/highlights/{highlightID}", authMiddleware.ParseJWTToken(h.DeleteHighlight()))
+	web.Handle("GET /api/highlights", authMiddleware.ParseJWTToken(h.ListHighlights()))
+}
+
+func (h *handler) CreateHighlight() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		id := r.PathValue("id")
+		var payload highlight.HighlightPayload
+
+		json.NewDecoder(r.Body).Decode(&payload)
+		if err, errMap := h.validator.Validate(payload); err != nil {
+			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+			return
+		}
+
+		created, err := h.highlightService.CreateHighlight(ac.Context, ac.UserID, id, payload)
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusCreated, created)
+	}
+}
+
+func (h *handler) ListArticleHighlights() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		id := r.PathValue("id")
+
+		highlights, err := h.highlightService.ListArticleHighlights(ac.Context, ac.UserID, id)
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, highlights)
+	}
+}
+
+func (h *handler) UpdateHighlight() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		id := r.PathValue("id")
+		highlightID := r.PathValue("highlightID")
+		var payload highlight.UpdateHighlightPayload
+
+		json.NewDecoder(r.Body).Decode(&payload)
+		if err, errMap := h.validator.Validate(payload); err != nil {
+			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+			return
+		}
+
+		updated, err := h.highlightService.UpdateHighlight(ac.Context, ac.UserID, id, highlightID, payload)
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, updated)
+	}
+}
+
+func (h *handler) DeleteHighlight() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		err := h.highlightService.DeleteHighlight(ac.Context, ac.UserID, r.PathValue("id"), r.PathValue("highlightID"))
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, nil)
+	}
+}
+
+func (h *handler) ListHighlights() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		query := r.URL.Query()
+
+		pagination, errMap, err := pagination.ValidateParam(query.Get("page"), query.Get("size"))
+		if err != nil {
+			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+			return
+		}
+
+		highlights, meta, err := h.highlightService.ListHighlights(ac.Context, ac.UserID, *pagination)
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseDataWithPagination(w, http.StatusOK, highlights, *meta)
+	}
+}
+
+func (h *handler) writeErr(w http.ResponseWriter, err error) {
+	if vErr, ok := err.(*validation.Error); ok {
+		h.rw.WriteErrMessage(w, validation.HttpErrMap[vErr.Err], vErr.Message)
+		return
+	}
+	h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+}
@@ -0,0 +1,93 @@
+package highlight
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/pagination"
+)
+
+const DefaultColor = "yellow"
+
+// Selector anchors a highlight to the article's plain text. The quote with its surrounding context is the
+// source of truth, Start and End are rune offsets used as a hint to pick between repeated quotes.
+type Selector struct {
+	Exact  string `json:"exact" gorm:"type:text;not null" validate:"required,max=5000"`
+	Prefix string `json:"prefix" gorm:"type:varchar;not null" validate:"max=64"`
+	Suffix string `json:"suffix" gorm:"type:varchar;not null" validate:"max=64"`
+	Start  int    `json:"start" gorm:"column:start_offset;not null" validate:"gte=0"`
+	End    int    `json:"end" gorm:"column:end_offset;not null" validate:"gtefield=Start"`
+}
+
+type Highlight struct {
+	ID        string          `json:"id" gorm:"type:varchar"`
+	ArticleID string          `json:"article_id" gorm:"type:varchar;not null;index"`
+	UserID    string          `json:"-" gorm:"type:varchar;not null;index"`
+	Selector  Selector        `json:"selector" gorm:"embedded"`
+	Color     string          `json:"color" gorm:"type:varchar;not null"`
+	Note      string          `json:"note" gorm:"type:text;not null"`
+	CreatedAt time.Time       `json:"created_at" gorm:"type:timestamptz;not null"`
+	UpdatedAt time.Time       `json:"updated_at" gorm:"type:timestamptz;not null"`
+	Article   article.Article `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+
+	// ArticleTitle and ArticleLink are only populated on the cross article feed.
+	ArticleTitle string `json:"article_title,omitempty" gorm:"->;-:migration"`
+	ArticleLink  string `json:"article_link,omitempty" gorm:"->;-:migration"`
+	// Orphaned is set when the quote can no longer be found in the current article content.
+	Orphaned bool `json:"orphaned" gorm:"-"`
+}
+
+type NewHighlightArg struct {
+	ArticleID string
+	UserID    string
+	Selector  Selector
+	Color     string
+	Note      string
+}
+
+type HighlightPayload struct {
+	Selector Selector `json:"selector"`
+	Color    string   `json:"color" validate:"omitempty,oneof=yellow green blue pink purple"`
+	Note     string   `json:"note" validate:"max=10000"`
+}
+
+type UpdateHighlightPayload struct {
+	Color string `json:"color" validate:"omitempty,oneof=yellow green blue pink purple"`
+	Note  string `json:"note" validate:"max=10000"`
+}
+
+func NewHighlight(arg NewHighlightArg) *Highlight {
+	color := arg.Color
+	if color == "" {
+		color = DefaultColor
+	}
+
+	return &Highlight{
+		ID:        uuid.NewString(),
+		ArticleID: arg.ArticleID,
+		UserID:    arg.UserID,
+		Selector:  arg.Selector,
+		Color:     color,
+		Note:      arg.Note,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+}
+
+type HighlightService interface {
+	CreateHighlight(ctx context.Context, userID, articleID string, arg HighlightPayload) (*Highlight, error)
+	ListArticleHighlights(ctx context.Context, userID, articleID string) ([]*Highlight, error)
+	UpdateHighlight(ctx context.Context, userID, articleID, highlightID string, arg UpdateHighlightPayload) (*Highlight, error)
+	DeleteHighlight(ctx context.Context, userID, articleID, highlightID string) error
+	ListHighlights(ctx context.Context, userID string, page pagination.Pagination) ([]*Highlight, *pagination.Meta, error)
+}
+
+type HighlightRepository interface {
+	Save(ctx context.Context, arg Highlight) error
+	ListByArticle(ctx context.Context, userID, articleID string) ([]*Highlight, error)
+	List(ctx context.Context, userID string, page pagination.Pagination) (highlights []*Highlight, total int64, err error)
+	Update(ctx context.Context, arg Highlight) (*Highlight, error)
+	Delete(ctx context.Context, userID, articleID, highlightID string) error
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ryanadiputraa/unclatter/app/highlight"
+	"github.com/ryanadiputraa/unclatter/app/pagination"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) highlight.HighlightRepository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Save(ctx context.Context, arg highlight.Highlight) error {
+	return r.db.Omit(clause.Associations).Create(&arg).Error
+}
+
+func (r *repository) ListByArticle(ctx context.Context, userID, articleID string) (highlights []*highlight.Highlight, err error) {
+	err = r.db.
+		Where("user_id = ? AND article_id = ?", userID, articleID).
+		Order("start_offset ASC, created_at ASC").
+		Find(&highlights).Error
+	return
+}
+
+func (r *repository) List(ctx context.Context, userID string, page pagination.Pagination) (highlights []*highlight.Highlight, total int64, err error) {
+	// highlights of trashed articles are hidden until the article is restored
+	err = r.db.Model(&highlight.Highlight{}).
+		Joins("JOIN articles ON articles.id = highlights.article_id").
+		Where("highlights.user_id = ? AND articles.deleted_at IS NULL", userID).
+		Count(&total).Error
+	if err != nil {
+		return
+	}
+
+	err = r.db.
+		Select("highlights.*, articles.title AS article_title, articles.article_link AS article_link").
+		Joins("JOIN articles ON articles.id = highlights.article_id").
+		Where("highlights.user_id = ? AND articles.deleted_at IS NULL", userID).
+		Order("highlights.created_at DESC, highlights.id DESC").
+		Limit(page.Limit).Offset(page.Offset).
+		Find(&highlights).Error
+	return
+}
+
+func (r *repository) Update(ctx context.Context, arg highlight.Highlight) (updated *highlight.Highlight, err error) {
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&updated, "id = ? AND article_id = ?", arg.ID, arg.ArticleID).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				err = validation.NewError(validation.NotFound, "no highlight found with given id")
+			}
+			return err
+		}
+
+		if updated.UserID != arg.UserID {
+			return validation.NewError(validation.Forbidden, "forbidden access")
+		}
+
+		if arg.Color != "" {
+			updated.Color = arg.Color
+		}
+		updated.Note = arg.Note
+		updated.UpdatedAt = arg.UpdatedAt
+
+		return tx.Model(&updated).Omit(clause.Associations).Select("color", "note", "updated_at").Updates(updated).Error
+	})
+
+	return
+}
+
+func (r *repository) Delete(ctx context.Context, userID, articleID, highlightID string) error {
+	res := r.db.Where("id = ? AND article_id = ? AND user_id = ?", highlightID, articleID, userID).Delete(&highlight.Highlight{})
+	if res.RowsAffected == 0 && res.Error == nil {
+		return validation.NewError(validation.NotFound, "no highlight found with given id")
+	}
+	return res.Error
+}
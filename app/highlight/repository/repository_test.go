@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/ryanadiputraa/unclatter/app/highlight"
+	"github.com/ryanadiputraa/unclatter/app/pagination"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/test"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+const selectFromHighlights = "^SELECT (.+) FROM \"highlights\""
+
+var testHighlight = highlight.NewHighlight(highlight.NewHighlightArg{
+	ArticleID: test.TestArticle.ID,
+	UserID:    test.TestUser.ID,
+	Selector:  highlight.Selector{Exact: "article content", Prefix: "Google", Start: 6, End: 21},
+	Note:      "worth rereading",
+})
+
+func TestSave(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	expectedExec := "^INSERT INTO \"highlights\""
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		err           error
+	}{
+		{
+			name: "should insert new highlight",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(expectedExec).
+					WithArgs(testHighlight.ID, testHighlight.ArticleID, testHighlight.UserID, testHighlight.Selector.Exact,
+						testHighlight.Selector.Prefix, testHighlight.Selector.Suffix, testHighlight.Selector.Start, testHighlight.Selector.End,
+						testHighlight.Color, testHighlight.Note, testHighlight.CreatedAt, testHighlight.UpdatedAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			err: nil,
+		},
+		{
+			name: "should return error when fail to insert new highlight",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(expectedExec).WillReturnError(gorm.ErrInvalidDB)
+				mock.ExpectRollback()
+			},
+			err: gorm.ErrInvalidDB,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+			err := r.Save(context.Background(), *testHighlight)
+			assert.Equal(t, c.err, err)
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	page := pagination.Pagination{Limit: 10, Offset: 10}
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		total         int64
+		err           error
+	}{
+		{
+			name: "should return user's highlights with their article",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT count(.+) FROM \"highlights\" JOIN articles (.+) WHERE highlights.user_id = (.+) AND articles.deleted_at IS NULL").
+					WithArgs(test.TestUser.ID).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(11))
+				mock.ExpectQuery("^SELECT highlights.\\*, articles.title AS article_title, articles.article_link AS article_link FROM \"highlights\" JOIN articles (.+) ORDER BY highlights.created_at DESC").
+					WithArgs(test.TestUser.ID, page.Limit, page.Offset).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "article_id", "exact", "article_title", "article_link"}).
+						AddRow(testHighlight.ID, testHighlight.ArticleID, testHighlight.Selector.Exact, test.TestArticle.Title, test.TestArticle.ArticleLink))
+			},
+			total: 11,
+			err:   nil,
+		},
+		{
+			name: "should return err when fail to count highlights",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT count(.+) FROM \"highlights\"").WillReturnError(gorm.ErrInvalidDB)
+			},
+			total: 0,
+			err:   gorm.ErrInvalidDB,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+
+			highlights, total, err := r.List(context.Background(), test.TestUser.ID, page)
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.total, total)
+			if err != nil {
+				return
+			}
+			assert.Len(t, highlights, 1)
+			assert.Equal(t, testHighlight.Selector.Exact, highlights[0].Selector.Exact)
+			assert.Equal(t, test.TestArticle.Title, highlights[0].ArticleTitle)
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	update := highlight.Highlight{
+		ID:        testHighlight.ID,
+		ArticleID: testHighlight.ArticleID,
+		UserID:    testHighlight.UserID,
+		Color:     "green",
+		Note:      "",
+		UpdatedAt: time.Now().UTC(),
+	}
+	invalidUpdate := update
+	invalidUpdate.UserID = uuid.NewString()
+
+	cases := []struct {
+		name          string
+		arg           highlight.Highlight
+		mockBehaviour func(mock sqlmock.Sqlmock, arg highlight.Highlight)
+		err           error
+	}{
+		{
+			name: "should update highlight color and clear its note",
+			arg:  update,
+			mockBehaviour: func(mock sqlmock.Sqlmock, arg highlight.Highlight) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectFromHighlights).
+					WithArgs(arg.ID, arg.ArticleID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "article_id", "user_id", "color", "note"}).
+						AddRow(testHighlight.ID, testHighlight.ArticleID, testHighlight.UserID, testHighlight.Color, testHighlight.Note))
+				mock.ExpectExec("^UPDATE \"highlights\" SET \"color\"=(.+),\"note\"=(.+),\"updated_at\"=(.+) WHERE \"id\" = ").
+					WithArgs("green", "", test.AnyTime{}, arg.ID).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			err: nil,
+		},
+		{
+			name: "should return err when updating non existing highlight",
+			arg:  update,
+			mockBehaviour: func(mock sqlmock.Sqlmock, arg highlight.Highlight) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectFromHighlights).
+					WithArgs(arg.ID, arg.ArticleID, 1).
+					WillReturnError(gorm.ErrRecordNotFound)
+				mock.ExpectRollback()
+			},
+			err: validation.NewError(validation.NotFound, "no highlight found with given id"),
+		},
+		{
+			name: "should return err when updating another user's highlight",
+			arg:  invalidUpdate,
+			mockBehaviour: func(mock sqlmock.Sqlmock, arg highlight.Highlight) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectFromHighlights).
+					WithArgs(arg.ID, arg.ArticleID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "article_id", "user_id"}).
+						AddRow(testHighlight.ID, testHighlight.ArticleID, testHighlight.UserID))
+				mock.ExpectRollback()
+			},
+			err: validation.NewError(validation.Forbidden, "forbidden access"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock, c.arg)
+
+			updated, err := r.Update(context.Background(), c.arg)
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.arg.Color, updated.Color)
+			assert.Equal(t, c.arg.Note, updated.Note)
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	expectedExec := "^DELETE FROM \"highlights\" WHERE id = (.+) AND article_id = (.+) AND user_id = "
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		err           error
+	}{
+		{
+			name: "should delete user's highlight",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(expectedExec).
+					WithArgs(testHighlight.ID, testHighlight.ArticleID, testHighlight.UserID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			err: nil,
+		},
+		{
+			name: "should return not found err when no highlight deleted",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(expectedExec).
+					WithArgs(testHighlight.ID, testHighlight.ArticleID, testHighlight.UserID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			err: validation.NewError(validation.NotFound, "no highlight found with given id"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+			err := r.Delete(context.Background(), testHighlight.UserID, testHighlight.ArticleID, testHighlight.ID)
+			assert.Equal(t, c.err, err)
+		})
+	}
+}
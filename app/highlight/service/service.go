@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/highlight"
+	"github.com/ryanadiputraa/unclatter/app/pagination"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/pkg/logger"
+	"github.com/ryanadiputraa/unclatter/pkg/plaintext"
+)
+
+type service struct {
+	log               logger.Logger
+	repository        highlight.HighlightRepository
+	articleRepository article.ArticleRepository
+}
+
+func NewService(log logger.Logger, repository highlight.HighlightRepository, articleRepository article.ArticleRepository) highlight.HighlightService {
+	return &service{
+		log:               log,
+		repository:        repository,
+		articleRepository: articleRepository,
+	}
+}
+
+func (s *service) CreateHighlight(ctx context.Context, userID, articleID string, arg highlight.HighlightPayload) (*highlight.Highlight, error) {
+	a, err := s.findArticle(ctx, userID, articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// store the resolved position so the hint matches the content the highlight was made on
+	selector := arg.Selector
+	start, end, ok := highlight.Anchor(plaintext.FromHTML(a.Content), selector)
+	if !ok {
+		return nil, validation.NewError(validation.BadRequest, "highlighted text is not found in the article")
+	}
+	selector.Start, selector.End = start, end
+
+	h := highlight.NewHighlight(highlight.NewHighlightArg{
+		ArticleID: articleID,
+		UserID:    userID,
+		Selector:  selector,
+		Color:     arg.Color,
+		Note:      arg.Note,
+	})
+	if err = s.repository.Save(ctx, *h); err != nil {
+		s.log.Error("highlight service: fail to save highlight", err)
+		return nil, err
+	}
+	return h, nil
+}
+
+func (s *service) ListArticleHighlights(ctx context.Context, userID, articleID string) ([]*highlight.Highlight, error) {
+	a, err := s.findArticle(ctx, userID, articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	highlights, err := s.repository.ListByArticle(ctx, userID, articleID)
+	if err != nil {
+		s.log.Error("highlight service: fail to fetch article highlights", err)
+		return nil, err
+	}
+
+	text := plaintext.FromHTML(a.Content)
+	for _, h := range highlights {
+		start, end, ok := highlight.Anchor(text, h.Selector)
+		if !ok {
+			h.Orphaned = true
+			continue
+		}
+		h.Selector.Start, h.Selector.End = start, end
+	}
+	return highlights, nil
+}
+
+func (s *service) UpdateHighlight(ctx context.Context, userID, articleID, highlightID string, arg highlight.UpdateHighlightPayload) (updated *highlight.Highlight, err error) {
+	updated, err = s.repository.Update(ctx, highlight.Highlight{
+		ID:        highlightID,
+		ArticleID: articleID,
+		UserID:    userID,
+		Color:     arg.Color,
+		Note:      arg.Note,
+		UpdatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		s.log.Warn("highlight service: fail to update highlight", err)
+	}
+	return
+}
+
+func (s *service) DeleteHighlight(ctx context.Context, userID, articleID, highlightID string) error {
+	if err := s.repository.Delete(ctx, userID, articleID, highlightID); err != nil {
+		s.log.Warn("highlight service: fail to delete highlight", err)
+		return err
+	}
+	return nil
+}
+
+func (s *service) ListHighlights(ctx context.Context, userID string, page pagination.Pagination) (highlights []*highlight.Highlight, meta *pagination.Meta, err error) {
+	highlights, total, err := s.repository.List(ctx, userID, page)
+	if err != nil {
+		s.log.Error("highlight service: fail to fetch user's highlights", err)
+		return
+	}
+
+	meta = pagination.NewMeta(page, total)
+	return
+}
+
+func (s *service) findArticle(ctx context.Context, userID, articleID string) (*article.Article, error) {
+	a, err := s.articleRepository.FindByID(ctx, articleID)
+	if err != nil {
+		s.log.Warn("highlight service: fail to fetch article ", articleID, " ", err)
+		return nil, err
+	}
+
+	if a.UserID != userID {
+		return nil, validation.NewError(validation.Forbidden, "forbidden access")
+	}
+	return a, nil
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/highlight"
+	"github.com/ryanadiputraa/unclatter/app/mocks"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/pkg/logger"
+	"github.com/ryanadiputraa/unclatter/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateHighlight(t *testing.T) {
+	cases := []struct {
+		name                     string
+		userID                   string
+		arg                      highlight.HighlightPayload
+		expected                 highlight.Selector
+		err                      error
+		mockArticleRepoBehaviour func(mockRepo *mocks.ArticleRepository)
+		mockRepoBehaviour        func(mockRepo *mocks.HighlightRepository)
+	}{
+		{
+			name:   "should save highlight anchored to the article text",
+			userID: test.TestArticle.UserID,
+			arg: highlight.HighlightPayload{
+				Selector: highlight.Selector{Exact: "article content", Start: 0, End: 15},
+				Note:     "note",
+			},
+			expected: highlight.Selector{Exact: "article content", Start: 7, End: 22},
+			err:      nil,
+			mockArticleRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+			},
+			mockRepoBehaviour: func(mockRepo *mocks.HighlightRepository) {
+				mockRepo.On("Save", context.Background(), mock.Anything).Return(nil)
+			},
+		},
+		{
+			name:   "should return err when quote isn't in the article",
+			userID: test.TestArticle.UserID,
+			arg: highlight.HighlightPayload{
+				Selector: highlight.Selector{Exact: "not in article", Start: 0, End: 14},
+			},
+			err: validation.NewError(validation.BadRequest, "highlighted text is not found in the article"),
+			mockArticleRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+			},
+			mockRepoBehaviour: func(mockRepo *mocks.HighlightRepository) {},
+		},
+		{
+			name:   "should return err when highlighting other user's article",
+			userID: uuid.NewString(),
+			arg: highlight.HighlightPayload{
+				Selector: highlight.Selector{Exact: "article content", Start: 0, End: 15},
+			},
+			err: validation.NewError(validation.Forbidden, "forbidden access"),
+			mockArticleRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+			},
+			mockRepoBehaviour: func(mockRepo *mocks.HighlightRepository) {},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			articleRepo := new(mocks.ArticleRepository)
+			c.mockArticleRepoBehaviour(articleRepo)
+			r := new(mocks.HighlightRepository)
+			c.mockRepoBehaviour(r)
+
+			s := NewService(logger.NewLogger(), r, articleRepo)
+			h, err := s.CreateHighlight(context.Background(), c.userID, test.TestArticle.ID, c.arg)
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				return
+			}
+
+			assert.NotEmpty(t, h.ID)
+			assert.Equal(t, c.expected, h.Selector)
+			assert.Equal(t, highlight.DefaultColor, h.Color)
+			assert.Equal(t, c.arg.Note, h.Note)
+		})
+	}
+}
+
+func TestListArticleHighlights(t *testing.T) {
+	edited := &article.Article{
+		ID:      test.TestArticle.ID,
+		UserID:  test.TestArticle.UserID,
+		Content: "<h1>Intro</h1><div><a href=\"http://www.google.com\">Google</a><p>article content</p></div>",
+	}
+	kept := &highlight.Highlight{ID: uuid.NewString(), Selector: highlight.Selector{Exact: "article content", Prefix: "Google\n", Start: 7, End: 22}}
+	removed := &highlight.Highlight{ID: uuid.NewString(), Selector: highlight.Selector{Exact: "removed paragraph", Start: 30, End: 47}}
+
+	articleRepo := new(mocks.ArticleRepository)
+	articleRepo.On("FindByID", context.Background(), edited.ID).Return(edited, nil)
+	r := new(mocks.HighlightRepository)
+	r.On("ListByArticle", context.Background(), edited.UserID, edited.ID).Return([]*highlight.Highlight{kept, removed}, nil)
+
+	s := NewService(logger.NewLogger(), r, articleRepo)
+	highlights, err := s.ListArticleHighlights(context.Background(), edited.UserID, edited.ID)
+
+	assert.NoError(t, err)
+	assert.Len(t, highlights, 2)
+	assert.False(t, highlights[0].Orphaned)
+	assert.Equal(t, 13, highlights[0].Selector.Start)
+	assert.Equal(t, 28, highlights[0].Selector.End)
+	assert.True(t, highlights[1].Orphaned)
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/ryanadiputraa/unclatter/app/importer"
+	"github.com/ryanadiputraa/unclatter/app/middleware"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	_http "github.com/ryanadiputraa/unclatter/pkg/http"
+)
+
+const (
+	maxUploadSize = 32 << 20
+	// maxMemory is the part of the upload kept in memory, the rest is buffered in a temporary file.
+	maxMemory = 8 << 20
+)
+
+type handler struct {
+	rw            _http.ResponseWriter
+	importService importer.ImportService
+}
+
+func NewHandler(web *http.ServeMux, rw _http.ResponseWriter, importService importer.ImportService, authMiddleware middleware.AuthMiddleware) {
+	h := &handler{
+		rw:            rw,
+		importService: importService,
+	}
+
+	web.Handle("POST /api/imports", authMiddleware.ParseJWTToken(h.StartImport()))
+	web.Handle("GET /api/imports", authMiddleware.ParseJWTToken(h.ListImports()))
+	web.Handle("GET /api/imports/{id}", authMiddleware.ParseJWTToken(h.GetImport()))
+}
+
+func (h *handler) StartImport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+		if err := r.ParseMultipartForm(maxMemory); err != nil {
+			h.rw.WriteErrMessage(w, http.StatusBadRequest, "import file should be a multipart upload of at most 32MB")
+			return
+		}
+		defer r.MultipartForm.RemoveAll()
+
+		errMap := map[string]string{}
+		format := r.FormValue("format")
+		if format == "" {
+			errMap["format"] = "format is required"
+		}
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			errMap["file"] = "file is required"
+		} else {
+			defer file.Close()
+		}
+		if len(errMap) > 0 {
+			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+			return
+		}
+
+		job, err := h.importService.StartImport(ac.Context, ac.UserID, importer.Format(format), file)
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusAccepted, job)
+	}
+}
+
+func (h *handler) ListImports() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		jobs, err := h.importService.ListImports(ac.Context, ac.UserID)
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, jobs)
+	}
+}
+
+func (h *handler) GetImport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		job, err := h.importService.GetImport(ac.Context, ac.UserID, r.PathValue("id"))
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, job)
+	}
+}
+
+func (h *handler) writeErr(w http.ResponseWriter, err error) {
+	if vErr, ok := err.(*validation.Error); ok {
+		h.rw.WriteErrMessage(w, validation.HttpErrMap[vErr.Err], vErr.Message)
+		return
+	}
+	h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+}
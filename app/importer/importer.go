@@ -0,0 +1,240 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/tag"
+)
+
+// MaxRecords caps the bookmarks of a single import file.
+const MaxRecords = 5000
+
+type Format string
+
+const (
+	FormatNetscape      Format = "netscape"
+	FormatPocketHTML    Format = "pocket_html"
+	FormatPocketCSV     Format = "pocket_csv"
+	FormatInstapaperCSV Format = "instapaper_csv"
+	FormatOmnivoreJSON  Format = "omnivore_json"
+	FormatWallabagJSON  Format = "wallabag_json"
+)
+
+func (f Format) IsValid() bool {
+	switch f {
+	case FormatNetscape, FormatPocketHTML, FormatPocketCSV, FormatInstapaperCSV, FormatOmnivoreJSON, FormatWallabagJSON:
+		return true
+	default:
+		return false
+	}
+}
+
+type JobStatus string
+
+const (
+	// JobProcessing jobs still have articles waiting for their content to be scraped.
+	JobProcessing JobStatus = "processing"
+	JobCompleted  JobStatus = "completed"
+)
+
+type ItemStatus string
+
+const (
+	// ItemPending items are bookmarked and wait in the queue for their content to be scraped.
+	ItemPending ItemStatus = "pending"
+	ItemDone    ItemStatus = "done"
+	// ItemFailed items couldn't be bookmarked or scraped.
+	ItemFailed ItemStatus = "failed"
+	// ItemSkipped items weren't bookmarked, like invalid or already bookmarked links.
+	ItemSkipped ItemStatus = "skipped"
+)
+
+// Job is a single import file, its items are bookmarked right away and their content is scraped in the background.
+type Job struct {
+	ID        string    `json:"id" gorm:"type:varchar"`
+	UserID    string    `json:"-" gorm:"type:varchar;not null;index"`
+	Format    Format    `json:"format" gorm:"type:varchar;not null"`
+	Total     int       `json:"total" gorm:"type:integer;not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"type:timestamptz;not null"`
+
+	Items []*Item `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+
+	// Status, Progress and Errors are derived from the job's items.
+	Status   JobStatus `json:"status" gorm:"-"`
+	Progress Progress  `json:"progress" gorm:"-"`
+	Errors   []*Item   `json:"errors,omitempty" gorm:"-"`
+}
+
+func (Job) TableName() string {
+	return "import_jobs"
+}
+
+func NewJob(userID string, format Format, total int) *Job {
+	return &Job{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		Format:    format,
+		Total:     total,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// Summarize sets the job's status from its progress.
+func (j *Job) Summarize(progress Progress) {
+	j.Progress = progress
+	j.Status = JobCompleted
+	if progress.Pending > 0 {
+		j.Status = JobProcessing
+	}
+}
+
+// Progress counts the job's items by status.
+type Progress struct {
+	Pending int64 `json:"pending"`
+	Done    int64 `json:"done"`
+	Failed  int64 `json:"failed"`
+	Skipped int64 `json:"skipped"`
+}
+
+func (p *Progress) Add(status ItemStatus, count int64) {
+	switch status {
+	case ItemPending:
+		p.Pending += count
+	case ItemDone:
+		p.Done += count
+	case ItemFailed:
+		p.Failed += count
+	case ItemSkipped:
+		p.Skipped += count
+	}
+}
+
+// Item is a single bookmark of an import file.
+type Item struct {
+	ID    string `json:"-" gorm:"type:varchar"`
+	JobID string `json:"-" gorm:"type:varchar;not null;index"`
+	// Position is the 1-based position of the bookmark in the import file.
+	Position  int        `json:"position" gorm:"type:integer;not null"`
+	URL       string     `json:"url" gorm:"type:varchar;not null"`
+	ArticleID *string    `json:"article_id,omitempty" gorm:"type:varchar"`
+	Status    ItemStatus `json:"status" gorm:"type:varchar;not null;index"`
+	Error     string     `json:"error,omitempty" gorm:"type:varchar;not null;default:''"`
+	UpdatedAt time.Time  `json:"-" gorm:"type:timestamptz;not null"`
+
+	Article *article.Article `json:"-" gorm:"constraint:OnDelete:SET NULL"`
+}
+
+func (Item) TableName() string {
+	return "import_items"
+}
+
+// Bookmark is an article of the import file, it's saved with the item's job and the item is pointed to it.
+type Bookmark struct {
+	Article *article.Article
+	Tags    []tag.Tag
+	Item    *Item
+}
+
+func NewItem(jobID string, position int, url string) *Item {
+	return &Item{
+		ID:        uuid.NewString(),
+		JobID:     jobID,
+		Position:  position,
+		URL:       url,
+		Status:    ItemPending,
+		UpdatedAt: time.Now().UTC(),
+	}
+}
+
+func (i *Item) Skip(reason string) {
+	i.Status = ItemSkipped
+	i.Error = reason
+}
+
+func (i *Item) Fail(reason string) {
+	i.Status = ItemFailed
+	i.Error = reason
+}
+
+// Record is a bookmark read from an import file, fields the format doesn't have are left empty.
+type Record struct {
+	URL      string
+	Title    string
+	Tags     []string
+	SavedAt  time.Time
+	Read     bool
+	Archived bool
+	Favorite bool
+	// Content is the article html for formats that export it.
+	Content string
+}
+
+var ErrInvalidURL = errors.New("invalid url")
+
+// NewArticle creates the bookmark of a record, keeping its saved date and states.
+func NewArticle(userID string, rec Record) (*article.Article, error) {
+	link := strings.TrimSpace(rec.URL)
+	u, err := url.Parse(link)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, ErrInvalidURL
+	}
+
+	title := strings.TrimSpace(rec.Title)
+	if title == "" {
+		title = link
+	}
+
+	a := article.NewArticle(article.NewArticleArg{
+		Title:       title,
+		Content:     rec.Content,
+		ArticleLink: link,
+		UserID:      userID,
+	})
+	if !rec.SavedAt.IsZero() && rec.SavedAt.Before(a.CreatedAt) {
+		a.CreatedAt = rec.SavedAt.UTC()
+		a.UpdatedAt = a.CreatedAt
+	}
+	// the export formats don't keep when an article was read, so the states are dated when it was saved
+	at := a.CreatedAt
+	if rec.Read || rec.Archived {
+		a.ReadAt = &at
+	}
+	if rec.Archived {
+		a.ArchivedAt = &at
+	}
+	if rec.Favorite {
+		a.FavoritedAt = &at
+	}
+	return a, nil
+}
+
+type ImportService interface {
+	// StartImport bookmarks the file's links and queues their content to be scraped in the background.
+	StartImport(ctx context.Context, userID string, format Format, file io.Reader) (*Job, error)
+	ListImports(ctx context.Context, userID string) ([]*Job, error)
+	// GetImport returns the job's progress along with the items that couldn't be imported.
+	GetImport(ctx context.Context, userID, jobID string) (*Job, error)
+	// ScrapePending scrapes the content of up to limit queued articles and returns how many were processed.
+	ScrapePending(ctx context.Context, limit int) (int, error)
+}
+
+type ImportRepository interface {
+	// Save creates the job along with its items and bookmarks in batches. The item of a bookmark whose link the
+	// user already bookmarked is skipped instead.
+	Save(ctx context.Context, job Job, items []*Item, bookmarks []Bookmark) error
+	ListJobs(ctx context.Context, userID string) ([]*Job, error)
+	FindJob(ctx context.Context, userID, jobID string) (*Job, error)
+	// PendingItems returns the oldest items waiting for their content to be scraped.
+	PendingItems(ctx context.Context, limit int) ([]*Item, error)
+	// CompleteItem fills the content of the item's article, the columns derived from it and the page's keywords,
+	// unless the content was set in the meantime.
+	CompleteItem(ctx context.Context, item Item, content string, readingTime int, excerpt, keywords string) error
+	FailItem(ctx context.Context, item Item) error
+}
@@ -0,0 +1,108 @@
+package importer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewJob(t *testing.T) {
+	userID := uuid.NewString()
+
+	j := NewJob(userID, FormatPocketCSV, 3)
+	assert.NotEmpty(t, j.ID)
+	assert.Equal(t, userID, j.UserID)
+	assert.Equal(t, FormatPocketCSV, j.Format)
+	assert.Equal(t, 3, j.Total)
+	assert.NotEmpty(t, j.CreatedAt)
+}
+
+func TestNewItem(t *testing.T) {
+	jobID := uuid.NewString()
+
+	i := NewItem(jobID, 2, "https://example.com")
+	assert.NotEmpty(t, i.ID)
+	assert.Equal(t, jobID, i.JobID)
+	assert.Equal(t, 2, i.Position)
+	assert.Equal(t, "https://example.com", i.URL)
+	assert.Equal(t, ItemPending, i.Status)
+	assert.Empty(t, i.Error)
+}
+
+func TestSummarize(t *testing.T) {
+	var progress Progress
+	progress.Add(ItemDone, 2)
+	progress.Add(ItemPending, 1)
+	progress.Add(ItemSkipped, 1)
+
+	j := &Job{}
+	j.Summarize(progress)
+	assert.Equal(t, JobProcessing, j.Status)
+	assert.Equal(t, Progress{Pending: 1, Done: 2, Skipped: 1}, j.Progress)
+
+	progress.Pending = 0
+	j.Summarize(progress)
+	assert.Equal(t, JobCompleted, j.Status)
+}
+
+func TestNewArticle(t *testing.T) {
+	userID := uuid.NewString()
+	savedAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	cases := []struct {
+		name string
+		rec  Record
+		err  error
+	}{
+		{
+			name: "should keep saved date and states",
+			rec:  Record{URL: " https://example.com/post ", Title: "Post", SavedAt: savedAt, Archived: true, Favorite: true},
+			err:  nil,
+		},
+		{
+			name: "should use the link as title",
+			rec:  Record{URL: "https://example.com/post"},
+			err:  nil,
+		},
+		{
+			name: "should return err when url isn't http",
+			rec:  Record{URL: "javascript:alert(1)"},
+			err:  ErrInvalidURL,
+		},
+		{
+			name: "should return err when url has no host",
+			rec:  Record{URL: "https://"},
+			err:  ErrInvalidURL,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			a, err := NewArticle(userID, c.rec)
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				assert.Nil(t, a)
+				return
+			}
+
+			assert.Equal(t, "https://example.com/post", a.ArticleLink)
+			assert.Equal(t, userID, a.UserID)
+			if c.rec.Title == "" {
+				assert.Equal(t, a.ArticleLink, a.Title)
+			}
+			if c.rec.SavedAt.IsZero() {
+				assert.Nil(t, a.ReadAt)
+				assert.Nil(t, a.ArchivedAt)
+				assert.Nil(t, a.FavoritedAt)
+				return
+			}
+			assert.Equal(t, savedAt, a.CreatedAt)
+			assert.Equal(t, savedAt, a.UpdatedAt)
+			assert.Equal(t, savedAt, *a.ReadAt)
+			assert.Equal(t, savedAt, *a.ArchivedAt)
+			assert.Equal(t, savedAt, *a.FavoritedAt)
+		})
+	}
+}
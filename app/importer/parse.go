@@ -0,0 +1,239 @@
+package importer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryanadiputraa/unclatter/pkg/netscape"
+)
+
+// pocketArchive is the heading of the read articles in pocket's html export.
+const pocketArchive = "read archive"
+
+var errMissingColumn = errors.New("missing url column")
+
+// Parse reads the bookmarks of an import file in the given format.
+func Parse(format Format, r io.Reader) ([]Record, error) {
+	switch format {
+	case FormatNetscape, FormatPocketHTML:
+		return parseHTML(format, r)
+	case FormatPocketCSV:
+		return parseCSV(r, pocketRecord)
+	case FormatInstapaperCSV:
+		return parseCSV(r, instapaperRecord)
+	case FormatOmnivoreJSON:
+		return parseJSON(r, omnivoreRecord)
+	case FormatWallabagJSON:
+		return parseJSON(r, wallabagRecord)
+	default:
+		return nil, errors.New("unsupported import format")
+	}
+}
+
+func parseHTML(format Format, r io.Reader) ([]Record, error) {
+	bookmarks, err := netscape.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]Record, len(bookmarks))
+	for i, b := range bookmarks {
+		records[i] = Record{
+			URL:     b.URL,
+			Title:   b.Title,
+			Tags:    b.Tags,
+			SavedAt: b.AddDate,
+		}
+		if format == FormatPocketHTML && strings.EqualFold(b.Folder, pocketArchive) {
+			records[i].Archived = true
+		}
+	}
+	return records, nil
+}
+
+// row gives access to a csv row by its lowercased header name.
+type row struct {
+	columns map[string]int
+	fields  []string
+}
+
+func (r row) get(name string) string {
+	i, ok := r.columns[name]
+	if !ok || i >= len(r.fields) {
+		return ""
+	}
+	return strings.TrimSpace(r.fields[i])
+}
+
+func parseCSV(r io.Reader, toRecord func(row) Record) ([]Record, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["url"]; !ok {
+		return nil, errMissingColumn
+	}
+
+	var records []Record
+	for {
+		fields, err := cr.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, toRecord(row{columns: columns, fields: fields}))
+	}
+}
+
+// pocketRecord reads a row of pocket's csv export: title, url, time_added, tags separated by | and status.
+func pocketRecord(r row) Record {
+	return Record{
+		URL:      r.get("url"),
+		Title:    r.get("title"),
+		Tags:     splitTags(r.get("tags"), "|"),
+		SavedAt:  parseTime(r.get("time_added")),
+		Archived: r.get("status") == "archive",
+	}
+}
+
+// instapaperRecord reads a row of instapaper's csv export: url, title, selection, folder, timestamp and tags
+// as a json array. Articles outside of the builtin folders are tagged with their folder name.
+func instapaperRecord(r row) Record {
+	rec := Record{
+		URL:     r.get("url"),
+		Title:   r.get("title"),
+		SavedAt: parseTime(r.get("timestamp")),
+	}
+
+	var tags []string
+	if err := json.Unmarshal([]byte(r.get("tags")), &tags); err == nil {
+		rec.Tags = tags
+	}
+	switch folder := r.get("folder"); strings.ToLower(folder) {
+	case "", "unread":
+	case "archive":
+		rec.Archived = true
+	case "starred":
+		rec.Favorite = true
+	default:
+		rec.Tags = append(rec.Tags, folder)
+	}
+	return rec
+}
+
+func parseJSON[T any](r io.Reader, toRecord func(T) Record) ([]Record, error) {
+	var entries []T
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, err
+	}
+
+	records := make([]Record, len(entries))
+	for i, e := range entries {
+		records[i] = toRecord(e)
+	}
+	return records, nil
+}
+
+type omnivoreEntry struct {
+	URL     string   `json:"url"`
+	Title   string   `json:"title"`
+	Labels  []string `json:"labels"`
+	SavedAt string   `json:"savedAt"`
+	State   string   `json:"state"`
+	// ReadingProgress is the read percentage of the article.
+	ReadingProgress float64 `json:"readingProgress"`
+}
+
+func omnivoreRecord(e omnivoreEntry) Record {
+	return Record{
+		URL:      e.URL,
+		Title:    e.Title,
+		Tags:     e.Labels,
+		SavedAt:  parseTime(e.SavedAt),
+		Read:     e.ReadingProgress >= 100,
+		Archived: strings.EqualFold(e.State, "archived"),
+	}
+}
+
+type wallabagEntry struct {
+	URL        string   `json:"url"`
+	Title      string   `json:"title"`
+	Tags       []string `json:"tags"`
+	CreatedAt  string   `json:"created_at"`
+	IsArchived flexBool `json:"is_archived"`
+	IsStarred  flexBool `json:"is_starred"`
+	Content    string   `json:"content"`
+}
+
+func wallabagRecord(e wallabagEntry) Record {
+	return Record{
+		URL:      e.URL,
+		Title:    e.Title,
+		Tags:     e.Tags,
+		SavedAt:  parseTime(e.CreatedAt),
+		Archived: bool(e.IsArchived),
+		Favorite: bool(e.IsStarred),
+		Content:  e.Content,
+	}
+}
+
+// flexBool accepts both booleans and the 0 and 1 integers older wallabag versions export.
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	switch strings.Trim(string(data), `"`) {
+	case "true", "1":
+		*b = true
+	case "false", "0", "null", "":
+		*b = false
+	default:
+		return errors.New("invalid boolean value " + string(data))
+	}
+	return nil
+}
+
+func splitTags(s, sep string) []string {
+	var tags []string
+	for _, t := range strings.Split(s, sep) {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05-0700", "2006-01-02 15:04:05"}
+
+// parseTime reads unix timestamps and the date formats of the supported exports, it's zero when s isn't a date.
+func parseTime(s string) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+	if sec, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if sec <= 0 {
+			return time.Time{}
+		}
+		return time.Unix(sec, 0).UTC()
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,110 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	savedAt := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		format   Format
+		file     string
+		expected []Record
+		err      bool
+	}{
+		{
+			name:   "should parse browser bookmarks",
+			format: FormatNetscape,
+			file: `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<DL><p>
+<DT><H3>Reading</H3>
+<DL><p>
+<DT><A HREF="https://example.com/a" ADD_DATE="1682935200" TAGS="go,web">A   post</A>
+</DL><p>
+</DL><p>`,
+			expected: []Record{
+				{URL: "https://example.com/a", Title: "A post", Tags: []string{"go", "web"}, SavedAt: savedAt},
+			},
+		},
+		{
+			name:   "should parse pocket html archive",
+			format: FormatPocketHTML,
+			file: `<h1>Unread</h1><ul><li><a href="https://example.com/a" time_added="1682935200" tags="">A</a></li></ul>
+<h1>Read Archive</h1><ul><li><a href="https://example.com/b" time_added="1682935200" tags="go">B</a></li></ul>`,
+			expected: []Record{
+				{URL: "https://example.com/a", Title: "A", SavedAt: savedAt},
+				{URL: "https://example.com/b", Title: "B", Tags: []string{"go"}, SavedAt: savedAt, Archived: true},
+			},
+		},
+		{
+			name:   "should parse pocket csv",
+			format: FormatPocketCSV,
+			file: "\ufefftitle,url,time_added,tags,status\n" +
+				"A,https://example.com/a,1682935200,go|web,archive\n" +
+				"\"B, quoted\",https://example.com/b,,,unread\n",
+			expected: []Record{
+				{URL: "https://example.com/a", Title: "A", Tags: []string{"go", "web"}, SavedAt: savedAt, Archived: true},
+				{URL: "https://example.com/b", Title: "B, quoted"},
+			},
+		},
+		{
+			name:   "should parse instapaper csv",
+			format: FormatInstapaperCSV,
+			file: "URL,Title,Selection,Folder,Timestamp,Tags\n" +
+				"https://example.com/a,A,,Archive,1682935200,\"[\"\"go\"\"]\"\n" +
+				"https://example.com/b,B,,Starred,1682935200,[]\n" +
+				"https://example.com/c,C,,Research,1682935200,\n",
+			expected: []Record{
+				{URL: "https://example.com/a", Title: "A", Tags: []string{"go"}, SavedAt: savedAt, Archived: true},
+				{URL: "https://example.com/b", Title: "B", Tags: []string{}, SavedAt: savedAt, Favorite: true},
+				{URL: "https://example.com/c", Title: "C", Tags: []string{"Research"}, SavedAt: savedAt},
+			},
+		},
+		{
+			name:   "should parse omnivore json",
+			format: FormatOmnivoreJSON,
+			file:   `[{"url":"https://example.com/a","title":"A","labels":["go"],"savedAt":"2023-05-01T10:00:00.000Z","state":"Archived","readingProgress":100}]`,
+			expected: []Record{
+				{URL: "https://example.com/a", Title: "A", Tags: []string{"go"}, SavedAt: savedAt, Read: true, Archived: true},
+			},
+		},
+		{
+			name:   "should parse wallabag json",
+			format: FormatWallabagJSON,
+			file:   `[{"url":"https://example.com/a","title":"A","tags":["go"],"created_at":"2023-05-01T12:00:00+0200","is_archived":1,"is_starred":false,"content":"<p>hi</p>"}]`,
+			expected: []Record{
+				{URL: "https://example.com/a", Title: "A", Tags: []string{"go"}, SavedAt: savedAt, Archived: true, Content: "<p>hi</p>"},
+			},
+		},
+		{
+			name:   "should return err when csv has no url column",
+			format: FormatPocketCSV,
+			file:   "title,link\nA,https://example.com/a\n",
+			err:    true,
+		},
+		{
+			name:   "should return err when json is malformed",
+			format: FormatWallabagJSON,
+			file:   `{"url":`,
+			err:    true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			records, err := Parse(c.format, strings.NewReader(c.file))
+			if c.err {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, c.expected, records)
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/importer"
+	"github.com/ryanadiputraa/unclatter/app/tag"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const itemBatchSize = 500
+
+type articleTag struct {
+	ArticleID string
+	TagID     string
+}
+
+func (articleTag) TableName() string {
+	return "article_tags"
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) importer.ImportRepository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Save(ctx context.Context, job importer.Job, items []*importer.Item, bookmarks []importer.Bookmark) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&job).Error; err != nil {
+			return err
+		}
+		if err := saveBookmarks(tx, job.UserID, bookmarks); err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(items, itemBatchSize).Error
+	})
+}
+
+// saveBookmarks creates the articles a batch at a time, conflicting ones are left out by the insert instead of
+// failing the whole import and their items are skipped. Tags are created and assigned once every batch is in.
+func saveBookmarks(tx *gorm.DB, userID string, bookmarks []importer.Bookmark) error {
+	var tagged []importer.Bookmark
+	for start := 0; start < len(bookmarks); start += itemBatchSize {
+		batch := bookmarks[start:min(start+itemBatchSize, len(bookmarks))]
+		articles := make([]*article.Article, len(batch))
+		ids := make([]string, len(batch))
+		for i, b := range batch {
+			articles[i] = b.Article
+			ids[i] = b.Article.ID
+		}
+
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&articles).Error; err != nil {
+			return err
+		}
+		var saved []string
+		if err := tx.Model(&article.Article{}).Where("id IN ?", ids).Pluck("id", &saved).Error; err != nil {
+			return err
+		}
+		created := make(map[string]bool, len(saved))
+		for _, id := range saved {
+			created[id] = true
+		}
+
+		for _, b := range batch {
+			if !created[b.Article.ID] {
+				b.Item.ArticleID = nil
+				b.Item.Skip("this url is already bookmarked")
+				continue
+			}
+			if len(b.Tags) > 0 {
+				tagged = append(tagged, b)
+			}
+		}
+	}
+	return saveTags(tx, userID, tagged)
+}
+
+// saveTags creates the bookmarks' tags the user doesn't have yet and assigns them.
+func saveTags(tx *gorm.DB, userID string, bookmarks []importer.Bookmark) error {
+	if len(bookmarks) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var tags []tag.Tag
+	var names []string
+	for _, b := range bookmarks {
+		for _, t := range b.Tags {
+			if !seen[t.Name] {
+				seen[t.Name] = true
+				tags = append(tags, t)
+				names = append(names, t.Name)
+			}
+		}
+	}
+
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "name"}},
+		DoNothing: true,
+	}).CreateInBatches(&tags, itemBatchSize).Error
+	if err != nil {
+		return err
+	}
+
+	var saved []*tag.Tag
+	if err = tx.Where("user_id = ? AND name IN ?", userID, names).Find(&saved).Error; err != nil {
+		return err
+	}
+	tagIDs := make(map[string]string, len(saved))
+	for _, t := range saved {
+		tagIDs[t.Name] = t.ID
+	}
+
+	var rows []articleTag
+	for _, b := range bookmarks {
+		for _, t := range b.Tags {
+			rows = append(rows, articleTag{ArticleID: b.Article.ID, TagID: tagIDs[t.Name]})
+		}
+	}
+	return tx.CreateInBatches(rows, itemBatchSize).Error
+}
+
+type statusCount struct {
+	JobID  string
+	Status importer.ItemStatus
+	Count  int64
+}
+
+func (r *repository) ListJobs(ctx context.Context, userID string) (jobs []*importer.Job, err error) {
+	if err = r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&jobs).Error; err != nil {
+		return
+	}
+	if len(jobs) == 0 {
+		return []*importer.Job{}, nil
+	}
+
+	ids := make([]string, len(jobs))
+	for i, j := range jobs {
+		ids[i] = j.ID
+	}
+	progress, err := r.progress(ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, j := range jobs {
+		j.Summarize(progress[j.ID])
+	}
+	return
+}
+
+func (r *repository) FindJob(ctx context.Context, userID, jobID string) (job *importer.Job, err error) {
+	err = r.db.First(&job, "id = ? AND user_id = ?", jobID, userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, validation.NewError(validation.NotFound, "no import found with given id")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	progress, err := r.progress([]string{job.ID})
+	if err != nil {
+		return nil, err
+	}
+	job.Summarize(progress[job.ID])
+
+	err = r.db.
+		Where("job_id = ? AND status IN ?", job.ID, []importer.ItemStatus{importer.ItemFailed, importer.ItemSkipped}).
+		Order("position").
+		Find(&job.Errors).Error
+	return
+}
+
+func (r *repository) progress(jobIDs []string) (map[string]importer.Progress, error) {
+	var counts []statusCount
+	err := r.db.Model(&importer.Item{}).
+		Select("job_id, status, COUNT(*) AS count").
+		Where("job_id IN ?", jobIDs).
+		Group("job_id, status").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	progress := make(map[string]importer.Progress, len(jobIDs))
+	for _, c := range counts {
+		p := progress[c.JobID]
+		p.Add(c.Status, c.Count)
+		progress[c.JobID] = p
+	}
+	return progress, nil
+}
+
+func (r *repository) PendingItems(ctx context.Context, limit int) (items []*importer.Item, err error) {
+	err = r.db.Where("status = ?", importer.ItemPending).Order("updated_at").Limit(limit).Find(&items).Error
+	return
+}
+
+func (r *repository) CompleteItem(ctx context.Context, item importer.Item, content string, readingTime int, excerpt, keywords string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// the article may have been edited since it was imported, its content is kept then. The content isn't an
+		// edit so the version stays, the term vector is marked stale to index the content.
+		err := tx.Model(&article.Article{}).
+			Where("id = ? AND content = ''", item.ArticleID).
+			UpdateColumns(map[string]any{"content": content, "reading_time": readingTime, "excerpt": excerpt, "keywords": keywords, "indexed_version": 0}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&importer.Item{}).
+			Where("id = ?", item.ID).
+			UpdateColumns(map[string]any{"status": importer.ItemDone, "error": "", "updated_at": time.Now().UTC()}).Error
+	})
+}
+
+func (r *repository) FailItem(ctx context.Context, item importer.Item) error {
+	return r.db.Model(&importer.Item{}).
+		Where("id = ?", item.ID).
+		UpdateColumns(map[string]any{"status": importer.ItemFailed, "error": item.Error, "updated_at": time.Now().UTC()}).Error
+}
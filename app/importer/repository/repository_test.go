@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/ryanadiputraa/unclatter/app/importer"
+	"github.com/ryanadiputraa/unclatter/app/tag"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/test"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestSave(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	articleQuery := "^INSERT INTO \"articles\" (.+) ON CONFLICT DO NOTHING"
+	savedQuery := "^SELECT \"id\" FROM \"articles\" WHERE id IN (.+) AND \"articles\".\"deleted_at\" IS NULL"
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock, job *importer.Job, item *importer.Item, tags []tag.Tag)
+		status        importer.ItemStatus
+		articleID     *string
+	}{
+		{
+			name: "should save the bookmark and its tags along with the job",
+			mockBehaviour: func(mock sqlmock.Sqlmock, job *importer.Job, item *importer.Item, tags []tag.Tag) {
+				mock.ExpectBegin()
+				mock.ExpectExec("^INSERT INTO \"import_jobs\"").
+					WithArgs(job.ID, job.UserID, job.Format, job.Total, job.CreatedAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(articleQuery).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery(savedQuery).
+					WithArgs(test.TestArticle.ID).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(test.TestArticle.ID))
+				mock.ExpectExec("^INSERT INTO \"tags\" (.+) ON CONFLICT \\(\"user_id\",\"name\"\\) DO NOTHING").
+					WithArgs(tags[0].ID, job.UserID, "go", tags[0].CreatedAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("^SELECT \\* FROM \"tags\" WHERE user_id = (.+) AND name IN (.+)").
+					WithArgs(job.UserID, "go").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name"}).AddRow("tag-1", job.UserID, "go"))
+				mock.ExpectExec("^INSERT INTO \"article_tags\"").
+					WithArgs(test.TestArticle.ID, "tag-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("^INSERT INTO \"import_items\"").
+					WithArgs(item.ID, item.JobID, item.Position, item.URL, &test.TestArticle.ID, importer.ItemPending, "", item.UpdatedAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			status:    importer.ItemPending,
+			articleID: &test.TestArticle.ID,
+		},
+		{
+			name: "should skip the item of a link that's already bookmarked",
+			mockBehaviour: func(mock sqlmock.Sqlmock, job *importer.Job, item *importer.Item, tags []tag.Tag) {
+				mock.ExpectBegin()
+				mock.ExpectExec("^INSERT INTO \"import_jobs\"").
+					WithArgs(job.ID, job.UserID, job.Format, job.Total, job.CreatedAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(articleQuery).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(savedQuery).
+					WithArgs(test.TestArticle.ID).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectExec("^INSERT INTO \"import_items\"").
+					WithArgs(item.ID, item.JobID, item.Position, item.URL, nil, importer.ItemSkipped, "this url is already bookmarked", item.UpdatedAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			status:    importer.ItemSkipped,
+			articleID: nil,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			job := importer.NewJob(test.TestUser.ID, importer.FormatNetscape, 1)
+			item := importer.NewItem(job.ID, 1, test.TestArticle.ArticleLink)
+			item.ArticleID = &test.TestArticle.ID
+			a := *test.TestArticle
+			tags := tag.NewTags(test.TestUser.ID, []string{"go"})
+			c.mockBehaviour(mock, job, item, tags)
+
+			err := r.Save(context.Background(), *job, []*importer.Item{item}, []importer.Bookmark{{Article: &a, Tags: tags, Item: item}})
+			assert.Nil(t, err)
+			assert.Equal(t, c.status, item.Status)
+			assert.Equal(t, c.articleID, item.ArticleID)
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestFindJob(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	jobID := uuid.NewString()
+	expectedQuery := "^SELECT \\* FROM \"import_jobs\" WHERE id = (.+) AND user_id = (.+) LIMIT (.+)$"
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		expected      *importer.Job
+		err           error
+	}{
+		{
+			name: "should return job with progress and errors",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(expectedQuery).
+					WithArgs(jobID, test.TestUser.ID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "format", "total"}).
+						AddRow(jobID, test.TestUser.ID, importer.FormatPocketCSV, 3))
+				mock.ExpectQuery("^SELECT job_id, status, COUNT\\(\\*\\) AS count FROM \"import_items\" WHERE job_id IN (.+) GROUP BY job_id, status$").
+					WithArgs(jobID).
+					WillReturnRows(sqlmock.NewRows([]string{"job_id", "status", "count"}).
+						AddRow(jobID, importer.ItemPending, 1).
+						AddRow(jobID, importer.ItemDone, 1).
+						AddRow(jobID, importer.ItemSkipped, 1))
+				mock.ExpectQuery("^SELECT \\* FROM \"import_items\" WHERE job_id = (.+) AND status IN \\((.+),(.+)\\) ORDER BY position$").
+					WithArgs(jobID, "failed", "skipped").
+					WillReturnRows(sqlmock.NewRows([]string{"position", "url", "status", "error"}).
+						AddRow(2, "https://example.com", importer.ItemSkipped, "invalid url"))
+			},
+			expected: &importer.Job{
+				ID:       jobID,
+				UserID:   test.TestUser.ID,
+				Format:   importer.FormatPocketCSV,
+				Total:    3,
+				Status:   importer.JobProcessing,
+				Progress: importer.Progress{Pending: 1, Done: 1, Skipped: 1},
+				Errors: []*importer.Item{
+					{Position: 2, URL: "https://example.com", Status: importer.ItemSkipped, Error: "invalid url"},
+				},
+			},
+			err: nil,
+		},
+		{
+			name: "should return not found err when job doesn't exists",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(expectedQuery).
+					WithArgs(jobID, test.TestUser.ID, 1).
+					WillReturnError(gorm.ErrRecordNotFound)
+			},
+			expected: nil,
+			err:      validation.NewError(validation.NotFound, "no import found with given id"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+
+			job, err := r.FindJob(context.Background(), test.TestUser.ID, jobID)
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.expected, job)
+		})
+	}
+}
+
+func TestPendingItems(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	itemID := uuid.NewString()
+
+	mock.ExpectQuery("^SELECT \\* FROM \"import_items\" WHERE status = (.+) ORDER BY updated_at LIMIT (.+)$").
+		WithArgs(importer.ItemPending, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "article_id", "status"}).
+			AddRow(itemID, test.TestArticle.ID, importer.ItemPending))
+
+	items, err := r.PendingItems(context.Background(), 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []*importer.Item{{ID: itemID, ArticleID: &test.TestArticle.ID, Status: importer.ItemPending}}, items)
+}
+
+func TestCompleteItem(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	item := importer.NewItem(uuid.NewString(), 1, test.TestArticle.ArticleLink)
+	item.ArticleID = &test.TestArticle.ID
+
+	mock.ExpectBegin()
+	mock.ExpectExec("^UPDATE \"articles\" SET \"content\"=(.+),\"excerpt\"=(.+),\"indexed_version\"=(.+),\"keywords\"=(.+),\"reading_time\"=(.+) WHERE \\(id = (.+) AND content = ''\\) AND \"articles\".\"deleted_at\" IS NULL$").
+		WithArgs("<p>content</p>", "content", 0, "postgres,indexes", 1, test.TestArticle.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("^UPDATE \"import_items\" SET \"error\"=(.+),\"status\"=(.+),\"updated_at\"=(.+) WHERE id = (.+)$").
+		WithArgs("", importer.ItemDone, test.AnyTime{}, item.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := r.CompleteItem(context.Background(), *item, "<p>content</p>", 1, "content", "postgres,indexes")
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestFailItem(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	item := importer.NewItem(uuid.NewString(), 1, test.TestArticle.ArticleLink)
+	item.Fail("fail to scrape article content")
+
+	mock.ExpectBegin()
+	mock.ExpectExec("^UPDATE \"import_items\" SET \"error\"=(.+),\"status\"=(.+),\"updated_at\"=(.+) WHERE id = (.+)$").
+		WithArgs(item.Error, importer.ItemFailed, test.AnyTime{}, item.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := r.FailItem(context.Background(), *item)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/importer"
+	"github.com/ryanadiputraa/unclatter/app/tag"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/pkg/logger"
+	"github.com/ryanadiputraa/unclatter/pkg/sanitizer"
+	"github.com/ryanadiputraa/unclatter/pkg/scrapper"
+)
+
+type service struct {
+	log        logger.Logger
+	scrapper   scrapper.Scrapper
+	sanitizer  sanitizer.Sanitizer
+	repository importer.ImportRepository
+}
+
+func NewService(log logger.Logger, scrapper scrapper.Scrapper, sanitizer sanitizer.Sanitizer, repository importer.ImportRepository) importer.ImportService {
+	return &service{
+		log:        log,
+		scrapper:   scrapper,
+		sanitizer:  sanitizer,
+		repository: repository,
+	}
+}
+
+func (s *service) StartImport(ctx context.Context, userID string, format importer.Format, file io.Reader) (*importer.Job, error) {
+	if !format.IsValid() {
+		return nil, validation.NewError(validation.BadRequest, "unsupported import format")
+	}
+
+	records, err := importer.Parse(format, file)
+	if err != nil {
+		return nil, validation.NewError(validation.BadRequest, "invalid import file: "+err.Error())
+	}
+	if len(records) == 0 {
+		return nil, validation.NewError(validation.BadRequest, "no bookmarks found in import file")
+	}
+	if len(records) > importer.MaxRecords {
+		return nil, validation.NewError(validation.BadRequest, fmt.Sprintf("import file can't have more than %d bookmarks", importer.MaxRecords))
+	}
+
+	job := importer.NewJob(userID, format, len(records))
+	items := make([]*importer.Item, len(records))
+	bookmarks := make([]importer.Bookmark, 0, len(records))
+	seen := make(map[string]bool, len(records))
+
+	for i, rec := range records {
+		item := importer.NewItem(job.ID, i+1, rec.URL)
+		items[i] = item
+		if b, ok := s.importRecord(userID, rec, item, seen); ok {
+			bookmarks = append(bookmarks, b)
+		}
+	}
+
+	// the bookmarks are saved in batches along with the job, the items of links the user already bookmarked
+	// are skipped by the repository
+	if err = s.repository.Save(ctx, *job, items, bookmarks); err != nil {
+		s.log.Error("import service: fail to save import job", err)
+		return nil, err
+	}
+
+	var progress importer.Progress
+	for _, item := range items {
+		progress.Add(item.Status, 1)
+	}
+	job.Summarize(progress)
+	return job, nil
+}
+
+// importRecord makes the record's bookmark and sets the item's status, the content of bookmarks without one is
+// left pending to be scraped in the background. Records that can't be bookmarked are skipped.
+func (s *service) importRecord(userID string, rec importer.Record, item *importer.Item, seen map[string]bool) (importer.Bookmark, bool) {
+	if rec.Content != "" {
+		rec.Content = s.sanitizer.Sanitize(rec.Content)
+	}
+	a, err := importer.NewArticle(userID, rec)
+	if err != nil {
+		item.Skip("invalid url")
+		return importer.Bookmark{}, false
+	}
+	if seen[a.NormalizedLink] {
+		item.Skip("duplicate link in import file")
+		return importer.Bookmark{}, false
+	}
+	seen[a.NormalizedLink] = true
+
+	item.ArticleID = &a.ID
+	if a.Content != "" {
+		item.Status = importer.ItemDone
+	}
+	return importer.Bookmark{Article: a, Tags: tag.NewTags(userID, rec.Tags), Item: item}, true
+}
+
+func (s *service) ListImports(ctx context.Context, userID string) (jobs []*importer.Job, err error) {
+	jobs, err = s.repository.ListJobs(ctx, userID)
+	if err != nil {
+		s.log.Error("import service: fail to fetch user's imports", err)
+	}
+	return
+}
+
+func (s *service) GetImport(ctx context.Context, userID, jobID string) (job *importer.Job, err error) {
+	job, err = s.repository.FindJob(ctx, userID, jobID)
+	if err != nil {
+		if _, ok := err.(*validation.Error); !ok {
+			s.log.Error("import service: fail to fetch import", err)
+		}
+	}
+	return
+}
+
+func (s *service) ScrapePending(ctx context.Context, limit int) (int, error) {
+	items, err := s.repository.PendingItems(ctx, limit)
+	if err != nil {
+		s.log.Error("import service: fail to fetch pending items", err)
+		return 0, err
+	}
+
+	for _, item := range items {
+		if err = ctx.Err(); err != nil {
+			return 0, err
+		}
+		if err = s.scrapeItem(ctx, *item); err != nil {
+			s.log.Error("import service: fail to update import item", err)
+			return 0, err
+		}
+	}
+	return len(items), nil
+}
+
+func (s *service) scrapeItem(ctx context.Context, item importer.Item) error {
+	// the article was deleted since it was imported
+	if item.ArticleID == nil {
+		item.Fail("bookmark was deleted")
+		return s.repository.FailItem(ctx, item)
+	}
+
+	page, err := s.scrapper.ScrapePage(item.URL)
+	if err != nil || page.Content == "" {
+		s.log.Warn("import service: fail to scrape ", item.URL, " ", err)
+		item.Fail("fail to scrape article content")
+		return s.repository.FailItem(ctx, item)
+	}
+
+	content := s.sanitizer.Sanitize(page.Content)
+	// imported articles are saved with the default language
+	return s.repository.CompleteItem(ctx, item, content, article.ReadingTime(content), article.Summarize(content, article.DefaultLanguage), article.JoinKeywords(page.Keywords))
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ryanadiputraa/unclatter/app/importer"
+	"github.com/ryanadiputraa/unclatter/app/mocks"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/pkg/logger"
+	"github.com/ryanadiputraa/unclatter/pkg/sanitizer"
+	"github.com/ryanadiputraa/unclatter/pkg/scrapper"
+	"github.com/ryanadiputraa/unclatter/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStartImport(t *testing.T) {
+	csv := "title,url,time_added,tags,status\n" +
+		"A,https://example.com/a,1682935200,go,unread\n" +
+		"A again,https://example.com/a,1682935200,,unread\n" +
+		"B,https://example.com/b,1682935200,,archive\n" +
+		"C,not a link,1682935200,,unread\n"
+
+	cases := []struct {
+		name              string
+		format            importer.Format
+		file              string
+		expected          importer.Progress
+		err               error
+		mockRepoBehaviour func(mockRepo *mocks.ImportRepository)
+	}{
+		{
+			name:   "should bookmark records and queue their content",
+			format: importer.FormatPocketCSV,
+			file:   csv,
+			expected: importer.Progress{
+				Pending: 1,
+				Skipped: 3,
+			},
+			err: nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ImportRepository) {
+				mockRepo.On("Save", context.Background(), mock.Anything, mock.MatchedBy(func(items []*importer.Item) bool {
+					return items[0].Status == importer.ItemPending && items[0].ArticleID != nil &&
+						items[1].Error == "duplicate link in import file" &&
+						items[3].Error == "invalid url"
+				}), mock.MatchedBy(func(bookmarks []importer.Bookmark) bool {
+					return len(bookmarks) == 2 &&
+						bookmarks[0].Article.Title == "A" && len(bookmarks[0].Tags) == 1 && bookmarks[0].Tags[0].Name == "go" &&
+						bookmarks[1].Article.Title == "B" && len(bookmarks[1].Tags) == 0
+				})).Run(func(args mock.Arguments) {
+					// the repository skips the bookmark whose link the user already bookmarked
+					b := args.Get(3).([]importer.Bookmark)[1]
+					b.Item.ArticleID = nil
+					b.Item.Skip("this url is already bookmarked")
+				}).Return(nil)
+			},
+		},
+		{
+			name:   "should return err when the import can't be saved",
+			format: importer.FormatPocketCSV,
+			file:   csv,
+			err:    errors.New("db error"),
+			mockRepoBehaviour: func(mockRepo *mocks.ImportRepository) {
+				mockRepo.On("Save", context.Background(), mock.Anything, mock.Anything, mock.Anything).Return(errors.New("db error"))
+			},
+		},
+		{
+			name:              "should return err when format is unsupported",
+			format:            importer.Format("delicious"),
+			file:              csv,
+			err:               validation.NewError(validation.BadRequest, "unsupported import format"),
+			mockRepoBehaviour: func(mockRepo *mocks.ImportRepository) {},
+		},
+		{
+			name:              "should return err when file has no bookmarks",
+			format:            importer.FormatPocketCSV,
+			file:              "title,url,time_added,tags,status\n",
+			err:               validation.NewError(validation.BadRequest, "no bookmarks found in import file"),
+			mockRepoBehaviour: func(mockRepo *mocks.ImportRepository) {},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.ImportRepository)
+			c.mockRepoBehaviour(r)
+
+			s := NewService(logger.NewLogger(), new(mocks.Scrapper), sanitizer.NewSanitizer(), r)
+			job, err := s.StartImport(context.Background(), test.TestUser.ID, c.format, strings.NewReader(c.file))
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				assert.Nil(t, job)
+				return
+			}
+			assert.Equal(t, 4, job.Total)
+			assert.Equal(t, c.expected, job.Progress)
+			assert.Equal(t, importer.JobProcessing, job.Status)
+			r.AssertExpectations(t)
+		})
+	}
+}
+
+func TestScrapePending(t *testing.T) {
+	done := importer.NewItem(test.TestArticle.ID, 1, "https://example.com/a")
+	done.ArticleID = &test.TestArticle.ID
+	failed := importer.NewItem(test.TestArticle.ID, 2, "https://example.com/b")
+	failed.ArticleID = &test.TestArticle.ID
+	deleted := importer.NewItem(test.TestArticle.ID, 3, "https://example.com/c")
+
+	mockScrapper := new(mocks.Scrapper)
+	mockScrapper.On("ScrapePage", done.URL).Return(&scrapper.Page{Content: "<p>content</p>", Keywords: []string{"postgres", "indexes"}}, nil)
+	mockScrapper.On("ScrapePage", failed.URL).Return(nil, errors.New("timeout"))
+
+	r := new(mocks.ImportRepository)
+	r.On("PendingItems", context.Background(), 10).Return([]*importer.Item{done, failed, deleted}, nil)
+	r.On("CompleteItem", context.Background(), *done, "<p>content</p>", 1, "content", "postgres,indexes").Return(nil)
+	r.On("FailItem", context.Background(), mock.MatchedBy(func(i importer.Item) bool {
+		return i.ID == failed.ID && i.Error == "fail to scrape article content"
+	})).Return(nil)
+	r.On("FailItem", context.Background(), mock.MatchedBy(func(i importer.Item) bool {
+		return i.ID == deleted.ID && i.Error == "bookmark was deleted"
+	})).Return(nil)
+
+	s := NewService(logger.NewLogger(), mockScrapper, sanitizer.NewSanitizer(), r)
+	n, err := s.ScrapePending(context.Background(), 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, n)
+	r.AssertExpectations(t)
+}
+
+func TestScrapePendingRefusesNonPublicAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(`<body><p>Internal</p></body>`))
+	}))
+	defer server.Close()
+
+	item := importer.NewItem(test.TestArticle.ID, 1, server.URL)
+	item.ArticleID = &test.TestArticle.ID
+
+	r := new(mocks.ImportRepository)
+	r.On("PendingItems", context.Background(), 10).Return([]*importer.Item{item}, nil)
+	r.On("FailItem", context.Background(), mock.MatchedBy(func(i importer.Item) bool {
+		return i.ID == item.ID && i.Error == "fail to scrape article content"
+	})).Return(nil)
+
+	s := NewService(logger.NewLogger(), scrapper.NewScrapper(), sanitizer.NewSanitizer(), r)
+	n, err := s.ScrapePending(context.Background(), 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+	r.AssertExpectations(t)
+	r.AssertNotCalled(t, "CompleteItem", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/linkhealth"
+	"github.com/ryanadiputraa/unclatter/app/middleware"
+	"github.com/ryanadiputraa/unclatter/app/pagination"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	_http "github.com/ryanadiputraa/unclatter/pkg/http"
+	"github.com/ryanadiputraa/unclatter/pkg/validator"
+)
+
+type handler struct {
+	rw                _http.ResponseWriter
+	linkHealthService linkhealth.LinkHealthService
+	validator         validator.Validator
+}
+
+func NewHandler(web *http.ServeMux, rw _http.ResponseWriter, linkHealthService linkhealth.LinkHealthService, authMiddleware middleware.AuthMiddleware, validator validator.Validator) {
+	h := &handler{
+		rw:                rw,
+		linkHealthService: linkHealthService,
+		validator:         validator,
+	}
+
+	web.Handle("GET /api/link-health", authMiddleware.ParseJWTToken(h.GetSummary()))
+	web.Handle("GET /api/link-health/links", authMiddleware.ParseJWTToken(h.ListLinks()))
+	web.Handle("GET /api/articles/bookmarks/{id}/link-checks", authMiddleware.ParseJWTToken(h.ListChecks()))
+}
+
+func (h *handler) GetSummary() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		summary, err := h.linkHealthService.GetSummary(ac.Context, ac.UserID)
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, summary)
+	}
+}
+
+func (h *handler) ListLinks() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		query := r.URL.Query()
+
+		params := linkhealth.LinksParams{Health: query.Get("health")}
+		if err, errMap := h.validator.Validate(params); err != nil {
+			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+			return
+		}
+
+		pagination, errMap, err := pagination.ValidateParam(query.Get("page"), query.Get("size"))
+		if err != nil {
+			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+			return
+		}
+
+		links, meta, err := h.linkHealthService.ListLinks(ac.Context, ac.UserID, article.LinkHealth(params.Health), *pagination)
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseDataWithPagination(w, http.StatusOK, links, *meta)
+	}
+}
+
+func (h *handler) ListChecks() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		checks, err := h.linkHealthService.ListChecks(ac.Context, ac.UserID, r.PathValue("id"))
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, checks)
+	}
+}
+
+func (h *handler) writeErr(w http.ResponseWriter, err error) {
+	if vErr, ok := err.(*validation.Error); ok {
+		h.rw.WriteErrMessage(w, validation.HttpErrMap[vErr.Err], vErr.Message)
+		return
+	}
+	h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+}
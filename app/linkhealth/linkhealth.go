@@ -0,0 +1,255 @@
+package linkhealth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/pagination"
+	"github.com/ryanadiputraa/unclatter/pkg/linkcheck"
+	"github.com/ryanadiputraa/unclatter/pkg/nlp"
+)
+
+const (
+	// ChangedDistance is the most bits the fingerprint of a page can drift from the bookmarked content before it
+	// counts as changed. It's well above article.NearDuplicateDistance so edited ads, dates and comment counts
+	// don't flag a page, while a rewritten or replaced page differs in about half of the 64 bits.
+	ChangedDistance = 16
+	// MaxFailures is how many checks in a row can't reach a link before it's flagged as broken.
+	MaxFailures = 3
+	// MaxChecks is how many of its latest checks are kept in the history of a link.
+	MaxChecks = 20
+	// retryDelay is how long a link that couldn't be reached waits before it's checked again.
+	retryDelay = 6 * time.Hour
+)
+
+// Link is the health of an article link, it's tracked from the first time the link is due for a check.
+type Link struct {
+	ArticleID string             `json:"article_id" gorm:"type:varchar;primaryKey"`
+	UserID    string             `json:"-" gorm:"type:varchar;not null;index"`
+	Health    article.LinkHealth `json:"health" gorm:"type:varchar;not null;default:''"`
+	// StatusCode is the response status of the last check that reached the link.
+	StatusCode int `json:"status_code" gorm:"type:smallint;not null;default:0"`
+	// MovedTo is where a moved link redirects to.
+	MovedTo string `json:"moved_to,omitempty" gorm:"type:varchar;not null;default:''"`
+	// Fingerprint is the simhash of the content the article was bookmarked with, downloaded content is compared to
+	// it. Articles too short to fingerprint are compared to the content the link served on its first check instead.
+	Fingerprint int64 `json:"-" gorm:"type:bigint;not null;default:0"`
+	// Distance is how many bits the fingerprint of the latest content differs from Fingerprint.
+	Distance     int    `json:"-" gorm:"type:smallint;not null;default:0"`
+	ETag         string `json:"-" gorm:"column:etag;type:varchar;not null;default:''"`
+	LastModified string `json:"-" gorm:"type:varchar;not null;default:''"`
+	// Failures counts the latest checks in a row that couldn't reach the link.
+	Failures int    `json:"failures" gorm:"type:smallint;not null;default:0"`
+	Error    string `json:"error,omitempty" gorm:"type:varchar;not null;default:''"`
+	// HealthChangedAt is since when the link has its health.
+	HealthChangedAt *time.Time `json:"health_changed_at" gorm:"type:timestamptz"`
+	CheckedAt       *time.Time `json:"checked_at" gorm:"type:timestamptz"`
+	NextCheckAt     time.Time  `json:"-" gorm:"type:timestamptz;not null;index"`
+
+	Article *article.Article `json:"article,omitempty" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+func (Link) TableName() string {
+	return "article_links"
+}
+
+// Check is a single check in the history of a link, Health is empty when the check couldn't tell.
+type Check struct {
+	ID         string             `json:"id" gorm:"type:varchar"`
+	ArticleID  string             `json:"-" gorm:"type:varchar;not null;index:idx_link_checks_article_checked_at,priority:1"`
+	Method     string             `json:"method" gorm:"type:varchar;not null;default:''"`
+	StatusCode int                `json:"status_code" gorm:"type:smallint;not null;default:0"`
+	FinalURL   string             `json:"final_url" gorm:"type:varchar;not null;default:''"`
+	Health     article.LinkHealth `json:"health" gorm:"type:varchar;not null;default:''"`
+	Error      string             `json:"error,omitempty" gorm:"type:varchar;not null;default:''"`
+	CheckedAt  time.Time          `json:"checked_at" gorm:"type:timestamptz;not null;index:idx_link_checks_article_checked_at,priority:2"`
+
+	Article *article.Article `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+func (Check) TableName() string {
+	return "link_checks"
+}
+
+// Record applies the result of checking the link to its health and returns the check for its history. A link is
+// flagged as broken right away when it's gone, while other failures are retried sooner a few times before.
+func (l *Link) Record(res *linkcheck.Result, err error, now time.Time, recheckAfter time.Duration) *Check {
+	now = now.UTC()
+	check := &Check{
+		ID:        uuid.NewString(),
+		ArticleID: l.ArticleID,
+		CheckedAt: now,
+	}
+	l.CheckedAt = &now
+	l.NextCheckAt = now.Add(recheckAfter)
+
+	if err != nil {
+		l.fail(check, "link couldn't be reached", now, recheckAfter)
+		return check
+	}
+	check.Method = res.Method
+	check.StatusCode = res.StatusCode
+	check.FinalURL = res.FinalURL
+	if res.Disallowed {
+		check.Error = "robots.txt doesn't allow checking the link"
+		return check
+	}
+
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		l.settle(article.LinkHealthBroken, "page not found", res, now)
+	case res.StatusCode == http.StatusGone:
+		l.settle(article.LinkHealthBroken, "page is gone", res, now)
+	case res.StatusCode == http.StatusNotModified || (res.StatusCode >= 200 && res.StatusCode <= 299):
+		l.compare(res)
+		health, reason := l.classify(res)
+		l.settle(health, reason, res, now)
+	default:
+		// rate limits, paywalls and server errors don't tell whether the page is still there
+		l.fail(check, fmt.Sprintf("unexpected status %d", res.StatusCode), now, recheckAfter)
+		return check
+	}
+
+	check.Health = l.Health
+	check.Error = l.Error
+	return check
+}
+
+// compare fingerprints the downloaded content against the article's, links tracked before they were seeded
+// with it take it from the article.
+func (l *Link) compare(res *linkcheck.Result) {
+	if res.NotModified {
+		return
+	}
+	if res.ETag != "" || res.LastModified != "" {
+		l.ETag, l.LastModified = res.ETag, res.LastModified
+	}
+	fingerprint := article.Fingerprint(res.Content)
+	if fingerprint == 0 {
+		return
+	}
+	if l.Fingerprint == 0 && l.Article != nil {
+		l.Fingerprint = l.Article.Fingerprint
+	}
+	if l.Fingerprint == 0 {
+		l.Fingerprint = fingerprint
+		return
+	}
+	l.Distance = nlp.HammingDistance(uint64(l.Fingerprint), uint64(fingerprint))
+}
+
+// classify a link that answered, a redirect to another page outweighs its content.
+func (l *Link) classify(res *linkcheck.Result) (article.LinkHealth, string) {
+	moved := l.Article != nil && redirected(l.Article.ArticleLink, res.FinalURL)
+	switch {
+	case moved && isHomepage(res.FinalURL) && !isHomepage(l.Article.ArticleLink):
+		return article.LinkHealthBroken, "link redirects to the site's homepage"
+	case moved:
+		return article.LinkHealthMoved, ""
+	case l.Distance > ChangedDistance:
+		return article.LinkHealthChanged, ""
+	default:
+		return article.LinkHealthOK, ""
+	}
+}
+
+func (l *Link) settle(health article.LinkHealth, reason string, res *linkcheck.Result, now time.Time) {
+	l.setHealth(health, now)
+	l.StatusCode = res.StatusCode
+	l.Failures = 0
+	l.Error = reason
+	l.MovedTo = ""
+	if health == article.LinkHealthMoved {
+		l.MovedTo = res.FinalURL
+	}
+}
+
+func (l *Link) fail(check *Check, reason string, now time.Time, recheckAfter time.Duration) {
+	l.Failures++
+	l.Error = reason
+	check.Error = reason
+	if l.Failures >= MaxFailures {
+		l.setHealth(article.LinkHealthBroken, now)
+		check.Health = l.Health
+		return
+	}
+	if retryDelay < recheckAfter {
+		l.NextCheckAt = now.Add(retryDelay)
+	}
+}
+
+func (l *Link) setHealth(health article.LinkHealth, now time.Time) {
+	if l.Health != health {
+		l.Health = health
+		l.HealthChangedAt = &now
+	}
+}
+
+// redirected reports whether the link ended up on another page, upgrading to https or dropping tracking params
+// doesn't count.
+func redirected(link, finalURL string) bool {
+	if finalURL == "" {
+		return false
+	}
+	from, err := article.NormalizeLink(link)
+	if err != nil {
+		return false
+	}
+	to, err := article.NormalizeLink(finalURL)
+	if err != nil {
+		return false
+	}
+	return from != to
+}
+
+func isHomepage(link string) bool {
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	path := strings.Trim(u.Path, "/")
+	return path == "" || path == "index.html" || path == "index.php"
+}
+
+// Summary counts the user's articles by the health of their link.
+type Summary struct {
+	OK            int64      `json:"ok"`
+	Moved         int64      `json:"moved"`
+	Changed       int64      `json:"changed"`
+	Broken        int64      `json:"broken"`
+	Unchecked     int64      `json:"unchecked"`
+	LastCheckedAt *time.Time `json:"last_checked_at"`
+}
+
+// LinksParams filters the report of flagged links, every flagged link is listed when Health is empty.
+type LinksParams struct {
+	Health string `validate:"omitempty,oneof=ok moved changed broken"`
+}
+
+type LinkHealthService interface {
+	GetSummary(ctx context.Context, userID string) (*Summary, error)
+	// ListLinks returns the user's links with the health, or the moved, changed and broken ones when it's empty.
+	ListLinks(ctx context.Context, userID string, health article.LinkHealth, page pagination.Pagination) ([]*Link, *pagination.Meta, error)
+	// ListChecks returns the history of the article's link, latest first.
+	ListChecks(ctx context.Context, userID, articleID string) ([]*Check, error)
+	// CheckDueLinks checks up to limit links that are due and returns how many were checked.
+	CheckDueLinks(ctx context.Context, now time.Time, limit int) (int, error)
+}
+
+type LinkHealthRepository interface {
+	// Track starts tracking the links of articles that aren't tracked yet, they're due at now.
+	Track(ctx context.Context, now time.Time) (int64, error)
+	// ListDue returns the tracked links that are due with their article, the most overdue first.
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*Link, error)
+	// SaveCheck updates the link and adds the check to its history, dropping checks past MaxChecks.
+	SaveCheck(ctx context.Context, link Link, check Check) error
+	Summary(ctx context.Context, userID string) (*Summary, error)
+	List(ctx context.Context, userID string, health article.LinkHealth, page pagination.Pagination) (links []*Link, total int64, err error)
+	ListChecks(ctx context.Context, articleID string) ([]*Check, error)
+}
@@ -0,0 +1,190 @@
+package linkhealth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/pkg/linkcheck"
+	"github.com/stretchr/testify/assert"
+)
+
+const link = "https://example.com/posts/postgres-indexes"
+
+var (
+	original = "<p>" + strings.Repeat("postgres builds btree indexes on the columns queries filter and sort by ", 8) + "</p>"
+	edited   = "<p>" + strings.Repeat("postgres builds btree indexes on the columns queries filter and sort by ", 8) + "updated</p>"
+	replaced = "<p>" + strings.Repeat("this domain is for sale contact the owner to make an offer today ", 8) + "</p>"
+)
+
+func TestRecord(t *testing.T) {
+	now := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+	recheckAfter := 7 * 24 * time.Hour
+
+	cases := []struct {
+		name     string
+		link     Link
+		article  string
+		res      *linkcheck.Result
+		err      error
+		health   article.LinkHealth
+		check    article.LinkHealth
+		movedTo  string
+		failures int
+		next     time.Time
+	}{
+		{
+			name:    "should flag link as changed on its first check when content differs from the article",
+			link:    Link{},
+			article: original,
+			res:     &linkcheck.Result{Method: "GET", StatusCode: 200, FinalURL: link, Content: replaced},
+			health:  article.LinkHealthChanged,
+			check:   article.LinkHealthChanged,
+			next:    now.Add(recheckAfter),
+		},
+		{
+			name:    "should stay ok on its first check when content matches the article",
+			link:    Link{},
+			article: edited,
+			res:     &linkcheck.Result{Method: "GET", StatusCode: 200, FinalURL: link, Content: original},
+			health:  article.LinkHealthOK,
+			check:   article.LinkHealthOK,
+			next:    now.Add(recheckAfter),
+		},
+		{
+			name:   "should keep the first content as the fingerprint to compare to when article has none",
+			link:   Link{},
+			res:    &linkcheck.Result{Method: "GET", StatusCode: 200, FinalURL: link, Content: original},
+			health: article.LinkHealthOK,
+			check:  article.LinkHealthOK,
+			next:   now.Add(recheckAfter),
+		},
+		{
+			name:   "should stay ok when content is slightly edited",
+			link:   Link{Health: article.LinkHealthOK, Fingerprint: article.Fingerprint(original)},
+			res:    &linkcheck.Result{Method: "GET", StatusCode: 200, FinalURL: link, Content: edited},
+			health: article.LinkHealthOK,
+			check:  article.LinkHealthOK,
+			next:   now.Add(recheckAfter),
+		},
+		{
+			name:   "should flag link as changed when content is replaced",
+			link:   Link{Health: article.LinkHealthOK, Fingerprint: article.Fingerprint(original)},
+			res:    &linkcheck.Result{Method: "GET", StatusCode: 200, FinalURL: link, Content: replaced},
+			health: article.LinkHealthChanged,
+			check:  article.LinkHealthChanged,
+			next:   now.Add(recheckAfter),
+		},
+		{
+			name:   "should stay changed when page wasn't modified since",
+			link:   Link{Health: article.LinkHealthChanged, Fingerprint: article.Fingerprint(original), Distance: ChangedDistance + 10},
+			res:    &linkcheck.Result{Method: "HEAD", StatusCode: 304, FinalURL: link, NotModified: true},
+			health: article.LinkHealthChanged,
+			check:  article.LinkHealthChanged,
+			next:   now.Add(recheckAfter),
+		},
+		{
+			name:    "should flag link as moved when it redirects to another page",
+			link:    Link{Health: article.LinkHealthOK},
+			res:     &linkcheck.Result{Method: "HEAD", StatusCode: 200, FinalURL: "https://blog.example.com/postgres-indexes", NotModified: true},
+			health:  article.LinkHealthMoved,
+			check:   article.LinkHealthMoved,
+			movedTo: "https://blog.example.com/postgres-indexes",
+			next:    now.Add(recheckAfter),
+		},
+		{
+			name:   "should stay ok when redirected to https",
+			link:   Link{Health: article.LinkHealthOK},
+			res:    &linkcheck.Result{Method: "HEAD", StatusCode: 200, FinalURL: "https://www.example.com/posts/postgres-indexes/", NotModified: true},
+			health: article.LinkHealthOK,
+			check:  article.LinkHealthOK,
+			next:   now.Add(recheckAfter),
+		},
+		{
+			name:   "should flag link as broken when it redirects to the homepage",
+			link:   Link{Health: article.LinkHealthOK},
+			res:    &linkcheck.Result{Method: "GET", StatusCode: 200, FinalURL: "https://example.com/", Content: replaced},
+			health: article.LinkHealthBroken,
+			check:  article.LinkHealthBroken,
+			next:   now.Add(recheckAfter),
+		},
+		{
+			name:   "should flag link as broken when page isn't found",
+			link:   Link{Health: article.LinkHealthOK},
+			res:    &linkcheck.Result{Method: "HEAD", StatusCode: 404, FinalURL: link},
+			health: article.LinkHealthBroken,
+			check:  article.LinkHealthBroken,
+			next:   now.Add(recheckAfter),
+		},
+		{
+			name:     "should retry sooner when link can't be reached",
+			link:     Link{Health: article.LinkHealthOK},
+			err:      errors.New("dial tcp: i/o timeout"),
+			health:   article.LinkHealthOK,
+			check:    "",
+			failures: 1,
+			next:     now.Add(retryDelay),
+		},
+		{
+			name:     "should retry sooner on server errors",
+			link:     Link{Health: article.LinkHealthOK},
+			res:      &linkcheck.Result{Method: "GET", StatusCode: 503, FinalURL: link},
+			health:   article.LinkHealthOK,
+			check:    "",
+			failures: 1,
+			next:     now.Add(retryDelay),
+		},
+		{
+			name:     "should flag link as broken when it failed every recent check",
+			link:     Link{Health: article.LinkHealthOK, Failures: MaxFailures - 1},
+			err:      errors.New("dial tcp: no such host"),
+			health:   article.LinkHealthBroken,
+			check:    article.LinkHealthBroken,
+			failures: MaxFailures,
+			next:     now.Add(recheckAfter),
+		},
+		{
+			name:    "should keep health when robots.txt disallows checking",
+			link:    Link{Health: article.LinkHealthMoved, MovedTo: "https://blog.example.com/postgres-indexes"},
+			res:     &linkcheck.Result{FinalURL: link, Disallowed: true},
+			health:  article.LinkHealthMoved,
+			check:   "",
+			movedTo: "https://blog.example.com/postgres-indexes",
+			next:    now.Add(recheckAfter),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			l := c.link
+			l.ArticleID = "article"
+			l.Article = &article.Article{ID: "article", ArticleLink: link, Fingerprint: article.Fingerprint(c.article)}
+
+			check := l.Record(c.res, c.err, now, recheckAfter)
+			assert.Equal(t, c.health, l.Health)
+			assert.Equal(t, c.check, check.Health)
+			assert.Equal(t, c.movedTo, l.MovedTo)
+			assert.Equal(t, c.failures, l.Failures)
+			assert.Equal(t, c.next, l.NextCheckAt)
+			assert.Equal(t, now, *l.CheckedAt)
+			assert.Equal(t, l.ArticleID, check.ArticleID)
+		})
+	}
+}
+
+func TestRecordHealthChangedAt(t *testing.T) {
+	now := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+	l := Link{ArticleID: "article", Article: &article.Article{ID: "article", ArticleLink: link}}
+
+	l.Record(&linkcheck.Result{StatusCode: 200, FinalURL: link}, nil, now, time.Hour)
+	assert.Equal(t, now, *l.HealthChangedAt)
+
+	l.Record(&linkcheck.Result{StatusCode: 200, FinalURL: link}, nil, now.Add(time.Hour), time.Hour)
+	assert.Equal(t, now, *l.HealthChangedAt)
+
+	l.Record(&linkcheck.Result{StatusCode: 410, FinalURL: link}, nil, now.Add(2*time.Hour), time.Hour)
+	assert.Equal(t, now.Add(2*time.Hour), *l.HealthChangedAt)
+	assert.Equal(t, "page is gone", l.Error)
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/linkhealth"
+	"github.com/ryanadiputraa/unclatter/app/pagination"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) linkhealth.LinkHealthRepository {
+	return &repository{
+		db: db,
+	}
+}
+
+// trackQuery adds the links of articles bookmarked since the last run with the article's fingerprint to compare
+// their content to, trashed articles are tracked again once they're restored.
+const trackQuery = `INSERT INTO article_links (article_id, user_id, fingerprint, next_check_at)
+SELECT id, user_id, fingerprint, ? FROM articles
+WHERE deleted_at IS NULL AND NOT EXISTS (SELECT 1 FROM article_links WHERE article_links.article_id = articles.id)
+ON CONFLICT (article_id) DO NOTHING`
+
+func (r *repository) Track(ctx context.Context, now time.Time) (int64, error) {
+	res := r.db.Exec(trackQuery, now)
+	return res.RowsAffected, res.Error
+}
+
+func (r *repository) ListDue(ctx context.Context, now time.Time, limit int) (links []*linkhealth.Link, err error) {
+	err = r.db.Joins("JOIN articles ON articles.id = article_links.article_id AND articles.deleted_at IS NULL").
+		Preload("Article", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id, article_link, user_id, fingerprint")
+		}).
+		Where("article_links.next_check_at <= ?", now).
+		Order("article_links.next_check_at").
+		Limit(limit).
+		Find(&links).Error
+	return
+}
+
+func (r *repository) SaveCheck(ctx context.Context, link linkhealth.Link, check linkhealth.Check) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&linkhealth.Link{}).
+			Where("article_id = ?", link.ArticleID).
+			UpdateColumns(map[string]any{
+				"health":            link.Health,
+				"status_code":       link.StatusCode,
+				"moved_to":          link.MovedTo,
+				"fingerprint":       link.Fingerprint,
+				"distance":          link.Distance,
+				"etag":              link.ETag,
+				"last_modified":     link.LastModified,
+				"failures":          link.Failures,
+				"error":             link.Error,
+				"health_changed_at": link.HealthChangedAt,
+				"checked_at":        link.CheckedAt,
+				"next_check_at":     link.NextCheckAt,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		if err = tx.Create(&check).Error; err != nil {
+			return err
+		}
+		return tx.Where("article_id = ? AND id NOT IN (?)", link.ArticleID,
+			tx.Model(&linkhealth.Check{}).
+				Select("id").
+				Where("article_id = ?", link.ArticleID).
+				Order("checked_at DESC").
+				Limit(linkhealth.MaxChecks),
+		).Delete(&linkhealth.Check{}).Error
+	})
+}
+
+func (r *repository) Summary(ctx context.Context, userID string) (summary *linkhealth.Summary, err error) {
+	err = r.db.Model(&article.Article{}).
+		Select(`COUNT(*) FILTER (WHERE article_links.health = 'ok') AS ok,
+			COUNT(*) FILTER (WHERE article_links.health = 'moved') AS moved,
+			COUNT(*) FILTER (WHERE article_links.health = 'changed') AS changed,
+			COUNT(*) FILTER (WHERE article_links.health = 'broken') AS broken,
+			COUNT(*) FILTER (WHERE article_links.health IS NULL OR article_links.health = '') AS unchecked,
+			MAX(article_links.checked_at) AS last_checked_at`).
+		Joins("LEFT JOIN article_links ON article_links.article_id = articles.id").
+		Where("articles.user_id = ?", userID).
+		Scan(&summary).Error
+	return
+}
+
+// flaggedHealth is what the report lists when it isn't filtered.
+var flaggedHealth = []article.LinkHealth{article.LinkHealthMoved, article.LinkHealthChanged, article.LinkHealthBroken}
+
+func (r *repository) List(ctx context.Context, userID string, health article.LinkHealth, page pagination.Pagination) (links []*linkhealth.Link, total int64, err error) {
+	scope := func(db *gorm.DB) *gorm.DB {
+		db = db.Joins("JOIN articles ON articles.id = article_links.article_id AND articles.deleted_at IS NULL").
+			Where("article_links.user_id = ?", userID)
+		if health != "" {
+			return db.Where("article_links.health = ?", health)
+		}
+		return db.Where("article_links.health IN ?", flaggedHealth)
+	}
+
+	err = r.db.Model(&linkhealth.Link{}).Scopes(scope).Count(&total).Error
+	if err != nil {
+		return
+	}
+
+	err = r.db.Scopes(scope).
+		Preload("Article", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id, title, article_link, domain, created_at, updated_at")
+		}).
+		Order("article_links.health_changed_at DESC, article_links.article_id").
+		Limit(page.Limit).
+		Offset(page.Offset).
+		Find(&links).Error
+	return
+}
+
+func (r *repository) ListChecks(ctx context.Context, articleID string) (checks []*linkhealth.Check, err error) {
+	err = r.db.Where("article_id = ?", articleID).
+		Order("checked_at DESC").
+		Limit(linkhealth.MaxChecks).
+		Find(&checks).Error
+	return
+}
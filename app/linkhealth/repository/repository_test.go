@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/linkhealth"
+	"github.com/ryanadiputraa/unclatter/app/pagination"
+	"github.com/ryanadiputraa/unclatter/test"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestTrack(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	now := time.Now().UTC()
+
+	mock.ExpectExec("^INSERT INTO article_links \\(article_id, user_id, fingerprint, next_check_at\\) SELECT id, user_id, fingerprint, (.+) FROM articles WHERE deleted_at IS NULL AND NOT EXISTS (.+) ON CONFLICT \\(article_id\\) DO NOTHING").
+		WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	tracked, err := r.Track(context.Background(), now)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), tracked)
+}
+
+func TestListDue(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	now := time.Now().UTC()
+
+	mock.ExpectQuery("^SELECT (.+) FROM \"article_links\" JOIN articles ON articles.id = article_links.article_id AND articles.deleted_at IS NULL WHERE article_links.next_check_at <= (.+) ORDER BY article_links.next_check_at LIMIT (.+)").
+		WithArgs(now, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"article_id", "user_id", "health"}).
+			AddRow(test.TestArticle.ID, test.TestArticle.UserID, article.LinkHealthOK))
+	mock.ExpectQuery("^SELECT id, article_link, user_id, fingerprint FROM \"articles\" WHERE \"articles\".\"id\" = (.+) AND \"articles\".\"deleted_at\" IS NULL").
+		WithArgs(test.TestArticle.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "article_link", "user_id", "fingerprint"}).
+			AddRow(test.TestArticle.ID, test.TestArticle.ArticleLink, test.TestArticle.UserID, 42))
+
+	links, err := r.ListDue(context.Background(), now, 10)
+	assert.Nil(t, err)
+	assert.Len(t, links, 1)
+	assert.Equal(t, article.LinkHealthOK, links[0].Health)
+	assert.Equal(t, test.TestArticle.ArticleLink, links[0].Article.ArticleLink)
+	assert.Equal(t, int64(42), links[0].Article.Fingerprint)
+}
+
+func TestSaveCheck(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	now := time.Now().UTC()
+	link := linkhealth.Link{
+		ArticleID:       test.TestArticle.ID,
+		Health:          article.LinkHealthBroken,
+		StatusCode:      404,
+		Error:           "page not found",
+		HealthChangedAt: &now,
+		CheckedAt:       &now,
+		NextCheckAt:     now.Add(time.Hour),
+	}
+	check := linkhealth.Check{ID: "check", ArticleID: test.TestArticle.ID, Method: "HEAD", StatusCode: 404, Health: article.LinkHealthBroken, CheckedAt: now}
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		err           error
+	}{
+		{
+			name: "should update link and add the check to its history",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("^UPDATE \"article_links\" SET \"checked_at\"=\\$1,\"distance\"=\\$2,\"error\"=\\$3,\"etag\"=\\$4,\"failures\"=\\$5,\"fingerprint\"=\\$6,\"health\"=\\$7,\"health_changed_at\"=\\$8,\"last_modified\"=\\$9,\"moved_to\"=\\$10,\"next_check_at\"=\\$11,\"status_code\"=\\$12 WHERE article_id = \\$13").
+					WithArgs(link.CheckedAt, 0, link.Error, "", 0, int64(0), link.Health, link.HealthChangedAt, "", "", link.NextCheckAt, link.StatusCode, link.ArticleID).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("^INSERT INTO \"link_checks\"").
+					WithArgs(check.ID, check.ArticleID, check.Method, check.StatusCode, check.FinalURL, check.Health, check.Error, check.CheckedAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("^DELETE FROM \"link_checks\" WHERE article_id = \\$1 AND id NOT IN \\(SELECT \"id\" FROM \"link_checks\" WHERE article_id = \\$2 ORDER BY checked_at DESC LIMIT \\$3\\)").
+					WithArgs(link.ArticleID, link.ArticleID, linkhealth.MaxChecks).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			err: nil,
+		},
+		{
+			name: "should return err when fail to save check",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("^UPDATE \"article_links\"").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("^INSERT INTO \"link_checks\"").
+					WillReturnError(gorm.ErrInvalidDB)
+				mock.ExpectRollback()
+			},
+			err: gorm.ErrInvalidDB,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+
+			err := r.SaveCheck(context.Background(), link, check)
+			assert.Equal(t, c.err, err)
+		})
+	}
+}
+
+func TestSummary(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	checkedAt := time.Now().UTC()
+
+	mock.ExpectQuery("^SELECT COUNT(.+) FROM \"articles\" LEFT JOIN article_links ON article_links.article_id = articles.id WHERE articles.user_id = (.+) AND \"articles\".\"deleted_at\" IS NULL").
+		WithArgs(test.TestUser.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"ok", "moved", "changed", "broken", "unchecked", "last_checked_at"}).
+			AddRow(10, 2, 1, 3, 4, checkedAt))
+
+	summary, err := r.Summary(context.Background(), test.TestUser.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, &linkhealth.Summary{OK: 10, Moved: 2, Changed: 1, Broken: 3, Unchecked: 4, LastCheckedAt: &checkedAt}, summary)
+}
+
+func TestList(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	page := pagination.Pagination{Limit: 10, Offset: 0}
+	countQuery := "^SELECT count\\(\\*\\) FROM \"article_links\" JOIN articles ON (.+) WHERE article_links.user_id = (.+) AND article_links.health "
+	listQuery := "^SELECT (.+) FROM \"article_links\" JOIN articles ON (.+) WHERE article_links.user_id = (.+) AND article_links.health (.+) ORDER BY article_links.health_changed_at DESC, article_links.article_id LIMIT (.+)$"
+	articleQuery := "^SELECT id, title, article_link, domain, created_at, updated_at FROM \"articles\" WHERE \"articles\".\"id\" = (.+) AND \"articles\".\"deleted_at\" IS NULL"
+
+	cases := []struct {
+		name          string
+		health        article.LinkHealth
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		total         int64
+		err           error
+	}{
+		{
+			name:   "should return user's links with the health",
+			health: article.LinkHealthBroken,
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(countQuery+"= ").
+					WithArgs(test.TestUser.ID, article.LinkHealthBroken).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery(listQuery).
+					WithArgs(test.TestUser.ID, article.LinkHealthBroken, page.Limit).
+					WillReturnRows(sqlmock.NewRows([]string{"article_id", "health"}).AddRow(test.TestArticle.ID, article.LinkHealthBroken))
+				mock.ExpectQuery(articleQuery).
+					WithArgs(test.TestArticle.ID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(test.TestArticle.ID, test.TestArticle.Title))
+			},
+			total: 1,
+			err:   nil,
+		},
+		{
+			name:   "should return every flagged link when health is empty",
+			health: "",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(countQuery+"IN ").
+					WithArgs(test.TestUser.ID, article.LinkHealthMoved, article.LinkHealthChanged, article.LinkHealthBroken).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectQuery(listQuery).
+					WithArgs(test.TestUser.ID, article.LinkHealthMoved, article.LinkHealthChanged, article.LinkHealthBroken, page.Limit).
+					WillReturnRows(sqlmock.NewRows([]string{"article_id", "health"}))
+			},
+			total: 0,
+			err:   nil,
+		},
+		{
+			name:   "should return err when fail to count links",
+			health: article.LinkHealthMoved,
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(countQuery).
+					WillReturnError(gorm.ErrInvalidDB)
+			},
+			total: 0,
+			err:   gorm.ErrInvalidDB,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+
+			links, total, err := r.List(context.Background(), test.TestUser.ID, c.health, page)
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.total, total)
+			if err != nil {
+				return
+			}
+			assert.Len(t, links, int(c.total))
+		})
+	}
+}
+
+func TestListChecks(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+
+	mock.ExpectQuery("^SELECT \\* FROM \"link_checks\" WHERE article_id = (.+) ORDER BY checked_at DESC LIMIT (.+)").
+		WithArgs(test.TestArticle.ID, linkhealth.MaxChecks).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status_code", "health"}).AddRow("check", 200, article.LinkHealthOK))
+
+	checks, err := r.ListChecks(context.Background(), test.TestArticle.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, []*linkhealth.Check{{ID: "check", StatusCode: 200, Health: article.LinkHealthOK}}, checks)
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/linkhealth"
+	"github.com/ryanadiputraa/unclatter/app/pagination"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/pkg/linkcheck"
+	"github.com/ryanadiputraa/unclatter/pkg/logger"
+)
+
+type service struct {
+	log               logger.Logger
+	checker           linkcheck.Checker
+	repository        linkhealth.LinkHealthRepository
+	articleRepository article.ArticleRepository
+	// recheckAfter is how long a link that was reached waits before it's checked again.
+	recheckAfter time.Duration
+}
+
+func NewService(log logger.Logger, checker linkcheck.Checker, repository linkhealth.LinkHealthRepository, articleRepository article.ArticleRepository, recheckAfter time.Duration) linkhealth.LinkHealthService {
+	return &service{
+		log:               log,
+		checker:           checker,
+		repository:        repository,
+		articleRepository: articleRepository,
+		recheckAfter:      recheckAfter,
+	}
+}
+
+func (s *service) GetSummary(ctx context.Context, userID string) (*linkhealth.Summary, error) {
+	summary, err := s.repository.Summary(ctx, userID)
+	if err != nil {
+		s.log.Error("link health service: fail to summarize user's links", err)
+		return nil, err
+	}
+	return summary, nil
+}
+
+func (s *service) ListLinks(ctx context.Context, userID string, health article.LinkHealth, page pagination.Pagination) (links []*linkhealth.Link, meta *pagination.Meta, err error) {
+	links, total, err := s.repository.List(ctx, userID, health, page)
+	if err != nil {
+		s.log.Error("link health service: fail to fetch user's links", err)
+		return
+	}
+
+	meta = pagination.NewMeta(page, total)
+	return
+}
+
+func (s *service) ListChecks(ctx context.Context, userID, articleID string) ([]*linkhealth.Check, error) {
+	a, err := s.articleRepository.FindByID(ctx, articleID)
+	if err != nil {
+		s.log.Warn("link health service: fail to fetch article ", articleID, " ", err)
+		return nil, err
+	}
+	if a.UserID != userID {
+		return nil, validation.NewError(validation.Forbidden, "forbidden access")
+	}
+
+	checks, err := s.repository.ListChecks(ctx, articleID)
+	if err != nil {
+		s.log.Error("link health service: fail to fetch link checks", err)
+		return nil, err
+	}
+	return checks, nil
+}
+
+func (s *service) CheckDueLinks(ctx context.Context, now time.Time, limit int) (int, error) {
+	if _, err := s.repository.Track(ctx, now); err != nil {
+		s.log.Error("link health service: fail to track new links", err)
+		return 0, err
+	}
+
+	links, err := s.repository.ListDue(ctx, now, limit)
+	if err != nil {
+		s.log.Error("link health service: fail to fetch due links", err)
+		return 0, err
+	}
+
+	checked := 0
+	for _, l := range links {
+		if ctx.Err() != nil {
+			return checked, ctx.Err()
+		}
+
+		res, err := s.checker.Check(ctx, linkcheck.Request{
+			URL:          l.Article.ArticleLink,
+			ETag:         l.ETag,
+			LastModified: l.LastModified,
+		})
+		// a check cut short by stopping the job says nothing about the link
+		if ctx.Err() != nil {
+			return checked, ctx.Err()
+		}
+		if err != nil {
+			s.log.Warn("link health service: fail to check ", l.Article.ArticleLink, " ", err)
+		}
+
+		check := l.Record(res, err, now, s.recheckAfter)
+		if err = s.repository.SaveCheck(ctx, *l, *check); err != nil {
+			s.log.Error("link health service: fail to save link check", err)
+			return checked, err
+		}
+		checked++
+	}
+	return checked, nil
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/linkhealth"
+	"github.com/ryanadiputraa/unclatter/app/mocks"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/pkg/linkcheck"
+	"github.com/ryanadiputraa/unclatter/pkg/logger"
+	"github.com/ryanadiputraa/unclatter/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const recheckAfter = 7 * 24 * time.Hour
+
+func TestListChecks(t *testing.T) {
+	cases := []struct {
+		name                 string
+		userID               string
+		expected             []*linkhealth.Check
+		err                  error
+		mockArticleBehaviour func(mockRepo *mocks.ArticleRepository)
+		mockRepoBehaviour    func(mockRepo *mocks.LinkHealthRepository)
+	}{
+		{
+			name:     "should return history of user's article link",
+			userID:   test.TestArticle.UserID,
+			expected: []*linkhealth.Check{{ID: "check", Health: article.LinkHealthOK}},
+			err:      nil,
+			mockArticleBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+			},
+			mockRepoBehaviour: func(mockRepo *mocks.LinkHealthRepository) {
+				mockRepo.On("ListChecks", context.Background(), test.TestArticle.ID).
+					Return([]*linkhealth.Check{{ID: "check", Health: article.LinkHealthOK}}, nil)
+			},
+		},
+		{
+			name:     "should return err when article is another user's",
+			userID:   uuid.NewString(),
+			expected: nil,
+			err:      validation.NewError(validation.Forbidden, "forbidden access"),
+			mockArticleBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+			},
+			mockRepoBehaviour: func(mockRepo *mocks.LinkHealthRepository) {},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.LinkHealthRepository)
+			c.mockRepoBehaviour(r)
+			articleRepo := new(mocks.ArticleRepository)
+			c.mockArticleBehaviour(articleRepo)
+
+			s := NewService(logger.NewLogger(), new(mocks.LinkChecker), r, articleRepo, recheckAfter)
+			checks, err := s.ListChecks(context.Background(), c.userID, test.TestArticle.ID)
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.expected, checks)
+			r.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCheckDueLinks(t *testing.T) {
+	now := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name                 string
+		health               article.LinkHealth
+		failures             int
+		mockCheckerBehaviour func(mockChecker *mocks.LinkChecker)
+	}{
+		{
+			name:   "should record that link works",
+			health: article.LinkHealthOK,
+			mockCheckerBehaviour: func(mockChecker *mocks.LinkChecker) {
+				mockChecker.On("Check", context.Background(), linkcheck.Request{URL: test.TestArticle.ArticleLink, ETag: `"v1"`}).
+					Return(&linkcheck.Result{Method: "HEAD", StatusCode: 304, FinalURL: test.TestArticle.ArticleLink, NotModified: true}, nil)
+			},
+		},
+		{
+			name:   "should record that link is broken",
+			health: article.LinkHealthBroken,
+			mockCheckerBehaviour: func(mockChecker *mocks.LinkChecker) {
+				mockChecker.On("Check", context.Background(), mock.Anything).
+					Return(&linkcheck.Result{Method: "HEAD", StatusCode: 404, FinalURL: test.TestArticle.ArticleLink}, nil)
+			},
+		},
+		{
+			name:     "should record failed check when link can't be reached",
+			health:   article.LinkHealthOK,
+			failures: 1,
+			mockCheckerBehaviour: func(mockChecker *mocks.LinkChecker) {
+				mockChecker.On("Check", context.Background(), mock.Anything).Return(nil, errors.New("dial tcp: i/o timeout"))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			due := &linkhealth.Link{
+				ArticleID: test.TestArticle.ID,
+				UserID:    test.TestArticle.UserID,
+				Health:    article.LinkHealthOK,
+				ETag:      `"v1"`,
+				Article:   &article.Article{ID: test.TestArticle.ID, ArticleLink: test.TestArticle.ArticleLink},
+			}
+
+			r := new(mocks.LinkHealthRepository)
+			r.On("Track", context.Background(), now).Return(int64(0), nil)
+			r.On("ListDue", context.Background(), now, 10).Return([]*linkhealth.Link{due}, nil)
+			r.On("SaveCheck", context.Background(), mock.MatchedBy(func(l linkhealth.Link) bool {
+				return l.ArticleID == test.TestArticle.ID && l.Health == c.health && l.Failures == c.failures
+			}), mock.MatchedBy(func(check linkhealth.Check) bool {
+				return check.ArticleID == test.TestArticle.ID && check.CheckedAt.Equal(now)
+			})).Return(nil)
+			checker := new(mocks.LinkChecker)
+			c.mockCheckerBehaviour(checker)
+
+			s := NewService(logger.NewLogger(), checker, r, new(mocks.ArticleRepository), recheckAfter)
+			checked, err := s.CheckDueLinks(context.Background(), now, 10)
+			assert.Nil(t, err)
+			assert.Equal(t, 1, checked)
+			r.AssertExpectations(t)
+			checker.AssertExpectations(t)
+		})
+	}
+}
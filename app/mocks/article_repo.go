@@ -7,9 +7,11 @@ import (
 
 	article "github.com/ryanadiputraa/unclatter/app/article"
 
-	mock "github.com/stretchr/testify/mock"
-
 	pagination "github.com/ryanadiputraa/unclatter/app/pagination"
+
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
 )
 
 // ArticleRepository is an autogenerated mock type for the ArticleRepository type
@@ -17,6 +19,66 @@ type ArticleRepository struct {
 	mock.Mock
 }
 
+// BulkUpdate provides a mock function with given fields: ctx, userID, op
+func (_m *ArticleRepository) BulkUpdate(ctx context.Context, userID string, op article.BulkOperation) ([]*article.BulkResult, error) {
+	ret := _m.Called(ctx, userID, op)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BulkUpdate")
+	}
+
+	var r0 []*article.BulkResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, article.BulkOperation) ([]*article.BulkResult, error)); ok {
+		return rf(ctx, userID, op)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, article.BulkOperation) []*article.BulkResult); ok {
+		r0 = rf(ctx, userID, op)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*article.BulkResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, article.BulkOperation) error); ok {
+		r1 = rf(ctx, userID, op)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountStates provides a mock function with given fields: ctx, userID
+func (_m *ArticleRepository) CountStates(ctx context.Context, userID string) (*article.StateCounts, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountStates")
+	}
+
+	var r0 *article.StateCounts
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*article.StateCounts, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *article.StateCounts); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*article.StateCounts)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Delete provides a mock function with given fields: ctx, userID, articleID
 func (_m *ArticleRepository) Delete(ctx context.Context, userID string, articleID string) error {
 	ret := _m.Called(ctx, userID, articleID)
@@ -65,14 +127,238 @@ func (_m *ArticleRepository) FindByID(ctx context.Context, articleID string) (*a
 	return r0, r1
 }
 
-// List provides a mock function with given fields: ctx, userID, page
-func (_m *ArticleRepository) List(ctx context.Context, userID string, page pagination.Pagination) ([]*article.Article, int64, error) {
-	ret := _m.Called(ctx, userID, page)
+// FindByLink provides a mock function with given fields: ctx, userID, normalizedLink
+func (_m *ArticleRepository) FindByLink(ctx context.Context, userID string, normalizedLink string) (*article.Article, error) {
+	ret := _m.Called(ctx, userID, normalizedLink)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByLink")
+	}
+
+	var r0 *article.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*article.Article, error)); ok {
+		return rf(ctx, userID, normalizedLink)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *article.Article); ok {
+		r0 = rf(ctx, userID, normalizedLink)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*article.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, normalizedLink)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindNearDuplicates provides a mock function with given fields: ctx, userID, articleID, fingerprint
+func (_m *ArticleRepository) FindNearDuplicates(ctx context.Context, userID string, articleID string, fingerprint int64) ([]*article.Article, error) {
+	ret := _m.Called(ctx, userID, articleID, fingerprint)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindNearDuplicates")
+	}
+
+	var r0 []*article.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) ([]*article.Article, error)); ok {
+		return rf(ctx, userID, articleID, fingerprint)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) []*article.Article); ok {
+		r0 = rf(ctx, userID, articleID, fingerprint)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*article.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int64) error); ok {
+		r1 = rf(ctx, userID, articleID, fingerprint)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindRevision provides a mock function with given fields: ctx, articleID, revisionID
+func (_m *ArticleRepository) FindRevision(ctx context.Context, articleID string, revisionID string) (*article.Revision, error) {
+	ret := _m.Called(ctx, articleID, revisionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindRevision")
+	}
+
+	var r0 *article.Revision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*article.Revision, error)); ok {
+		return rf(ctx, articleID, revisionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *article.Revision); ok {
+		r0 = rf(ctx, articleID, revisionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*article.Revision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, articleID, revisionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, userID, filter, page
+func (_m *ArticleRepository) List(ctx context.Context, userID string, filter article.ListFilter, page pagination.Pagination) ([]*article.Article, int64, error) {
+	ret := _m.Called(ctx, userID, filter, page)
 
 	if len(ret) == 0 {
 		panic("no return value specified for List")
 	}
 
+	var r0 []*article.Article
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, article.ListFilter, pagination.Pagination) ([]*article.Article, int64, error)); ok {
+		return rf(ctx, userID, filter, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, article.ListFilter, pagination.Pagination) []*article.Article); ok {
+		r0 = rf(ctx, userID, filter, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*article.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, article.ListFilter, pagination.Pagination) int64); ok {
+		r1 = rf(ctx, userID, filter, page)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, article.ListFilter, pagination.Pagination) error); ok {
+		r2 = rf(ctx, userID, filter, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ListFingerprinted provides a mock function with given fields: ctx, userID
+func (_m *ArticleRepository) ListFingerprinted(ctx context.Context, userID string) ([]*article.Article, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListFingerprinted")
+	}
+
+	var r0 []*article.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*article.Article, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*article.Article); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*article.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListRelated provides a mock function with given fields: ctx, userID, articleID, limit
+func (_m *ArticleRepository) ListRelated(ctx context.Context, userID string, articleID string, limit int) ([]*article.Article, error) {
+	ret := _m.Called(ctx, userID, articleID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRelated")
+	}
+
+	var r0 []*article.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) ([]*article.Article, error)); ok {
+		return rf(ctx, userID, articleID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) []*article.Article); ok {
+		r0 = rf(ctx, userID, articleID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*article.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int) error); ok {
+		r1 = rf(ctx, userID, articleID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListRevisions provides a mock function with given fields: ctx, articleID, page
+func (_m *ArticleRepository) ListRevisions(ctx context.Context, articleID string, page pagination.Pagination) ([]*article.Revision, int64, error) {
+	ret := _m.Called(ctx, articleID, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRevisions")
+	}
+
+	var r0 []*article.Revision
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, pagination.Pagination) ([]*article.Revision, int64, error)); ok {
+		return rf(ctx, articleID, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, pagination.Pagination) []*article.Revision); ok {
+		r0 = rf(ctx, articleID, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*article.Revision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, pagination.Pagination) int64); ok {
+		r1 = rf(ctx, articleID, page)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, pagination.Pagination) error); ok {
+		r2 = rf(ctx, articleID, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ListTrashed provides a mock function with given fields: ctx, userID, page
+func (_m *ArticleRepository) ListTrashed(ctx context.Context, userID string, page pagination.Pagination) ([]*article.Article, int64, error) {
+	ret := _m.Called(ctx, userID, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTrashed")
+	}
+
 	var r0 []*article.Article
 	var r1 int64
 	var r2 error
@@ -102,6 +388,220 @@ func (_m *ArticleRepository) List(ctx context.Context, userID string, page pagin
 	return r0, r1, r2
 }
 
+// ListUnindexed provides a mock function with given fields: ctx, limit
+func (_m *ArticleRepository) ListUnindexed(ctx context.Context, limit int) ([]*article.Article, error) {
+	ret := _m.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUnindexed")
+	}
+
+	var r0 []*article.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]*article.Article, error)); ok {
+		return rf(ctx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []*article.Article); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*article.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListUnsanitized provides a mock function with given fields: ctx, version, limit
+func (_m *ArticleRepository) ListUnsanitized(ctx context.Context, version int, limit int) ([]*article.Article, error) {
+	ret := _m.Called(ctx, version, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUnsanitized")
+	}
+
+	var r0 []*article.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]*article.Article, error)); ok {
+		return rf(ctx, version, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []*article.Article); ok {
+		r0 = rf(ctx, version, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*article.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, version, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkSanitized provides a mock function with given fields: ctx, articleID, version
+func (_m *ArticleRepository) MarkSanitized(ctx context.Context, articleID string, version int) error {
+	ret := _m.Called(ctx, articleID, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkSanitized")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) error); ok {
+		r0 = rf(ctx, articleID, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Merge provides a mock function with given fields: ctx, userID, keepID, duplicateIDs
+func (_m *ArticleRepository) Merge(ctx context.Context, userID string, keepID string, duplicateIDs []string) (*article.Article, error) {
+	ret := _m.Called(ctx, userID, keepID, duplicateIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Merge")
+	}
+
+	var r0 *article.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string) (*article.Article, error)); ok {
+		return rf(ctx, userID, keepID, duplicateIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string) *article.Article); ok {
+		r0 = rf(ctx, userID, keepID, duplicateIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*article.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, []string) error); ok {
+		r1 = rf(ctx, userID, keepID, duplicateIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Purge provides a mock function with given fields: ctx, userID, articleID
+func (_m *ArticleRepository) Purge(ctx context.Context, userID string, articleID string) error {
+	ret := _m.Called(ctx, userID, articleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Purge")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, articleID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PurgeTrashedBefore provides a mock function with given fields: ctx, before
+func (_m *ArticleRepository) PurgeTrashedBefore(ctx context.Context, before time.Time) (int64, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeTrashedBefore")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReadingStats provides a mock function with given fields: ctx, userID, period
+func (_m *ArticleRepository) ReadingStats(ctx context.Context, userID string, period article.StatsPeriod) (*article.Stats, error) {
+	ret := _m.Called(ctx, userID, period)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReadingStats")
+	}
+
+	var r0 *article.Stats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, article.StatsPeriod) (*article.Stats, error)); ok {
+		return rf(ctx, userID, period)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, article.StatsPeriod) *article.Stats); ok {
+		r0 = rf(ctx, userID, period)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*article.Stats)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, article.StatsPeriod) error); ok {
+		r1 = rf(ctx, userID, period)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, userID, articleID
+func (_m *ArticleRepository) Restore(ctx context.Context, userID string, articleID string) (*article.Article, error) {
+	ret := _m.Called(ctx, userID, articleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restore")
+	}
+
+	var r0 *article.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*article.Article, error)); ok {
+		return rf(ctx, userID, articleID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *article.Article); ok {
+		r0 = rf(ctx, userID, articleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*article.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, articleID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Save provides a mock function with given fields: ctx, arg
 func (_m *ArticleRepository) Save(ctx context.Context, arg article.Article) error {
 	ret := _m.Called(ctx, arg)
@@ -120,9 +620,27 @@ func (_m *ArticleRepository) Save(ctx context.Context, arg article.Article) erro
 	return r0
 }
 
-// Update provides a mock function with given fields: ctx, arg
-func (_m *ArticleRepository) Update(ctx context.Context, arg article.Article) (*article.Article, error) {
-	ret := _m.Called(ctx, arg)
+// SaveIndex provides a mock function with given fields: ctx, a, terms
+func (_m *ArticleRepository) SaveIndex(ctx context.Context, a article.Article, terms []*article.Term) error {
+	ret := _m.Called(ctx, a, terms)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveIndex")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, article.Article, []*article.Term) error); ok {
+		r0 = rf(ctx, a, terms)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, arg, source
+func (_m *ArticleRepository) Update(ctx context.Context, arg article.Article, source article.RevisionSource) (*article.Article, error) {
+	ret := _m.Called(ctx, arg, source)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Update")
@@ -130,19 +648,97 @@ func (_m *ArticleRepository) Update(ctx context.Context, arg article.Article) (*
 
 	var r0 *article.Article
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, article.Article) (*article.Article, error)); ok {
-		return rf(ctx, arg)
+	if rf, ok := ret.Get(0).(func(context.Context, article.Article, article.RevisionSource) (*article.Article, error)); ok {
+		return rf(ctx, arg, source)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, article.Article) *article.Article); ok {
-		r0 = rf(ctx, arg)
+	if rf, ok := ret.Get(0).(func(context.Context, article.Article, article.RevisionSource) *article.Article); ok {
+		r0 = rf(ctx, arg, source)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*article.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, article.Article, article.RevisionSource) error); ok {
+		r1 = rf(ctx, arg, source)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateExcerpt provides a mock function with given fields: ctx, articleID, excerpt
+func (_m *ArticleRepository) UpdateExcerpt(ctx context.Context, articleID string, excerpt string) error {
+	ret := _m.Called(ctx, articleID, excerpt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateExcerpt")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, articleID, excerpt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateSnooze provides a mock function with given fields: ctx, userID, articleID, until
+func (_m *ArticleRepository) UpdateSnooze(ctx context.Context, userID string, articleID string, until *time.Time) (*article.Article, error) {
+	ret := _m.Called(ctx, userID, articleID, until)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSnooze")
+	}
+
+	var r0 *article.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *time.Time) (*article.Article, error)); ok {
+		return rf(ctx, userID, articleID, until)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *time.Time) *article.Article); ok {
+		r0 = rf(ctx, userID, articleID, until)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*article.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *time.Time) error); ok {
+		r1 = rf(ctx, userID, articleID, until)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateState provides a mock function with given fields: ctx, userID, articleID, state, at
+func (_m *ArticleRepository) UpdateState(ctx context.Context, userID string, articleID string, state article.State, at *time.Time) (*article.Article, error) {
+	ret := _m.Called(ctx, userID, articleID, state, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateState")
+	}
+
+	var r0 *article.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, article.State, *time.Time) (*article.Article, error)); ok {
+		return rf(ctx, userID, articleID, state, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, article.State, *time.Time) *article.Article); ok {
+		r0 = rf(ctx, userID, articleID, state, at)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*article.Article)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, article.Article) error); ok {
-		r1 = rf(ctx, arg)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, article.State, *time.Time) error); ok {
+		r1 = rf(ctx, userID, articleID, state, at)
 	} else {
 		r1 = ret.Error(1)
 	}
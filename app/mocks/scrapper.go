@@ -2,30 +2,36 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	scrapper "github.com/ryanadiputraa/unclatter/pkg/scrapper"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 // Scrapper is an autogenerated mock type for the Scrapper type
 type Scrapper struct {
 	mock.Mock
 }
 
-// ScrapeTextContent provides a mock function with given fields: url
-func (_m *Scrapper) ScrapeTextContent(url string) (string, error) {
+// ScrapePage provides a mock function with given fields: url
+func (_m *Scrapper) ScrapePage(url string) (*scrapper.Page, error) {
 	ret := _m.Called(url)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ScrapeTextContent")
+		panic("no return value specified for ScrapePage")
 	}
 
-	var r0 string
+	var r0 *scrapper.Page
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+	if rf, ok := ret.Get(0).(func(string) (*scrapper.Page, error)); ok {
 		return rf(url)
 	}
-	if rf, ok := ret.Get(0).(func(string) string); ok {
+	if rf, ok := ret.Get(0).(func(string) *scrapper.Page); ok {
 		r0 = rf(url)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*scrapper.Page)
+		}
 	}
 
 	if rf, ok := ret.Get(1).(func(string) error); ok {
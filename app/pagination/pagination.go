@@ -1,22 +1,42 @@
 package pagination
 
-import "strconv"
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
 
 const (
 	defaultPage = 1
 	defaultSize = 20
+	maxSize     = 100
 )
 
 type Meta struct {
-	CurrentPage int   `json:"current_page"`
+	// CurrentPage is left out of cursor pages, they aren't numbered.
+	CurrentPage int   `json:"current_page,omitempty"`
 	TotalPages  int   `json:"total_pages"`
 	Size        int   `json:"size"`
 	TotalData   int64 `json:"total_data"`
+	// NextCursor fetches the page after this one, it's empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 type Pagination struct {
 	Limit  int
 	Offset int
+	// Cursor replaces Offset for keyset pagination, rows are fetched after the cursor position.
+	Cursor *Cursor
+}
+
+// Cursor is the (updated_at, id) position of the last row of a page.
+type Cursor struct {
+	UpdatedAt time.Time `json:"u"`
+	ID        string    `json:"i"`
 }
 
 func NewPagination(page, size int) *Pagination {
@@ -26,27 +46,90 @@ func NewPagination(page, size int) *Pagination {
 	}
 }
 
+func NewMeta(page Pagination, total int64) *Meta {
+	totalPages := 0
+	if total > 0 {
+		totalPages = int(math.Ceil(float64(total) / float64(page.Limit)))
+	}
+
+	meta := &Meta{
+		TotalPages: totalPages,
+		Size:       page.Limit,
+		TotalData:  total,
+	}
+	if page.Cursor == nil {
+		meta.CurrentPage = page.Offset/page.Limit + 1
+	}
+	return meta
+}
+
+// Encode returns the opaque cursor string handed out to clients.
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func DecodeCursor(cursor string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	var c *Cursor
+	if err = json.Unmarshal(b, &c); err != nil {
+		return nil, err
+	}
+	if c == nil || c.ID == "" || c.UpdatedAt.IsZero() {
+		return nil, errors.New("incomplete cursor")
+	}
+	return c, nil
+}
+
 func ValidateParam(pageParam, sizeParam string) (pagination *Pagination, errDetail map[string]string, err error) {
-	var page int
-	var size int
+	page := defaultPage
+	size := defaultSize
 	errDetail = make(map[string]string)
 
-	if len(pageParam) == 0 {
-		page = defaultPage
-	} else {
+	if len(pageParam) > 0 {
 		page, err = strconv.Atoi(pageParam)
 		if err != nil {
 			errDetail["page"] = "invalid 'page' param expecting int"
+		} else if page < 1 {
+			errDetail["page"] = "'page' param should be greater than or equal to 1"
 		}
 	}
-	if len(sizeParam) == 0 {
-		size = defaultSize
-	} else {
+	if len(sizeParam) > 0 {
 		size, err = strconv.Atoi(sizeParam)
 		if err != nil {
 			errDetail["size"] = "invalid 'size' param expecting int"
+		} else if size < 1 || size > maxSize {
+			errDetail["size"] = fmt.Sprintf("'size' param should be between 1 and %d", maxSize)
 		}
 	}
 
-	return NewPagination(page, size), errDetail, err
+	if len(errDetail) > 0 {
+		return nil, errDetail, errors.New("invalid params")
+	}
+	return NewPagination(page, size), errDetail, nil
+}
+
+// ValidateCursorParam is ValidateParam for lists supporting keyset pagination, a cursor can't be combined with a page.
+func ValidateCursorParam(pageParam, sizeParam, cursorParam string) (pagination *Pagination, errDetail map[string]string, err error) {
+	pagination, errDetail, err = ValidateParam(pageParam, sizeParam)
+	if len(cursorParam) == 0 {
+		return
+	}
+
+	if len(pageParam) > 0 {
+		errDetail["cursor"] = "'cursor' param can't be combined with 'page'"
+	} else if cursor, cErr := DecodeCursor(cursorParam); cErr != nil {
+		errDetail["cursor"] = "invalid 'cursor' param"
+	} else if pagination != nil {
+		pagination.Cursor = cursor
+	}
+
+	if len(errDetail) > 0 {
+		return nil, errDetail, errors.New("invalid params")
+	}
+	return
 }
@@ -0,0 +1,150 @@
+package pagination
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateParam(t *testing.T) {
+	cases := []struct {
+		name       string
+		page       string
+		size       string
+		expected   *Pagination
+		errDetails map[string]string
+	}{
+		{
+			name:       "should return default pagination when params are empty",
+			expected:   &Pagination{Limit: defaultSize, Offset: 0},
+			errDetails: map[string]string{},
+		},
+		{
+			name:       "should return pagination for the given page and size",
+			page:       "3",
+			size:       "10",
+			expected:   &Pagination{Limit: 10, Offset: 20},
+			errDetails: map[string]string{},
+		},
+		{
+			name:       "should return err when page is not a number",
+			page:       "first",
+			size:       "10",
+			expected:   nil,
+			errDetails: map[string]string{"page": "invalid 'page' param expecting int"},
+		},
+		{
+			name:     "should return err when page and size are out of bounds",
+			page:     "0",
+			size:     "101",
+			expected: nil,
+			errDetails: map[string]string{
+				"page": "'page' param should be greater than or equal to 1",
+				"size": "'size' param should be between 1 and 100",
+			},
+		},
+		{
+			name:       "should return err when size is negative",
+			size:       "-5",
+			expected:   nil,
+			errDetails: map[string]string{"size": "'size' param should be between 1 and 100"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			pagination, errDetails, err := ValidateParam(c.page, c.size)
+			assert.Equal(t, c.expected, pagination)
+			assert.Equal(t, c.errDetails, errDetails)
+			if len(c.errDetails) > 0 {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateCursorParam(t *testing.T) {
+	cursor := Cursor{UpdatedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), ID: "article-1"}
+
+	cases := []struct {
+		name       string
+		page       string
+		cursor     string
+		expected   *Pagination
+		errDetails map[string]string
+	}{
+		{
+			name:       "should return pagination with decoded cursor",
+			cursor:     cursor.Encode(),
+			expected:   &Pagination{Limit: defaultSize, Cursor: &cursor},
+			errDetails: map[string]string{},
+		},
+		{
+			name:       "should return err when cursor is malformed",
+			cursor:     "not-a-cursor",
+			expected:   nil,
+			errDetails: map[string]string{"cursor": "invalid 'cursor' param"},
+		},
+		{
+			name:       "should return err when cursor is combined with page",
+			page:       "2",
+			cursor:     cursor.Encode(),
+			expected:   nil,
+			errDetails: map[string]string{"cursor": "'cursor' param can't be combined with 'page'"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			pagination, errDetails, err := ValidateCursorParam(c.page, "", c.cursor)
+			assert.Equal(t, c.expected, pagination)
+			assert.Equal(t, c.errDetails, errDetails)
+			if len(c.errDetails) > 0 {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestNewMeta(t *testing.T) {
+	cursor := Cursor{UpdatedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), ID: "article-1"}
+
+	cases := []struct {
+		name     string
+		page     Pagination
+		total    int64
+		expected *Meta
+		json     string
+	}{
+		{
+			name:     "should number offset pages",
+			page:     Pagination{Limit: 20, Offset: 40},
+			total:    45,
+			expected: &Meta{CurrentPage: 3, TotalPages: 3, Size: 20, TotalData: 45},
+			json:     `{"current_page":3,"total_pages":3,"size":20,"total_data":45}`,
+		},
+		{
+			name:     "should leave the current page out of cursor pages",
+			page:     Pagination{Limit: 20, Cursor: &cursor},
+			total:    45,
+			expected: &Meta{TotalPages: 3, Size: 20, TotalData: 45},
+			json:     `{"total_pages":3,"size":20,"total_data":45}`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			meta := NewMeta(c.page, c.total)
+			assert.Equal(t, c.expected, meta)
+			b, err := json.Marshal(meta)
+			assert.NoError(t, err)
+			assert.JSONEq(t, c.json, string(b))
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ryanadiputraa/unclatter/app/middleware"
+	"github.com/ryanadiputraa/unclatter/app/pagination"
+	"github.com/ryanadiputraa/unclatter/app/progress"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	_http "github.com/ryanadiputraa/unclatter/pkg/http"
+	"github.com/ryanadiputraa/unclatter/pkg/validator"
+)
+
+type handler struct {
+	rw              _http.ResponseWriter
+	progressService progress.ProgressService
+	validator       validator.Validator
+}
+
+func NewHandler(web *http.ServeMux, rw _http.ResponseWriter, progressService progress.ProgressService, authMiddleware middleware.AuthMiddleware, validator validator.Validator) {
+	h := &handler{
+		rw:              rw,
+		progressService: progressService,
+		validator:       validator,
+	}
+
+	web.Handle("PUT /api/articles/bookmarks/{id}/progress", authMiddleware.ParseJWTToken(h.RecordProgress()))
+	web.Handle("GET /api/articles/bookmarks/{id}/progress", authMiddleware.ParseJWTToken(h.GetArticleProgress()))
+	web.Handle("GET /api/articles/continue", authMiddleware.ParseJWTToken(h.ListContinueReading()))
+}
+
+func (h *handler) RecordProgress() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		id := r.PathValue("id")
+		var payload progress.ProgressPayload
+
+		json.NewDecoder(r.Body).Decode(&payload)
+		if err, errMap := h.validator.Validate(payload); err != nil {
+			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+			return
+		}
+
+		p, err := h.progressService.RecordProgress(ac.Context, ac.UserID, id, payload)
+		if err != nil {
+			if vErr, ok := err.(*validation.Error); ok {
+				h.rw.WriteErrMessage(w, validation.HttpErrMap[vErr.Err], vErr.Message)
+				return
+			}
+			h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, p)
+	}
+}
+
+func (h *handler) GetArticleProgress() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		id := r.PathValue("id")
+
+		p, err := h.progressService.GetArticleProgress(ac.Context, ac.UserID, id)
+		if err != nil {
+			h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, p)
+	}
+}
+
+func (h *handler) ListContinueReading() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		query := r.URL.Query()
+
+		pagination, errMap, err := pagination.ValidateParam(query.Get("page"), query.Get("size"))
+		if err != nil {
+			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+			return
+		}
+
+		items, meta, err := h.progressService.ListContinueReading(ac.Context, ac.UserID, *pagination)
+		if err != nil {
+			h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		h.rw.WriteResponseDataWithPagination(w, http.StatusOK, items, *meta)
+	}
+}
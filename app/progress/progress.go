@@ -0,0 +1,82 @@
+package progress
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/pagination"
+)
+
+// ReadingProgress is the last known reading position of an article on one of the user's devices.
+// Positions are reconciled with last-writer-wins on UpdatedAt, which is the time reported by the device.
+type ReadingProgress struct {
+	ID             string          `json:"-" gorm:"type:varchar"`
+	UserID         string          `json:"-" gorm:"type:varchar;not null;uniqueIndex:idx_reading_progresses_device"`
+	ArticleID      string          `json:"article_id" gorm:"type:varchar;not null;uniqueIndex:idx_reading_progresses_device"`
+	DeviceID       string          `json:"device_id" gorm:"type:varchar;not null;uniqueIndex:idx_reading_progresses_device"`
+	Percentage     float64         `json:"percentage" gorm:"type:numeric(5,2);not null"`
+	ParagraphIndex *int            `json:"paragraph_index" gorm:"type:integer"`
+	TextOffset     *int            `json:"text_offset" gorm:"type:integer"`
+	UpdatedAt      time.Time       `json:"updated_at" gorm:"type:timestamptz;not null;index;autoUpdateTime:false"`
+	Article        article.Article `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// ContinueReading is an unfinished article with its most recent reading progress across devices.
+type ContinueReading struct {
+	ArticleID      string    `json:"article_id"`
+	Title          string    `json:"title"`
+	ArticleLink    string    `json:"article_link"`
+	DeviceID       string    `json:"device_id"`
+	Percentage     float64   `json:"percentage"`
+	ParagraphIndex *int      `json:"paragraph_index"`
+	TextOffset     *int      `json:"text_offset"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type NewReadingProgressArg struct {
+	UserID         string
+	ArticleID      string
+	DeviceID       string
+	Percentage     float64
+	ParagraphIndex *int
+	TextOffset     *int
+	UpdatedAt      time.Time
+}
+
+type ProgressPayload struct {
+	DeviceID       string  `json:"device_id" validate:"required,max=64"`
+	Percentage     float64 `json:"percentage" validate:"gte=0,lte=100"`
+	ParagraphIndex *int    `json:"paragraph_index" validate:"omitempty,gte=0"`
+	TextOffset     *int    `json:"text_offset" validate:"omitempty,gte=0"`
+	// UpdatedAt is when the position was captured on the device, defaults to the time it's received.
+	UpdatedAt string `json:"updated_at" validate:"omitempty,iso8601date"`
+}
+
+func NewReadingProgress(arg NewReadingProgressArg) *ReadingProgress {
+	return &ReadingProgress{
+		ID:             uuid.NewString(),
+		UserID:         arg.UserID,
+		ArticleID:      arg.ArticleID,
+		DeviceID:       arg.DeviceID,
+		Percentage:     arg.Percentage,
+		ParagraphIndex: arg.ParagraphIndex,
+		TextOffset:     arg.TextOffset,
+		UpdatedAt:      arg.UpdatedAt.UTC(),
+	}
+}
+
+type ProgressService interface {
+	RecordProgress(ctx context.Context, userID, articleID string, arg ProgressPayload) (*ReadingProgress, error)
+	GetArticleProgress(ctx context.Context, userID, articleID string) ([]*ReadingProgress, error)
+	ListContinueReading(ctx context.Context, userID string, page pagination.Pagination) ([]*ContinueReading, *pagination.Meta, error)
+}
+
+type ProgressRepository interface {
+	// Upsert saves the progress unless the device already reported a newer one, stale or unauthorized writes return false.
+	Upsert(ctx context.Context, arg ReadingProgress) (saved bool, err error)
+	FindByDevice(ctx context.Context, userID, articleID, deviceID string) (*ReadingProgress, error)
+	FindByArticle(ctx context.Context, userID, articleID string) ([]*ReadingProgress, error)
+	ListContinueReading(ctx context.Context, userID string, page pagination.Pagination) (items []*ContinueReading, total int64, err error)
+}
@@ -0,0 +1,54 @@
+package progress
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewReadingProgress(t *testing.T) {
+	paragraph := 12
+	reportedAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.FixedZone("WIB", 7*60*60))
+
+	cases := []struct {
+		name     string
+		arg      NewReadingProgressArg
+		expected *ReadingProgress
+	}{
+		{
+			name: "should return a valid reading progress in UTC",
+			arg: NewReadingProgressArg{
+				UserID:         uuid.NewString(),
+				ArticleID:      uuid.NewString(),
+				DeviceID:       "phone",
+				Percentage:     42.5,
+				ParagraphIndex: &paragraph,
+				UpdatedAt:      reportedAt,
+			},
+			expected: &ReadingProgress{
+				DeviceID:       "phone",
+				Percentage:     42.5,
+				ParagraphIndex: &paragraph,
+				UpdatedAt:      reportedAt.UTC(),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p := NewReadingProgress(c.arg)
+
+			assert.NotEmpty(t, p.ID)
+			assert.Equal(t, c.arg.UserID, p.UserID)
+			assert.Equal(t, c.arg.ArticleID, p.ArticleID)
+			assert.Equal(t, c.expected.DeviceID, p.DeviceID)
+			assert.Equal(t, c.expected.Percentage, p.Percentage)
+			assert.Equal(t, c.expected.ParagraphIndex, p.ParagraphIndex)
+			assert.Nil(t, p.TextOffset)
+			assert.Equal(t, time.UTC, p.UpdatedAt.Location())
+			assert.True(t, c.expected.UpdatedAt.Equal(p.UpdatedAt))
+		})
+	}
+}
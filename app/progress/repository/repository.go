@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ryanadiputraa/unclatter/app/pagination"
+	"github.com/ryanadiputraa/unclatter/app/progress"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"gorm.io/gorm"
+)
+
+// upsertQuery writes the progress in a single statement, it's a no-op when the article isn't owned by the user,
+// is in the trash or when the device already reported a position captured later than this one.
+const upsertQuery = `INSERT INTO reading_progresses (id, user_id, article_id, device_id, percentage, paragraph_index, text_offset, updated_at)
+SELECT ?, ?, ?, ?, ?::numeric, ?::integer, ?::integer, ?::timestamptz
+WHERE EXISTS (SELECT 1 FROM articles WHERE id = ? AND user_id = ? AND deleted_at IS NULL)
+ON CONFLICT (user_id, article_id, device_id) DO UPDATE SET
+	percentage = excluded.percentage,
+	paragraph_index = excluded.paragraph_index,
+	text_offset = excluded.text_offset,
+	updated_at = excluded.updated_at
+WHERE reading_progresses.updated_at < excluded.updated_at`
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) progress.ProgressRepository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Upsert(ctx context.Context, arg progress.ReadingProgress) (bool, error) {
+	res := r.db.Exec(upsertQuery,
+		arg.ID, arg.UserID, arg.ArticleID, arg.DeviceID, arg.Percentage, arg.ParagraphIndex, arg.TextOffset, arg.UpdatedAt,
+		arg.ArticleID, arg.UserID,
+	)
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *repository) FindByDevice(ctx context.Context, userID, articleID, deviceID string) (p *progress.ReadingProgress, err error) {
+	err = r.db.
+		Where("user_id = ? AND article_id = ? AND device_id = ?", userID, articleID, deviceID).
+		First(&p).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = validation.NewError(validation.NotFound, "no reading progress found")
+	}
+	return
+}
+
+func (r *repository) FindByArticle(ctx context.Context, userID, articleID string) (p []*progress.ReadingProgress, err error) {
+	err = r.db.
+		Where("user_id = ? AND article_id = ?", userID, articleID).
+		Order("updated_at DESC").
+		Find(&p).Error
+	return
+}
+
+func (r *repository) ListContinueReading(ctx context.Context, userID string, page pagination.Pagination) (items []*progress.ContinueReading, total int64, err error) {
+	latest := r.db.Table("reading_progresses AS p").
+		Select("DISTINCT ON (p.article_id) p.article_id, a.title, a.article_link, p.device_id, p.percentage, p.paragraph_index, p.text_offset, p.updated_at").
+		Joins("JOIN articles AS a ON a.id = p.article_id").
+		Where("p.user_id = ? AND a.read_at IS NULL AND a.archived_at IS NULL AND a.deleted_at IS NULL", userID).
+		Order("p.article_id, p.updated_at DESC")
+
+	// finished articles are filtered after picking the latest position so an older device doesn't bring them back
+	err = r.db.Table("(?) AS latest", latest).Where("percentage < 100").Count(&total).Error
+	if err != nil {
+		return
+	}
+
+	err = r.db.Table("(?) AS latest", latest).
+		Where("percentage < 100").
+		Order("updated_at DESC").
+		Limit(page.Limit).Offset(page.Offset).
+		Scan(&items).Error
+	return
+}
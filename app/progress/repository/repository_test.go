@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ryanadiputraa/unclatter/app/pagination"
+	"github.com/ryanadiputraa/unclatter/app/progress"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/test"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+var testProgress = progress.NewReadingProgress(progress.NewReadingProgressArg{
+	UserID:     test.TestUser.ID,
+	ArticleID:  test.TestArticle.ID,
+	DeviceID:   "laptop",
+	Percentage: 35,
+	UpdatedAt:  time.Now(),
+})
+
+func TestUpsert(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	expectedExec := "^INSERT INTO reading_progresses (.+) WHERE EXISTS (.+) ON CONFLICT (.+) DO UPDATE SET (.+) WHERE reading_progresses.updated_at < excluded.updated_at"
+	args := []driver.Value{
+		testProgress.ID, testProgress.UserID, testProgress.ArticleID, testProgress.DeviceID, testProgress.Percentage,
+		testProgress.ParagraphIndex, testProgress.TextOffset, testProgress.UpdatedAt, testProgress.ArticleID, testProgress.UserID,
+	}
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		saved         bool
+		err           error
+	}{
+		{
+			name: "should save reading progress",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(expectedExec).
+					WithArgs(args...).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			saved: true,
+			err:   nil,
+		},
+		{
+			name: "should not save stale or unauthorized reading progress",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(expectedExec).
+					WithArgs(args...).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			saved: false,
+			err:   nil,
+		},
+		{
+			name: "should return err when fail to save reading progress",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(expectedExec).
+					WithArgs(args...).
+					WillReturnError(gorm.ErrInvalidDB)
+			},
+			saved: false,
+			err:   gorm.ErrInvalidDB,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+
+			saved, err := r.Upsert(context.Background(), *testProgress)
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.saved, saved)
+		})
+	}
+}
+
+func TestFindByDevice(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	expectedQuery := "^SELECT (.+) FROM \"reading_progresses\" WHERE user_id = (.+) AND article_id = (.+) AND device_id = "
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		expected      *progress.ReadingProgress
+		err           error
+	}{
+		{
+			name: "should return device reading progress",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(expectedQuery).
+					WithArgs(testProgress.UserID, testProgress.ArticleID, testProgress.DeviceID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "article_id", "device_id", "percentage", "updated_at"}).
+						AddRow(testProgress.ID, testProgress.UserID, testProgress.ArticleID, testProgress.DeviceID, testProgress.Percentage, testProgress.UpdatedAt))
+			},
+			expected: testProgress,
+			err:      nil,
+		},
+		{
+			name: "should return not found err when device has no progress",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(expectedQuery).
+					WithArgs(testProgress.UserID, testProgress.ArticleID, testProgress.DeviceID, 1).
+					WillReturnError(gorm.ErrRecordNotFound)
+			},
+			expected: nil,
+			err:      validation.NewError(validation.NotFound, "no reading progress found"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+
+			p, err := r.FindByDevice(context.Background(), testProgress.UserID, testProgress.ArticleID, testProgress.DeviceID)
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.expected.ID, p.ID)
+			assert.Equal(t, c.expected.Percentage, p.Percentage)
+			assert.Equal(t, c.expected.UpdatedAt, p.UpdatedAt)
+		})
+	}
+}
+
+func TestListContinueReading(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	expectedCountQuery := "^SELECT count(.+) FROM \\(SELECT DISTINCT ON \\(p.article_id\\) (.+)\\) AS latest WHERE percentage < 100"
+	expectedSelectQuery := "^SELECT (.+) FROM \\(SELECT DISTINCT ON \\(p.article_id\\) (.+)\\) AS latest WHERE percentage < 100 ORDER BY updated_at DESC"
+	page := pagination.Pagination{Limit: 10, Offset: 0}
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		total         int64
+		err           error
+	}{
+		{
+			name: "should return latest unfinished progress per article",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(expectedCountQuery).
+					WithArgs(test.TestUser.ID).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery(expectedSelectQuery).
+					WithArgs(test.TestUser.ID, page.Limit).
+					WillReturnRows(sqlmock.NewRows([]string{"article_id", "title", "article_link", "device_id", "percentage", "updated_at"}).
+						AddRow(test.TestArticle.ID, test.TestArticle.Title, test.TestArticle.ArticleLink, "laptop", 35.0, time.Now().UTC()))
+			},
+			total: 1,
+			err:   nil,
+		},
+		{
+			name: "should return err when fail to count unfinished articles",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(expectedCountQuery).
+					WithArgs(test.TestUser.ID).
+					WillReturnError(gorm.ErrInvalidDB)
+			},
+			total: 0,
+			err:   gorm.ErrInvalidDB,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+
+			items, total, err := r.ListContinueReading(context.Background(), test.TestUser.ID, page)
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.total, total)
+			if err != nil {
+				return
+			}
+			assert.Len(t, items, 1)
+			assert.Equal(t, test.TestArticle.ID, items[0].ArticleID)
+			assert.Equal(t, 35.0, items[0].Percentage)
+		})
+	}
+}
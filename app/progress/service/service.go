@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ryanadiputraa/unclatter/app/pagination"
+	"github.com/ryanadiputraa/unclatter/app/progress"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/pkg/logger"
+)
+
+type service struct {
+	log        logger.Logger
+	repository progress.ProgressRepository
+}
+
+func NewService(log logger.Logger, repository progress.ProgressRepository) progress.ProgressService {
+	return &service{
+		log:        log,
+		repository: repository,
+	}
+}
+
+func (s *service) RecordProgress(ctx context.Context, userID, articleID string, arg progress.ProgressPayload) (*progress.ReadingProgress, error) {
+	// a device with a clock running ahead would otherwise win every future write
+	updatedAt := time.Now().UTC()
+	if arg.UpdatedAt != "" {
+		reportedAt, err := time.Parse(time.RFC3339Nano, arg.UpdatedAt)
+		if err == nil && reportedAt.Before(updatedAt) {
+			updatedAt = reportedAt
+		}
+	}
+
+	p := progress.NewReadingProgress(progress.NewReadingProgressArg{
+		UserID:         userID,
+		ArticleID:      articleID,
+		DeviceID:       arg.DeviceID,
+		Percentage:     arg.Percentage,
+		ParagraphIndex: arg.ParagraphIndex,
+		TextOffset:     arg.TextOffset,
+		UpdatedAt:      updatedAt,
+	})
+
+	saved, err := s.repository.Upsert(ctx, *p)
+	if err != nil {
+		s.log.Error("progress service: fail to save reading progress", err)
+		return nil, err
+	}
+	if saved {
+		return p, nil
+	}
+
+	// nothing was written, either the device already has a newer position or the article isn't the user's
+	latest, err := s.repository.FindByDevice(ctx, userID, articleID, arg.DeviceID)
+	if err != nil {
+		if _, ok := err.(*validation.Error); ok {
+			return nil, validation.NewError(validation.NotFound, "no article found with given id")
+		}
+		s.log.Error("progress service: fail to fetch reading progress", err)
+		return nil, err
+	}
+	return latest, nil
+}
+
+func (s *service) GetArticleProgress(ctx context.Context, userID, articleID string) (p []*progress.ReadingProgress, err error) {
+	p, err = s.repository.FindByArticle(ctx, userID, articleID)
+	if err != nil {
+		s.log.Error("progress service: fail to fetch article reading progress", err)
+	}
+	return
+}
+
+func (s *service) ListContinueReading(ctx context.Context, userID string, page pagination.Pagination) (items []*progress.ContinueReading, meta *pagination.Meta, err error) {
+	items, total, err := s.repository.ListContinueReading(ctx, userID, page)
+	if err != nil {
+		s.log.Error("progress service: fail to fetch continue reading list", err)
+		return
+	}
+
+	meta = pagination.NewMeta(page, total)
+	return
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ryanadiputraa/unclatter/app/mocks"
+	"github.com/ryanadiputraa/unclatter/app/progress"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/pkg/logger"
+	"github.com/ryanadiputraa/unclatter/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestRecordProgress(t *testing.T) {
+	reportedAt := time.Now().UTC().Add(-time.Minute)
+	newer := &progress.ReadingProgress{
+		ArticleID:  test.TestArticle.ID,
+		DeviceID:   "phone",
+		Percentage: 80,
+		UpdatedAt:  time.Now().UTC(),
+	}
+
+	cases := []struct {
+		name              string
+		arg               progress.ProgressPayload
+		expected          float64
+		err               error
+		mockRepoBehaviour func(mockRepo *mocks.ProgressRepository)
+	}{
+		{
+			name: "should save reading progress with the reported time",
+			arg: progress.ProgressPayload{
+				DeviceID:   "phone",
+				Percentage: 40,
+				UpdatedAt:  reportedAt.Format(time.RFC3339Nano),
+			},
+			expected: 40,
+			err:      nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ProgressRepository) {
+				mockRepo.On("Upsert", context.Background(), mock.MatchedBy(func(p progress.ReadingProgress) bool {
+					return p.UpdatedAt.Equal(reportedAt) && p.UserID == test.TestUser.ID
+				})).Return(true, nil)
+			},
+		},
+		{
+			name: "should clamp reported time from the future",
+			arg: progress.ProgressPayload{
+				DeviceID:   "phone",
+				Percentage: 40,
+				UpdatedAt:  time.Now().Add(time.Hour).Format(time.RFC3339Nano),
+			},
+			expected: 40,
+			err:      nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ProgressRepository) {
+				mockRepo.On("Upsert", context.Background(), mock.MatchedBy(func(p progress.ReadingProgress) bool {
+					return !p.UpdatedAt.After(time.Now())
+				})).Return(true, nil)
+			},
+		},
+		{
+			name: "should return the newer stored progress when the write is stale",
+			arg: progress.ProgressPayload{
+				DeviceID:   "phone",
+				Percentage: 40,
+				UpdatedAt:  reportedAt.Format(time.RFC3339Nano),
+			},
+			expected: 80,
+			err:      nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ProgressRepository) {
+				mockRepo.On("Upsert", context.Background(), mock.Anything).Return(false, nil)
+				mockRepo.On("FindByDevice", context.Background(), test.TestUser.ID, test.TestArticle.ID, "phone").Return(newer, nil)
+			},
+		},
+		{
+			name: "should return not found err when article isn't the user's",
+			arg: progress.ProgressPayload{
+				DeviceID:   "phone",
+				Percentage: 40,
+			},
+			err: validation.NewError(validation.NotFound, "no article found with given id"),
+			mockRepoBehaviour: func(mockRepo *mocks.ProgressRepository) {
+				mockRepo.On("Upsert", context.Background(), mock.Anything).Return(false, nil)
+				mockRepo.On("FindByDevice", context.Background(), test.TestUser.ID, test.TestArticle.ID, "phone").
+					Return(nil, validation.NewError(validation.NotFound, "no reading progress found"))
+			},
+		},
+		{
+			name: "should return err when fail to save reading progress",
+			arg: progress.ProgressPayload{
+				DeviceID:   "phone",
+				Percentage: 40,
+			},
+			err: gorm.ErrInvalidDB,
+			mockRepoBehaviour: func(mockRepo *mocks.ProgressRepository) {
+				mockRepo.On("Upsert", context.Background(), mock.Anything).Return(false, gorm.ErrInvalidDB)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.ProgressRepository)
+			c.mockRepoBehaviour(r)
+
+			s := NewService(logger.NewLogger(), r)
+			p, err := s.RecordProgress(context.Background(), test.TestUser.ID, test.TestArticle.ID, c.arg)
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				assert.Nil(t, p)
+				return
+			}
+			assert.Equal(t, c.expected, p.Percentage)
+			r.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ryanadiputraa/unclatter/app/middleware"
+	"github.com/ryanadiputraa/unclatter/app/pagination"
+	"github.com/ryanadiputraa/unclatter/app/reminder"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	_http "github.com/ryanadiputraa/unclatter/pkg/http"
+	"github.com/ryanadiputraa/unclatter/pkg/validator"
+)
+
+type handler struct {
+	rw              _http.ResponseWriter
+	reminderService reminder.ReminderService
+	validator       validator.Validator
+}
+
+func NewHandler(web *http.ServeMux, rw _http.ResponseWriter, reminderService reminder.ReminderService, authMiddleware middleware.AuthMiddleware, validator validator.Validator) {
+	h := &handler{
+		rw:              rw,
+		reminderService: reminderService,
+		validator:       validator,
+	}
+
+	web.Handle("POST /api/articles/bookmarks/{id}/reminders", authMiddleware.ParseJWTToken(h.CreateReminder()))
+	web.Handle("GET /api/reminders", authMiddleware.ParseJWTToken(h.ListReminders()))
+	web.Handle("DELETE /api/reminders/{id}", authMiddleware.ParseJWTToken(h.DeleteReminder()))
+	web.Handle("GET /api/reminders/webhook", authMiddleware.ParseJWTToken(h.GetWebhook()))
+	web.Handle("PUT /api/reminders/webhook", authMiddleware.ParseJWTToken(h.SetWebhook()))
+	web.Handle("DELETE /api/reminders/webhook", authMiddleware.ParseJWTToken(h.DeleteWebhook()))
+}
+
+func (h *handler) CreateReminder() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		var payload reminder.ReminderPayload
+
+		json.NewDecoder(r.Body).Decode(&payload)
+		if err, errMap := h.validator.Validate(payload); err != nil {
+			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+			return
+		}
+
+		created, err := h.reminderService.CreateReminder(ac.Context, ac.UserID, r.PathValue("id"), payload)
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusCreated, created)
+	}
+}
+
+func (h *handler) ListReminders() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		query := r.URL.Query()
+
+		pagination, errMap, err := pagination.ValidateParam(query.Get("page"), query.Get("size"))
+		if err != nil {
+			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+			return
+		}
+
+		reminders, meta, err := h.reminderService.ListReminders(ac.Context, ac.UserID, *pagination)
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseDataWithPagination(w, http.StatusOK, reminders, *meta)
+	}
+}
+
+func (h *handler) DeleteReminder() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		if err := h.reminderService.DeleteReminder(ac.Context, ac.UserID, r.PathValue("id")); err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, nil)
+	}
+}
+
+func (h *handler) GetWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		webhook, err := h.reminderService.GetWebhook(ac.Context, ac.UserID)
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, webhook)
+	}
+}
+
+func (h *handler) SetWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		var payload reminder.WebhookPayload
+
+		json.NewDecoder(r.Body).Decode(&payload)
+		if err, errMap := h.validator.Validate(payload); err != nil {
+			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+			return
+		}
+
+		webhook, err := h.reminderService.SetWebhook(ac.Context, ac.UserID, payload)
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, webhook)
+	}
+}
+
+func (h *handler) DeleteWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		if err := h.reminderService.DeleteWebhook(ac.Context, ac.UserID); err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, nil)
+	}
+}
+
+func (h *handler) writeErr(w http.ResponseWriter, err error) {
+	if vErr, ok := err.(*validation.Error); ok {
+		h.rw.WriteErrMessage(w, validation.HttpErrMap[vErr.Err], vErr.Message)
+		return
+	}
+	h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+}
@@ -0,0 +1,209 @@
+package reminder
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/digest"
+	"github.com/ryanadiputraa/unclatter/app/pagination"
+)
+
+const (
+	// MaxPending caps the reminders a user has waiting to be sent.
+	MaxPending = 100
+	// MaxAttempts is how many times sending a reminder is tried before it's marked as failed.
+	MaxAttempts = 3
+	// retryDelay is how long a reminder waits after its first failed attempt, the delay doubles after every attempt.
+	retryDelay = 5 * time.Minute
+
+	// EventReminder is the webhook event of a due reminder.
+	EventReminder = "article.reminder"
+)
+
+type Channel string
+
+const (
+	ChannelEmail   Channel = "email"
+	ChannelWebhook Channel = "webhook"
+)
+
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusSent    Status = "sent"
+	StatusFailed  Status = "failed"
+)
+
+// Reminder brings an article back to the user's attention at the given time through email or their webhook.
+type Reminder struct {
+	ID        string    `json:"id" gorm:"type:varchar"`
+	UserID    string    `json:"-" gorm:"type:varchar;not null;index"`
+	ArticleID string    `json:"article_id" gorm:"type:varchar;not null;index"`
+	Channel   Channel   `json:"channel" gorm:"type:varchar;not null"`
+	RemindAt  time.Time `json:"remind_at" gorm:"type:timestamptz;not null"`
+	Status    Status    `json:"status" gorm:"type:varchar;not null;default:'pending'"`
+	// NextAttemptAt is when a pending reminder is sent, it's pushed back after a failed attempt.
+	NextAttemptAt *time.Time `json:"-" gorm:"type:timestamptz;index:idx_reminders_due,where:status = 'pending'"`
+	Attempts      int        `json:"attempts" gorm:"type:smallint;not null;default:0"`
+	// Error is why the last attempt failed, smtp and http errors are kept out of it.
+	Error     string     `json:"error,omitempty" gorm:"type:varchar;not null;default:''"`
+	SentAt    *time.Time `json:"sent_at" gorm:"type:timestamptz"`
+	CreatedAt time.Time  `json:"created_at" gorm:"type:timestamptz;not null"`
+
+	Article *article.Article `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+func (Reminder) TableName() string {
+	return "reminders"
+}
+
+type ReminderPayload struct {
+	RemindAt string  `json:"remind_at" validate:"required,iso8601date"`
+	Channel  Channel `json:"channel" validate:"required,oneof=email webhook"`
+}
+
+func NewReminder(userID, articleID string, channel Channel, remindAt time.Time) *Reminder {
+	remindAt = remindAt.UTC()
+	return &Reminder{
+		ID:            uuid.NewString(),
+		UserID:        userID,
+		ArticleID:     articleID,
+		Channel:       channel,
+		RemindAt:      remindAt,
+		Status:        StatusPending,
+		NextAttemptAt: &remindAt,
+		CreatedAt:     time.Now().UTC(),
+	}
+}
+
+// Sent marks the reminder as sent.
+func (r *Reminder) Sent(now time.Time) {
+	sentAt := now.UTC()
+	r.Attempts++
+	r.Status = StatusSent
+	r.SentAt = &sentAt
+	r.NextAttemptAt = nil
+	r.Error = ""
+}
+
+// Fail records a failed attempt, the reminder is tried again later unless the failure is permanent or it ran out
+// of attempts.
+func (r *Reminder) Fail(reason string, now time.Time, permanent bool) {
+	r.Attempts++
+	r.Error = reason
+	if permanent || r.Attempts >= MaxAttempts {
+		r.Status = StatusFailed
+		r.NextAttemptAt = nil
+		return
+	}
+	next := now.UTC().Add(retryDelay << (r.Attempts - 1))
+	r.NextAttemptAt = &next
+}
+
+// Webhook is where the user's webhook reminders are posted, every request is signed with its secret.
+type Webhook struct {
+	UserID string `json:"-" gorm:"type:varchar;primaryKey"`
+	URL    string `json:"url" gorm:"type:varchar;not null"`
+	// Secret is shown to the user so they can verify signatures, it's kept when the url changes.
+	Secret    string    `json:"secret" gorm:"type:varchar;not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"type:timestamptz;not null"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"type:timestamptz;not null"`
+}
+
+func (Webhook) TableName() string {
+	return "reminder_webhooks"
+}
+
+type WebhookPayload struct {
+	URL string `json:"url" validate:"required,http_url,max=2048"`
+}
+
+func NewWebhook(userID, url string) (*Webhook, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	return &Webhook{
+		UserID:    userID,
+		URL:       url,
+		Secret:    base64.RawURLEncoding.EncodeToString(b),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// Event is the json body posted to the webhook when a reminder is due.
+type Event struct {
+	Event      string       `json:"event"`
+	ReminderID string       `json:"reminder_id"`
+	RemindAt   time.Time    `json:"remind_at"`
+	Article    EventArticle `json:"article"`
+}
+
+type EventArticle struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	ArticleLink string `json:"article_link"`
+	Domain      string `json:"domain"`
+	ReadingTime int    `json:"reading_time"`
+	Excerpt     string `json:"excerpt"`
+}
+
+func NewEvent(r *Reminder, a *article.Article) Event {
+	return Event{
+		Event:      EventReminder,
+		ReminderID: r.ID,
+		RemindAt:   r.RemindAt,
+		Article: EventArticle{
+			ID:          a.ID,
+			Title:       a.Title,
+			ArticleLink: a.ArticleLink,
+			Domain:      a.Domain,
+			ReadingTime: a.ReadingTime,
+			Excerpt:     excerpt(a),
+		},
+	}
+}
+
+// excerpt prefers the stored summary and falls back to the beginning of the content.
+func excerpt(a *article.Article) string {
+	if a.Excerpt != "" {
+		return a.Excerpt
+	}
+	return digest.Excerpt(a.Content)
+}
+
+type ReminderService interface {
+	// CreateReminder schedules a reminder for the user's article, webhook reminders need the user's webhook set.
+	CreateReminder(ctx context.Context, userID, articleID string, arg ReminderPayload) (*Reminder, error)
+	ListReminders(ctx context.Context, userID string, page pagination.Pagination) ([]*Reminder, *pagination.Meta, error)
+	DeleteReminder(ctx context.Context, userID, reminderID string) error
+	GetWebhook(ctx context.Context, userID string) (*Webhook, error)
+	// SetWebhook saves the url reminders are posted to, a secret is generated the first time it's set.
+	SetWebhook(ctx context.Context, userID string, arg WebhookPayload) (*Webhook, error)
+	DeleteWebhook(ctx context.Context, userID string) error
+	// SendDueReminders sends up to limit reminders that are due and returns how many were sent.
+	SendDueReminders(ctx context.Context, now time.Time, limit int) (int, error)
+}
+
+type ReminderRepository interface {
+	Save(ctx context.Context, arg Reminder) error
+	// List returns the user's reminders, the next ones first and the already sent or failed ones after them.
+	List(ctx context.Context, userID string, page pagination.Pagination) (reminders []*Reminder, total int64, err error)
+	CountPending(ctx context.Context, userID string) (int64, error)
+	Delete(ctx context.Context, userID, reminderID string) error
+	// ListDue returns the pending reminders whose next attempt is due, the most overdue first.
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*Reminder, error)
+	// UpdateAttempt saves the outcome of sending the reminder.
+	UpdateAttempt(ctx context.Context, arg Reminder) error
+	FindWebhook(ctx context.Context, userID string) (*Webhook, error)
+	// SaveWebhook creates the webhook or updates its url, the secret of an existing webhook is kept.
+	SaveWebhook(ctx context.Context, arg Webhook) (*Webhook, error)
+	DeleteWebhook(ctx context.Context, userID string) error
+}
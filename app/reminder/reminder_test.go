@@ -0,0 +1,137 @@
+package reminder
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewReminder(t *testing.T) {
+	remindAt := time.Date(2024, 3, 4, 8, 0, 0, 0, time.FixedZone("WIB", 7*60*60))
+
+	r := NewReminder(uuid.NewString(), uuid.NewString(), ChannelEmail, remindAt)
+	assert.Equal(t, StatusPending, r.Status)
+	assert.Equal(t, time.UTC, r.RemindAt.Location())
+	assert.True(t, r.RemindAt.Equal(remindAt))
+	assert.Equal(t, r.RemindAt, *r.NextAttemptAt)
+}
+
+func TestFail(t *testing.T) {
+	now := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name      string
+		attempts  int
+		permanent bool
+		status    Status
+		next      *time.Time
+	}{
+		{
+			name:     "should retry after the first failed attempt",
+			attempts: 0,
+			status:   StatusPending,
+			next:     test.Ptr(now.Add(5 * time.Minute)),
+		},
+		{
+			name:     "should double the delay after every failed attempt",
+			attempts: 1,
+			status:   StatusPending,
+			next:     test.Ptr(now.Add(10 * time.Minute)),
+		},
+		{
+			name:     "should fail when out of attempts",
+			attempts: MaxAttempts - 1,
+			status:   StatusFailed,
+			next:     nil,
+		},
+		{
+			name:      "should fail right away when failure is permanent",
+			attempts:  0,
+			permanent: true,
+			status:    StatusFailed,
+			next:      nil,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := NewReminder(uuid.NewString(), uuid.NewString(), ChannelWebhook, now)
+			r.Attempts = c.attempts
+
+			r.Fail("reason", now, c.permanent)
+			assert.Equal(t, c.attempts+1, r.Attempts)
+			assert.Equal(t, c.status, r.Status)
+			assert.Equal(t, c.next, r.NextAttemptAt)
+			assert.Equal(t, "reason", r.Error)
+		})
+	}
+}
+
+func TestSent(t *testing.T) {
+	now := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+	r := NewReminder(uuid.NewString(), uuid.NewString(), ChannelEmail, now)
+	r.Fail("reason", now, false)
+
+	r.Sent(now.Add(5 * time.Minute))
+	assert.Equal(t, StatusSent, r.Status)
+	assert.Equal(t, 2, r.Attempts)
+	assert.Equal(t, now.Add(5*time.Minute), *r.SentAt)
+	assert.Nil(t, r.NextAttemptAt)
+	assert.Empty(t, r.Error)
+}
+
+func TestNewWebhook(t *testing.T) {
+	userID := uuid.NewString()
+
+	w, err := NewWebhook(userID, "https://example.com/hook")
+	assert.Nil(t, err)
+	assert.Equal(t, userID, w.UserID)
+	assert.Len(t, w.Secret, 43)
+
+	other, _ := NewWebhook(userID, "https://example.com/hook")
+	assert.NotEqual(t, w.Secret, other.Secret)
+}
+
+func TestNewEvent(t *testing.T) {
+	r := NewReminder(uuid.NewString(), uuid.NewString(), ChannelWebhook, time.Now())
+	a := &article.Article{
+		ID:          r.ArticleID,
+		Title:       "Title",
+		Content:     "<p>Postgres indexes explained</p>",
+		ArticleLink: "https://example.com/postgres",
+		Domain:      "example.com",
+		ReadingTime: 4,
+	}
+
+	e := NewEvent(r, a)
+	assert.Equal(t, EventReminder, e.Event)
+	assert.Equal(t, r.ID, e.ReminderID)
+	assert.Equal(t, a.ArticleLink, e.Article.ArticleLink)
+	assert.Equal(t, "Postgres indexes explained", e.Article.Excerpt)
+
+	a.Excerpt = "Stored summary"
+	e = NewEvent(r, a)
+	assert.Equal(t, "Stored summary", e.Article.Excerpt)
+}
+
+func TestRender(t *testing.T) {
+	m := Message{
+		Title:       "<b>Title</b>",
+		Link:        "https://example.com/postgres",
+		Domain:      "example.com",
+		ReadingTime: 4,
+		AppURL:      "https://unclatter.com",
+	}
+
+	text, html, err := Render(m)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(text, "example.com · 4 min read"))
+	assert.True(t, strings.Contains(text, m.Link))
+	assert.True(t, strings.Contains(html, "&lt;b&gt;Title&lt;/b&gt;"))
+	assert.Equal(t, "Reminder: <b>Title</b>", Subject(m))
+}
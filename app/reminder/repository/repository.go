@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ryanadiputraa/unclatter/app/pagination"
+	"github.com/ryanadiputraa/unclatter/app/reminder"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) reminder.ReminderRepository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Save(ctx context.Context, arg reminder.Reminder) error {
+	return r.db.Create(&arg).Error
+}
+
+func (r *repository) List(ctx context.Context, userID string, page pagination.Pagination) (reminders []*reminder.Reminder, total int64, err error) {
+	err = r.db.Model(&reminder.Reminder{}).Where("user_id = ?", userID).Count(&total).Error
+	if err != nil {
+		return
+	}
+
+	err = r.db.Where("user_id = ?", userID).
+		Order("status = 'pending' DESC, remind_at, id").
+		Limit(page.Limit).
+		Offset(page.Offset).
+		Find(&reminders).Error
+	return
+}
+
+func (r *repository) CountPending(ctx context.Context, userID string) (count int64, err error) {
+	err = r.db.Model(&reminder.Reminder{}).
+		Where("user_id = ? AND status = ?", userID, reminder.StatusPending).
+		Count(&count).Error
+	return
+}
+
+func (r *repository) Delete(ctx context.Context, userID, reminderID string) error {
+	res := r.db.Where("id = ? AND user_id = ?", reminderID, userID).Delete(&reminder.Reminder{})
+	if res.RowsAffected == 0 && res.Error == nil {
+		return validation.NewError(validation.NotFound, "no reminder found with given id")
+	}
+	return res.Error
+}
+
+func (r *repository) ListDue(ctx context.Context, now time.Time, limit int) (reminders []*reminder.Reminder, err error) {
+	err = r.db.Where("status = ? AND next_attempt_at <= ?", reminder.StatusPending, now).
+		Order("next_attempt_at").
+		Limit(limit).
+		Find(&reminders).Error
+	return
+}
+
+func (r *repository) UpdateAttempt(ctx context.Context, arg reminder.Reminder) error {
+	// the reminder may have been deleted while it was being sent, there's nothing left to update then
+	return r.db.Model(&reminder.Reminder{}).
+		Where("id = ? AND status = ?", arg.ID, reminder.StatusPending).
+		UpdateColumns(map[string]any{
+			"status":          arg.Status,
+			"next_attempt_at": arg.NextAttemptAt,
+			"attempts":        arg.Attempts,
+			"error":           arg.Error,
+			"sent_at":         arg.SentAt,
+		}).Error
+}
+
+func (r *repository) FindWebhook(ctx context.Context, userID string) (webhook *reminder.Webhook, err error) {
+	err = r.db.First(&webhook, "user_id = ?", userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, validation.NewError(validation.NotFound, "no webhook found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (r *repository) SaveWebhook(ctx context.Context, arg reminder.Webhook) (webhook *reminder.Webhook, err error) {
+	err = r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"url", "updated_at"}),
+	}, clause.Returning{}).Create(&arg).Error
+	if err != nil {
+		return nil, err
+	}
+	return &arg, nil
+}
+
+func (r *repository) DeleteWebhook(ctx context.Context, userID string) error {
+	res := r.db.Where("user_id = ?", userID).Delete(&reminder.Webhook{})
+	if res.RowsAffected == 0 && res.Error == nil {
+		return validation.NewError(validation.NotFound, "no webhook found")
+	}
+	return res.Error
+}
@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/ryanadiputraa/unclatter/app/pagination"
+	"github.com/ryanadiputraa/unclatter/app/reminder"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/test"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestSave(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	arg := reminder.NewReminder(test.TestUser.ID, test.TestArticle.ID, reminder.ChannelEmail, time.Now().Add(time.Hour))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("^INSERT INTO \"reminders\"").
+		WithArgs(arg.ID, arg.UserID, arg.ArticleID, arg.Channel, arg.RemindAt, arg.Status, arg.NextAttemptAt,
+			arg.Attempts, arg.Error, arg.SentAt, arg.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := r.Save(context.Background(), *arg)
+	assert.Nil(t, err)
+}
+
+func TestList(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	page := pagination.Pagination{Limit: 10, Offset: 0}
+	countQuery := "^SELECT count(.*) FROM \"reminders\" WHERE user_id = "
+	listQuery := "^SELECT \\* FROM \"reminders\" WHERE user_id = (.+) ORDER BY status = 'pending' DESC, remind_at, id LIMIT (.+)$"
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		expected      []*reminder.Reminder
+		total         int64
+		err           error
+	}{
+		{
+			name: "should return user's reminders",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(countQuery).
+					WithArgs(test.TestUser.ID).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery(listQuery).
+					WithArgs(test.TestUser.ID, page.Limit).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "article_id", "status"}).
+						AddRow("reminder", test.TestArticle.ID, reminder.StatusPending))
+			},
+			expected: []*reminder.Reminder{{ID: "reminder", ArticleID: test.TestArticle.ID, Status: reminder.StatusPending}},
+			total:    1,
+			err:      nil,
+		},
+		{
+			name: "should return err when fail to count reminders",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(countQuery).
+					WithArgs(test.TestUser.ID).
+					WillReturnError(gorm.ErrInvalidDB)
+			},
+			expected: nil,
+			total:    0,
+			err:      gorm.ErrInvalidDB,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+
+			reminders, total, err := r.List(context.Background(), test.TestUser.ID, page)
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.expected, reminders)
+			assert.Equal(t, c.total, total)
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	reminderID := uuid.NewString()
+	expectedQuery := "^DELETE FROM \"reminders\" WHERE id = (.+) AND user_id = (.+)$"
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		err           error
+	}{
+		{
+			name: "should delete user's reminder",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(expectedQuery).
+					WithArgs(reminderID, test.TestUser.ID).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			err: nil,
+		},
+		{
+			name: "should return not found err when reminder isn't the user's",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(expectedQuery).
+					WithArgs(reminderID, test.TestUser.ID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			err: validation.NewError(validation.NotFound, "no reminder found with given id"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+
+			err := r.Delete(context.Background(), test.TestUser.ID, reminderID)
+			assert.Equal(t, c.err, err)
+		})
+	}
+}
+
+func TestListDue(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	now := time.Now().UTC()
+
+	mock.ExpectQuery("^SELECT \\* FROM \"reminders\" WHERE status = (.+) AND next_attempt_at <= (.+) ORDER BY next_attempt_at LIMIT (.+)").
+		WithArgs(reminder.StatusPending, now, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "channel"}).AddRow("reminder", reminder.ChannelWebhook))
+
+	reminders, err := r.ListDue(context.Background(), now, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []*reminder.Reminder{{ID: "reminder", Channel: reminder.ChannelWebhook}}, reminders)
+}
+
+func TestUpdateAttempt(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	now := time.Now().UTC()
+	arg := reminder.NewReminder(test.TestUser.ID, test.TestArticle.ID, reminder.ChannelEmail, now)
+	arg.Fail("fail to send reminder", now, false)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("^UPDATE \"reminders\" SET \"attempts\"=\\$1,\"error\"=\\$2,\"next_attempt_at\"=\\$3,\"sent_at\"=\\$4,\"status\"=\\$5 WHERE id = \\$6 AND status = \\$7").
+		WithArgs(arg.Attempts, arg.Error, arg.NextAttemptAt, nil, reminder.StatusPending, arg.ID, reminder.StatusPending).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := r.UpdateAttempt(context.Background(), *arg)
+	assert.Nil(t, err)
+}
+
+func TestFindWebhook(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	expectedQuery := "^SELECT \\* FROM \"reminder_webhooks\" WHERE user_id = (.+) ORDER BY (.+) LIMIT (.+)$"
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		err           error
+	}{
+		{
+			name: "should return user's webhook",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(expectedQuery).
+					WithArgs(test.TestUser.ID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id", "url", "secret"}).
+						AddRow(test.TestUser.ID, "https://example.com/hook", "secret"))
+			},
+			err: nil,
+		},
+		{
+			name: "should return not found err when user has no webhook",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(expectedQuery).
+					WithArgs(test.TestUser.ID, 1).
+					WillReturnError(gorm.ErrRecordNotFound)
+			},
+			err: validation.NewError(validation.NotFound, "no webhook found"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+
+			w, err := r.FindWebhook(context.Background(), test.TestUser.ID)
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				assert.Nil(t, w)
+				return
+			}
+			assert.Equal(t, "https://example.com/hook", w.URL)
+		})
+	}
+}
+
+func TestSaveWebhook(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	arg, _ := reminder.NewWebhook(test.TestUser.ID, "https://example.com/hook")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("^INSERT INTO \"reminder_webhooks\" (.+) ON CONFLICT \\(\"user_id\"\\) DO UPDATE SET \"url\"=\"excluded\".\"url\",\"updated_at\"=\"excluded\".\"updated_at\" RETURNING \\*").
+		WithArgs(arg.UserID, arg.URL, arg.Secret, arg.CreatedAt, arg.UpdatedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "url", "secret"}).AddRow(arg.UserID, arg.URL, "stored secret"))
+	mock.ExpectCommit()
+
+	w, err := r.SaveWebhook(context.Background(), *arg)
+	assert.Nil(t, err)
+	assert.Equal(t, "stored secret", w.Secret)
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/pagination"
+	"github.com/ryanadiputraa/unclatter/app/reminder"
+	"github.com/ryanadiputraa/unclatter/app/user"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/pkg/logger"
+	"github.com/ryanadiputraa/unclatter/pkg/mailer"
+	"github.com/ryanadiputraa/unclatter/pkg/webhook"
+)
+
+type service struct {
+	log               logger.Logger
+	mailer            mailer.Mailer
+	webhook           webhook.Sender
+	repository        reminder.ReminderRepository
+	articleRepository article.ArticleRepository
+	userRepository    user.UserRepository
+	// appURL is the frontend reminder emails link to.
+	appURL string
+}
+
+func NewService(log logger.Logger, mailer mailer.Mailer, webhook webhook.Sender, repository reminder.ReminderRepository, articleRepository article.ArticleRepository, userRepository user.UserRepository, appURL string) reminder.ReminderService {
+	return &service{
+		log:               log,
+		mailer:            mailer,
+		webhook:           webhook,
+		repository:        repository,
+		articleRepository: articleRepository,
+		userRepository:    userRepository,
+		appURL:            appURL,
+	}
+}
+
+func (s *service) CreateReminder(ctx context.Context, userID, articleID string, arg reminder.ReminderPayload) (*reminder.Reminder, error) {
+	remindAt, err := time.Parse(time.RFC3339Nano, arg.RemindAt)
+	if err != nil || !remindAt.After(time.Now()) {
+		return nil, validation.NewError(validation.BadRequest, "remind_at should be in the future")
+	}
+
+	a, err := s.articleRepository.FindByID(ctx, articleID)
+	if err != nil {
+		s.log.Warn("reminder service: fail to fetch article ", articleID, " ", err)
+		return nil, err
+	}
+	if a.UserID != userID {
+		return nil, validation.NewError(validation.Forbidden, "forbidden access")
+	}
+
+	if arg.Channel == reminder.ChannelWebhook {
+		if _, err = s.repository.FindWebhook(ctx, userID); err != nil {
+			if vErr, ok := err.(*validation.Error); ok && vErr.Err == validation.NotFound {
+				return nil, validation.NewError(validation.BadRequest, "set a webhook before adding webhook reminders")
+			}
+			s.log.Error("reminder service: fail to fetch webhook", err)
+			return nil, err
+		}
+	}
+
+	pending, err := s.repository.CountPending(ctx, userID)
+	if err != nil {
+		s.log.Error("reminder service: fail to count pending reminders", err)
+		return nil, err
+	}
+	if pending >= reminder.MaxPending {
+		return nil, validation.NewError(validation.BadRequest, "too many pending reminders, delete some before adding more")
+	}
+
+	created := reminder.NewReminder(userID, articleID, arg.Channel, remindAt)
+	if err = s.repository.Save(ctx, *created); err != nil {
+		s.log.Error("reminder service: fail to save reminder", err)
+		return nil, err
+	}
+	return created, nil
+}
+
+func (s *service) ListReminders(ctx context.Context, userID string, page pagination.Pagination) (reminders []*reminder.Reminder, meta *pagination.Meta, err error) {
+	reminders, total, err := s.repository.List(ctx, userID, page)
+	if err != nil {
+		s.log.Error("reminder service: fail to fetch user's reminders", err)
+		return
+	}
+
+	meta = pagination.NewMeta(page, total)
+	return
+}
+
+func (s *service) DeleteReminder(ctx context.Context, userID, reminderID string) error {
+	if err := s.repository.Delete(ctx, userID, reminderID); err != nil {
+		s.log.Warn("reminder service: fail to delete reminder ", reminderID, " ", err)
+		return err
+	}
+	return nil
+}
+
+func (s *service) GetWebhook(ctx context.Context, userID string) (*reminder.Webhook, error) {
+	webhook, err := s.repository.FindWebhook(ctx, userID)
+	if err != nil {
+		if _, ok := err.(*validation.Error); !ok {
+			s.log.Error("reminder service: fail to fetch webhook", err)
+		}
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (s *service) SetWebhook(ctx context.Context, userID string, arg reminder.WebhookPayload) (*reminder.Webhook, error) {
+	if err := s.webhook.CheckURL(ctx, arg.URL); err != nil {
+		return nil, validation.NewError(validation.BadRequest, "webhook url should resolve to a public address")
+	}
+
+	webhook, err := reminder.NewWebhook(userID, arg.URL)
+	if err != nil {
+		s.log.Error("reminder service: fail to generate webhook secret", err)
+		return nil, err
+	}
+
+	saved, err := s.repository.SaveWebhook(ctx, *webhook)
+	if err != nil {
+		s.log.Error("reminder service: fail to save webhook", err)
+		return nil, err
+	}
+	return saved, nil
+}
+
+func (s *service) DeleteWebhook(ctx context.Context, userID string) error {
+	if err := s.repository.DeleteWebhook(ctx, userID); err != nil {
+		if _, ok := err.(*validation.Error); !ok {
+			s.log.Error("reminder service: fail to delete webhook", err)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *service) SendDueReminders(ctx context.Context, now time.Time, limit int) (int, error) {
+	reminders, err := s.repository.ListDue(ctx, now, limit)
+	if err != nil {
+		s.log.Error("reminder service: fail to fetch due reminders", err)
+		return 0, err
+	}
+
+	sent := 0
+	for _, r := range reminders {
+		if ctx.Err() != nil {
+			return sent, ctx.Err()
+		}
+
+		if err := s.sendReminder(ctx, r); err != nil {
+			s.log.Warn("reminder service: fail to send reminder ", r.ID, " ", err)
+			reason, permanent := failure(err)
+			r.Fail(reason, now, permanent)
+		} else {
+			r.Sent(now)
+			sent++
+		}
+		if err = s.repository.UpdateAttempt(ctx, *r); err != nil {
+			s.log.Error("reminder service: fail to save reminder attempt", err)
+			return sent, err
+		}
+	}
+	return sent, nil
+}
+
+// sendReminder sends the reminder through its channel, the article is fetched again since it may have been
+// trashed after the reminder was set.
+func (s *service) sendReminder(ctx context.Context, r *reminder.Reminder) error {
+	a, err := s.articleRepository.FindByID(ctx, r.ArticleID)
+	if err != nil {
+		return err
+	}
+
+	switch r.Channel {
+	case reminder.ChannelWebhook:
+		w, err := s.repository.FindWebhook(ctx, r.UserID)
+		if err != nil {
+			return err
+		}
+		return s.webhook.Send(ctx, webhook.Request{
+			URL:     w.URL,
+			Secret:  w.Secret,
+			Event:   reminder.EventReminder,
+			Payload: reminder.NewEvent(r, a),
+		})
+	default:
+		u, err := s.userRepository.FindByID(ctx, r.UserID)
+		if err != nil {
+			return err
+		}
+		msg := reminder.NewMessage(a, s.appURL)
+		text, html, err := reminder.Render(msg)
+		if err != nil {
+			return err
+		}
+		return s.mailer.Send(ctx, mailer.Message{
+			To:      u.Email,
+			Subject: reminder.Subject(msg),
+			Text:    text,
+			HTML:    html,
+		})
+	}
+}
+
+// failure describes why sending failed without leaking smtp or http details, missing articles and webhooks won't
+// come back by retrying.
+func failure(err error) (reason string, permanent bool) {
+	if vErr, ok := err.(*validation.Error); ok && vErr.Err == validation.NotFound {
+		return vErr.Message, true
+	}
+	return "fail to send reminder", false
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ryanadiputraa/unclatter/app/mocks"
+	"github.com/ryanadiputraa/unclatter/app/reminder"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/pkg/logger"
+	"github.com/ryanadiputraa/unclatter/pkg/mailer"
+	"github.com/ryanadiputraa/unclatter/pkg/webhook"
+	"github.com/ryanadiputraa/unclatter/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const appURL = "https://unclatter.com"
+
+func TestCreateReminder(t *testing.T) {
+	remindAt := time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339Nano)
+
+	cases := []struct {
+		name                 string
+		userID               string
+		arg                  reminder.ReminderPayload
+		err                  error
+		mockArticleBehaviour func(mockRepo *mocks.ArticleRepository)
+		mockRepoBehaviour    func(mockRepo *mocks.ReminderRepository)
+	}{
+		{
+			name:   "should create email reminder",
+			userID: test.TestArticle.UserID,
+			arg:    reminder.ReminderPayload{RemindAt: remindAt, Channel: reminder.ChannelEmail},
+			err:    nil,
+			mockArticleBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+			},
+			mockRepoBehaviour: func(mockRepo *mocks.ReminderRepository) {
+				mockRepo.On("CountPending", context.Background(), test.TestArticle.UserID).Return(int64(3), nil)
+				mockRepo.On("Save", context.Background(), mock.MatchedBy(func(r reminder.Reminder) bool {
+					return r.ArticleID == test.TestArticle.ID && r.Channel == reminder.ChannelEmail && r.Status == reminder.StatusPending
+				})).Return(nil)
+			},
+		},
+		{
+			name:                 "should return err when remind at is in the past",
+			userID:               test.TestArticle.UserID,
+			arg:                  reminder.ReminderPayload{RemindAt: time.Now().Add(-time.Hour).Format(time.RFC3339Nano), Channel: reminder.ChannelEmail},
+			err:                  validation.NewError(validation.BadRequest, "remind_at should be in the future"),
+			mockArticleBehaviour: func(mockRepo *mocks.ArticleRepository) {},
+			mockRepoBehaviour:    func(mockRepo *mocks.ReminderRepository) {},
+		},
+		{
+			name:   "should return err when article is another user's",
+			userID: uuid.NewString(),
+			arg:    reminder.ReminderPayload{RemindAt: remindAt, Channel: reminder.ChannelEmail},
+			err:    validation.NewError(validation.Forbidden, "forbidden access"),
+			mockArticleBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+			},
+			mockRepoBehaviour: func(mockRepo *mocks.ReminderRepository) {},
+		},
+		{
+			name:   "should return err when webhook isn't set",
+			userID: test.TestArticle.UserID,
+			arg:    reminder.ReminderPayload{RemindAt: remindAt, Channel: reminder.ChannelWebhook},
+			err:    validation.NewError(validation.BadRequest, "set a webhook before adding webhook reminders"),
+			mockArticleBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+			},
+			mockRepoBehaviour: func(mockRepo *mocks.ReminderRepository) {
+				mockRepo.On("FindWebhook", context.Background(), test.TestArticle.UserID).
+					Return(nil, validation.NewError(validation.NotFound, "no webhook found"))
+			},
+		},
+		{
+			name:   "should return err when user has too many pending reminders",
+			userID: test.TestArticle.UserID,
+			arg:    reminder.ReminderPayload{RemindAt: remindAt, Channel: reminder.ChannelEmail},
+			err:    validation.NewError(validation.BadRequest, "too many pending reminders, delete some before adding more"),
+			mockArticleBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+			},
+			mockRepoBehaviour: func(mockRepo *mocks.ReminderRepository) {
+				mockRepo.On("CountPending", context.Background(), test.TestArticle.UserID).Return(int64(reminder.MaxPending), nil)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.ReminderRepository)
+			c.mockRepoBehaviour(r)
+			articleRepo := new(mocks.ArticleRepository)
+			c.mockArticleBehaviour(articleRepo)
+
+			s := NewService(logger.NewLogger(), new(mocks.Mailer), new(mocks.WebhookSender), r, articleRepo, new(mocks.UserRepository), appURL)
+			created, err := s.CreateReminder(context.Background(), c.userID, test.TestArticle.ID, c.arg)
+			assert.Equal(t, c.err, err)
+			r.AssertExpectations(t)
+			articleRepo.AssertExpectations(t)
+			if err != nil {
+				assert.Nil(t, created)
+				return
+			}
+			assert.Equal(t, c.arg.Channel, created.Channel)
+		})
+	}
+}
+
+func TestSetWebhook(t *testing.T) {
+	cases := []struct {
+		name                 string
+		url                  string
+		err                  error
+		mockRepoBehaviour    func(mockRepo *mocks.ReminderRepository)
+		mockWebhookBehaviour func(mockWebhook *mocks.WebhookSender)
+	}{
+		{
+			name: "should save webhook",
+			url:  "https://example.com/hook",
+			err:  nil,
+			mockRepoBehaviour: func(mockRepo *mocks.ReminderRepository) {
+				mockRepo.On("SaveWebhook", context.Background(), mock.MatchedBy(func(w reminder.Webhook) bool {
+					return w.UserID == test.TestUser.ID && w.URL == "https://example.com/hook" && w.Secret != ""
+				})).Return(&reminder.Webhook{UserID: test.TestUser.ID, URL: "https://example.com/hook", Secret: "secret"}, nil)
+			},
+			mockWebhookBehaviour: func(mockWebhook *mocks.WebhookSender) {
+				mockWebhook.On("CheckURL", context.Background(), "https://example.com/hook").Return(nil)
+			},
+		},
+		{
+			name:              "should return err when webhook points to an internal address",
+			url:               "http://169.254.169.254/latest/meta-data",
+			err:               validation.NewError(validation.BadRequest, "webhook url should resolve to a public address"),
+			mockRepoBehaviour: func(mockRepo *mocks.ReminderRepository) {},
+			mockWebhookBehaviour: func(mockWebhook *mocks.WebhookSender) {
+				mockWebhook.On("CheckURL", context.Background(), "http://169.254.169.254/latest/meta-data").
+					Return(errors.New("address isn't publicly routable: 169.254.169.254"))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.ReminderRepository)
+			c.mockRepoBehaviour(r)
+			hook := new(mocks.WebhookSender)
+			c.mockWebhookBehaviour(hook)
+
+			s := NewService(logger.NewLogger(), new(mocks.Mailer), hook, r, new(mocks.ArticleRepository), new(mocks.UserRepository), appURL)
+			saved, err := s.SetWebhook(context.Background(), test.TestUser.ID, reminder.WebhookPayload{URL: c.url})
+			assert.Equal(t, c.err, err)
+			r.AssertExpectations(t)
+			hook.AssertExpectations(t)
+			if err != nil {
+				assert.Nil(t, saved)
+				return
+			}
+			assert.Equal(t, c.url, saved.URL)
+		})
+	}
+}
+
+func TestSendDueReminders(t *testing.T) {
+	now := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+	hook := &reminder.Webhook{UserID: test.TestUser.ID, URL: "https://example.com/hook", Secret: "secret"}
+
+	cases := []struct {
+		name                 string
+		channel              reminder.Channel
+		sent                 int
+		status               reminder.Status
+		mockArticleBehaviour func(mockRepo *mocks.ArticleRepository)
+		mockRepoBehaviour    func(mockRepo *mocks.ReminderRepository)
+		mockMailerBehaviour  func(mockMailer *mocks.Mailer)
+		mockWebhookBehaviour func(mockWebhook *mocks.WebhookSender)
+	}{
+		{
+			name:    "should email due reminder",
+			channel: reminder.ChannelEmail,
+			sent:    1,
+			status:  reminder.StatusSent,
+			mockArticleBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+			},
+			mockRepoBehaviour: func(mockRepo *mocks.ReminderRepository) {},
+			mockMailerBehaviour: func(mockMailer *mocks.Mailer) {
+				mockMailer.On("Send", context.Background(), mock.MatchedBy(func(msg mailer.Message) bool {
+					return msg.To == test.TestUser.Email &&
+						msg.Subject == "Reminder: "+test.TestArticle.Title &&
+						strings.Contains(msg.Text, test.TestArticle.ArticleLink)
+				})).Return(nil)
+			},
+			mockWebhookBehaviour: func(mockWebhook *mocks.WebhookSender) {},
+		},
+		{
+			name:    "should post due reminder to user's webhook",
+			channel: reminder.ChannelWebhook,
+			sent:    1,
+			status:  reminder.StatusSent,
+			mockArticleBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+			},
+			mockRepoBehaviour: func(mockRepo *mocks.ReminderRepository) {
+				mockRepo.On("FindWebhook", context.Background(), test.TestUser.ID).Return(hook, nil)
+			},
+			mockMailerBehaviour: func(mockMailer *mocks.Mailer) {},
+			mockWebhookBehaviour: func(mockWebhook *mocks.WebhookSender) {
+				mockWebhook.On("Send", context.Background(), mock.MatchedBy(func(req webhook.Request) bool {
+					e, ok := req.Payload.(reminder.Event)
+					return req.URL == hook.URL && req.Secret == hook.Secret && req.Event == reminder.EventReminder &&
+						ok && e.Article.ID == test.TestArticle.ID
+				})).Return(nil)
+			},
+		},
+		{
+			name:    "should retry reminder when webhook fails",
+			channel: reminder.ChannelWebhook,
+			sent:    0,
+			status:  reminder.StatusPending,
+			mockArticleBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+			},
+			mockRepoBehaviour: func(mockRepo *mocks.ReminderRepository) {
+				mockRepo.On("FindWebhook", context.Background(), test.TestUser.ID).Return(hook, nil)
+			},
+			mockMailerBehaviour: func(mockMailer *mocks.Mailer) {},
+			mockWebhookBehaviour: func(mockWebhook *mocks.WebhookSender) {
+				mockWebhook.On("Send", context.Background(), mock.Anything).Return(errors.New("webhook responded with 502"))
+			},
+		},
+		{
+			name:    "should fail reminder when webhook was deleted",
+			channel: reminder.ChannelWebhook,
+			sent:    0,
+			status:  reminder.StatusFailed,
+			mockArticleBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+			},
+			mockRepoBehaviour: func(mockRepo *mocks.ReminderRepository) {
+				mockRepo.On("FindWebhook", context.Background(), test.TestUser.ID).
+					Return(nil, validation.NewError(validation.NotFound, "no webhook found"))
+			},
+			mockMailerBehaviour:  func(mockMailer *mocks.Mailer) {},
+			mockWebhookBehaviour: func(mockWebhook *mocks.WebhookSender) {},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			due := reminder.NewReminder(test.TestUser.ID, test.TestArticle.ID, c.channel, now.Add(-time.Minute))
+
+			r := new(mocks.ReminderRepository)
+			r.On("ListDue", context.Background(), now, 10).Return([]*reminder.Reminder{due}, nil)
+			r.On("UpdateAttempt", context.Background(), mock.MatchedBy(func(arg reminder.Reminder) bool {
+				return arg.ID == due.ID && arg.Status == c.status && arg.Attempts == 1
+			})).Return(nil)
+			c.mockRepoBehaviour(r)
+			userRepo := new(mocks.UserRepository)
+			userRepo.On("FindByID", context.Background(), test.TestUser.ID).Return(test.TestUser, nil)
+			articleRepo := new(mocks.ArticleRepository)
+			c.mockArticleBehaviour(articleRepo)
+			m := new(mocks.Mailer)
+			c.mockMailerBehaviour(m)
+			w := new(mocks.WebhookSender)
+			c.mockWebhookBehaviour(w)
+
+			s := NewService(logger.NewLogger(), m, w, r, articleRepo, userRepo, appURL)
+			sent, err := s.SendDueReminders(context.Background(), now, 10)
+			assert.Nil(t, err)
+			assert.Equal(t, c.sent, sent)
+			r.AssertExpectations(t)
+			m.AssertExpectations(t)
+			w.AssertExpectations(t)
+		})
+	}
+}
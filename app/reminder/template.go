@@ -0,0 +1,89 @@
+package reminder
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/ryanadiputraa/unclatter/app/article"
+)
+
+// Message is the content of a reminder email.
+type Message struct {
+	Title       string
+	Link        string
+	Domain      string
+	ReadingTime int
+	Excerpt     string
+	AppURL      string
+}
+
+func NewMessage(a *article.Article, appURL string) Message {
+	return Message{
+		Title:       a.Title,
+		Link:        a.ArticleLink,
+		Domain:      a.Domain,
+		ReadingTime: a.ReadingTime,
+		Excerpt:     excerpt(a),
+		AppURL:      appURL,
+	}
+}
+
+var textTemplate = texttemplate.Must(texttemplate.New("reminder").Parse(strings.TrimLeft(`
+You asked Unclatter to remind you about this article.
+
+{{.Title}}
+{{.Domain}} · {{.ReadingTime}} min read
+{{- if .Excerpt}}
+{{.Excerpt}}
+{{- end}}
+{{.Link}}
+
+--
+Read it in Unclatter at {{.AppURL}}
+`, "\n")))
+
+var htmlTemplate = htmltemplate.Must(htmltemplate.New("reminder").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Reading reminder</title>
+</head>
+<body style="margin:0;padding:24px;background:#f6f6f4;font-family:Georgia,serif;color:#1f1f1f">
+<div style="max-width:600px;margin:0 auto;background:#ffffff;padding:24px">
+<p style="font-size:18px;margin:0 0 24px">You asked Unclatter to remind you about this article.</p>
+<div style="margin:0 0 24px">
+<a href="{{.Link}}" style="font-size:20px;color:#1f1f1f;text-decoration:none;font-weight:bold">{{.Title}}</a>
+<p style="margin:4px 0;font-family:Helvetica,Arial,sans-serif;font-size:13px;color:#6b6b6b">{{.Domain}} · {{.ReadingTime}} min read</p>
+{{- if .Excerpt}}
+<p style="margin:4px 0;font-size:15px;line-height:1.5">{{.Excerpt}}</p>
+{{- end}}
+</div>
+<p style="margin:24px 0 0;font-family:Helvetica,Arial,sans-serif;font-size:12px;color:#6b6b6b"><a href="{{.AppURL}}" style="color:#6b6b6b">Read it in Unclatter</a></p>
+</div>
+</body>
+</html>
+`))
+
+// Render returns the plain text and html bodies of the reminder email.
+func Render(m Message) (text, html string, err error) {
+	var buf bytes.Buffer
+	if err = textTemplate.Execute(&buf, m); err != nil {
+		return
+	}
+	text = buf.String()
+
+	buf.Reset()
+	if err = htmlTemplate.Execute(&buf, m); err != nil {
+		return
+	}
+	html = buf.String()
+	return
+}
+
+// Subject is the subject of the reminder email.
+func Subject(m Message) string {
+	return "Reminder: " + m.Title
+}
@@ -1,7 +1,9 @@
 package server
 
 import (
+	"context"
 	"net/http"
+	"time"
 
 	articleHandler "github.com/ryanadiputraa/unclatter/app/article/handler"
 	_articleRepository "github.com/ryanadiputraa/unclatter/app/article/repository"
@@ -9,15 +11,53 @@ import (
 	authHandler "github.com/ryanadiputraa/unclatter/app/auth/handler"
 	_authRepository "github.com/ryanadiputraa/unclatter/app/auth/repository"
 	_authService "github.com/ryanadiputraa/unclatter/app/auth/service"
+	collectionHandler "github.com/ryanadiputraa/unclatter/app/collection/handler"
+	_collectionRepository "github.com/ryanadiputraa/unclatter/app/collection/repository"
+	_collectionService "github.com/ryanadiputraa/unclatter/app/collection/service"
+	deliveryHandler "github.com/ryanadiputraa/unclatter/app/delivery/handler"
+	_deliveryRepository "github.com/ryanadiputraa/unclatter/app/delivery/repository"
+	_deliveryService "github.com/ryanadiputraa/unclatter/app/delivery/service"
+	digestHandler "github.com/ryanadiputraa/unclatter/app/digest/handler"
+	_digestRepository "github.com/ryanadiputraa/unclatter/app/digest/repository"
+	_digestService "github.com/ryanadiputraa/unclatter/app/digest/service"
+	exportHandler "github.com/ryanadiputraa/unclatter/app/export/handler"
+	_exportRepository "github.com/ryanadiputraa/unclatter/app/export/repository"
+	_exportService "github.com/ryanadiputraa/unclatter/app/export/service"
+	highlightHandler "github.com/ryanadiputraa/unclatter/app/highlight/handler"
+	_highlightRepository "github.com/ryanadiputraa/unclatter/app/highlight/repository"
+	_highlightService "github.com/ryanadiputraa/unclatter/app/highlight/service"
+	importHandler "github.com/ryanadiputraa/unclatter/app/importer/handler"
+	_importRepository "github.com/ryanadiputraa/unclatter/app/importer/repository"
+	_importService "github.com/ryanadiputraa/unclatter/app/importer/service"
+	linkHealthHandler "github.com/ryanadiputraa/unclatter/app/linkhealth/handler"
+	_linkHealthRepository "github.com/ryanadiputraa/unclatter/app/linkhealth/repository"
+	_linkHealthService "github.com/ryanadiputraa/unclatter/app/linkhealth/service"
 	"github.com/ryanadiputraa/unclatter/app/middleware"
+	progressHandler "github.com/ryanadiputraa/unclatter/app/progress/handler"
+	_progressRepository "github.com/ryanadiputraa/unclatter/app/progress/repository"
+	_progressService "github.com/ryanadiputraa/unclatter/app/progress/service"
+	reminderHandler "github.com/ryanadiputraa/unclatter/app/reminder/handler"
+	_reminderRepository "github.com/ryanadiputraa/unclatter/app/reminder/repository"
+	_reminderService "github.com/ryanadiputraa/unclatter/app/reminder/service"
+	shareHandler "github.com/ryanadiputraa/unclatter/app/share/handler"
+	_shareRepository "github.com/ryanadiputraa/unclatter/app/share/repository"
+	_shareService "github.com/ryanadiputraa/unclatter/app/share/service"
+	tagHandler "github.com/ryanadiputraa/unclatter/app/tag/handler"
+	_tagRepository "github.com/ryanadiputraa/unclatter/app/tag/repository"
+	_tagService "github.com/ryanadiputraa/unclatter/app/tag/service"
 	userHandler "github.com/ryanadiputraa/unclatter/app/user/handler"
 	_userRepository "github.com/ryanadiputraa/unclatter/app/user/repository"
 	_userService "github.com/ryanadiputraa/unclatter/app/user/service"
+	"github.com/ryanadiputraa/unclatter/pkg/epub"
+	"github.com/ryanadiputraa/unclatter/pkg/filestore"
 	"github.com/ryanadiputraa/unclatter/pkg/jwt"
+	"github.com/ryanadiputraa/unclatter/pkg/linkcheck"
+	"github.com/ryanadiputraa/unclatter/pkg/mailer"
 	"github.com/ryanadiputraa/unclatter/pkg/oauth"
 	"github.com/ryanadiputraa/unclatter/pkg/sanitizer"
 	"github.com/ryanadiputraa/unclatter/pkg/scrapper"
 	"github.com/ryanadiputraa/unclatter/pkg/validator"
+	"github.com/ryanadiputraa/unclatter/pkg/webhook"
 )
 
 func (s *Server) setupHandlers() {
@@ -26,6 +66,9 @@ func (s *Server) setupHandlers() {
 	jwtTokens := jwt.NewJWTTokens(s.config.JWT)
 	scrapper := scrapper.NewScrapper()
 	sanitizer := sanitizer.NewSanitizer()
+	mailer := mailer.NewMailer(s.config.SMTP)
+	webhook := webhook.NewSender(s.config.Reminder.WebhookTimeout)
+	linkChecker := linkcheck.NewChecker(s.config.LinkCheck.Timeout, s.config.LinkCheck.HostDelay)
 
 	authMiddleware := middleware.NewAuthMiddleware(s.log, s.config.JWT, s.rw, jwtTokens)
 
@@ -38,8 +81,93 @@ func (s *Server) setupHandlers() {
 	authHandler.NewHandler(s.web, s.config, s.log, authService, userService, googleOauth, jwtTokens)
 
 	articleRepository := _articleRepository.NewRepository(s.db)
-	articleService := _articleService.NewService(s.log, scrapper, sanitizer, articleRepository)
+	collectionRepository := _collectionRepository.NewRepository(s.db)
+	tagRepository := _tagRepository.NewRepository(s.db)
+	articleService := _articleService.NewService(s.log, scrapper, sanitizer, articleRepository, collectionRepository, tagRepository)
 	articleHandler.NewHandler(s.web, s.rw, articleService, *authMiddleware, validator)
+	s.jobs.Every("purge expired trash", s.config.Trash.PurgeInterval, func(ctx context.Context) error {
+		_, err := articleService.PurgeExpiredTrash(ctx, s.config.Trash.Retention)
+		return err
+	})
+	s.jobs.Every("migrate sanitized content", s.config.Sanitizer.MigrateInterval, func(ctx context.Context) error {
+		_, err := articleService.MigrateSanitizedContent(ctx, s.config.Sanitizer.MigrateBatchSize)
+		return err
+	})
+	s.jobs.Every("index articles", s.config.Related.IndexInterval, func(ctx context.Context) error {
+		_, err := articleService.IndexArticles(ctx, s.config.Related.IndexBatchSize)
+		return err
+	})
+
+	progressRepository := _progressRepository.NewRepository(s.db)
+	progressService := _progressService.NewService(s.log, progressRepository)
+	progressHandler.NewHandler(s.web, s.rw, progressService, *authMiddleware, validator)
+
+	highlightRepository := _highlightRepository.NewRepository(s.db)
+	highlightService := _highlightService.NewService(s.log, highlightRepository, articleRepository)
+	highlightHandler.NewHandler(s.web, s.rw, highlightService, *authMiddleware, validator)
+
+	tagService := _tagService.NewService(s.log, tagRepository, articleRepository)
+	tagHandler.NewHandler(s.web, s.rw, tagService, *authMiddleware, validator)
+
+	collectionService := _collectionService.NewService(s.log, collectionRepository, userRepository)
+	collectionHandler.NewHandler(s.web, s.rw, collectionService, *authMiddleware, validator)
+
+	shareRepository := _shareRepository.NewRepository(s.db)
+	shareService := _shareService.NewService(s.log, sanitizer, shareRepository, articleRepository)
+	shareHandler.NewHandler(s.web, s.rw, shareService, *authMiddleware, validator)
+
+	imageFetcher := epub.NewHTTPFetcher()
+	exportRepository := _exportRepository.NewRepository(s.db)
+	exportStore := filestore.NewStore(s.config.Export.Dir)
+	exportService := _exportService.NewService(s.log, sanitizer, imageFetcher, exportStore, exportRepository, articleRepository, collectionRepository, s.config.Export.Retention)
+	exportHandler.NewHandler(s.web, s.rw, exportService, *authMiddleware)
+	s.jobs.Every("build queued exports", s.config.Export.BuildInterval, func(ctx context.Context) error {
+		if _, err := exportService.PurgeExpiredExports(ctx, time.Now()); err != nil {
+			return err
+		}
+		_, err := exportService.BuildQueuedExports(ctx, s.config.Export.BatchSize)
+		return err
+	})
+
+	importRepository := _importRepository.NewRepository(s.db)
+	importService := _importService.NewService(s.log, scrapper, sanitizer, importRepository)
+	importHandler.NewHandler(s.web, s.rw, importService, *authMiddleware)
+	s.jobs.Every("scrape imported articles", s.config.Import.ScrapeInterval, func(ctx context.Context) error {
+		_, err := importService.ScrapePending(ctx, s.config.Import.ScrapeBatchSize)
+		return err
+	})
+
+	deliveryRepository := _deliveryRepository.NewRepository(s.db)
+	deliveryService := _deliveryService.NewService(s.log, mailer, sanitizer, imageFetcher, deliveryRepository, articleRepository, collectionRepository)
+	deliveryHandler.NewHandler(s.web, s.rw, deliveryService, *authMiddleware, validator)
+	s.jobs.Every("send queued deliveries", s.config.Delivery.SendInterval, func(ctx context.Context) error {
+		_, err := deliveryService.SendQueuedDeliveries(ctx, s.config.Delivery.BatchSize)
+		return err
+	})
+
+	digestRepository := _digestRepository.NewRepository(s.db)
+	digestService := _digestService.NewService(s.log, mailer, digestRepository, articleRepository, userRepository, s.config.Server.BaseURL, s.config.FrontendURL)
+	digestHandler.NewHandler(s.web, s.rw, digestService, *authMiddleware, validator)
+	s.jobs.Every("send reading digests", s.config.Digest.SendInterval, func(ctx context.Context) error {
+		_, err := digestService.SendDueDigests(ctx, time.Now(), s.config.Digest.BatchSize)
+		return err
+	})
+
+	reminderRepository := _reminderRepository.NewRepository(s.db)
+	reminderService := _reminderService.NewService(s.log, mailer, webhook, reminderRepository, articleRepository, userRepository, s.config.FrontendURL)
+	reminderHandler.NewHandler(s.web, s.rw, reminderService, *authMiddleware, validator)
+	s.jobs.Every("send due reminders", s.config.Reminder.SendInterval, func(ctx context.Context) error {
+		_, err := reminderService.SendDueReminders(ctx, time.Now(), s.config.Reminder.BatchSize)
+		return err
+	})
+
+	linkHealthRepository := _linkHealthRepository.NewRepository(s.db)
+	linkHealthService := _linkHealthService.NewService(s.log, linkChecker, linkHealthRepository, articleRepository, s.config.LinkCheck.RecheckAfter)
+	linkHealthHandler.NewHandler(s.web, s.rw, linkHealthService, *authMiddleware, validator)
+	s.jobs.Every("check article links", s.config.LinkCheck.Interval, func(ctx context.Context) error {
+		_, err := linkHealthService.CheckDueLinks(ctx, time.Now(), s.config.LinkCheck.BatchSize)
+		return err
+	})
 
 	s.web.HandleFunc("GET /", func(w http.ResponseWriter, r *http.Request) {
 		s.rw.WriteResponseData(w, 200, "ok")
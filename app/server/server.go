@@ -13,6 +13,7 @@ import (
 	"github.com/ryanadiputraa/unclatter/config"
 	_http "github.com/ryanadiputraa/unclatter/pkg/http"
 	"github.com/ryanadiputraa/unclatter/pkg/logger"
+	"github.com/ryanadiputraa/unclatter/pkg/scheduler"
 	"gorm.io/gorm"
 )
 
@@ -22,6 +23,7 @@ type Server struct {
 	web    *http.ServeMux
 	db     *gorm.DB
 	rw     _http.ResponseWriter
+	jobs   scheduler.Scheduler
 }
 
 func NewHTTPServer(config *config.Config, log logger.Logger, db *gorm.DB) *Server {
@@ -31,6 +33,7 @@ func NewHTTPServer(config *config.Config, log logger.Logger, db *gorm.DB) *Serve
 		web:    http.NewServeMux(),
 		db:     db,
 		rw:     _http.NewResponseWriter(),
+		jobs:   scheduler.NewScheduler(log),
 	}
 }
 
@@ -53,6 +56,9 @@ func (s *Server) ServeHTTP() error {
 		}
 	}()
 
+	s.jobs.Start()
+	defer s.jobs.Stop()
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ryanadiputraa/unclatter/app/middleware"
+	"github.com/ryanadiputraa/unclatter/app/share"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	_http "github.com/ryanadiputraa/unclatter/pkg/http"
+	"github.com/ryanadiputraa/unclatter/pkg/validator"
+)
+
+// passwordHeader carries the password of a protected share, it's kept out of the url so it isn't logged.
+const passwordHeader = "X-Share-Password"
+
+type handler struct {
+	rw           _http.ResponseWriter
+	shareService share.ShareService
+	validator    validator.Validator
+}
+
+func NewHandler(web *http.ServeMux, rw _http.ResponseWriter, shareService share.ShareService, authMiddleware middleware.AuthMiddleware, validator validator.Validator) {
+	h := &handler{
+		rw:           rw,
+		shareService: shareService,
+		validator:    validator,
+	}
+
+	web.Handle("POST /api/articles/bookmarks/{id}/shares", authMiddleware.ParseJWTToken(h.CreateShare()))
+	web.Handle("GET /api/shares", authMiddleware.ParseJWTToken(h.ListShares()))
+	web.Handle("DELETE /api/shares/{id}", authMiddleware.ParseJWTToken(h.RevokeShare()))
+	web.Handle("GET /api/public/shares/{token}", h.GetSharedArticle())
+}
+
+func (h *handler) CreateShare() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		var payload share.SharePayload
+
+		json.NewDecoder(r.Body).Decode(&payload)
+		if err, errMap := h.validator.Validate(payload); err != nil {
+			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+			return
+		}
+
+		created, err := h.shareService.CreateShare(ac.Context, ac.UserID, r.PathValue("id"), payload)
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusCreated, created)
+	}
+}
+
+func (h *handler) ListShares() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		shares, err := h.shareService.ListShares(ac.Context, ac.UserID)
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, shares)
+	}
+}
+
+func (h *handler) RevokeShare() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		if err := h.shareService.RevokeShare(ac.Context, ac.UserID, r.PathValue("id")); err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, nil)
+	}
+}
+
+func (h *handler) GetSharedArticle() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		shared, err := h.shareService.GetSharedArticle(r.Context(), r.PathValue("token"), r.Header.Get(passwordHeader))
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "private, no-store")
+		h.rw.WriteResponseData(w, http.StatusOK, shared)
+	}
+}
+
+func (h *handler) writeErr(w http.ResponseWriter, err error) {
+	if vErr, ok := err.(*validation.Error); ok {
+		h.rw.WriteErrMessage(w, validation.HttpErrMap[vErr.Err], vErr.Message)
+		return
+	}
+	h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ryanadiputraa/unclatter/app/share"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) share.ShareRepository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Save(ctx context.Context, arg share.Share) error {
+	return r.db.Create(&arg).Error
+}
+
+func (r *repository) ListActive(ctx context.Context, userID string, now time.Time) (shares []*share.Share, err error) {
+	err = r.db.
+		Where("user_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", userID, now).
+		Order("created_at DESC").
+		Find(&shares).Error
+	for _, s := range shares {
+		s.PasswordProtected = s.PasswordHash != ""
+	}
+	return
+}
+
+func (r *repository) FindByTokenHash(ctx context.Context, tokenHash string) (s *share.Share, err error) {
+	err = r.db.Preload("Article").First(&s, "token_hash = ?", tokenHash).Error
+	// trashed articles aren't preloaded
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && s.Article == nil) {
+		return nil, validation.NewError(validation.NotFound, "no share found with given token")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.PasswordProtected = s.PasswordHash != ""
+	return
+}
+
+func (r *repository) Revoke(ctx context.Context, userID, shareID string, at time.Time) error {
+	res := r.db.Model(&share.Share{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", shareID, userID).
+		UpdateColumn("revoked_at", at)
+	if res.RowsAffected == 0 && res.Error == nil {
+		return validation.NewError(validation.NotFound, "no share found with given id")
+	}
+	return res.Error
+}
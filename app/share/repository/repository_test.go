@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/ryanadiputraa/unclatter/app/share"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/test"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestSave(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	s, _ := share.NewShare(share.NewShareArg{ArticleID: test.TestArticle.ID, UserID: test.TestUser.ID})
+
+	mock.ExpectBegin()
+	mock.ExpectExec("^INSERT INTO \"shares\"").
+		WithArgs(s.ID, s.ArticleID, s.UserID, s.TokenHash, "", nil, nil, s.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := r.Save(context.Background(), *s)
+	assert.Nil(t, err)
+}
+
+func TestListActive(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	now := time.Now().UTC()
+	shareID := uuid.NewString()
+
+	mock.ExpectQuery("^SELECT \\* FROM \"shares\" WHERE user_id = (.+) AND revoked_at IS NULL AND \\(expires_at IS NULL OR expires_at > (.+)\\) ORDER BY created_at DESC").
+		WithArgs(test.TestUser.ID, now).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "article_id", "password_hash"}).
+			AddRow(shareID, test.TestArticle.ID, "$2a$10$hash"))
+
+	shares, err := r.ListActive(context.Background(), test.TestUser.ID, now)
+	assert.Nil(t, err)
+	assert.Equal(t, []*share.Share{
+		{ID: shareID, ArticleID: test.TestArticle.ID, PasswordHash: "$2a$10$hash", PasswordProtected: true},
+	}, shares)
+}
+
+func TestFindByTokenHash(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	tokenHash := share.HashToken("token")
+	shareID := uuid.NewString()
+	expectedQuery := "^SELECT \\* FROM \"shares\" WHERE token_hash = (.+) LIMIT (.+)$"
+	preloadArticle := "^SELECT \\* FROM \"articles\" WHERE \"articles\".\"id\" = (.+) AND \"articles\".\"deleted_at\" IS NULL"
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		err           error
+	}{
+		{
+			name: "should return share with its article",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(expectedQuery).
+					WithArgs(tokenHash, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "article_id", "token_hash"}).AddRow(shareID, test.TestArticle.ID, tokenHash))
+				mock.ExpectQuery(preloadArticle).
+					WithArgs(test.TestArticle.ID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(test.TestArticle.ID, test.TestArticle.Title))
+			},
+			err: nil,
+		},
+		{
+			name: "should return not found err when shared article is in the trash",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(expectedQuery).
+					WithArgs(tokenHash, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "article_id", "token_hash"}).AddRow(shareID, test.TestArticle.ID, tokenHash))
+				mock.ExpectQuery(preloadArticle).
+					WithArgs(test.TestArticle.ID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title"}))
+			},
+			err: validation.NewError(validation.NotFound, "no share found with given token"),
+		},
+		{
+			name: "should return not found err when no share has the token",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(expectedQuery).
+					WithArgs(tokenHash, 1).
+					WillReturnError(gorm.ErrRecordNotFound)
+			},
+			err: validation.NewError(validation.NotFound, "no share found with given token"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+
+			s, err := r.FindByTokenHash(context.Background(), tokenHash)
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				assert.Nil(t, s)
+				return
+			}
+			assert.Equal(t, shareID, s.ID)
+			assert.Equal(t, test.TestArticle.Title, s.Article.Title)
+		})
+	}
+}
+
+func TestRevoke(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	shareID := uuid.NewString()
+	now := time.Now().UTC()
+	updateQuery := "^UPDATE \"shares\" SET \"revoked_at\"=(.+) WHERE id = (.+) AND user_id = (.+) AND revoked_at IS NULL"
+
+	cases := []struct {
+		name         string
+		rowsAffected int64
+		err          error
+	}{
+		{
+			name:         "should revoke user's share",
+			rowsAffected: 1,
+			err:          nil,
+		},
+		{
+			name:         "should return not found err when user has no active share with given id",
+			rowsAffected: 0,
+			err:          validation.NewError(validation.NotFound, "no share found with given id"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectExec(updateQuery).
+				WithArgs(now, shareID, test.TestUser.ID).
+				WillReturnResult(sqlmock.NewResult(0, c.rowsAffected))
+			mock.ExpectCommit()
+
+			err := r.Revoke(context.Background(), test.TestUser.ID, shareID, now)
+			assert.Equal(t, c.err, err)
+		})
+	}
+}
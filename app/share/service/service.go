@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/share"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/pkg/logger"
+	"github.com/ryanadiputraa/unclatter/pkg/sanitizer"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type service struct {
+	log               logger.Logger
+	sanitizer         sanitizer.Sanitizer
+	repository        share.ShareRepository
+	articleRepository article.ArticleRepository
+}
+
+func NewService(log logger.Logger, sanitizer sanitizer.Sanitizer, repository share.ShareRepository, articleRepository article.ArticleRepository) share.ShareService {
+	return &service{
+		log:               log,
+		sanitizer:         sanitizer,
+		repository:        repository,
+		articleRepository: articleRepository,
+	}
+}
+
+func (s *service) CreateShare(ctx context.Context, userID, articleID string, arg share.SharePayload) (created *share.Share, err error) {
+	var expiresAt *time.Time
+	if arg.ExpiresAt != "" {
+		t, err := time.Parse(time.RFC3339Nano, arg.ExpiresAt)
+		if err != nil || !t.After(time.Now()) {
+			return nil, validation.NewError(validation.BadRequest, "expires_at should be in the future")
+		}
+		t = t.UTC()
+		expiresAt = &t
+	}
+
+	a, err := s.articleRepository.FindByID(ctx, articleID)
+	if err != nil {
+		s.log.Warn("share service: fail to fetch article ", articleID, " ", err)
+		return
+	}
+	if a.UserID != userID {
+		err = validation.NewError(validation.Forbidden, "forbidden access")
+		return
+	}
+
+	var passwordHash string
+	if arg.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(arg.Password), bcrypt.DefaultCost)
+		if err != nil {
+			s.log.Error("share service: fail to hash share password", err)
+			return nil, err
+		}
+		passwordHash = string(hash)
+	}
+
+	created, err = share.NewShare(share.NewShareArg{
+		ArticleID:    articleID,
+		UserID:       userID,
+		PasswordHash: passwordHash,
+		ExpiresAt:    expiresAt,
+	})
+	if err != nil {
+		s.log.Error("share service: fail to generate share token", err)
+		return
+	}
+
+	if err = s.repository.Save(ctx, *created); err != nil {
+		s.log.Error("share service: fail to save share", err)
+		return nil, err
+	}
+	return
+}
+
+func (s *service) ListShares(ctx context.Context, userID string) (shares []*share.Share, err error) {
+	shares, err = s.repository.ListActive(ctx, userID, time.Now().UTC())
+	if err != nil {
+		s.log.Error("share service: fail to fetch user's shares", err)
+	}
+	return
+}
+
+func (s *service) RevokeShare(ctx context.Context, userID, shareID string) error {
+	if err := s.repository.Revoke(ctx, userID, shareID, time.Now().UTC()); err != nil {
+		s.log.Warn("share service: fail to revoke share ", shareID, " ", err)
+		return err
+	}
+	return nil
+}
+
+func (s *service) GetSharedArticle(ctx context.Context, token, password string) (*share.SharedArticle, error) {
+	sh, err := s.repository.FindByTokenHash(ctx, share.HashToken(token))
+	if err != nil {
+		if _, ok := err.(*validation.Error); !ok {
+			s.log.Error("share service: fail to fetch share", err)
+		}
+		return nil, err
+	}
+
+	// revoked and expired shares look the same as unknown ones
+	if !sh.IsActive(time.Now()) {
+		return nil, validation.NewError(validation.NotFound, "no share found with given token")
+	}
+	if sh.PasswordProtected {
+		if password == "" {
+			return nil, validation.NewError(validation.Unauthorized, "password is required to open this share")
+		}
+		if bcrypt.CompareHashAndPassword([]byte(sh.PasswordHash), []byte(password)) != nil {
+			return nil, validation.NewError(validation.Unauthorized, "invalid share password")
+		}
+	}
+
+	return &share.SharedArticle{
+		Title:       sh.Article.Title,
+		Content:     s.sanitizer.Sanitize(sh.Article.Content),
+		ArticleLink: sh.Article.ArticleLink,
+		Language:    sh.Article.Language,
+		Domain:      sh.Article.Domain,
+		ReadingTime: sh.Article.ReadingTime,
+		ExpiresAt:   sh.ExpiresAt,
+	}, nil
+}
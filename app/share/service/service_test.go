@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ryanadiputraa/unclatter/app/mocks"
+	"github.com/ryanadiputraa/unclatter/app/share"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/pkg/logger"
+	"github.com/ryanadiputraa/unclatter/pkg/sanitizer"
+	"github.com/ryanadiputraa/unclatter/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestCreateShare(t *testing.T) {
+	cases := []struct {
+		name                     string
+		userID                   string
+		arg                      share.SharePayload
+		err                      error
+		mockArticleRepoBehaviour func(mockRepo *mocks.ArticleRepository)
+		mockRepoBehaviour        func(mockRepo *mocks.ShareRepository)
+	}{
+		{
+			name:   "should create password protected share",
+			userID: test.TestArticle.UserID,
+			arg: share.SharePayload{
+				ExpiresAt: time.Now().Add(time.Hour).Format(time.RFC3339Nano),
+				Password:  "sharepassword",
+			},
+			err: nil,
+			mockArticleRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+			},
+			mockRepoBehaviour: func(mockRepo *mocks.ShareRepository) {
+				mockRepo.On("Save", context.Background(), mock.Anything).Return(nil)
+			},
+		},
+		{
+			name:   "should return err when expiry is in the past",
+			userID: test.TestArticle.UserID,
+			arg: share.SharePayload{
+				ExpiresAt: time.Now().Add(-time.Hour).Format(time.RFC3339Nano),
+			},
+			err:                      validation.NewError(validation.BadRequest, "expires_at should be in the future"),
+			mockArticleRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {},
+			mockRepoBehaviour:        func(mockRepo *mocks.ShareRepository) {},
+		},
+		{
+			name:   "should return err when sharing other user's article",
+			userID: uuid.NewString(),
+			arg:    share.SharePayload{},
+			err:    validation.NewError(validation.Forbidden, "forbidden access"),
+			mockArticleRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+			},
+			mockRepoBehaviour: func(mockRepo *mocks.ShareRepository) {},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			articleRepo := new(mocks.ArticleRepository)
+			c.mockArticleRepoBehaviour(articleRepo)
+			r := new(mocks.ShareRepository)
+			c.mockRepoBehaviour(r)
+
+			s := NewService(logger.NewLogger(), sanitizer.NewSanitizer(), r, articleRepo)
+			created, err := s.CreateShare(context.Background(), c.userID, test.TestArticle.ID, c.arg)
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				assert.Nil(t, created)
+				return
+			}
+			assert.NotEmpty(t, created.Token)
+			assert.Equal(t, share.HashToken(created.Token), created.TokenHash)
+			assert.True(t, created.PasswordProtected)
+			assert.Nil(t, bcrypt.CompareHashAndPassword([]byte(created.PasswordHash), []byte(c.arg.Password)))
+			assert.NotNil(t, created.ExpiresAt)
+		})
+	}
+}
+
+func TestGetSharedArticle(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("sharepassword"), bcrypt.MinCost)
+	past := time.Now().Add(-time.Hour)
+	token := "token"
+
+	cases := []struct {
+		name     string
+		password string
+		share    share.Share
+		err      error
+	}{
+		{
+			name:  "should open share",
+			share: share.Share{},
+			err:   nil,
+		},
+		{
+			name:     "should open password protected share",
+			password: "sharepassword",
+			share:    share.Share{PasswordHash: string(hash), PasswordProtected: true},
+			err:      nil,
+		},
+		{
+			name:  "should return err when password is missing",
+			share: share.Share{PasswordHash: string(hash), PasswordProtected: true},
+			err:   validation.NewError(validation.Unauthorized, "password is required to open this share"),
+		},
+		{
+			name:     "should return err when password is wrong",
+			password: "wrongpassword",
+			share:    share.Share{PasswordHash: string(hash), PasswordProtected: true},
+			err:      validation.NewError(validation.Unauthorized, "invalid share password"),
+		},
+		{
+			name:  "should return not found err when share is revoked",
+			share: share.Share{RevokedAt: &past},
+			err:   validation.NewError(validation.NotFound, "no share found with given token"),
+		},
+		{
+			name:  "should return not found err when share is expired",
+			share: share.Share{ExpiresAt: &past},
+			err:   validation.NewError(validation.NotFound, "no share found with given token"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			sh := c.share
+			sh.Article = test.TestArticle
+			r := new(mocks.ShareRepository)
+			r.On("FindByTokenHash", context.Background(), share.HashToken(token)).Return(&sh, nil)
+
+			s := NewService(logger.NewLogger(), sanitizer.NewSanitizer(), r, new(mocks.ArticleRepository))
+			shared, err := s.GetSharedArticle(context.Background(), token, c.password)
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				assert.Nil(t, shared)
+				return
+			}
+			assert.Equal(t, test.TestArticle.Title, shared.Title)
+			assert.Equal(t, test.TestArticle.ArticleLink, shared.ArticleLink)
+		})
+	}
+}
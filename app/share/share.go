@@ -0,0 +1,108 @@
+package share
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ryanadiputraa/unclatter/app/article"
+)
+
+// tokenBytes is the entropy of a share token, it's encoded as 43 url safe characters.
+const tokenBytes = 32
+
+// Share gives public read access to a single article through an unguessable token.
+type Share struct {
+	ID        string `json:"id" gorm:"type:varchar"`
+	ArticleID string `json:"article_id" gorm:"type:varchar;not null;index"`
+	UserID    string `json:"-" gorm:"type:varchar;not null;index"`
+	// TokenHash is the sha256 of the token, the token itself is never stored.
+	TokenHash string `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	// PasswordHash is the bcrypt hash of the optional share password.
+	PasswordHash string     `json:"-" gorm:"type:varchar"`
+	ExpiresAt    *time.Time `json:"expires_at" gorm:"type:timestamptz"`
+	RevokedAt    *time.Time `json:"revoked_at" gorm:"type:timestamptz"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"type:timestamptz;not null"`
+
+	Article *article.Article `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+
+	// Token is only returned when the share is created.
+	Token             string `json:"token,omitempty" gorm:"-"`
+	PasswordProtected bool   `json:"password_protected" gorm:"-"`
+}
+
+type SharePayload struct {
+	// ExpiresAt is optional, the share stays active until it's revoked when it's empty.
+	ExpiresAt string `json:"expires_at" validate:"omitempty,iso8601date"`
+	// Password is optional, bcrypt only uses the first 72 bytes.
+	Password string `json:"password" validate:"omitempty,min=8,max=72"`
+}
+
+type NewShareArg struct {
+	ArticleID    string
+	UserID       string
+	PasswordHash string
+	ExpiresAt    *time.Time
+}
+
+// SharedArticle is the public view of a shared article.
+type SharedArticle struct {
+	Title       string     `json:"title"`
+	Content     string     `json:"content"`
+	ArticleLink string     `json:"article_link"`
+	Language    string     `json:"language"`
+	Domain      string     `json:"domain"`
+	ReadingTime int        `json:"reading_time"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+}
+
+func NewShare(arg NewShareArg) (*Share, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	return &Share{
+		ID:                uuid.NewString(),
+		ArticleID:         arg.ArticleID,
+		UserID:            arg.UserID,
+		TokenHash:         HashToken(token),
+		PasswordHash:      arg.PasswordHash,
+		ExpiresAt:         arg.ExpiresAt,
+		CreatedAt:         time.Now().UTC(),
+		Token:             token,
+		PasswordProtected: arg.PasswordHash != "",
+	}, nil
+}
+
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsActive reports whether the share can still be opened.
+func (s *Share) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && (s.ExpiresAt == nil || s.ExpiresAt.After(now))
+}
+
+type ShareService interface {
+	CreateShare(ctx context.Context, userID, articleID string, arg SharePayload) (*Share, error)
+	// ListShares returns the user's shares that are neither revoked nor expired.
+	ListShares(ctx context.Context, userID string) ([]*Share, error)
+	RevokeShare(ctx context.Context, userID, shareID string) error
+	// GetSharedArticle opens a share without authentication, password is required for protected shares.
+	GetSharedArticle(ctx context.Context, token, password string) (*SharedArticle, error)
+}
+
+type ShareRepository interface {
+	Save(ctx context.Context, arg Share) error
+	ListActive(ctx context.Context, userID string, now time.Time) ([]*Share, error)
+	// FindByTokenHash returns the share with its article, trashed articles can't be opened.
+	FindByTokenHash(ctx context.Context, tokenHash string) (*Share, error)
+	Revoke(ctx context.Context, userID, shareID string, at time.Time) error
+}
@@ -0,0 +1,70 @@
+package share
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewShare(t *testing.T) {
+	arg := NewShareArg{
+		ArticleID: uuid.NewString(),
+		UserID:    uuid.NewString(),
+	}
+
+	s, err := NewShare(arg)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, s.ID)
+	assert.Equal(t, arg.ArticleID, s.ArticleID)
+	assert.Equal(t, arg.UserID, s.UserID)
+	assert.Len(t, s.Token, 43)
+	assert.Equal(t, HashToken(s.Token), s.TokenHash)
+	assert.NotEqual(t, s.Token, s.TokenHash)
+	assert.False(t, s.PasswordProtected)
+	assert.NotEmpty(t, s.CreatedAt)
+
+	other, err := NewShare(arg)
+	assert.Nil(t, err)
+	assert.NotEqual(t, s.Token, other.Token)
+}
+
+func TestIsActive(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+
+	cases := []struct {
+		name     string
+		share    Share
+		expected bool
+	}{
+		{
+			name:     "should be active without expiry",
+			share:    Share{},
+			expected: true,
+		},
+		{
+			name:     "should be active before expiry",
+			share:    Share{ExpiresAt: &future},
+			expected: true,
+		},
+		{
+			name:     "should be inactive after expiry",
+			share:    Share{ExpiresAt: &past},
+			expected: false,
+		},
+		{
+			name:     "should be inactive once revoked",
+			share:    Share{RevokedAt: &past},
+			expected: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, c.share.IsActive(now))
+		})
+	}
+}
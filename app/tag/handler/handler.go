@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ryanadiputraa/unclatter/app/middleware"
+	"github.com/ryanadiputraa/unclatter/app/tag"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	_http "github.com/ryanadiputraa/unclatter/pkg/http"
+	"github.com/ryanadiputraa/unclatter/pkg/validator"
+)
+
+type handler struct {
+	rw         _http.ResponseWriter
+	tagService tag.TagService
+	validator  validator.Validator
+}
+
+func NewHandler(web *http.ServeMux, rw _http.ResponseWriter, tagService tag.TagService, authMiddleware middleware.AuthMiddleware, validator validator.Validator) {
+	h := &handler{
+		rw:         rw,
+		tagService: tagService,
+		validator:  validator,
+	}
+
+	web.Handle("GET /api/tags", authMiddleware.ParseJWTToken(h.ListTags()))
+	web.Handle("DELETE /api/tags/{id}", authMiddleware.ParseJWTToken(h.DeleteTag()))
+	web.Handle("PUT /api/articles/bookmarks/{id}/tags", authMiddleware.ParseJWTToken(h.SetArticleTags()))
+	web.Handle("POST /api/articles/bookmarks/{id}/tags", authMiddleware.ParseJWTToken(h.AddArticleTags()))
+	web.Handle("GET /api/articles/bookmarks/{id}/tags/suggestions", authMiddleware.ParseJWTToken(h.SuggestArticleTags()))
+}
+
+func (h *handler) ListTags() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		tags, err := h.tagService.ListTags(ac.Context, ac.UserID)
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, tags)
+	}
+}
+
+func (h *handler) SetArticleTags() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		var payload tag.TagsPayload
+
+		json.NewDecoder(r.Body).Decode(&payload)
+		if err, errMap := h.validator.Validate(payload); err != nil {
+			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+			return
+		}
+
+		tags, err := h.tagService.SetArticleTags(ac.Context, ac.UserID, r.PathValue("id"), payload)
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, tags)
+	}
+}
+
+func (h *handler) AddArticleTags() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+		var payload tag.TagsPayload
+
+		json.NewDecoder(r.Body).Decode(&payload)
+		if err, errMap := h.validator.Validate(payload); err != nil {
+			h.rw.WriteErrDetails(w, http.StatusBadRequest, "invalid params", errMap)
+			return
+		}
+
+		tags, err := h.tagService.AddArticleTags(ac.Context, ac.UserID, r.PathValue("id"), payload)
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, tags)
+	}
+}
+
+func (h *handler) SuggestArticleTags() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		names, err := h.tagService.SuggestArticleTags(ac.Context, ac.UserID, r.PathValue("id"))
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, names)
+	}
+}
+
+func (h *handler) DeleteTag() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac := r.Context().(*middleware.AuthContext)
+
+		err := h.tagService.DeleteTag(ac.Context, ac.UserID, r.PathValue("id"))
+		if err != nil {
+			h.writeErr(w, err)
+			return
+		}
+
+		h.rw.WriteResponseData(w, http.StatusOK, nil)
+	}
+}
+
+func (h *handler) writeErr(w http.ResponseWriter, err error) {
+	if vErr, ok := err.(*validation.Error); ok {
+		h.rw.WriteErrMessage(w, validation.HttpErrMap[vErr.Err], vErr.Message)
+		return
+	}
+	h.rw.WriteErrMessage(w, http.StatusInternalServerError, "internal server error")
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ryanadiputraa/unclatter/app/tag"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// articleTag is a row of the article_tags join table.
+type articleTag struct {
+	ArticleID string
+	TagID     string
+}
+
+func (articleTag) TableName() string {
+	return "article_tags"
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) tag.TagRepository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) List(ctx context.Context, userID string) (tags []*tag.Tag, err error) {
+	// trashed articles don't count towards a tag until they're restored
+	err = r.db.
+		Select("tags.*, COUNT(articles.id) AS article_count").
+		Joins("LEFT JOIN article_tags ON article_tags.tag_id = tags.id").
+		Joins("LEFT JOIN articles ON articles.id = article_tags.article_id AND articles.deleted_at IS NULL").
+		Where("tags.user_id = ?", userID).
+		Group("tags.id").
+		Order("tags.name ASC").
+		Find(&tags).Error
+	return
+}
+
+func (r *repository) ReplaceArticleTags(ctx context.Context, userID, articleID string, tags []tag.Tag) (replaced []*tag.Tag, err error) {
+	replaced = []*tag.Tag{}
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("article_id = ?", articleID).Delete(&articleTag{}).Error; err != nil {
+			return err
+		}
+		if len(tags) == 0 {
+			return nil
+		}
+
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "name"}},
+			DoNothing: true,
+		}).Create(&tags).Error
+		if err != nil {
+			return err
+		}
+
+		names := make([]string, len(tags))
+		for i, t := range tags {
+			names[i] = t.Name
+		}
+		err = tx.Where("user_id = ? AND name IN ?", userID, names).Order("name ASC").Find(&replaced).Error
+		if err != nil {
+			return err
+		}
+
+		rows := make([]articleTag, len(replaced))
+		for i, t := range replaced {
+			rows[i] = articleTag{ArticleID: articleID, TagID: t.ID}
+		}
+		return tx.Create(&rows).Error
+	})
+	return
+}
+
+func (r *repository) AddArticleTags(ctx context.Context, userID, articleID string, tags []tag.Tag) (articleTags []*tag.Tag, err error) {
+	articleTags = []*tag.Tag{}
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if len(tags) > 0 {
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "user_id"}, {Name: "name"}},
+				DoNothing: true,
+			}).Create(&tags).Error
+			if err != nil {
+				return err
+			}
+
+			names := make([]string, len(tags))
+			for i, t := range tags {
+				names[i] = t.Name
+			}
+			var added []*tag.Tag
+			if err = tx.Where("user_id = ? AND name IN ?", userID, names).Find(&added).Error; err != nil {
+				return err
+			}
+
+			rows := make([]articleTag, len(added))
+			for i, t := range added {
+				rows[i] = articleTag{ArticleID: articleID, TagID: t.ID}
+			}
+			if err = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Joins("JOIN article_tags ON article_tags.tag_id = tags.id").
+			Where("article_tags.article_id = ?", articleID).
+			Order("tags.name ASC").
+			Find(&articleTags).Error
+	})
+	return
+}
+
+func (r *repository) Delete(ctx context.Context, userID, tagID string) error {
+	res := r.db.Where("id = ? AND user_id = ?", tagID, userID).Delete(&tag.Tag{})
+	if res.RowsAffected == 0 && res.Error == nil {
+		return validation.NewError(validation.NotFound, "no tag found with given id")
+	}
+	return res.Error
+}
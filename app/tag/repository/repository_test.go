@@ -0,0 +1,283 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/ryanadiputraa/unclatter/app/tag"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/test"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestList(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	userID := test.TestUser.ID
+	expectedQuery := "^SELECT tags.\\*, COUNT\\(articles.id\\) AS article_count FROM \"tags\" LEFT JOIN article_tags (.+) " +
+		"LEFT JOIN articles (.+) AND articles.deleted_at IS NULL WHERE tags.user_id = (.+) GROUP BY \"tags\".\"id\" ORDER BY tags.name ASC"
+
+	cases := []struct {
+		name          string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		expected      []*tag.Tag
+		err           error
+	}{
+		{
+			name: "should return user's tags with article count",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(expectedQuery).
+					WithArgs(userID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "article_count"}).
+						AddRow("tag-1", "databases", 1).
+						AddRow("tag-2", "go", 3))
+			},
+			expected: []*tag.Tag{
+				{ID: "tag-1", Name: "databases", ArticleCount: 1},
+				{ID: "tag-2", Name: "go", ArticleCount: 3},
+			},
+			err: nil,
+		},
+		{
+			name: "should return err when fail to fetch tags",
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(expectedQuery).
+					WithArgs(userID).
+					WillReturnError(gorm.ErrInvalidDB)
+			},
+			expected: nil,
+			err:      gorm.ErrInvalidDB,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock)
+
+			tags, err := r.List(context.Background(), userID)
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.expected, tags)
+		})
+	}
+}
+
+func TestReplaceArticleTags(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	userID := test.TestUser.ID
+	articleID := test.TestArticle.ID
+	deleteQuery := "^DELETE FROM \"article_tags\" WHERE article_id = "
+
+	cases := []struct {
+		name          string
+		tags          []tag.Tag
+		mockBehaviour func(mock sqlmock.Sqlmock, tags []tag.Tag)
+		expected      []*tag.Tag
+		err           error
+	}{
+		{
+			name: "should replace article tags with the given ones",
+			tags: tag.NewTags(userID, []string{"go"}),
+			mockBehaviour: func(mock sqlmock.Sqlmock, tags []tag.Tag) {
+				mock.ExpectBegin()
+				mock.ExpectExec(deleteQuery).
+					WithArgs(articleID).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec("^INSERT INTO \"tags\" (.+) ON CONFLICT \\(\"user_id\",\"name\"\\) DO NOTHING").
+					WithArgs(tags[0].ID, userID, "go", tags[0].CreatedAt).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("^SELECT (.+) FROM \"tags\" WHERE user_id = (.+) AND name IN (.+) ORDER BY name ASC").
+					WithArgs(userID, "go").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name"}).AddRow("tag-1", userID, "go"))
+				mock.ExpectExec("^INSERT INTO \"article_tags\"").
+					WithArgs(articleID, "tag-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			expected: []*tag.Tag{{ID: "tag-1", UserID: userID, Name: "go"}},
+			err:      nil,
+		},
+		{
+			name: "should clear article tags",
+			tags: []tag.Tag{},
+			mockBehaviour: func(mock sqlmock.Sqlmock, tags []tag.Tag) {
+				mock.ExpectBegin()
+				mock.ExpectExec(deleteQuery).
+					WithArgs(articleID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			expected: []*tag.Tag{},
+			err:      nil,
+		},
+		{
+			name: "should return err when fail to clear article tags",
+			tags: tag.NewTags(userID, []string{"go"}),
+			mockBehaviour: func(mock sqlmock.Sqlmock, tags []tag.Tag) {
+				mock.ExpectBegin()
+				mock.ExpectExec(deleteQuery).
+					WithArgs(articleID).
+					WillReturnError(gorm.ErrInvalidDB)
+				mock.ExpectRollback()
+			},
+			expected: nil,
+			err:      gorm.ErrInvalidDB,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock, c.tags)
+
+			tags, err := r.ReplaceArticleTags(context.Background(), userID, articleID, c.tags)
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.expected, tags)
+		})
+	}
+}
+
+func TestAddArticleTags(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	userID := test.TestUser.ID
+	articleID := test.TestArticle.ID
+	listQuery := "^SELECT (.+) FROM \"tags\" JOIN article_tags ON article_tags.tag_id = tags.id WHERE article_tags.article_id = (.+) ORDER BY tags.name ASC"
+
+	cases := []struct {
+		name          string
+		tags          []tag.Tag
+		mockBehaviour func(mock sqlmock.Sqlmock, tags []tag.Tag)
+		expected      []*tag.Tag
+		err           error
+	}{
+		{
+			name: "should add tags to the ones the article has",
+			tags: tag.NewTags(userID, []string{"postgres"}),
+			mockBehaviour: func(mock sqlmock.Sqlmock, tags []tag.Tag) {
+				mock.ExpectBegin()
+				mock.ExpectExec("^INSERT INTO \"tags\" (.+) ON CONFLICT \\(\"user_id\",\"name\"\\) DO NOTHING").
+					WithArgs(tags[0].ID, userID, "postgres", tags[0].CreatedAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("^SELECT (.+) FROM \"tags\" WHERE user_id = (.+) AND name IN (.+)").
+					WithArgs(userID, "postgres").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name"}).AddRow("tag-2", userID, "postgres"))
+				mock.ExpectExec("^INSERT INTO \"article_tags\" (.+) ON CONFLICT DO NOTHING").
+					WithArgs(articleID, "tag-2").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(listQuery).
+					WithArgs(articleID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name"}).
+						AddRow("tag-1", userID, "go").
+						AddRow("tag-2", userID, "postgres"))
+				mock.ExpectCommit()
+			},
+			expected: []*tag.Tag{
+				{ID: "tag-1", UserID: userID, Name: "go"},
+				{ID: "tag-2", UserID: userID, Name: "postgres"},
+			},
+			err: nil,
+		},
+		{
+			name: "should return article tags when there's none to add",
+			tags: []tag.Tag{},
+			mockBehaviour: func(mock sqlmock.Sqlmock, tags []tag.Tag) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(listQuery).
+					WithArgs(articleID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name"}).AddRow("tag-1", userID, "go"))
+				mock.ExpectCommit()
+			},
+			expected: []*tag.Tag{{ID: "tag-1", UserID: userID, Name: "go"}},
+			err:      nil,
+		},
+		{
+			name: "should return err when fail to create tags",
+			tags: tag.NewTags(userID, []string{"postgres"}),
+			mockBehaviour: func(mock sqlmock.Sqlmock, tags []tag.Tag) {
+				mock.ExpectBegin()
+				mock.ExpectExec("^INSERT INTO \"tags\"").
+					WillReturnError(gorm.ErrInvalidDB)
+				mock.ExpectRollback()
+			},
+			expected: nil,
+			err:      gorm.ErrInvalidDB,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock, c.tags)
+
+			tags, err := r.AddArticleTags(context.Background(), userID, articleID, c.tags)
+			assert.Equal(t, c.err, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.expected, tags)
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	gormDB, db, mock := test.NewMockDB(t)
+	defer db.Close()
+
+	r := NewRepository(gormDB)
+	deleteQuery := "^DELETE FROM \"tags\" WHERE id = (.+) AND user_id = "
+
+	cases := []struct {
+		name          string
+		tagID         string
+		mockBehaviour func(mock sqlmock.Sqlmock, tagID string)
+		err           error
+	}{
+		{
+			name:  "should delete user's tag",
+			tagID: uuid.NewString(),
+			mockBehaviour: func(mock sqlmock.Sqlmock, tagID string) {
+				mock.ExpectBegin()
+				mock.ExpectExec(deleteQuery).
+					WithArgs(tagID, test.TestUser.ID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			err: nil,
+		},
+		{
+			name:  "should return not found err when user has no tag with given id",
+			tagID: uuid.NewString(),
+			mockBehaviour: func(mock sqlmock.Sqlmock, tagID string) {
+				mock.ExpectBegin()
+				mock.ExpectExec(deleteQuery).
+					WithArgs(tagID, test.TestUser.ID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			err: validation.NewError(validation.NotFound, "no tag found with given id"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mockBehaviour(mock, c.tagID)
+
+			err := r.Delete(context.Background(), test.TestUser.ID, c.tagID)
+			assert.Equal(t, c.err, err)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/tag"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/pkg/logger"
+)
+
+type service struct {
+	log               logger.Logger
+	repository        tag.TagRepository
+	articleRepository article.ArticleRepository
+}
+
+func NewService(log logger.Logger, repository tag.TagRepository, articleRepository article.ArticleRepository) tag.TagService {
+	return &service{
+		log:               log,
+		repository:        repository,
+		articleRepository: articleRepository,
+	}
+}
+
+func (s *service) ListTags(ctx context.Context, userID string) (tags []*tag.Tag, err error) {
+	tags, err = s.repository.List(ctx, userID)
+	if err != nil {
+		s.log.Error("tag service: fail to fetch user's tags", err)
+	}
+	return
+}
+
+func (s *service) SetArticleTags(ctx context.Context, userID, articleID string, arg tag.TagsPayload) (tags []*tag.Tag, err error) {
+	if _, err = s.findArticle(ctx, userID, articleID); err != nil {
+		return
+	}
+
+	tags, err = s.repository.ReplaceArticleTags(ctx, userID, articleID, tag.NewTags(userID, arg.Names))
+	if err != nil {
+		s.log.Error("tag service: fail to set article tags", err)
+	}
+	return
+}
+
+func (s *service) AddArticleTags(ctx context.Context, userID, articleID string, arg tag.TagsPayload) (tags []*tag.Tag, err error) {
+	if _, err = s.findArticle(ctx, userID, articleID); err != nil {
+		return
+	}
+
+	tags, err = s.repository.AddArticleTags(ctx, userID, articleID, tag.NewTags(userID, arg.Names))
+	if err != nil {
+		s.log.Error("tag service: fail to add article tags", err)
+	}
+	return
+}
+
+func (s *service) SuggestArticleTags(ctx context.Context, userID, articleID string) ([]string, error) {
+	a, err := s.findArticle(ctx, userID, articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repository.List(ctx, userID)
+	if err != nil {
+		s.log.Error("tag service: fail to fetch user's tags", err)
+		return nil, err
+	}
+	return article.SuggestTags(*a, existing), nil
+}
+
+// findArticle returns the user's own article, tags can't be set on articles of shared collections.
+func (s *service) findArticle(ctx context.Context, userID, articleID string) (*article.Article, error) {
+	a, err := s.articleRepository.FindByID(ctx, articleID)
+	if err != nil {
+		s.log.Warn("tag service: fail to fetch article ", articleID, " ", err)
+		return nil, err
+	}
+	if a.UserID != userID {
+		return nil, validation.NewError(validation.Forbidden, "forbidden access")
+	}
+	return a, nil
+}
+
+func (s *service) DeleteTag(ctx context.Context, userID, tagID string) error {
+	if err := s.repository.Delete(ctx, userID, tagID); err != nil {
+		s.log.Warn("tag service: fail to delete tag ", tagID, " ", err)
+		return err
+	}
+
+	return nil
+}
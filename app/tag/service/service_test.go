@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"github.com/ryanadiputraa/unclatter/app/mocks"
+	"github.com/ryanadiputraa/unclatter/app/tag"
+	"github.com/ryanadiputraa/unclatter/app/validation"
+	"github.com/ryanadiputraa/unclatter/pkg/logger"
+	"github.com/ryanadiputraa/unclatter/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSetArticleTags(t *testing.T) {
+	goTag := &tag.Tag{ID: uuid.NewString(), UserID: test.TestArticle.UserID, Name: "go"}
+
+	cases := []struct {
+		name                     string
+		userID                   string
+		arg                      tag.TagsPayload
+		expected                 []*tag.Tag
+		err                      error
+		mockArticleRepoBehaviour func(mockRepo *mocks.ArticleRepository)
+		mockRepoBehaviour        func(mockRepo *mocks.TagRepository)
+	}{
+		{
+			name:     "should replace article tags with normalized names",
+			userID:   test.TestArticle.UserID,
+			arg:      tag.TagsPayload{Names: []string{"Go", "go "}},
+			expected: []*tag.Tag{goTag},
+			err:      nil,
+			mockArticleRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+			},
+			mockRepoBehaviour: func(mockRepo *mocks.TagRepository) {
+				mockRepo.On("ReplaceArticleTags", context.Background(), test.TestArticle.UserID, test.TestArticle.ID,
+					mock.MatchedBy(func(tags []tag.Tag) bool {
+						return len(tags) == 1 && tags[0].Name == "go"
+					})).Return([]*tag.Tag{goTag}, nil)
+			},
+		},
+		{
+			name:     "should return err when tagging another user's article",
+			userID:   uuid.NewString(),
+			arg:      tag.TagsPayload{Names: []string{"go"}},
+			expected: nil,
+			err:      validation.NewError(validation.Forbidden, "forbidden access"),
+			mockArticleRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+			},
+			mockRepoBehaviour: func(mockRepo *mocks.TagRepository) {},
+		},
+		{
+			name:     "should return err when article doesn't exist",
+			userID:   test.TestArticle.UserID,
+			arg:      tag.TagsPayload{Names: []string{"go"}},
+			expected: nil,
+			err:      validation.NewError(validation.NotFound, "no article found with given id"),
+			mockArticleRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).
+					Return(nil, validation.NewError(validation.NotFound, "no article found with given id"))
+			},
+			mockRepoBehaviour: func(mockRepo *mocks.TagRepository) {},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			articleRepo := new(mocks.ArticleRepository)
+			r := new(mocks.TagRepository)
+			c.mockArticleRepoBehaviour(articleRepo)
+			c.mockRepoBehaviour(r)
+
+			s := NewService(logger.NewLogger(), r, articleRepo)
+			tags, err := s.SetArticleTags(context.Background(), c.userID, test.TestArticle.ID, c.arg)
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.expected, tags)
+			r.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAddArticleTags(t *testing.T) {
+	goTag := &tag.Tag{ID: uuid.NewString(), UserID: test.TestArticle.UserID, Name: "go"}
+	postgresTag := &tag.Tag{ID: uuid.NewString(), UserID: test.TestArticle.UserID, Name: "postgres"}
+
+	cases := []struct {
+		name                     string
+		userID                   string
+		arg                      tag.TagsPayload
+		expected                 []*tag.Tag
+		err                      error
+		mockArticleRepoBehaviour func(mockRepo *mocks.ArticleRepository)
+		mockRepoBehaviour        func(mockRepo *mocks.TagRepository)
+	}{
+		{
+			name:     "should add tags to the article and return all of its tags",
+			userID:   test.TestArticle.UserID,
+			arg:      tag.TagsPayload{Names: []string{"Postgres"}},
+			expected: []*tag.Tag{goTag, postgresTag},
+			err:      nil,
+			mockArticleRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+			},
+			mockRepoBehaviour: func(mockRepo *mocks.TagRepository) {
+				mockRepo.On("AddArticleTags", context.Background(), test.TestArticle.UserID, test.TestArticle.ID,
+					mock.MatchedBy(func(tags []tag.Tag) bool {
+						return len(tags) == 1 && tags[0].Name == "postgres"
+					})).Return([]*tag.Tag{goTag, postgresTag}, nil)
+			},
+		},
+		{
+			name:     "should return err when tagging another user's article",
+			userID:   uuid.NewString(),
+			arg:      tag.TagsPayload{Names: []string{"postgres"}},
+			expected: nil,
+			err:      validation.NewError(validation.Forbidden, "forbidden access"),
+			mockArticleRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(test.TestArticle, nil)
+			},
+			mockRepoBehaviour: func(mockRepo *mocks.TagRepository) {},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			articleRepo := new(mocks.ArticleRepository)
+			r := new(mocks.TagRepository)
+			c.mockArticleRepoBehaviour(articleRepo)
+			c.mockRepoBehaviour(r)
+
+			s := NewService(logger.NewLogger(), r, articleRepo)
+			tags, err := s.AddArticleTags(context.Background(), c.userID, test.TestArticle.ID, c.arg)
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.expected, tags)
+			r.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSuggestArticleTags(t *testing.T) {
+	a := &article.Article{
+		ID:       test.TestArticle.ID,
+		Title:    "Tuning Postgres indexes",
+		Content:  "<p>Postgres indexes speed up queries, but every index slows down writes. A partial index only covers the rows a query needs, and Postgres can combine several indexes with bitmap scans.</p>",
+		Language: "english",
+		UserID:   test.TestArticle.UserID,
+		Tags:     []*tag.Tag{{ID: uuid.NewString(), UserID: test.TestArticle.UserID, Name: "postgres"}},
+	}
+	withKeywords := *a
+	withKeywords.Keywords = "databases,Performance"
+
+	cases := []struct {
+		name                     string
+		userID                   string
+		expected                 []string
+		err                      error
+		mockArticleRepoBehaviour func(mockRepo *mocks.ArticleRepository)
+		mockRepoBehaviour        func(mockRepo *mocks.TagRepository)
+	}{
+		{
+			name:     "should suggest tags the article doesn't have yet",
+			userID:   test.TestArticle.UserID,
+			expected: []string{"bitmap scans", "indexes", "index", "index slows", "partial index"},
+			err:      nil,
+			mockArticleRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(a, nil)
+			},
+			mockRepoBehaviour: func(mockRepo *mocks.TagRepository) {
+				mockRepo.On("List", context.Background(), test.TestArticle.UserID).Return([]*tag.Tag{
+					a.Tags[0],
+					{ID: uuid.NewString(), UserID: test.TestArticle.UserID, Name: "bitmap scans", ArticleCount: 4},
+				}, nil)
+			},
+		},
+		{
+			name:     "should suggest the page's keywords saved with the article",
+			userID:   test.TestArticle.UserID,
+			expected: []string{"databases", "indexes", "performance", "index", "bitmap scans"},
+			err:      nil,
+			mockArticleRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(&withKeywords, nil)
+			},
+			mockRepoBehaviour: func(mockRepo *mocks.TagRepository) {
+				mockRepo.On("List", context.Background(), test.TestArticle.UserID).Return([]*tag.Tag{a.Tags[0]}, nil)
+			},
+		},
+		{
+			name:     "should return err when suggesting tags for another user's article",
+			userID:   uuid.NewString(),
+			expected: nil,
+			err:      validation.NewError(validation.Forbidden, "forbidden access"),
+			mockArticleRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(a, nil)
+			},
+			mockRepoBehaviour: func(mockRepo *mocks.TagRepository) {},
+		},
+		{
+			name:     "should return err when fail to fetch user's tags",
+			userID:   test.TestArticle.UserID,
+			expected: nil,
+			err:      errors.New("db: connection refused"),
+			mockArticleRepoBehaviour: func(mockRepo *mocks.ArticleRepository) {
+				mockRepo.On("FindByID", context.Background(), test.TestArticle.ID).Return(a, nil)
+			},
+			mockRepoBehaviour: func(mockRepo *mocks.TagRepository) {
+				mockRepo.On("List", context.Background(), test.TestArticle.UserID).
+					Return(nil, errors.New("db: connection refused"))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			articleRepo := new(mocks.ArticleRepository)
+			r := new(mocks.TagRepository)
+			c.mockArticleRepoBehaviour(articleRepo)
+			c.mockRepoBehaviour(r)
+
+			s := NewService(logger.NewLogger(), r, articleRepo)
+			names, err := s.SuggestArticleTags(context.Background(), c.userID, test.TestArticle.ID)
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.expected, names)
+			r.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteTag(t *testing.T) {
+	tagID := uuid.NewString()
+
+	cases := []struct {
+		name              string
+		err               error
+		mockRepoBehaviour func(mockRepo *mocks.TagRepository)
+	}{
+		{
+			name: "should delete user's tag",
+			err:  nil,
+			mockRepoBehaviour: func(mockRepo *mocks.TagRepository) {
+				mockRepo.On("Delete", context.Background(), test.TestUser.ID, tagID).Return(nil)
+			},
+		},
+		{
+			name: "should return err when tag doesn't exist",
+			err:  validation.NewError(validation.NotFound, "no tag found with given id"),
+			mockRepoBehaviour: func(mockRepo *mocks.TagRepository) {
+				mockRepo.On("Delete", context.Background(), test.TestUser.ID, tagID).
+					Return(validation.NewError(validation.NotFound, "no tag found with given id"))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := new(mocks.TagRepository)
+			c.mockRepoBehaviour(r)
+
+			s := NewService(logger.NewLogger(), r, new(mocks.ArticleRepository))
+			err := s.DeleteTag(context.Background(), test.TestUser.ID, tagID)
+			assert.Equal(t, c.err, err)
+		})
+	}
+}
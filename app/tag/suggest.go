@@ -0,0 +1,105 @@
+package tag
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ryanadiputraa/unclatter/pkg/nlp"
+)
+
+const (
+	// MaxSuggestions caps the tags suggested for an article.
+	MaxSuggestions = 5
+	// maxCandidatePhrases is how many keyword phrases of the text compete for a suggestion.
+	maxCandidatePhrases = 20
+	// maxTagWords leaves out longer keyword phrases, tags are a word or two.
+	maxTagWords = 2
+	// minSuggestionScore leaves out weak candidates, the most frequent word and the best keyword phrase of a text
+	// score 1 each.
+	minSuggestionScore = 0.4
+
+	// keywordScore is what a meta keyword of the page adds, authors pick them as the page's topics.
+	keywordScore = 1
+	// mentionScore is what an existing tag mentioned in the text adds even when it isn't a keyword phrase.
+	mentionScore = 0.5
+	// existingBoost multiplies the score of tags the user already has, on top of how often they're used.
+	existingBoost = 1.5
+)
+
+// Document is the text of an article tags are suggested for.
+type Document struct {
+	Title    string
+	Text     string
+	Language string
+	// Keywords are the meta keywords of the page, entries may be comma separated lists.
+	Keywords []string
+	// Assigned are the names of the tags the article already has, they're never suggested.
+	Assigned []string
+}
+
+// Suggest ranks tag names for the document by the frequency of its words, its RAKE keyword phrases and its meta
+// keywords. Tags the user already has are preferred the more articles they're used on, so suggestions follow the
+// user's own vocabulary.
+func Suggest(doc Document, existing []*Tag) []string {
+	text := doc.Title + "\n" + doc.Text
+	scores := make(map[string]float64)
+
+	frequency := make(map[string]int)
+	top := 0
+	for _, w := range nlp.ContentWords(text, doc.Language) {
+		frequency[w]++
+		top = max(top, frequency[w])
+	}
+	for w, n := range frequency {
+		scores[w] += float64(n) / float64(top)
+	}
+
+	phrases := nlp.KeyPhrases(text, doc.Language, maxCandidatePhrases)
+	for _, p := range phrases {
+		if len(strings.Fields(p.Text)) > 1 && len(strings.Fields(p.Text)) <= maxTagWords {
+			scores[NormalizeName(p.Text)] += p.Score / phrases[0].Score
+		}
+	}
+	for _, keywords := range doc.Keywords {
+		for _, k := range strings.Split(keywords, ",") {
+			if name := NormalizeName(k); name != "" {
+				scores[name] += keywordScore
+			}
+		}
+	}
+
+	words := " " + strings.Join(nlp.Words(text), " ") + " "
+	for _, t := range existing {
+		mentioned := strings.Contains(words, " "+strings.Join(nlp.Words(t.Name), " ")+" ")
+		if mentioned {
+			scores[t.Name] += mentionScore
+		}
+		if score, ok := scores[t.Name]; ok {
+			scores[t.Name] = score * existingBoost * (1 + math.Log1p(float64(t.ArticleCount)))
+		}
+	}
+
+	for _, name := range doc.Assigned {
+		delete(scores, NormalizeName(name))
+	}
+
+	names := make([]string, 0, len(scores))
+	for name, score := range scores {
+		if score < minSuggestionScore || utf8.RuneCountInString(name) > maxNameLength {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if scores[names[i]] != scores[names[j]] {
+			return scores[names[i]] > scores[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > MaxSuggestions {
+		names = names[:MaxSuggestions]
+	}
+	return names
+}
@@ -0,0 +1,75 @@
+package tag
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxNameLength is the longest tag name the name column holds.
+const maxNameLength = 64
+
+type Tag struct {
+	ID        string    `json:"id" gorm:"type:varchar"`
+	UserID    string    `json:"-" gorm:"type:varchar;not null;uniqueIndex:idx_tags_user_name,priority:1"`
+	Name      string    `json:"name" gorm:"type:varchar(64);not null;uniqueIndex:idx_tags_user_name,priority:2"`
+	CreatedAt time.Time `json:"created_at" gorm:"type:timestamptz;not null"`
+
+	// ArticleCount is only populated when listing the user's tags.
+	ArticleCount int64 `json:"article_count" gorm:"->;-:migration"`
+}
+
+type TagsPayload struct {
+	Names []string `json:"names" validate:"max=50,dive,required,max=64"`
+}
+
+func NewTag(userID, name string) *Tag {
+	return &Tag{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		Name:      NormalizeName(name),
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// NormalizeName lowercases the name and collapses whitespace so "Go  Lang" and "go lang" are the same tag.
+func NormalizeName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
+
+// NewTags returns a tag for every distinct normalized name, blank names are skipped.
+func NewTags(userID string, names []string) []Tag {
+	seen := make(map[string]bool)
+	tags := make([]Tag, 0, len(names))
+	for _, name := range names {
+		t := NewTag(userID, name)
+		if t.Name == "" || seen[t.Name] {
+			continue
+		}
+		seen[t.Name] = true
+		tags = append(tags, *t)
+	}
+	return tags
+}
+
+type TagService interface {
+	ListTags(ctx context.Context, userID string) ([]*Tag, error)
+	SetArticleTags(ctx context.Context, userID, articleID string, arg TagsPayload) ([]*Tag, error)
+	// AddArticleTags adds the tags to the ones the article has, suggested tags are accepted with it.
+	AddArticleTags(ctx context.Context, userID, articleID string, arg TagsPayload) ([]*Tag, error)
+	// SuggestArticleTags suggests tag names for the article's current content, leaving out the tags it has.
+	SuggestArticleTags(ctx context.Context, userID, articleID string) ([]string, error)
+	DeleteTag(ctx context.Context, userID, tagID string) error
+}
+
+type TagRepository interface {
+	List(ctx context.Context, userID string) ([]*Tag, error)
+	// ReplaceArticleTags creates the tags the user doesn't have yet and makes them the article's only tags.
+	ReplaceArticleTags(ctx context.Context, userID, articleID string, tags []Tag) ([]*Tag, error)
+	// AddArticleTags creates the tags the user doesn't have yet and adds them to the article, it returns every tag
+	// of the article.
+	AddArticleTags(ctx context.Context, userID, articleID string, tags []Tag) ([]*Tag, error)
+	Delete(ctx context.Context, userID, tagID string) error
+}
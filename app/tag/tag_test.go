@@ -0,0 +1,91 @@
+package tag
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTag(t *testing.T) {
+	userID := uuid.NewString()
+
+	cases := []struct {
+		name     string
+		arg      string
+		expected string
+	}{
+		{
+			name:     "should lowercase tag name",
+			arg:      "GoLang",
+			expected: "golang",
+		},
+		{
+			name:     "should collapse whitespace in tag name",
+			arg:      "  Machine \t Learning ",
+			expected: "machine learning",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tag := NewTag(userID, c.arg)
+
+			assert.NotEmpty(t, tag.ID)
+			assert.Equal(t, userID, tag.UserID)
+			assert.Equal(t, c.expected, tag.Name)
+			assert.NotEmpty(t, tag.CreatedAt)
+		})
+	}
+}
+
+func TestNewTags(t *testing.T) {
+	userID := uuid.NewString()
+
+	tags := NewTags(userID, []string{"Go", "go ", " ", "Databases"})
+
+	assert.Len(t, tags, 2)
+	assert.Equal(t, "go", tags[0].Name)
+	assert.Equal(t, "databases", tags[1].Name)
+}
+
+func TestSuggest(t *testing.T) {
+	doc := Document{
+		Title:    "Tuning Postgres indexes",
+		Text:     "Postgres indexes speed up queries, but every index slows down writes. A partial index only covers the rows a query needs, and Postgres can combine several indexes with bitmap scans.",
+		Language: "english",
+	}
+
+	cases := []struct {
+		name     string
+		keywords []string
+		assigned []string
+		existing []*Tag
+		expected []string
+	}{
+		{
+			name:     "should suggest frequent words and short keyword phrases",
+			expected: []string{"indexes", "postgres", "index", "bitmap scans", "index slows"},
+		},
+		{
+			name:     "should prefer meta keywords and the user's existing tags mentioned in the text",
+			keywords: []string{"Databases,performance", " "},
+			existing: []*Tag{{Name: "bitmap scans", ArticleCount: 4}, {Name: "cooking", ArticleCount: 3}},
+			expected: []string{"bitmap scans", "databases", "indexes", "performance", "postgres"},
+		},
+		{
+			name:     "should leave out tags the article already has",
+			assigned: []string{"Postgres", "indexes"},
+			expected: []string{"index", "bitmap scans", "index slows", "partial index", "query needs"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := doc
+			d.Keywords = c.keywords
+			d.Assigned = c.assigned
+			assert.Equal(t, c.expected, Suggest(d, c.existing))
+		})
+	}
+}
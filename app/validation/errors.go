@@ -11,7 +11,10 @@ const (
 	Unauthorized = "unauthorized"
 	Forbidden    = "forbidden"
 	NotFound     = "not_found"
+	Conflict     = "conflict"
 	ServerErr    = "server_err"
+	// PreconditionFailed is returned when a conditional request doesn't match the current state of the resource.
+	PreconditionFailed = "precondition_failed"
 
 	// Oauth errror
 	InvalidCallbackParam = "invalid_callback_param"
@@ -24,7 +27,10 @@ var (
 		Unauthorized: http.StatusUnauthorized,
 		Forbidden:    http.StatusForbidden,
 		NotFound:     http.StatusNotFound,
+		Conflict:     http.StatusConflict,
 		ServerErr:    http.StatusInternalServerError,
+
+		PreconditionFailed: http.StatusPreconditionFailed,
 	}
 )
 
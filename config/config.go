@@ -1,6 +1,12 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
 	"github.com/spf13/viper"
 )
 
@@ -9,11 +15,23 @@ type Config struct {
 	*Postgres    `mapstructure:"postgres"`
 	*GoogleOauth `mapstructure:"google_oauth"`
 	*JWT         `mapstructure:"jwt"`
+	*Trash       `mapstructure:"trash"`
+	*Import      `mapstructure:"import"`
+	*SMTP        `mapstructure:"smtp"`
+	*Delivery    `mapstructure:"delivery"`
+	*Digest      `mapstructure:"digest"`
+	*Related     `mapstructure:"related"`
+	*Sanitizer   `mapstructure:"sanitizer"`
+	*Reminder    `mapstructure:"reminder"`
+	*LinkCheck   `mapstructure:"link_check"`
+	*Export      `mapstructure:"export"`
 }
 
 type Server struct {
 	Port        int    `mapstructure:"port"`
 	FrontendURL string `mapstructure:"fe_url"`
+	// BaseURL is the public api url emailed links point to.
+	BaseURL string `mapstructure:"base_url"`
 }
 
 type Postgres struct {
@@ -29,6 +47,69 @@ type JWT struct {
 	Secret string `mapstructure:"secret"`
 }
 
+type Trash struct {
+	Retention     time.Duration `mapstructure:"retention"`
+	PurgeInterval time.Duration `mapstructure:"purge_interval"`
+}
+
+type Import struct {
+	ScrapeInterval  time.Duration `mapstructure:"scrape_interval"`
+	ScrapeBatchSize int           `mapstructure:"scrape_batch_size"`
+}
+
+type SMTP struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	// From has to be an approved sender for e-reader inboxes.
+	From string `mapstructure:"from"`
+	// TLS is one of starttls, tls or none.
+	TLS     string        `mapstructure:"tls"`
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+type Delivery struct {
+	SendInterval time.Duration `mapstructure:"send_interval"`
+	BatchSize    int           `mapstructure:"batch_size"`
+}
+
+type Digest struct {
+	SendInterval time.Duration `mapstructure:"send_interval"`
+	BatchSize    int           `mapstructure:"batch_size"`
+}
+
+type Related struct {
+	IndexInterval  time.Duration `mapstructure:"index_interval"`
+	IndexBatchSize int           `mapstructure:"index_batch_size"`
+}
+
+type Sanitizer struct {
+	MigrateInterval  time.Duration `mapstructure:"migrate_interval"`
+	MigrateBatchSize int           `mapstructure:"migrate_batch_size"`
+}
+
+type Reminder struct {
+	SendInterval   time.Duration `mapstructure:"send_interval"`
+	BatchSize      int           `mapstructure:"batch_size"`
+	WebhookTimeout time.Duration `mapstructure:"webhook_timeout"`
+}
+
+type LinkCheck struct {
+	Interval     time.Duration `mapstructure:"interval"`
+	BatchSize    int           `mapstructure:"batch_size"`
+	RecheckAfter time.Duration `mapstructure:"recheck_after"`
+	HostDelay    time.Duration `mapstructure:"host_delay"`
+	Timeout      time.Duration `mapstructure:"timeout"`
+}
+
+type Export struct {
+	Dir           string        `mapstructure:"dir"`
+	Retention     time.Duration `mapstructure:"retention"`
+	BuildInterval time.Duration `mapstructure:"build_interval"`
+	BatchSize     int           `mapstructure:"batch_size"`
+}
+
 type GoogleOauth struct {
 	RedirectURL  string `mapstructure:"redirect_url"`
 	ClientID     string `mapstructure:"client_id"`
@@ -39,6 +120,33 @@ type GoogleOauth struct {
 func LoadConfig(configType, filePath string) (*Config, error) {
 	viper.SetConfigType(configType)
 	viper.SetConfigFile(filePath)
+	viper.SetDefault("trash.retention", "720h")
+	viper.SetDefault("trash.purge_interval", "1h")
+	viper.SetDefault("import.scrape_interval", "30s")
+	viper.SetDefault("import.scrape_batch_size", 20)
+	viper.SetDefault("smtp.port", 587)
+	viper.SetDefault("smtp.tls", "starttls")
+	viper.SetDefault("smtp.timeout", "30s")
+	viper.SetDefault("delivery.send_interval", "15s")
+	viper.SetDefault("delivery.batch_size", 10)
+	viper.SetDefault("digest.send_interval", "5m")
+	viper.SetDefault("digest.batch_size", 100)
+	viper.SetDefault("related.index_interval", "1m")
+	viper.SetDefault("related.index_batch_size", 200)
+	viper.SetDefault("sanitizer.migrate_interval", "10m")
+	viper.SetDefault("sanitizer.migrate_batch_size", 200)
+	viper.SetDefault("reminder.send_interval", "1m")
+	viper.SetDefault("reminder.batch_size", 100)
+	viper.SetDefault("reminder.webhook_timeout", "10s")
+	viper.SetDefault("link_check.interval", "5m")
+	viper.SetDefault("link_check.batch_size", 50)
+	viper.SetDefault("link_check.recheck_after", "168h")
+	viper.SetDefault("link_check.host_delay", "2s")
+	viper.SetDefault("link_check.timeout", "15s")
+	viper.SetDefault("export.dir", filepath.Join(os.TempDir(), "unclatter-exports"))
+	viper.SetDefault("export.retention", "24h")
+	viper.SetDefault("export.build_interval", "30s")
+	viper.SetDefault("export.batch_size", 2)
 	if err := viper.ReadInConfig(); err != nil {
 		return nil, err
 	}
@@ -47,5 +155,59 @@ func LoadConfig(configType, filePath string) (*Config, error) {
 	if err := viper.Unmarshal(&config); err != nil {
 		return nil, err
 	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
 	return config, nil
 }
+
+// validate rejects settings the server would only fail on later.
+func (c *Config) validate() error {
+	// digest unsubscribe links point to the api
+	if c.SMTP != nil && c.SMTP.Host != "" && (c.Server == nil || c.Server.BaseURL == "") {
+		return errors.New("server.base_url is required to send digest emails")
+	}
+
+	durations := []struct {
+		key      string
+		duration time.Duration
+	}{
+		{"trash.retention", c.Trash.Retention},
+		{"trash.purge_interval", c.Trash.PurgeInterval},
+		{"import.scrape_interval", c.Import.ScrapeInterval},
+		{"delivery.send_interval", c.Delivery.SendInterval},
+		{"digest.send_interval", c.Digest.SendInterval},
+		{"related.index_interval", c.Related.IndexInterval},
+		{"sanitizer.migrate_interval", c.Sanitizer.MigrateInterval},
+		{"reminder.send_interval", c.Reminder.SendInterval},
+		{"link_check.interval", c.LinkCheck.Interval},
+		{"link_check.recheck_after", c.LinkCheck.RecheckAfter},
+		{"export.retention", c.Export.Retention},
+		{"export.build_interval", c.Export.BuildInterval},
+	}
+	for _, d := range durations {
+		if d.duration <= 0 {
+			return fmt.Errorf("%s should be a positive duration", d.key)
+		}
+	}
+
+	batchSizes := []struct {
+		key  string
+		size int
+	}{
+		{"import.scrape_batch_size", c.Import.ScrapeBatchSize},
+		{"delivery.batch_size", c.Delivery.BatchSize},
+		{"digest.batch_size", c.Digest.BatchSize},
+		{"related.index_batch_size", c.Related.IndexBatchSize},
+		{"sanitizer.migrate_batch_size", c.Sanitizer.MigrateBatchSize},
+		{"reminder.batch_size", c.Reminder.BatchSize},
+		{"link_check.batch_size", c.LinkCheck.BatchSize},
+		{"export.batch_size", c.Export.BatchSize},
+	}
+	for _, b := range batchSizes {
+		if b.size <= 0 {
+			return fmt.Errorf("%s should be a positive number", b.key)
+		}
+	}
+	return nil
+}
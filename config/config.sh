@@ -16,12 +16,25 @@ google_client_id="${10}"
 google_client_secret="${11}"
 google_state="${12}"
 
+trash_retention="${13:-720h}"
+trash_purge_interval="${14:-1h}"
+
+server_base_url="${15}"
+
+smtp_host="${16}"
+smtp_port="${17:-587}"
+smtp_username="${18}"
+smtp_password="${19}"
+smtp_from="${20}"
+smtp_tls="${21:-starttls}"
+
 
 # Define the YAML content with placeholders replaced by command line arguments
 YAML_CONTENT="
 server:
   port: $server_port
   fe_url: $server_fe_url
+  base_url: $server_base_url
 
 postgres:
   host: $postgres_host
@@ -34,6 +47,18 @@ postgres:
 jwt:
   secret: $jwt_secret
 
+trash:
+  retention: $trash_retention
+  purge_interval: $trash_purge_interval
+
+smtp:
+  host: $smtp_host
+  port: $smtp_port
+  username: \"$smtp_username\"
+  password: \"$smtp_password\"
+  from: \"$smtp_from\"
+  tls: $smtp_tls
+
 google_oauth:
   redirect_url: $google_redirect_url
   client_id: $google_client_id
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newConfig returns the defaults LoadConfig sets for the background jobs.
+func newConfig() Config {
+	return Config{
+		Server:    &Server{},
+		SMTP:      &SMTP{},
+		Trash:     &Trash{Retention: 720 * time.Hour, PurgeInterval: time.Hour},
+		Import:    &Import{ScrapeInterval: 30 * time.Second, ScrapeBatchSize: 20},
+		Delivery:  &Delivery{SendInterval: 15 * time.Second, BatchSize: 10},
+		Digest:    &Digest{SendInterval: 5 * time.Minute, BatchSize: 100},
+		Related:   &Related{IndexInterval: time.Minute, IndexBatchSize: 200},
+		Sanitizer: &Sanitizer{MigrateInterval: 10 * time.Minute, MigrateBatchSize: 200},
+		Reminder:  &Reminder{SendInterval: time.Minute, BatchSize: 100},
+		LinkCheck: &LinkCheck{Interval: 5 * time.Minute, BatchSize: 50, RecheckAfter: 168 * time.Hour},
+		Export:    &Export{Retention: 24 * time.Hour, BuildInterval: 30 * time.Second, BatchSize: 2},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name   string
+		config func(c *Config)
+		err    error
+	}{
+		{
+			name: "should accept digests with base url",
+			config: func(c *Config) {
+				c.Server.BaseURL = "https://api.unclatter.com"
+				c.SMTP.Host = "smtp.unclatter.com"
+			},
+			err: nil,
+		},
+		{
+			name:   "should accept missing base url when smtp isn't set",
+			config: func(c *Config) {},
+			err:    nil,
+		},
+		{
+			name: "should return err when digests are sent without base url",
+			config: func(c *Config) {
+				c.SMTP.Host = "smtp.unclatter.com"
+			},
+			err: errors.New("server.base_url is required to send digest emails"),
+		},
+		{
+			name: "should return err when a job interval is zero",
+			config: func(c *Config) {
+				c.LinkCheck.Interval = 0
+			},
+			err: errors.New("link_check.interval should be a positive duration"),
+		},
+		{
+			name: "should return err when a job interval is negative",
+			config: func(c *Config) {
+				c.Trash.PurgeInterval = -time.Hour
+			},
+			err: errors.New("trash.purge_interval should be a positive duration"),
+		},
+		{
+			name: "should return err when the trash retention is zero",
+			config: func(c *Config) {
+				c.Trash.Retention = 0
+			},
+			err: errors.New("trash.retention should be a positive duration"),
+		},
+		{
+			name: "should return err when the link recheck delay is zero",
+			config: func(c *Config) {
+				c.LinkCheck.RecheckAfter = 0
+			},
+			err: errors.New("link_check.recheck_after should be a positive duration"),
+		},
+		{
+			name: "should return err when a batch size is zero",
+			config: func(c *Config) {
+				c.Delivery.BatchSize = 0
+			},
+			err: errors.New("delivery.batch_size should be a positive number"),
+		},
+		{
+			name: "should return err when the export interval is zero",
+			config: func(c *Config) {
+				c.Export.BuildInterval = 0
+			},
+			err: errors.New("export.build_interval should be a positive duration"),
+		},
+		{
+			name: "should return err when the export retention is negative",
+			config: func(c *Config) {
+				c.Export.Retention = -time.Hour
+			},
+			err: errors.New("export.retention should be a positive duration"),
+		},
+		{
+			name: "should return err when a batch size is negative",
+			config: func(c *Config) {
+				c.Import.ScrapeBatchSize = -1
+			},
+			err: errors.New("import.scrape_batch_size should be a positive number"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			config := newConfig()
+			c.config(&config)
+			assert.Equal(t, c.err, config.validate())
+		})
+	}
+}
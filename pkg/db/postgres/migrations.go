@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ryanadiputraa/unclatter/app/article"
+	"gorm.io/gorm"
+)
+
+const backfillBatchSize = 500
+
+// statements run on every start, each one has to be idempotent.
+var statements = []string{
+	// bookmarks used to be unique by title across every user
+	`ALTER TABLE articles DROP CONSTRAINT IF EXISTS articles_title_key`,
+	`ALTER TABLE articles DROP CONSTRAINT IF EXISTS uni_articles_title`,
+	// replaced by idx_articles_active_user_link
+	`DROP INDEX IF EXISTS idx_articles_user_link`,
+}
+
+type backfill struct {
+	name string
+	run  func(db *gorm.DB) error
+}
+
+// backfill names are recorded in schema_migrations and must never change.
+var backfills = []backfill{
+	{name: "backfill_articles_normalized_link", run: backfillNormalizedLinks},
+	// the expressions match article.LinkDomain and article.ReadingTime
+	{name: "backfill_articles_domain", run: exec(`UPDATE articles SET domain = coalesce(lower(regexp_replace(substring(article_link from '^[A-Za-z][A-Za-z0-9+.-]*://(?:[^@/]*@)?([^/:?#]+)'), '^www\.', '')), '')
+		WHERE domain = '' AND article_link ~ '^[A-Za-z][A-Za-z0-9+.-]*://[^/?#]'`)},
+	{name: "backfill_articles_reading_time", run: exec(`UPDATE articles SET reading_time = GREATEST(1, CEIL(coalesce(array_length(regexp_split_to_array(btrim(regexp_replace(content, '<[^>]+>', ' ', 'g')), '\s+'), 1), 0) / 200.0))
+		WHERE reading_time = 0`)},
+}
+
+type appliedMigration struct {
+	Name      string    `gorm:"type:varchar;primaryKey"`
+	AppliedAt time.Time `gorm:"type:timestamptz;not null"`
+}
+
+func (appliedMigration) TableName() string {
+	return "schema_migrations"
+}
+
+func exec(stmt string) func(db *gorm.DB) error {
+	return func(db *gorm.DB) error {
+		return db.Exec(stmt).Error
+	}
+}
+
+func migrate(db *gorm.DB) error {
+	for _, stmt := range statements {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := db.AutoMigrate(&appliedMigration{}); err != nil {
+		return err
+	}
+	var applied []string
+	if err := db.Model(&appliedMigration{}).Pluck("name", &applied).Error; err != nil {
+		return err
+	}
+	return applyBackfills(db, applied)
+}
+
+// applyBackfills runs outside a transaction, so each backfill must be safe to repeat.
+func applyBackfills(db *gorm.DB, applied []string) error {
+	done := make(map[string]bool, len(applied))
+	for _, name := range applied {
+		done[name] = true
+	}
+
+	for _, b := range backfills {
+		if done[b.name] {
+			continue
+		}
+		if err := b.run(db); err != nil {
+			return err
+		}
+		if err := db.Create(&appliedMigration{Name: b.name, AppliedAt: time.Now().UTC()}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillNormalizedLinks leaves duplicates saved before the unique index without a normalized link.
+func backfillNormalizedLinks(db *gorm.DB) error {
+	var lastID string
+	for {
+		var articles []*article.Article
+		err := db.Select("id, article_link, user_id").
+			Where("normalized_link IS NULL AND id > ?", lastID).
+			Order("id").
+			Limit(backfillBatchSize).
+			Find(&articles).Error
+		if err != nil || len(articles) == 0 {
+			return err
+		}
+
+		for _, a := range articles {
+			normalized, err := article.NormalizeLink(a.ArticleLink)
+			if err != nil {
+				continue
+			}
+			err = db.Model(a).UpdateColumn("normalized_link", normalized).Error
+			if err != nil && !errors.Is(err, gorm.ErrDuplicatedKey) {
+				return err
+			}
+		}
+		lastID = articles[len(articles)-1].ID
+	}
+}
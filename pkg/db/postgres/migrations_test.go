@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ryanadiputraa/unclatter/test"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestApplyBackfills(t *testing.T) {
+	readingTimeQuery := "^UPDATE articles SET reading_time = (.+) WHERE reading_time = 0$"
+	recordQuery := "^INSERT INTO \"schema_migrations\" \\(\"name\",\"applied_at\"\\) VALUES"
+
+	cases := []struct {
+		name          string
+		applied       []string
+		mockBehaviour func(mock sqlmock.Sqlmock)
+		err           error
+	}{
+		{
+			name:          "should skip every applied backfill",
+			applied:       []string{"backfill_articles_normalized_link", "backfill_articles_domain", "backfill_articles_reading_time"},
+			mockBehaviour: func(mock sqlmock.Sqlmock) {},
+			err:           nil,
+		},
+		{
+			name:    "should run and record the backfills that weren't applied",
+			applied: []string{"backfill_articles_normalized_link", "backfill_articles_domain"},
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(readingTimeQuery).
+					WillReturnResult(sqlmock.NewResult(0, 12))
+				mock.ExpectBegin()
+				mock.ExpectExec(recordQuery).
+					WithArgs("backfill_articles_reading_time", test.AnyTime{}).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			err: nil,
+		},
+		{
+			name:    "should not record a backfill that failed",
+			applied: []string{"backfill_articles_normalized_link", "backfill_articles_domain"},
+			mockBehaviour: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(readingTimeQuery).
+					WillReturnError(gorm.ErrInvalidDB)
+			},
+			err: gorm.ErrInvalidDB,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			gormDB, db, mock := test.NewMockDB(t)
+			defer db.Close()
+
+			c.mockBehaviour(mock)
+			err := applyBackfills(gormDB, c.applied)
+			assert.Equal(t, c.err, err)
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -9,6 +9,17 @@ import (
 
 	"github.com/ryanadiputraa/unclatter/app/article"
 	"github.com/ryanadiputraa/unclatter/app/auth"
+	"github.com/ryanadiputraa/unclatter/app/collection"
+	"github.com/ryanadiputraa/unclatter/app/delivery"
+	"github.com/ryanadiputraa/unclatter/app/digest"
+	"github.com/ryanadiputraa/unclatter/app/export"
+	"github.com/ryanadiputraa/unclatter/app/highlight"
+	"github.com/ryanadiputraa/unclatter/app/importer"
+	"github.com/ryanadiputraa/unclatter/app/linkhealth"
+	"github.com/ryanadiputraa/unclatter/app/progress"
+	"github.com/ryanadiputraa/unclatter/app/reminder"
+	"github.com/ryanadiputraa/unclatter/app/share"
+	"github.com/ryanadiputraa/unclatter/app/tag"
 	"github.com/ryanadiputraa/unclatter/app/user"
 	"github.com/ryanadiputraa/unclatter/config"
 	"gorm.io/driver/postgres"
@@ -51,7 +62,10 @@ func NewDB(c *config.Config) (*gorm.DB, error) {
 		return nil, err
 	}
 
-	gormDB.AutoMigrate(&user.User{}, &auth.AuthProvider{}, &article.Article{})
+	gormDB.AutoMigrate(&user.User{}, &auth.AuthProvider{}, &tag.Tag{}, &collection.Collection{}, &collection.Member{}, &article.Article{}, &article.Revision{}, &article.Term{}, &importer.Job{}, &importer.Item{}, &progress.ReadingProgress{}, &highlight.Highlight{}, &share.Share{}, &delivery.Address{}, &delivery.Delivery{}, &digest.Preference{}, &reminder.Reminder{}, &reminder.Webhook{}, &linkhealth.Link{}, &linkhealth.Check{}, &export.Job{})
+	if err = migrate(gormDB); err != nil {
+		return nil, err
+	}
 
 	return gormDB, err
 }
@@ -0,0 +1,100 @@
+package diff
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLines(t *testing.T) {
+	cases := []struct {
+		name     string
+		a        string
+		b        string
+		expected []Line
+	}{
+		{
+			name:     "should return no lines when both are empty",
+			a:        "",
+			b:        "",
+			expected: []Line{},
+		},
+		{
+			name:     "should insert every line when a is empty",
+			a:        "",
+			b:        "one\ntwo",
+			expected: []Line{{Op: Insert, Text: "one"}, {Op: Insert, Text: "two"}},
+		},
+		{
+			name:     "should delete every line when b is empty",
+			a:        "one\ntwo",
+			b:        "",
+			expected: []Line{{Op: Delete, Text: "one"}, {Op: Delete, Text: "two"}},
+		},
+		{
+			name:     "should keep every line when identical",
+			a:        "one\ntwo\nthree",
+			b:        "one\ntwo\nthree",
+			expected: []Line{{Op: Equal, Text: "one"}, {Op: Equal, Text: "two"}, {Op: Equal, Text: "three"}},
+		},
+		{
+			name:     "should ignore a trailing newline",
+			a:        "one\ntwo\n",
+			b:        "one\ntwo",
+			expected: []Line{{Op: Equal, Text: "one"}, {Op: Equal, Text: "two"}},
+		},
+		{
+			name: "should replace every line when fully replaced",
+			a:    "one\ntwo",
+			b:    "three\nfour",
+			expected: []Line{
+				{Op: Delete, Text: "one"}, {Op: Delete, Text: "two"},
+				{Op: Insert, Text: "three"}, {Op: Insert, Text: "four"},
+			},
+		},
+		{
+			name: "should keep common lines around an edit",
+			a:    "title\nold paragraph\nfooter",
+			b:    "title\nnew paragraph\nfooter",
+			expected: []Line{
+				{Op: Equal, Text: "title"},
+				{Op: Delete, Text: "old paragraph"}, {Op: Insert, Text: "new paragraph"},
+				{Op: Equal, Text: "footer"},
+			},
+		},
+		{
+			name: "should find the shortest script for interleaved edits",
+			a:    "a\nb\nc\na\nb\nb\na",
+			b:    "c\nb\na\nb\na\nc",
+			expected: []Line{
+				{Op: Delete, Text: "a"}, {Op: Delete, Text: "b"}, {Op: Equal, Text: "c"}, {Op: Insert, Text: "b"},
+				{Op: Equal, Text: "a"}, {Op: Equal, Text: "b"}, {Op: Delete, Text: "b"}, {Op: Equal, Text: "a"},
+				{Op: Insert, Text: "c"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, Lines(c.a, c.b))
+		})
+	}
+}
+
+func TestDiffBeyondMaxEdits(t *testing.T) {
+	a := make([]string, maxEdits)
+	b := make([]string, maxEdits)
+	for i := range a {
+		a[i] = "a" + strconv.Itoa(i)
+		b[i] = "b" + strconv.Itoa(i)
+	}
+	a = append([]string{"header"}, a...)
+	b = append([]string{"header"}, b...)
+
+	lines := Diff(a, b)
+	assert.Len(t, lines, 1+2*maxEdits)
+	assert.Equal(t, Line{Op: Equal, Text: "header"}, lines[0])
+	assert.Equal(t, Line{Op: Delete, Text: "a0"}, lines[1])
+	assert.Equal(t, Line{Op: Insert, Text: "b0"}, lines[1+maxEdits])
+}
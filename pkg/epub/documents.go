@@ -0,0 +1,263 @@
+package epub
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const containerDocument = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+`
+
+// stylesheet is tuned for e-ink screens, no backgrounds or greys that ghost.
+const stylesheet = `body {
+  margin: 0 0.5em;
+  font-family: serif;
+  line-height: 1.5;
+  color: #000;
+  background: transparent;
+  hyphens: auto;
+  -webkit-hyphens: auto;
+}
+h1, h2, h3, h4, h5, h6 {
+  font-family: sans-serif;
+  line-height: 1.25;
+  text-align: left;
+  hyphens: none;
+  -webkit-hyphens: none;
+  page-break-after: avoid;
+  break-after: avoid;
+}
+h1 { font-size: 1.6em; margin: 1em 0 0.25em; }
+p { margin: 0 0 0.8em; }
+a { color: #000; text-decoration: underline; }
+img {
+  display: block;
+  max-width: 100%;
+  height: auto;
+  margin: 1em auto;
+  page-break-inside: avoid;
+  break-inside: avoid;
+}
+blockquote {
+  margin: 1em 0 1em 0.5em;
+  padding-left: 0.75em;
+  border-left: 2px solid #000;
+  font-style: italic;
+}
+pre, code { font-family: monospace; font-size: 0.85em; }
+pre {
+  white-space: pre-wrap;
+  word-wrap: break-word;
+  border: 1px solid #000;
+  padding: 0.5em;
+  page-break-inside: avoid;
+  break-inside: avoid;
+}
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #000; padding: 0.25em 0.5em; }
+hr { border: 0; border-top: 1px solid #000; }
+.byline { font-size: 0.85em; margin-bottom: 2em; }
+.cover { text-align: center; margin: 0; padding: 0; }
+.cover img { width: 100%; max-height: 100%; margin: 0; }
+nav ol { list-style: none; padding: 0; }
+nav li { margin: 0.5em 0; }
+`
+
+func chapterDocument(c Chapter, body string) string {
+	var b strings.Builder
+	b.WriteString(documentHead(c.Language, c.Title, "../style.css"))
+	b.WriteString("<section epub:type=\"chapter\">\n")
+	fmt.Fprintf(&b, "<h1>%s</h1>\n", esc(c.Title))
+	if c.Byline != "" {
+		byline := esc(c.Byline)
+		if c.Source != "" {
+			byline = fmt.Sprintf("<a href=\"%s\">%s</a>", esc(c.Source), byline)
+		}
+		fmt.Fprintf(&b, "<p class=\"byline\">%s</p>\n", byline)
+	}
+	b.WriteString(body)
+	b.WriteString("\n</section>\n</body>\n</html>\n")
+	return b.String()
+}
+
+func coverDocument(meta Metadata) string {
+	return documentHead(meta.Language, meta.Title, "style.css") +
+		"<section class=\"cover\" epub:type=\"cover\">\n" +
+		fmt.Sprintf("<img src=\"images/cover.svg\" alt=\"%s\"/>\n", esc(meta.Title)) +
+		"</section>\n</body>\n</html>\n"
+}
+
+func navDocument(meta Metadata, chapters []entry) string {
+	var b strings.Builder
+	b.WriteString(documentHead(meta.Language, meta.Title, "style.css"))
+	b.WriteString("<nav epub:type=\"toc\" id=\"toc\">\n<h1>Contents</h1>\n<ol>\n")
+	for _, c := range chapters {
+		fmt.Fprintf(&b, "<li><a href=\"%s\">%s</a></li>\n", c.path, esc(c.title))
+	}
+	b.WriteString("</ol>\n</nav>\n</body>\n</html>\n")
+	return b.String()
+}
+
+// ncxDocument is the EPUB 2 table of contents, older e-readers only read this one.
+func ncxDocument(meta Metadata, chapters []entry) string {
+	var b strings.Builder
+	b.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	b.WriteString("<ncx xmlns=\"http://www.daisy.org/z3986/2005/ncx/\" version=\"2005-1\">\n<head>\n")
+	fmt.Fprintf(&b, "<meta name=\"dtb:uid\" content=\"%s\"/>\n", esc(identifier(meta)))
+	b.WriteString("<meta name=\"dtb:depth\" content=\"1\"/>\n</head>\n")
+	fmt.Fprintf(&b, "<docTitle><text>%s</text></docTitle>\n<navMap>\n", esc(meta.Title))
+	for i, c := range chapters {
+		fmt.Fprintf(&b, "<navPoint id=\"nav-%s\" playOrder=\"%d\"><navLabel><text>%s</text></navLabel><content src=\"%s\"/></navPoint>\n",
+			c.id, i+1, esc(c.title), c.path)
+	}
+	b.WriteString("</navMap>\n</ncx>\n")
+	return b.String()
+}
+
+func packageDocument(meta Metadata, chapters, assets []entry) string {
+	var b strings.Builder
+	b.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	fmt.Fprintf(&b, "<package xmlns=\"http://www.idpf.org/2007/opf\" version=\"3.0\" unique-identifier=\"book-id\" xml:lang=\"%s\">\n", esc(meta.Language))
+
+	b.WriteString("<metadata xmlns:dc=\"http://purl.org/dc/elements/1.1/\">\n")
+	fmt.Fprintf(&b, "<dc:identifier id=\"book-id\">%s</dc:identifier>\n", esc(identifier(meta)))
+	fmt.Fprintf(&b, "<dc:title>%s</dc:title>\n", esc(meta.Title))
+	fmt.Fprintf(&b, "<dc:language>%s</dc:language>\n", esc(meta.Language))
+	if meta.Creator != "" {
+		fmt.Fprintf(&b, "<dc:creator>%s</dc:creator>\n", esc(meta.Creator))
+	}
+	if meta.Source != "" {
+		fmt.Fprintf(&b, "<dc:source>%s</dc:source>\n", esc(meta.Source))
+	}
+	if meta.Description != "" {
+		fmt.Fprintf(&b, "<dc:description>%s</dc:description>\n", esc(meta.Description))
+	}
+	for _, s := range meta.Subjects {
+		fmt.Fprintf(&b, "<dc:subject>%s</dc:subject>\n", esc(s))
+	}
+	if !meta.Date.IsZero() {
+		fmt.Fprintf(&b, "<dc:date>%s</dc:date>\n", formatTime(meta.Date))
+	}
+	fmt.Fprintf(&b, "<meta property=\"dcterms:modified\">%s</meta>\n", formatTime(time.Now()))
+	b.WriteString("<meta name=\"cover\" content=\"cover-image\"/>\n</metadata>\n")
+
+	b.WriteString("<manifest>\n")
+	b.WriteString("<item id=\"nav\" href=\"nav.xhtml\" media-type=\"application/xhtml+xml\" properties=\"nav\"/>\n")
+	b.WriteString("<item id=\"ncx\" href=\"toc.ncx\" media-type=\"application/x-dtbncx+xml\"/>\n")
+	b.WriteString("<item id=\"style\" href=\"style.css\" media-type=\"text/css\"/>\n")
+	b.WriteString("<item id=\"cover-image\" href=\"images/cover.svg\" media-type=\"image/svg+xml\" properties=\"cover-image\"/>\n")
+	b.WriteString("<item id=\"cover\" href=\"cover.xhtml\" media-type=\"application/xhtml+xml\"/>\n")
+	for _, entries := range [][]entry{chapters, assets} {
+		for _, e := range entries {
+			fmt.Fprintf(&b, "<item id=\"%s\" href=\"%s\" media-type=\"%s\"/>\n", e.id, e.path, e.mediaType)
+		}
+	}
+	b.WriteString("</manifest>\n")
+
+	b.WriteString("<spine toc=\"ncx\">\n<itemref idref=\"cover\"/>\n<itemref idref=\"nav\"/>\n")
+	for _, c := range chapters {
+		fmt.Fprintf(&b, "<itemref idref=\"%s\"/>\n", c.id)
+	}
+	b.WriteString("</spine>\n</package>\n")
+	return b.String()
+}
+
+const (
+	coverWidth      = 600
+	coverHeight     = 800
+	coverLineLength = 20
+	coverMaxLines   = 7
+)
+
+func coverImage(meta Metadata) string {
+	var b strings.Builder
+	b.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	fmt.Fprintf(&b, "<svg xmlns=\"http://www.w3.org/2000/svg\" version=\"1.1\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n",
+		coverWidth, coverHeight, coverWidth, coverHeight)
+	fmt.Fprintf(&b, "<rect width=\"%d\" height=\"%d\" fill=\"#fff\"/>\n", coverWidth, coverHeight)
+	fmt.Fprintf(&b, "<rect x=\"30\" y=\"30\" width=\"%d\" height=\"%d\" fill=\"none\" stroke=\"#000\" stroke-width=\"4\"/>\n", coverWidth-60, coverHeight-60)
+
+	lines := wrap(meta.Title, coverLineLength, coverMaxLines)
+	y := 220
+	for _, l := range lines {
+		fmt.Fprintf(&b, "<text x=\"%d\" y=\"%d\" font-family=\"serif\" font-size=\"44\" font-weight=\"bold\" text-anchor=\"middle\">%s</text>\n",
+			coverWidth/2, y, esc(l))
+		y += 56
+	}
+	if meta.Creator != "" {
+		fmt.Fprintf(&b, "<text x=\"%d\" y=\"%d\" font-family=\"sans-serif\" font-size=\"28\" text-anchor=\"middle\">%s</text>\n",
+			coverWidth/2, y+40, esc(meta.Creator))
+	}
+	if !meta.Date.IsZero() {
+		fmt.Fprintf(&b, "<text x=\"%d\" y=\"%d\" font-family=\"sans-serif\" font-size=\"24\" text-anchor=\"middle\">%s</text>\n",
+			coverWidth/2, coverHeight-80, meta.Date.UTC().Format("January 2, 2006"))
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// wrap ellipsizes the last line when there are more.
+func wrap(s string, width, maxLines int) (lines []string) {
+	var line string
+	for _, word := range strings.Fields(s) {
+		switch {
+		case line == "":
+			line = word
+		case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+		lines[maxLines-1] += "…"
+	}
+	return
+}
+
+func documentHead(language, title, stylesheet string) string {
+	return "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<!DOCTYPE html>\n" +
+		fmt.Sprintf("<html xmlns=\"http://www.w3.org/1999/xhtml\" xmlns:epub=\"http://www.idpf.org/2007/ops\" xml:lang=\"%s\" lang=\"%s\">\n", esc(language), esc(language)) +
+		fmt.Sprintf("<head>\n<meta charset=\"UTF-8\"/>\n<title>%s</title>\n", esc(title)) +
+		fmt.Sprintf("<link rel=\"stylesheet\" type=\"text/css\" href=\"%s\"/>\n</head>\n<body>\n", stylesheet)
+}
+
+func identifier(meta Metadata) string {
+	return "urn:uuid:" + meta.ID
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05Z")
+}
+
+func esc(s string) string {
+	return html.EscapeString(validXML(s))
+}
+
+// validXML drops characters xml doesn't allow, like control characters in scraped pages.
+func validXML(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\t', r == '\n', r == '\r',
+			r >= 0x20 && r <= 0xD7FF,
+			r >= 0xE000 && r <= 0xFFFD,
+			r >= 0x10000 && r <= 0x10FFFF:
+			return r
+		default:
+			return -1
+		}
+	}, s)
+}
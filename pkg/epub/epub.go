@@ -0,0 +1,369 @@
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	xhtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const (
+	mimetype   = "application/epub+zip"
+	contentDir = "OEBPS/"
+
+	// images past the caps are left out
+	maxImages     = 300
+	maxImagesSize = 100 << 20
+	// images not fetched within fetchBudget are replaced by their alt text
+	fetchConcurrency = 8
+	fetchBudget      = time.Minute
+)
+
+// mediaTypes are the image types every EPUB 3 reader supports.
+var mediaTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+type Metadata struct {
+	// ID lets readers tell apart newer builds of the same book.
+	ID          string
+	Title       string
+	Language    string
+	Creator     string
+	Source      string
+	Description string
+	Subjects    []string
+	Date        time.Time
+}
+
+type Chapter struct {
+	Title string
+	// Byline links to Source when it's set.
+	Byline   string
+	Source   string
+	Language string
+	Content  string
+}
+
+type ImageFetcher interface {
+	Fetch(ctx context.Context, url string) ([]byte, error)
+}
+
+type entry struct {
+	id        string
+	path      string
+	title     string
+	mediaType string
+}
+
+// Writer streams the book, only the table of contents is kept in memory.
+type Writer struct {
+	zw            *zip.Writer
+	meta          Metadata
+	images        ImageFetcher
+	chapters      []entry
+	assets        []entry
+	embedded      map[string]string
+	imagesSize    int
+	fetchDeadline time.Time
+}
+
+func NewWriter(w io.Writer, meta Metadata, images ImageFetcher) (*Writer, error) {
+	b := &Writer{
+		zw:            zip.NewWriter(w),
+		meta:          meta,
+		images:        images,
+		embedded:      make(map[string]string),
+		fetchDeadline: time.Now().Add(fetchBudget),
+	}
+	if b.meta.Language == "" {
+		b.meta.Language = "und"
+	}
+
+	// the mimetype must be the first file, stored uncompressed
+	if err := b.store("mimetype", []byte(mimetype)); err != nil {
+		return nil, err
+	}
+	if err := b.deflate("META-INF/container.xml", containerDocument); err != nil {
+		return nil, err
+	}
+	if err := b.deflate(contentDir+"style.css", stylesheet); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// AddChapter replaces images that can't be embedded by their alt text.
+func (b *Writer) AddChapter(ctx context.Context, c Chapter) error {
+	if c.Language == "" {
+		c.Language = b.meta.Language
+	}
+	body, err := b.chapterBody(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	n := len(b.chapters) + 1
+	ch := entry{
+		id:        fmt.Sprintf("chapter-%d", n),
+		path:      fmt.Sprintf("chapters/chapter-%d.xhtml", n),
+		title:     c.Title,
+		mediaType: "application/xhtml+xml",
+	}
+	if err = b.deflate(contentDir+ch.path, chapterDocument(c, body)); err != nil {
+		return err
+	}
+	b.chapters = append(b.chapters, ch)
+	return nil
+}
+
+// Close doesn't close the underlying writer.
+func (b *Writer) Close() error {
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"images/cover.svg", coverImage(b.meta)},
+		{"cover.xhtml", coverDocument(b.meta)},
+		{"nav.xhtml", navDocument(b.meta, b.chapters)},
+		{"toc.ncx", ncxDocument(b.meta, b.chapters)},
+		{"content.opf", packageDocument(b.meta, b.chapters, b.assets)},
+	}
+	for _, f := range files {
+		if err := b.deflate(contentDir+f.name, f.content); err != nil {
+			return err
+		}
+	}
+	return b.zw.Close()
+}
+
+func (b *Writer) chapterBody(ctx context.Context, c Chapter) (string, error) {
+	container := &xhtml.Node{Type: xhtml.ElementNode, Data: "div", DataAtom: atom.Div}
+	nodes, err := xhtml.ParseFragment(strings.NewReader(c.Content), &xhtml.Node{Type: xhtml.ElementNode, Data: "body", DataAtom: atom.Body})
+	if err != nil {
+		return "", err
+	}
+	for _, n := range nodes {
+		container.AppendChild(n)
+	}
+
+	base, _ := url.Parse(c.Source)
+	images := findImages(container)
+	srcs := make([]string, len(images))
+	for i, img := range images {
+		srcs[i] = resolve(base, attr(img, "src"))
+	}
+	fetched := b.fetchImages(ctx, srcs)
+	for i, img := range images {
+		if err = b.embedImage(img, srcs[i], fetched); err != nil {
+			return "", err
+		}
+	}
+
+	var buf bytes.Buffer
+	for n := container.FirstChild; n != nil; n = n.NextSibling {
+		if err = xhtml.Render(&buf, n); err != nil {
+			return "", err
+		}
+	}
+	return validXML(buf.String()), nil
+}
+
+func findImages(n *xhtml.Node) (images []*xhtml.Node) {
+	if n.Type == xhtml.ElementNode && n.DataAtom == atom.Img {
+		return []*xhtml.Node{n}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		images = append(images, findImages(c)...)
+	}
+	return
+}
+
+// fetchImages leaves out images that fail or run past the deadline or the caps.
+func (b *Writer) fetchImages(ctx context.Context, srcs []string) map[string][]byte {
+	fetched := make(map[string][]byte)
+	if b.images == nil {
+		return fetched
+	}
+
+	var pending []string
+	seen := make(map[string]bool)
+	for _, src := range srcs {
+		if _, ok := b.embedded[src]; ok || seen[src] || src == "" || strings.HasPrefix(src, "data:") {
+			continue
+		}
+		seen[src] = true
+		pending = append(pending, src)
+	}
+	if left := max(maxImages-len(b.assets), 0); len(pending) > left {
+		pending = pending[:left]
+	}
+
+	ctx, cancel := context.WithDeadline(ctx, b.fetchDeadline)
+	defer cancel()
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		size = b.imagesSize
+	)
+	sem := make(chan struct{}, fetchConcurrency)
+	for _, src := range pending {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			data, err := b.images.Fetch(ctx, src)
+			if err != nil {
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if size+len(data) > maxImagesSize {
+				return
+			}
+			size += len(data)
+			fetched[src] = data
+		}()
+	}
+	wg.Wait()
+	return fetched
+}
+
+// embedImage only returns errors writing the book.
+func (b *Writer) embedImage(img *xhtml.Node, src string, fetched map[string][]byte) error {
+	path, ok := b.embedded[src]
+	if !ok && src != "" {
+		data, mediaType := b.imageData(src, fetched)
+		if data != nil {
+			path = fmt.Sprintf("images/image-%d%s", len(b.assets)+1, mediaTypes[mediaType])
+			if err := b.store(contentDir+path, data); err != nil {
+				return err
+			}
+			b.assets = append(b.assets, entry{id: fmt.Sprintf("image-%d", len(b.assets)+1), path: path, mediaType: mediaType})
+			b.imagesSize += len(data)
+		}
+		// failed images are remembered so they aren't fetched again
+		b.embedded[src] = path
+	}
+
+	if path == "" {
+		alt := attr(img, "alt")
+		if alt != "" {
+			img.Parent.InsertBefore(&xhtml.Node{Type: xhtml.TextNode, Data: alt}, img)
+		}
+		img.Parent.RemoveChild(img)
+		return nil
+	}
+
+	attrs := []xhtml.Attribute{{Key: "src", Val: "../" + path}, {Key: "alt", Val: attr(img, "alt")}}
+	for _, a := range img.Attr {
+		// srcset would point readers back to the remote images
+		if a.Key != "src" && a.Key != "alt" && a.Key != "srcset" && a.Key != "sizes" && a.Key != "loading" {
+			attrs = append(attrs, a)
+		}
+	}
+	img.Attr = attrs
+	return nil
+}
+
+func (b *Writer) imageData(src string, fetched map[string][]byte) ([]byte, string) {
+	if len(b.assets) >= maxImages || b.imagesSize >= maxImagesSize {
+		return nil, ""
+	}
+
+	data := fetched[src]
+	if strings.HasPrefix(src, "data:") {
+		data = decodeDataURI(src)
+	}
+	if len(data) == 0 || b.imagesSize+len(data) > maxImagesSize {
+		return nil, ""
+	}
+
+	// servers often send a wrong content type
+	mediaType := http.DetectContentType(data)
+	if _, ok := mediaTypes[mediaType]; !ok {
+		return nil, ""
+	}
+	return data, mediaType
+}
+
+func decodeDataURI(src string) []byte {
+	header, payload, ok := strings.Cut(strings.TrimPrefix(src, "data:"), ",")
+	if !ok || !strings.HasSuffix(header, ";base64") {
+		return nil
+	}
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+func resolve(base *url.URL, src string) string {
+	src = strings.TrimSpace(src)
+	if src == "" || strings.HasPrefix(src, "data:") || base == nil {
+		return src
+	}
+	u, err := base.Parse(src)
+	if err != nil {
+		return ""
+	}
+	return u.String()
+}
+
+func attr(n *xhtml.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// store writes an uncompressed file, images are already compressed.
+func (b *Writer) store(name string, data []byte) error {
+	w, err := b.zw.CreateRaw(&zip.FileHeader{
+		Name:               name,
+		Method:             zip.Store,
+		CRC32:              crc32.ChecksumIEEE(data),
+		CompressedSize64:   uint64(len(data)),
+		UncompressedSize64: uint64(len(data)),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func (b *Writer) deflate(name, content string) error {
+	w, err := b.zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, content)
+	return err
+}
@@ -0,0 +1,68 @@
+package epub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/ryanadiputraa/unclatter/pkg/netguard"
+)
+
+const (
+	fetchTimeout = 10 * time.Second
+	maxImageSize = 5 << 20
+)
+
+var errImageTooLarge = errors.New("image is too large")
+
+type httpFetcher struct {
+	client *http.Client
+}
+
+// NewHTTPFetcher bounds every download in time and size and only connects to public addresses.
+func NewHTTPFetcher() ImageFetcher {
+	return &httpFetcher{
+		client: &http.Client{Timeout: fetchTimeout, Transport: netguard.NewTransport()},
+	}
+}
+
+func (f *httpFetcher) Fetch(ctx context.Context, src string) ([]byte, error) {
+	u, err := url.Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported image url scheme %q", u.Scheme)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "image/webp,image/png,image/jpeg,image/gif;q=0.9,*/*;q=0.5")
+
+	res, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d fetching image", res.StatusCode)
+	}
+	if res.ContentLength > maxImageSize {
+		return nil, errImageTooLarge
+	}
+
+	data, err := io.ReadAll(io.LimitReader(res.Body, maxImageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxImageSize {
+		return nil, errImageTooLarge
+	}
+	return data, nil
+}
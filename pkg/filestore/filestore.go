@@ -0,0 +1,90 @@
+package filestore
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var ErrInvalidName = errors.New("filestore: invalid file name")
+
+// Store keeps files built in the background until they're downloaded.
+type Store interface {
+	// Write only publishes the file once fn succeeds.
+	Write(name string, fn func(w io.Writer) error) (int64, error)
+	Open(name string) (*os.File, error)
+	// Remove ignores files that don't exist.
+	Remove(name string) error
+}
+
+type store struct {
+	dir string
+}
+
+func NewStore(dir string) Store {
+	return &store{
+		dir: dir,
+	}
+}
+
+func (s *store) Write(name string, fn func(w io.Writer) error) (int64, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return 0, err
+	}
+	if err = os.MkdirAll(s.dir, 0o750); err != nil {
+		return 0, err
+	}
+
+	// written next to the file so the rename never crosses file systems
+	f, err := os.CreateTemp(s.dir, "."+name+"-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(f.Name())
+
+	if err = fn(f); err != nil {
+		f.Close()
+		return 0, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return 0, err
+	}
+	if err = f.Close(); err != nil {
+		return 0, err
+	}
+	if err = os.Rename(f.Name(), path); err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+func (s *store) Open(name string) (*os.File, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+func (s *store) Remove(name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *store) path(name string) (string, error) {
+	if name == "" || strings.HasPrefix(name, ".") || filepath.Base(name) != name {
+		return "", ErrInvalidName
+	}
+	return filepath.Join(s.dir, name), nil
+}
@@ -0,0 +1,96 @@
+package filestore
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrite(t *testing.T) {
+	cases := []struct {
+		name     string
+		file     string
+		write    func(w io.Writer) error
+		size     int64
+		expected string
+		err      error
+	}{
+		{
+			name: "should write the file and return its size",
+			file: "export.zip",
+			write: func(w io.Writer) error {
+				_, err := io.WriteString(w, "archive")
+				return err
+			},
+			size:     7,
+			expected: "archive",
+			err:      nil,
+		},
+		{
+			name: "should leave nothing behind when the write fails",
+			file: "export.zip",
+			write: func(w io.Writer) error {
+				io.WriteString(w, "partial")
+				return errors.New("fail to build archive")
+			},
+			err: errors.New("fail to build archive"),
+		},
+		{
+			name:  "should reject names outside the store",
+			file:  "../export.zip",
+			write: func(w io.Writer) error { return nil },
+			err:   ErrInvalidName,
+		},
+		{
+			name:  "should reject hidden names",
+			file:  ".export.zip",
+			write: func(w io.Writer) error { return nil },
+			err:   ErrInvalidName,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "exports")
+			s := NewStore(dir)
+
+			size, err := s.Write(c.file, c.write)
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.size, size)
+
+			entries, _ := os.ReadDir(dir)
+			if c.err != nil {
+				assert.Empty(t, entries)
+				return
+			}
+			assert.Len(t, entries, 1)
+			b, err := os.ReadFile(filepath.Join(dir, c.file))
+			assert.Nil(t, err)
+			assert.Equal(t, c.expected, string(b))
+		})
+	}
+}
+
+func TestOpenAndRemove(t *testing.T) {
+	s := NewStore(t.TempDir())
+	_, err := s.Write("export.zip", func(w io.Writer) error {
+		_, err := io.WriteString(w, "archive")
+		return err
+	})
+	assert.Nil(t, err)
+
+	f, err := s.Open("export.zip")
+	assert.Nil(t, err)
+	b, _ := io.ReadAll(f)
+	f.Close()
+	assert.Equal(t, "archive", string(b))
+
+	assert.Nil(t, s.Remove("export.zip"))
+	_, err = s.Open("export.zip")
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+	assert.Nil(t, s.Remove("export.zip"))
+}
@@ -14,6 +14,7 @@ type ResponseWriter interface {
 	WriteResponseDataWithPagination(w http.ResponseWriter, code int, data any, meta pagination.Meta)
 	WriteErrMessage(w http.ResponseWriter, code int, message string)
 	WriteErrDetails(w http.ResponseWriter, code int, message string, errMap map[string]string)
+	WriteErrData(w http.ResponseWriter, code int, message string, data any)
 }
 
 type ResponseData struct {
@@ -29,6 +30,11 @@ type ErrMessage struct {
 	Message string `json:"message"`
 }
 
+type ErrData struct {
+	Message string `json:"message"`
+	Data    any    `json:"data"`
+}
+
 type ErrDetails struct {
 	Message string            `json:"message"`
 	Error   map[string]string `json:"error"`
@@ -75,3 +81,12 @@ func (rw *responseWriter) WriteErrDetails(w http.ResponseWriter, code int, messa
 		Error:   errMap,
 	})
 }
+
+func (rw *responseWriter) WriteErrData(w http.ResponseWriter, code int, message string, data any) {
+	rw.setJSONHeader(w)
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(&ErrData{
+		Message: message,
+		Data:    data,
+	})
+}
@@ -0,0 +1,238 @@
+package linkcheck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/ryanadiputraa/unclatter/pkg/netguard"
+	"github.com/ryanadiputraa/unclatter/pkg/scrapper"
+	"github.com/temoto/robotstxt"
+	"golang.org/x/net/html/charset"
+)
+
+const (
+	// UserAgent is matched against robots.txt rules.
+	UserAgent = "Unclatter-LinkChecker"
+
+	maxBodySize   = 2 << 20
+	maxRobotsSize = 512 << 10
+	robotsTTL     = 24 * time.Hour
+)
+
+// Request carries the validators of the previous check so an unchanged page isn't downloaded again.
+type Request struct {
+	URL          string
+	ETag         string
+	LastModified string
+}
+
+type Result struct {
+	// Method is HEAD when its response was enough.
+	Method       string
+	StatusCode   int
+	FinalURL     string
+	NotModified  bool
+	ETag         string
+	LastModified string
+	// Content is empty when the page wasn't downloaded or isn't html.
+	Content string
+	// Disallowed links aren't requested.
+	Disallowed bool
+}
+
+type Checker interface {
+	// Check falls back from HEAD to GET, requests to the same host are at least hostDelay apart.
+	Check(ctx context.Context, req Request) (*Result, error)
+}
+
+type robots struct {
+	group     *robotstxt.Group
+	fetchedAt time.Time
+}
+
+type checker struct {
+	client    *http.Client
+	hostDelay time.Duration
+
+	mu     sync.Mutex
+	next   map[string]time.Time
+	robots map[string]robots
+}
+
+// NewChecker only connects to public addresses, links are set by users.
+func NewChecker(timeout, hostDelay time.Duration) Checker {
+	return &checker{
+		client:    &http.Client{Timeout: timeout, Transport: netguard.NewTransport()},
+		hostDelay: hostDelay,
+		next:      make(map[string]time.Time),
+		robots:    make(map[string]robots),
+	}
+}
+
+func (c *checker) Check(ctx context.Context, req Request) (*Result, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported link scheme %q", u.Scheme)
+	}
+
+	allowed, err := c.allowed(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return &Result{FinalURL: req.URL, Disallowed: true}, nil
+	}
+
+	res, err := c.do(ctx, http.MethodHead, u, req)
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+	result := newResult(http.MethodHead, res)
+	switch {
+	case res.StatusCode == http.StatusNotModified:
+		result.NotModified = true
+		return result, nil
+	case res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusGone:
+		return result, nil
+	case res.StatusCode >= 200 && res.StatusCode <= 299 && unchanged(req, result):
+		result.NotModified = true
+		return result, nil
+	}
+
+	// the page has to be downloaded to compare its content, and some servers mishandle HEAD
+	res, err = c.do(ctx, http.MethodGet, u, req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	result = newResult(http.MethodGet, res)
+	if res.StatusCode == http.StatusNotModified {
+		result.NotModified = true
+		return result, nil
+	}
+	if res.StatusCode >= 200 && res.StatusCode <= 299 && isHTML(res.Header.Get("Content-Type")) {
+		body, err := charset.NewReader(io.LimitReader(res.Body, maxBodySize), res.Header.Get("Content-Type"))
+		if err != nil {
+			return nil, err
+		}
+		// extracted like the scrapper does so it compares to the article's
+		if result.Content, err = scrapper.ReadableHTML(body); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (c *checker) do(ctx context.Context, method string, u *url.URL, req Request) (*http.Response, error) {
+	if err := c.wait(ctx, u.Host); err != nil {
+		return nil, err
+	}
+
+	r, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	r.Header.Set("User-Agent", UserAgent)
+	r.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.8")
+	if req.ETag != "" {
+		r.Header.Set("If-None-Match", req.ETag)
+	}
+	if req.LastModified != "" {
+		r.Header.Set("If-Modified-Since", req.LastModified)
+	}
+	return c.client.Do(r)
+}
+
+func (c *checker) wait(ctx context.Context, host string) error {
+	now := time.Now()
+	c.mu.Lock()
+	at := c.next[host]
+	if at.Before(now) {
+		at = now
+	}
+	c.next[host] = at.Add(c.hostDelay)
+	if len(c.next) > 1000 {
+		for h, next := range c.next {
+			if next.Before(now) {
+				delete(c.next, h)
+			}
+		}
+	}
+	c.mu.Unlock()
+
+	t := time.NewTimer(at.Sub(now))
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// allowed treats a robots.txt that can't be fetched as allowing everything.
+func (c *checker) allowed(ctx context.Context, u *url.URL) (bool, error) {
+	key := u.Scheme + "://" + u.Host
+	c.mu.Lock()
+	cached, ok := c.robots[key]
+	c.mu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < robotsTTL {
+		return cached.group.Test(u.RequestURI()), nil
+	}
+
+	robotsURL := &url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/robots.txt"}
+	res, err := c.do(ctx, http.MethodGet, robotsURL, Request{})
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		return true, nil
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxRobotsSize))
+	if err != nil {
+		return true, nil
+	}
+	data, err := robotstxt.FromStatusAndBytes(res.StatusCode, body)
+	if err != nil {
+		return true, nil
+	}
+
+	group := data.FindGroup(UserAgent)
+	c.mu.Lock()
+	c.robots[key] = robots{group: group, fetchedAt: time.Now()}
+	c.mu.Unlock()
+	return group.Test(u.RequestURI()), nil
+}
+
+func newResult(method string, res *http.Response) *Result {
+	return &Result{
+		Method:       method,
+		StatusCode:   res.StatusCode,
+		FinalURL:     res.Request.URL.String(),
+		ETag:         res.Header.Get("ETag"),
+		LastModified: res.Header.Get("Last-Modified"),
+	}
+}
+
+func unchanged(req Request, result *Result) bool {
+	if req.ETag != "" && result.ETag != "" {
+		return req.ETag == result.ETag
+	}
+	return req.LastModified != "" && req.LastModified == result.LastModified
+}
+
+func isHTML(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "text/html" || mediaType == "application/xhtml+xml")
+}
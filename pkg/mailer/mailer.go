@@ -0,0 +1,143 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/ryanadiputraa/unclatter/config"
+)
+
+const (
+	TLSStartTLS = "starttls"
+	TLSImplicit = "tls"
+	// TLSNone is only meant for local smtp stand-ins.
+	TLSNone = "none"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+	// Headers are added as they are, like List-Unsubscribe.
+	Headers map[string]string
+
+	Attachments []Attachment
+}
+
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+type mailer struct {
+	config *config.SMTP
+}
+
+// NewMailer only checks the configuration when sending, so the server can start without one.
+func NewMailer(c *config.SMTP) Mailer {
+	return &mailer{
+		config: c,
+	}
+}
+
+func (m *mailer) Send(ctx context.Context, msg Message) error {
+	if m.config == nil || m.config.Host == "" {
+		return errors.New("smtp server isn't configured")
+	}
+	from, err := mail.ParseAddress(m.config.From)
+	if err != nil {
+		return fmt.Errorf("invalid smtp from address: %w", err)
+	}
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return err
+	}
+	body, err := compose(from, to, msg)
+	if err != nil {
+		return err
+	}
+
+	c, err := m.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if err = c.Mail(from.Address); err != nil {
+		return err
+	}
+	if err = c.Rcpt(to.Address); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(body); err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// dial opens an authenticated session that has to finish within the timeout.
+func (m *mailer) dial(ctx context.Context) (*smtp.Client, error) {
+	switch m.config.TLS {
+	case TLSStartTLS, TLSImplicit, TLSNone:
+	default:
+		return nil, fmt.Errorf("invalid smtp tls mode %q", m.config.TLS)
+	}
+
+	addr := net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+	dialer := &net.Dialer{Timeout: m.config.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if m.config.Timeout > 0 {
+		conn.SetDeadline(time.Now().Add(m.config.Timeout))
+	}
+
+	tlsConfig := &tls.Config{ServerName: m.config.Host}
+	if m.config.TLS == TLSImplicit {
+		conn = tls.Client(conn, tlsConfig)
+	}
+	c, err := smtp.NewClient(conn, m.config.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if m.config.TLS == TLSStartTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			c.Close()
+			return nil, fmt.Errorf("smtp server %s doesn't support starttls", addr)
+		}
+		if err = c.StartTLS(tlsConfig); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	if m.config.Username != "" {
+		if err = c.Auth(smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,259 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryanadiputraa/unclatter/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// smtpServer is an in-process smtp stand-in accepting a single session, it records the commands it receives
+// and the message sent.
+type smtpServer struct {
+	ln       net.Listener
+	auth     bool
+	commands []string
+	data     []byte
+	done     chan struct{}
+}
+
+func newSMTPServer(t *testing.T, auth bool) *smtpServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &smtpServer{ln: ln, auth: auth, done: make(chan struct{})}
+	t.Cleanup(func() { ln.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *smtpServer) port() int {
+	return s.ln.Addr().(*net.TCPAddr).Port
+}
+
+// session waits for the session to end so its commands and message can be read.
+func (s *smtpServer) session() *smtpServer {
+	select {
+	case <-s.done:
+	case <-time.After(5 * time.Second):
+	}
+	return s
+}
+
+func (s *smtpServer) serve() {
+	defer close(s.done)
+	conn, err := s.ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	tc := textproto.NewConn(conn)
+	tc.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := tc.ReadLine()
+		if err != nil {
+			return
+		}
+		s.commands = append(s.commands, line)
+
+		verb, _, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			if s.auth {
+				tc.PrintfLine("250-localhost")
+				tc.PrintfLine("250 AUTH PLAIN")
+			} else {
+				tc.PrintfLine("250 localhost")
+			}
+		case "AUTH":
+			tc.PrintfLine("235 2.7.0 Authentication successful")
+		case "MAIL", "RCPT":
+			tc.PrintfLine("250 OK")
+		case "DATA":
+			tc.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			if s.data, err = tc.ReadDotBytes(); err != nil {
+				return
+			}
+			tc.PrintfLine("250 OK")
+		case "QUIT":
+			tc.PrintfLine("221 Bye")
+			return
+		default:
+			tc.PrintfLine("502 Command not implemented")
+		}
+	}
+}
+
+func newConfig(port int, tlsMode string) *config.SMTP {
+	return &config.SMTP{
+		Host:    "127.0.0.1",
+		Port:    port,
+		From:    "Unclatter <no-reply@unclatter.com>",
+		TLS:     tlsMode,
+		Timeout: 5 * time.Second,
+	}
+}
+
+func TestSend(t *testing.T) {
+	msg := Message{
+		To:      "reader@kindle.com",
+		Subject: "Reading digest",
+		Text:    "Your weekly digest",
+		HTML:    "<p>Your weekly digest</p>",
+	}
+
+	cases := []struct {
+		name     string
+		auth     bool
+		username string
+		tlsMode  string
+		commands []string
+		err      string
+	}{
+		{
+			name:     "should send without starttls to local stand-in",
+			tlsMode:  TLSNone,
+			commands: []string{"EHLO localhost", "MAIL FROM:<no-reply@unclatter.com>", "RCPT TO:<reader@kindle.com>", "DATA", "QUIT"},
+			err:      "",
+		},
+		{
+			name:     "should authenticate with plain auth",
+			auth:     true,
+			username: "unclatter",
+			tlsMode:  TLSNone,
+			commands: []string{
+				"EHLO localhost",
+				"AUTH PLAIN " + base64.StdEncoding.EncodeToString([]byte("\x00unclatter\x00secret")),
+				"MAIL FROM:<no-reply@unclatter.com>", "RCPT TO:<reader@kindle.com>", "DATA", "QUIT",
+			},
+			err: "",
+		},
+		{
+			name:     "should return err when server doesn't support starttls",
+			tlsMode:  TLSStartTLS,
+			commands: []string{"EHLO localhost"},
+			err:      "doesn't support starttls",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			server := newSMTPServer(t, c.auth)
+			conf := newConfig(server.port(), c.tlsMode)
+			conf.Username = c.username
+			conf.Password = "secret"
+
+			err := NewMailer(conf).Send(context.Background(), msg)
+			if c.err != "" {
+				assert.ErrorContains(t, err, c.err)
+			} else {
+				assert.Nil(t, err)
+			}
+			assert.Equal(t, c.commands, withoutBodyParam(server.session().commands))
+		})
+	}
+}
+
+// withoutBodyParam drops the 8bitmime parameter net/smtp only adds when the server advertises it.
+func withoutBodyParam(commands []string) []string {
+	for i, c := range commands {
+		commands[i] = strings.TrimSuffix(c, " BODY=8BITMIME")
+	}
+	return commands
+}
+
+func TestSendNotConfigured(t *testing.T) {
+	err := NewMailer(&config.SMTP{}).Send(context.Background(), Message{To: "reader@kindle.com"})
+	assert.EqualError(t, err, "smtp server isn't configured")
+}
+
+func TestSendMessageLayout(t *testing.T) {
+	server := newSMTPServer(t, false)
+	book := bytes.Repeat([]byte("epub content "), 20)
+
+	err := NewMailer(newConfig(server.port(), TLSNone)).Send(context.Background(), Message{
+		To:      "reader@kindle.com",
+		Subject: "Sécurité des données",
+		Text:    "Article attached",
+		HTML:    "<p>Article attached</p>",
+		Headers: map[string]string{"list-unsubscribe": "<https://api.unclatter.com/unsubscribe>\r\nBcc: attacker@example.com"},
+		Attachments: []Attachment{
+			{Filename: "article.epub", ContentType: "application/epub+zip", Data: book},
+		},
+	})
+	assert.Nil(t, err)
+
+	m, err := mail.ReadMessage(bytes.NewReader(server.session().data))
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "\"Unclatter\" <no-reply@unclatter.com>", m.Header.Get("From"))
+	assert.Equal(t, "<reader@kindle.com>", m.Header.Get("To"))
+	subject, _ := new(mime.WordDecoder).DecodeHeader(m.Header.Get("Subject"))
+	assert.Equal(t, "Sécurité des données", subject)
+	assert.Equal(t, "<https://api.unclatter.com/unsubscribe>Bcc: attacker@example.com", m.Header.Get("List-Unsubscribe"))
+	assert.Empty(t, m.Header.Get("Bcc"))
+	assert.Equal(t, "1.0", m.Header.Get("MIME-Version"))
+
+	mediaType, params, err := mime.ParseMediaType(m.Header.Get("Content-Type"))
+	assert.Nil(t, err)
+	assert.Equal(t, "multipart/mixed", mediaType)
+	mixed := multipart.NewReader(m.Body, params["boundary"])
+
+	// the first part holds the text and html alternatives
+	part, err := mixed.NextPart()
+	if !assert.Nil(t, err) {
+		return
+	}
+	mediaType, params, _ = mime.ParseMediaType(part.Header.Get("Content-Type"))
+	assert.Equal(t, "multipart/alternative", mediaType)
+	alternative := multipart.NewReader(part, params["boundary"])
+	for _, expected := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", "Article attached"},
+		{"text/html; charset=utf-8", "<p>Article attached</p>"},
+	} {
+		text, err := alternative.NextRawPart()
+		if !assert.Nil(t, err) {
+			return
+		}
+		assert.Equal(t, expected.contentType, text.Header.Get("Content-Type"))
+		assert.Equal(t, "quoted-printable", text.Header.Get("Content-Transfer-Encoding"))
+		body, _ := io.ReadAll(quotedprintable.NewReader(text))
+		assert.Equal(t, expected.body, string(body))
+	}
+	_, err = alternative.NextPart()
+	assert.Equal(t, io.EOF, err)
+
+	// attachments follow as base64 parts
+	attachment, err := mixed.NextRawPart()
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "application/epub+zip; name=article.epub", attachment.Header.Get("Content-Type"))
+	assert.Equal(t, "attachment; filename=article.epub", attachment.Header.Get("Content-Disposition"))
+	assert.Equal(t, "base64", attachment.Header.Get("Content-Transfer-Encoding"))
+	encoded, _ := io.ReadAll(attachment)
+	for _, line := range strings.Split(strings.TrimSpace(string(encoded)), "\n") {
+		assert.LessOrEqual(t, len(strings.TrimSuffix(line, "\r")), lineLength)
+	}
+	data, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, bytes.NewReader(encoded)))
+	assert.Nil(t, err)
+	assert.Equal(t, book, data)
+
+	_, err = mixed.NextPart()
+	assert.Equal(t, io.EOF, err)
+}
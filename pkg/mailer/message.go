@@ -0,0 +1,136 @@
+package mailer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// lineLength keeps base64 lines short enough for smtp servers.
+const lineLength = 76
+
+var lineBreaks = strings.NewReplacer("\r", "", "\n", "")
+
+func compose(from, to *mail.Address, msg Message) ([]byte, error) {
+	var buf bytes.Buffer
+	mixed := multipart.NewWriter(&buf)
+	headers := [][2]string{
+		{"From", from.String()},
+		{"To", to.String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", msg.Subject)},
+		{"Date", time.Now().UTC().Format(time.RFC1123Z)},
+		{"Message-ID", fmt.Sprintf("<%s@%s>", uuid.NewString(), domain(from.Address))},
+	}
+	keys := make([]string, 0, len(msg.Headers))
+	for k := range msg.Headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		// line breaks would let a value add headers of its own
+		headers = append(headers, [2]string{textproto.CanonicalMIMEHeaderKey(k), lineBreaks.Replace(msg.Headers[k])})
+	}
+	headers = append(headers,
+		[2]string{"MIME-Version", "1.0"},
+		[2]string{"Content-Type", "multipart/mixed; boundary=" + mixed.Boundary()},
+	)
+	for _, h := range headers {
+		fmt.Fprintf(&buf, "%s: %s\r\n", h[0], h[1])
+	}
+	buf.WriteString("\r\n")
+
+	if err := writeAlternatives(mixed, msg); err != nil {
+		return nil, err
+	}
+	for _, a := range msg.Attachments {
+		if err := writeAttachment(mixed, a); err != nil {
+			return nil, err
+		}
+	}
+	if err := mixed.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeAlternatives(mixed *multipart.Writer, msg Message) error {
+	var buf bytes.Buffer
+	alternative := multipart.NewWriter(&buf)
+	if err := writeText(alternative, "text/plain; charset=utf-8", msg.Text); err != nil {
+		return err
+	}
+	if msg.HTML != "" {
+		if err := writeText(alternative, "text/html; charset=utf-8", msg.HTML); err != nil {
+			return err
+		}
+	}
+	if err := alternative.Close(); err != nil {
+		return err
+	}
+
+	w, err := mixed.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"multipart/alternative; boundary=" + alternative.Boundary()},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+func writeText(mw *multipart.Writer, contentType, text string) error {
+	w, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+	qw := quotedprintable.NewWriter(w)
+	if _, err = qw.Write([]byte(text)); err != nil {
+		return err
+	}
+	return qw.Close()
+}
+
+func writeAttachment(mw *multipart.Writer, a Attachment) error {
+	contentType := a.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {mime.FormatMediaType(contentType, map[string]string{"name": a.Filename})},
+		"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename})},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return err
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(a.Data)
+	for len(encoded) > lineLength {
+		if _, err = fmt.Fprintf(w, "%s\r\n", encoded[:lineLength]); err != nil {
+			return err
+		}
+		encoded = encoded[lineLength:]
+	}
+	_, err = fmt.Fprintf(w, "%s\r\n", encoded)
+	return err
+}
+
+func domain(address string) string {
+	if i := strings.LastIndex(address, "@"); i >= 0 {
+		return address[i+1:]
+	}
+	return "localhost"
+}
@@ -0,0 +1,248 @@
+package markdown
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	blankLines  = regexp.MustCompile(`\n{3,}`)
+	spaces      = regexp.MustCompile(`\s+`)
+	escapeChars = strings.NewReplacer(`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`)
+)
+
+// FromHTML converts sanitized html into CommonMark, elements without an equivalent are replaced by their content.
+func FromHTML(s string) string {
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(s), body)
+	if err != nil {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, n := range nodes {
+		b.WriteString(render(n))
+	}
+
+	lines := strings.Split(b.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+func render(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return escapeChars.Replace(spaces.ReplaceAllString(n.Data, " "))
+	case html.ElementNode:
+	default:
+		return children(n)
+	}
+
+	switch n.DataAtom {
+	case atom.Script, atom.Style, atom.Head:
+		return ""
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		level := int(n.Data[1] - '0')
+		return block(strings.Repeat("#", level) + " " + strings.TrimSpace(children(n)))
+	case atom.P, atom.Div, atom.Section, atom.Article, atom.Header, atom.Footer, atom.Main, atom.Aside,
+		atom.Figure, atom.Figcaption, atom.Dl, atom.Dt, atom.Dd, atom.Address:
+		return block(strings.TrimSpace(children(n)))
+	case atom.Br:
+		return "\\\n"
+	case atom.Hr:
+		return block("---")
+	case atom.Strong, atom.B:
+		return wrap(children(n), "**")
+	case atom.Em, atom.I:
+		return wrap(children(n), "_")
+	case atom.Del, atom.S:
+		return wrap(children(n), "~~")
+	case atom.Code:
+		return code(text(n))
+	case atom.Pre:
+		return block("```\n" + strings.Trim(text(n), "\n") + "\n```")
+	case atom.A:
+		label := strings.TrimSpace(children(n))
+		href := attr(n, "href")
+		if href == "" {
+			return label
+		}
+		if label == "" {
+			label = escapeChars.Replace(href)
+		}
+		return "[" + label + "](" + destination(href) + ")"
+	case atom.Img:
+		src := attr(n, "src")
+		if src == "" {
+			return ""
+		}
+		return "![" + escapeChars.Replace(attr(n, "alt")) + "](" + destination(src) + ")"
+	case atom.Ul, atom.Ol:
+		return list(n)
+	case atom.Blockquote:
+		lines := strings.Split(strings.TrimSpace(blankLines.ReplaceAllString(children(n), "\n\n")), "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight("> "+strings.TrimSpace(line), " ")
+		}
+		return block(strings.Join(lines, "\n"))
+	case atom.Table:
+		return table(n)
+	default:
+		return children(n)
+	}
+}
+
+func children(n *html.Node) string {
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(render(c))
+	}
+	return b.String()
+}
+
+// text keeps whitespace and markdown characters, it's used for code.
+func text(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.DataAtom == atom.Br {
+			b.WriteString("\n")
+			continue
+		}
+		b.WriteString(text(c))
+	}
+	return b.String()
+}
+
+func block(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "\n\n" + s + "\n\n"
+}
+
+// wrap keeps surrounding spaces outside the markers, markers followed by a space aren't emphasis.
+func wrap(s, marker string) string {
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" {
+		return s
+	}
+	start := s[:strings.Index(s, trimmed)]
+	end := s[len(start)+len(trimmed):]
+	return start + marker + trimmed + marker + end
+}
+
+func code(s string) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	if s == "" {
+		return ""
+	}
+	fence := "`"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+	return fence + s + fence
+}
+
+func destination(s string) string {
+	if strings.ContainsAny(s, " ()<>") {
+		return "<" + strings.NewReplacer("<", "%3C", ">", "%3E").Replace(s) + ">"
+	}
+	return s
+}
+
+func list(n *html.Node) string {
+	var b strings.Builder
+	i := 1
+	if start, err := strconv.Atoi(attr(n, "start")); err == nil {
+		i = start
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.DataAtom != atom.Li {
+			continue
+		}
+		marker := "- "
+		if n.DataAtom == atom.Ol {
+			marker = strconv.Itoa(i) + ". "
+			i++
+		}
+
+		// nested blocks are indented so they stay part of the item
+		item := strings.TrimSpace(blankLines.ReplaceAllString(children(c), "\n\n"))
+		indent := strings.Repeat(" ", len(marker))
+		lines := strings.Split(item, "\n")
+		for j := 1; j < len(lines); j++ {
+			if lines[j] != "" {
+				lines[j] = indent + lines[j]
+			}
+		}
+		b.WriteString(marker + strings.Join(lines, "\n") + "\n")
+	}
+	return block(strings.TrimRight(b.String(), "\n"))
+}
+
+func table(n *html.Node) string {
+	var rows [][]string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.DataAtom != atom.Tr {
+				walk(c)
+				continue
+			}
+			var cells []string
+			for cell := c.FirstChild; cell != nil; cell = cell.NextSibling {
+				if cell.DataAtom == atom.Td || cell.DataAtom == atom.Th {
+					content := strings.Join(strings.Fields(children(cell)), " ")
+					cells = append(cells, strings.ReplaceAll(content, "|", `\|`))
+				}
+			}
+			rows = append(rows, cells)
+		}
+	}
+	walk(n)
+	if len(rows) == 0 {
+		return ""
+	}
+
+	columns := 0
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+	if columns == 0 {
+		return ""
+	}
+
+	lines := make([]string, 0, len(rows)+1)
+	for i, row := range rows {
+		for len(row) < columns {
+			row = append(row, "")
+		}
+		lines = append(lines, "| "+strings.Join(row, " | ")+" |")
+		if i == 0 {
+			lines = append(lines, "|"+strings.Repeat(" --- |", columns))
+		}
+	}
+	return block(strings.Join(lines, "\n"))
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,76 @@
+package netguard
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPublic(t *testing.T) {
+	cases := []struct {
+		ip       string
+		expected bool
+	}{
+		{ip: "93.184.216.34", expected: true},
+		{ip: "2606:2800:220:1:248:1893:25c8:1946", expected: true},
+		{ip: "127.0.0.1", expected: false},
+		{ip: "::1", expected: false},
+		{ip: "10.0.0.8", expected: false},
+		{ip: "172.16.4.2", expected: false},
+		{ip: "192.168.1.1", expected: false},
+		{ip: "169.254.169.254", expected: false},
+		{ip: "fe80::1", expected: false},
+		{ip: "fd00::1", expected: false},
+		{ip: "0.0.0.0", expected: false},
+		{ip: "::", expected: false},
+		{ip: "100.100.100.200", expected: false},
+		{ip: "::ffff:127.0.0.1", expected: false},
+		{ip: "224.0.0.1", expected: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.ip, func(t *testing.T) {
+			assert.Equal(t, c.expected, IsPublic(net.ParseIP(c.ip)))
+		})
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	cases := []struct {
+		name      string
+		url       string
+		nonPublic bool
+		err       bool
+	}{
+		{name: "should accept public ip", url: "https://93.184.216.34/hook", err: false},
+		{name: "should reject loopback ip", url: "http://127.0.0.1:8080/hook", nonPublic: true, err: true},
+		{name: "should reject metadata address", url: "http://169.254.169.254/latest/meta-data", nonPublic: true, err: true},
+		{name: "should reject ipv6 loopback", url: "http://[::1]/hook", nonPublic: true, err: true},
+		{name: "should reject unsupported scheme", url: "ftp://93.184.216.34/hook", err: true},
+		{name: "should reject url without host", url: "https:///hook", err: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := CheckURL(context.Background(), c.url)
+			assert.Equal(t, c.err, err != nil)
+			assert.Equal(t, c.nonPublic, errors.Is(err, ErrNonPublicAddress))
+		})
+	}
+}
+
+func TestTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewTransport()}
+	_, err := client.Get(server.URL)
+	assert.ErrorIs(t, err, ErrNonPublicAddress)
+}
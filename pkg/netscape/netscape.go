@@ -0,0 +1,160 @@
+package netscape
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	xhtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const header = `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<!-- This is an automatically generated file.
+     It will be read and overwritten.
+     DO NOT EDIT! -->
+<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">
+<TITLE>Bookmarks</TITLE>
+<H1>Bookmarks</H1>
+<DL><p>
+`
+
+// Bookmark is a link of a Netscape bookmark file, the format browsers import and export.
+type Bookmark struct {
+	Title        string
+	URL          string
+	AddDate      time.Time
+	LastModified time.Time
+	Tags         []string
+	// Folder is only set when parsing.
+	Folder string
+}
+
+type Writer struct {
+	w       *bufio.Writer
+	started bool
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+func (w *Writer) Write(b Bookmark) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w.w, `    <DT><A HREF="%s"`, html.EscapeString(b.URL))
+	if !b.AddDate.IsZero() {
+		fmt.Fprintf(w.w, ` ADD_DATE="%d"`, b.AddDate.Unix())
+	}
+	if !b.LastModified.IsZero() {
+		fmt.Fprintf(w.w, ` LAST_MODIFIED="%d"`, b.LastModified.Unix())
+	}
+	if len(b.Tags) > 0 {
+		fmt.Fprintf(w.w, ` TAGS="%s"`, html.EscapeString(strings.Join(b.Tags, ",")))
+	}
+	_, err := fmt.Fprintf(w.w, ">%s</A>\n", html.EscapeString(b.Title))
+	return err
+}
+
+// Close doesn't close the underlying writer.
+func (w *Writer) Close() error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	if _, err := w.w.WriteString("</DL><p>\n"); err != nil {
+		return err
+	}
+	return w.w.Flush()
+}
+
+func (w *Writer) start() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+	_, err := w.w.WriteString(header)
+	return err
+}
+
+// Parse takes every link along with the last heading before it, browsers nest folders while other services
+// export plain lists under headings.
+func Parse(r io.Reader) (bookmarks []Bookmark, err error) {
+	z := xhtml.NewTokenizer(r)
+	var folder strings.Builder
+	var current *Bookmark
+	inHeading := false
+
+	for {
+		switch z.Next() {
+		case xhtml.ErrorToken:
+			if z.Err() == io.EOF {
+				return bookmarks, nil
+			}
+			return nil, z.Err()
+		case xhtml.StartTagToken:
+			name, hasAttr := z.TagName()
+			switch atom.Lookup(name) {
+			case atom.H1, atom.H2, atom.H3:
+				inHeading = true
+				folder.Reset()
+			case atom.A:
+				current = &Bookmark{Folder: strings.TrimSpace(folder.String())}
+				for hasAttr {
+					var key, val []byte
+					key, val, hasAttr = z.TagAttr()
+					setAttr(current, string(key), string(val))
+				}
+			}
+		case xhtml.EndTagToken:
+			name, _ := z.TagName()
+			switch atom.Lookup(name) {
+			case atom.H1, atom.H2, atom.H3:
+				inHeading = false
+			case atom.A:
+				if current != nil && current.URL != "" {
+					current.Title = strings.Join(strings.Fields(current.Title), " ")
+					bookmarks = append(bookmarks, *current)
+				}
+				current = nil
+			}
+		case xhtml.TextToken:
+			if current != nil {
+				current.Title += string(z.Text())
+			} else if inHeading {
+				folder.Write(z.Text())
+			}
+		}
+	}
+}
+
+func setAttr(b *Bookmark, key, val string) {
+	switch key {
+	case "href":
+		b.URL = strings.TrimSpace(val)
+	// pocket names the saved date time_added
+	case "add_date", "time_added":
+		b.AddDate = unixTime(val)
+	case "last_modified":
+		b.LastModified = unixTime(val)
+	case "tags":
+		for _, t := range strings.Split(val, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				b.Tags = append(b.Tags, t)
+			}
+		}
+	}
+}
+
+func unixTime(s string) time.Time {
+	sec, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil || sec <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0).UTC()
+}
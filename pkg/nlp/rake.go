@@ -0,0 +1,85 @@
+package nlp
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const maxPhraseWords = 3
+
+type Phrase struct {
+	Text  string
+	Score float64
+}
+
+// KeyPhrases extracts the n best keyword phrases with RAKE.
+func KeyPhrases(text, language string, n int) []Phrase {
+	var candidates [][]string
+	for _, sentence := range Sentences(text, language) {
+		for _, fragment := range strings.FieldsFunc(sentence, isPhraseBreak) {
+			var run []string
+			flush := func() {
+				if len(run) > 0 && len(run) <= maxPhraseWords {
+					candidates = append(candidates, run)
+				}
+				run = nil
+			}
+			for _, w := range Words(fragment) {
+				if r := []rune(w); IsStopWord(language, w) || isNumber(w) || (len(r) == 1 && !isUnspaced(r[0])) {
+					flush()
+					continue
+				}
+				run = append(run, w)
+			}
+			flush()
+		}
+	}
+
+	freq := make(map[string]float64)
+	degree := make(map[string]float64)
+	for _, c := range candidates {
+		for _, w := range c {
+			freq[w]++
+			degree[w] += float64(len(c))
+		}
+	}
+
+	occurrences := make(map[string]int)
+	scores := make(map[string]float64)
+	for _, c := range candidates {
+		text := strings.Join(c, " ")
+		occurrences[text]++
+		if _, ok := scores[text]; ok {
+			continue
+		}
+		for _, w := range c {
+			scores[text] += degree[w] / freq[w]
+		}
+	}
+
+	phrases := make([]Phrase, 0, len(scores))
+	for text, score := range scores {
+		phrases = append(phrases, Phrase{Text: text, Score: score * (1 + math.Log(float64(occurrences[text])))})
+	}
+	sort.Slice(phrases, func(i, j int) bool {
+		if phrases[i].Score != phrases[j].Score {
+			return phrases[i].Score > phrases[j].Score
+		}
+		return phrases[i].Text < phrases[j].Text
+	})
+	if len(phrases) > n {
+		phrases = phrases[:n]
+	}
+	return phrases
+}
+
+// isPhraseBreak keeps apostrophes and hyphens as part of words.
+func isPhraseBreak(r rune) bool {
+	switch r {
+	case '\'', '’', '-':
+		return false
+	}
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
@@ -0,0 +1,58 @@
+package nlp
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyPhrases(t *testing.T) {
+	cases := []struct {
+		name     string
+		text     string
+		n        int
+		expected []Phrase
+	}{
+		{
+			name:     "should return no phrases for empty text",
+			text:     "",
+			n:        5,
+			expected: []Phrase{},
+		},
+		{
+			name: "should score phrases by degree over frequency and boost repeated ones",
+			text: "Btree indexes, partial indexes and btree indexes.",
+			n:    5,
+			// btree: degree 4, frequency 2 and indexes: degree 6, frequency 3, the phrase appears twice
+			expected: []Phrase{
+				{Text: "btree indexes", Score: 4 * (1 + math.Log(2))},
+				{Text: "partial indexes", Score: 4},
+			},
+		},
+		{
+			name:     "should return the n best phrases",
+			text:     "Btree indexes, partial indexes and btree indexes.",
+			n:        1,
+			expected: []Phrase{{Text: "btree indexes", Score: 4 * (1 + math.Log(2))}},
+		},
+		{
+			name:     "should break phrases on numbers and leave out long runs",
+			text:     "Postgres 16 adds incremental backup support.",
+			n:        5,
+			expected: []Phrase{{Text: "postgres", Score: 1}},
+		},
+		{
+			name:     "should order ties alphabetically",
+			text:     "Vacuum, analyze.",
+			n:        5,
+			expected: []Phrase{{Text: "analyze", Score: 1}, {Text: "vacuum", Score: 1}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, KeyPhrases(c.text, "english", c.n))
+		})
+	}
+}
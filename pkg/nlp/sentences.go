@@ -0,0 +1,135 @@
+package nlp
+
+import (
+	"strings"
+	"unicode"
+)
+
+// abbreviations are keyed by text search configuration, lowercase without their final period.
+var abbreviations = map[string]map[string]bool{
+	"english": set("mr", "mrs", "ms", "dr", "prof", "sr", "jr", "st", "mt", "vs", "etc", "e.g", "i.e", "inc", "ltd",
+		"co", "corp", "dept", "est", "fig", "approx", "no", "vol", "jan", "feb", "mar", "apr", "jun", "jul", "aug", "sep",
+		"sept", "oct", "nov", "dec", "u.s", "u.k", "a.m", "p.m"),
+	"indonesian": set("dr", "drs", "dra", "ir", "prof", "h", "hj", "bpk", "yth", "dll", "dsb", "dst", "dkk", "tsb",
+		"no", "jl", "kab", "kec", "a.n", "u.p", "s.h", "s.e", "s.t", "s.kom", "m.si"),
+	"german": set("dr", "prof", "hr", "fr", "nr", "str", "bzw", "usw", "ca", "vgl", "evtl", "ggf", "z.b", "d.h",
+		"u.a", "s.o", "s.u", "bspw", "inkl", "zzgl", "abs", "jh", "mio", "mrd"),
+	"french": set("m", "mm", "mme", "mlle", "dr", "pr", "etc", "cf", "p.ex", "env", "av", "bd", "n°", "vol", "chap",
+		"janv", "févr", "avr", "juil", "sept", "oct", "nov", "déc"),
+	"spanish": set("sr", "sra", "srta", "dr", "dra", "ud", "uds", "etc", "p.ej", "pág", "núm", "aprox", "av", "dto",
+		"ene", "feb", "mar", "abr", "jun", "jul", "ago", "sept", "oct", "nov", "dic"),
+	"portuguese": set("sr", "sra", "srta", "dr", "dra", "prof", "etc", "p.ex", "pág", "núm", "av", "aprox", "jan",
+		"fev", "mar", "abr", "mai", "jun", "jul", "ago", "set", "out", "nov", "dez"),
+	"italian": set("sig", "sigg", "sig.ra", "dott", "dott.ssa", "prof", "ing", "avv", "ecc", "pag", "n", "es", "ca"),
+	"dutch":   set("dhr", "mevr", "mr", "dr", "prof", "ir", "bijv", "enz", "o.a", "d.w.z", "m.b.t", "ca", "nr", "blz"),
+}
+
+// ordinalLanguages write ordinals with a trailing period, like "3. Oktober".
+var ordinalLanguages = set("german", "danish", "norwegian", "finnish", "hungarian", "turkish", "serbian")
+
+func set(words ...string) map[string]bool {
+	m := make(map[string]bool, len(words))
+	for _, w := range words {
+		m[w] = true
+	}
+	return m
+}
+
+// Sentences always breaks on a line break, within a line the language decides which periods end a sentence.
+func Sentences(text, language string) (sentences []string) {
+	for _, line := range strings.Split(text, "\n") {
+		sentences = append(sentences, lineSentences(line, language)...)
+	}
+	return
+}
+
+func lineSentences(line, language string) (sentences []string) {
+	runes := []rune(line)
+	start := 0
+	for i := 0; i < len(runes); i++ {
+		end, ok := sentenceEnd(runes, i, language)
+		if !ok {
+			continue
+		}
+		if s := strings.TrimSpace(string(runes[start:end])); s != "" {
+			sentences = append(sentences, s)
+		}
+		start, i = end, end-1
+	}
+	if s := strings.TrimSpace(string(runes[start:])); s != "" {
+		sentences = append(sentences, s)
+	}
+	return
+}
+
+// sentenceEnd includes closing quotes and brackets after the terminator.
+func sentenceEnd(runes []rune, i int, language string) (int, bool) {
+	r := runes[i]
+	switch r {
+	case '。', '！', '？', '｡':
+		// full width terminators don't need a space after them
+		return closing(runes, i+1), true
+	case '।', '॥', '؟', '։', '።', '…', '!', '?', '.', ';':
+	default:
+		return 0, false
+	}
+	if r == ';' && language != "greek" {
+		// the greek question mark looks like a semicolon
+		return 0, false
+	}
+
+	end := closing(runes, i+1)
+	// terminators followed by anything but a space are part of a number, an url or an ellipsis
+	if end < len(runes) && !unicode.IsSpace(runes[end]) {
+		return 0, false
+	}
+	if r == '.' && !periodEnds(runes, i, end, language) {
+		return 0, false
+	}
+	return end, true
+}
+
+func closing(runes []rune, i int) int {
+	for i < len(runes) && strings.ContainsRune(`"'”’»)]」』`, runes[i]) {
+		i++
+	}
+	return i
+}
+
+// periodEnds tells apart periods ending an abbreviation, an initial or an ordinal.
+func periodEnds(runes []rune, i, end int, language string) bool {
+	start := i
+	for start > 0 && !unicode.IsSpace(runes[start-1]) && !strings.ContainsRune(`"'“‘«([`, runes[start-1]) {
+		start--
+	}
+	word := strings.ToLower(string(runes[start:i]))
+	if word == "" {
+		return true
+	}
+	if abbreviations[language][word] {
+		return false
+	}
+	if n := []rune(word); len(n) == 1 && unicode.IsLetter(n[0]) && unicode.IsUpper(runes[start]) {
+		// an initial like the J in "J. R. R. Tolkien"
+		return false
+	}
+	if ordinalLanguages[language] && isNumber(word) {
+		return false
+	}
+
+	// a lowercase start continues the sentence
+	next := end
+	for next < len(runes) && unicode.IsSpace(runes[next]) {
+		next++
+	}
+	return next == len(runes) || !unicode.IsLower(runes[next])
+}
+
+func isNumber(word string) bool {
+	for _, r := range word {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
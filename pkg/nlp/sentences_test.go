@@ -0,0 +1,83 @@
+package nlp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSentences(t *testing.T) {
+	cases := []struct {
+		name     string
+		text     string
+		language string
+		expected []string
+	}{
+		{
+			name:     "should return no sentences for empty text",
+			text:     "",
+			language: "english",
+			expected: nil,
+		},
+		{
+			name:     "should split on terminators followed by a space",
+			text:     "Postgres is fast. Is it? It is!",
+			language: "english",
+			expected: []string{"Postgres is fast.", "Is it?", "It is!"},
+		},
+		{
+			name:     "should end a sentence at every line break",
+			text:     "Indexes\nA btree index sorts its keys",
+			language: "english",
+			expected: []string{"Indexes", "A btree index sorts its keys"},
+		},
+		{
+			name:     "should keep abbreviations and initials within the sentence",
+			text:     "Dr. Smith met J. R. R. Tolkien at 5 p.m. on Monday. They talked.",
+			language: "english",
+			expected: []string{"Dr. Smith met J. R. R. Tolkien at 5 p.m. on Monday.", "They talked."},
+		},
+		{
+			name:     "should keep numbers, urls and ellipses within the sentence",
+			text:     "Version 3.14 is on example.com now... Try it.",
+			language: "english",
+			expected: []string{"Version 3.14 is on example.com now...", "Try it."},
+		},
+		{
+			name:     "should not end a sentence before a lowercase word",
+			text:     "The speed-up was approx. twice as fast.",
+			language: "english",
+			expected: []string{"The speed-up was approx. twice as fast."},
+		},
+		{
+			name:     "should keep closing quotes with the sentence",
+			text:     `He said "it works." Then he left.`,
+			language: "english",
+			expected: []string{`He said "it works."`, "Then he left."},
+		},
+		{
+			name:     "should keep german ordinals within the sentence",
+			text:     "Am 3. Oktober ist Feiertag. Dann ruhen wir.",
+			language: "german",
+			expected: []string{"Am 3. Oktober ist Feiertag.", "Dann ruhen wir."},
+		},
+		{
+			name:     "should split full width terminators without a space",
+			text:     "今日は晴れです。明日は雨です。",
+			language: "simple",
+			expected: []string{"今日は晴れです。", "明日は雨です。"},
+		},
+		{
+			name:     "should split on the greek question mark",
+			text:     "Τι κάνεις; Καλά.",
+			language: "greek",
+			expected: []string{"Τι κάνεις;", "Καλά."},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, Sentences(c.text, c.language))
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package nlp
+
+import (
+	"hash/fnv"
+	"math/bits"
+)
+
+// shingleSize words are hashed together, an edited word only changes the few shingles it's part of.
+const shingleSize = 3
+
+// SimHash fingerprints the words so near-identical texts differ in only a few bits.
+func SimHash(words []string) uint64 {
+	if len(words) == 0 {
+		return 0
+	}
+
+	var votes [64]int
+	shingles := max(len(words)-shingleSize+1, 1)
+	for i := 0; i < shingles; i++ {
+		h := fnv.New64a()
+		for _, w := range words[i:min(i+shingleSize, len(words))] {
+			h.Write([]byte(w))
+			h.Write([]byte{0})
+		}
+		sum := mix(h.Sum64())
+		for b := range votes {
+			if sum&(1<<b) != 0 {
+				votes[b]++
+			} else {
+				votes[b]--
+			}
+		}
+	}
+
+	var fingerprint uint64
+	for b, v := range votes {
+		if v > 0 {
+			fingerprint |= 1 << b
+		}
+	}
+	return fingerprint
+}
+
+// mix spreads the fnv hash of short inputs over every bit.
+func mix(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
@@ -0,0 +1,90 @@
+package nlp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimHash(t *testing.T) {
+	text := strings.Repeat("postgres builds btree indexes on the columns queries filter and sort by ", 8)
+	cases := []struct {
+		name        string
+		a           []string
+		b           []string
+		maxDistance int
+		minDistance int
+	}{
+		{
+			name:        "should fingerprint identical words the same",
+			a:           Words(text),
+			b:           Words(text),
+			maxDistance: 0,
+			minDistance: 0,
+		},
+		{
+			name:        "should keep near-identical texts a few bits apart",
+			a:           Words(text),
+			b:           Words(text + "updated"),
+			maxDistance: 6,
+			minDistance: 0,
+		},
+		{
+			name:        "should keep unrelated texts far apart",
+			a:           Words(text),
+			b:           Words(strings.Repeat("this domain is for sale contact the owner to make an offer today ", 8)),
+			maxDistance: 64,
+			minDistance: 16,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			distance := HammingDistance(SimHash(c.a), SimHash(c.b))
+			assert.LessOrEqual(t, distance, c.maxDistance)
+			assert.GreaterOrEqual(t, distance, c.minDistance)
+		})
+	}
+}
+
+func TestSimHashShortInput(t *testing.T) {
+	assert.Equal(t, uint64(0), SimHash(nil))
+	// fewer words than a shingle are hashed as one
+	assert.NotEqual(t, uint64(0), SimHash([]string{"postgres"}))
+	assert.Equal(t, SimHash([]string{"postgres", "indexes"}), SimHash([]string{"postgres", "indexes"}))
+}
+
+func TestHammingDistance(t *testing.T) {
+	cases := []struct {
+		name     string
+		a        uint64
+		b        uint64
+		expected int
+	}{
+		{
+			name:     "should be zero for equal fingerprints",
+			a:        0xdeadbeef,
+			b:        0xdeadbeef,
+			expected: 0,
+		},
+		{
+			name:     "should count differing bits",
+			a:        0b1010,
+			b:        0b0110,
+			expected: 2,
+		},
+		{
+			name:     "should count every bit for complements",
+			a:        0,
+			b:        ^uint64(0),
+			expected: 64,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, HammingDistance(c.a, c.b))
+		})
+	}
+}
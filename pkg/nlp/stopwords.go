@@ -0,0 +1,71 @@
+package nlp
+
+import "strings"
+
+// stopWords are keyed by text search configuration, languages without a list have no stop words.
+var stopWords = map[string]map[string]bool{
+	"english": fields(`a about above after again against all am an and any are aren't as at be because been before
+		being below between both but by can can't cannot could couldn't did didn't do does doesn't doing don't down
+		during each few for from further had hadn't has hasn't have haven't having he he'd he'll he's her here here's
+		hers herself him himself his how how's i i'd i'll i'm i've if in into is isn't it it's its itself just let's
+		like may me might more most much must mustn't my myself no nor not now of off on once only or other ought our
+		ours ourselves out over own same shall shan't she she'd she'll she's should shouldn't so some such than that
+		that's the their theirs them themselves then there there's these they they'd they'll they're they've this
+		those through to too under until up upon us very via was wasn't we we'd we'll we're we've were weren't what
+		what's when when's where where's whether which while who who's whom why why's will with won't would wouldn't
+		yet you you'd you'll you're you've your yours yourself yourselves also however still even ever every many
+		one two get got make made use used using new`),
+	"indonesian": fields(`ada adalah adanya agak agar akan akankah akhirnya aku akulah amat anda andalah antara
+		apa apaan apabila apakah apalagi atau ataukah ataupun bagai bagaimana bagaimanakah bagi bahkan bahwa
+		bahwasanya baik banyak barangkali bawah beberapa begini begitu belum berapa berbagai bersama betapa biasa
+		bila bilamana bisa boleh bukan bukankah dalam dan dapat dari daripada demi demikian dengan di dia dialah
+		diri dirinya dong dulu hal hampir hanya harus hingga ia ialah ini inilah itu itulah jadi jangan jika jikalau
+		juga justru kalau kami kamilah kamu kamulah kan karena kata ke kemudian kenapa kepada ketika kita kitalah
+		lagi lain lalu lebih maka makin malah mampu mana masih maupun melalui memang mereka merekalah meski
+		meskipun mungkin namun nanti oleh pada padahal para pasti per perlu pernah pula pun punya saat saja sambil
+		sampai sana sangat satu saya sayalah se sebab sebagai sebagaimana sebelum sebuah secara sedang sedangkan
+		segala sehingga sejak selain selalu sementara semua sendiri seorang seperti sering serta sesuatu setelah
+		setiap siapa suatu sudah supaya tak tanpa tapi telah tentang tentu terhadap tersebut tetapi tidak toh
+		untuk walau walaupun ya yaitu yakni yang`),
+	"german": fields(`aber alle allem allen aller alles als also am an ander andere anderem anderen anderer
+		anderes auch auf aus bei bin bis bist da damit dann das dass dasselbe dazu dein deine dem den denn der des
+		dich die dies diese diesem diesen dieser dieses dir doch dort du durch ein eine einem einen einer eines er
+		es etwas euch euer eure für gegen gewesen hab habe haben hat hatte hatten hier hin hinter ich ihm ihn ihnen
+		ihr ihre im in indem ins ist jede jedem jeden jeder jedes jene jetzt kann kein keine können könnte machen
+		man manche mein meine mich mir mit muss musste nach nicht nichts noch nun nur ob oder ohne sehr sein seine
+		sich sie sind so solche soll sollte sondern sonst über um und uns unser unter viel vom von vor war waren
+		warum was weil welche wenn werde werden wie wieder will wir wird wo wollen würde zu zum zur zwar zwischen`),
+	"french": fields(`a ai aie aient ait alors as au aucun aussi autre aux avec avoir avait bon c ça car ce cela
+		ces cet cette ceux chaque ci comme comment d dans de des donc dont du elle elles en encore est et été être
+		eu fait faire fois font hors ici il ils j je juste l la le les leur leurs lui m ma mais me même mes moi mon
+		n ne ni nos notre nous on ont ou où par parce pas peu peut plus pour pourquoi qu quand que quel quelle
+		quelles quels qui s sa sans se ses si sien son sont sous sur t ta te tes toi ton tous tout toute toutes
+		très tu un une vos votre vous y`),
+	"spanish": fields(`a al algo algunas algunos ante antes como con contra cual cuando de del desde donde durante
+		e el ella ellas ellos en entre era eran es esa esas ese eso esos esta está están estas este esto estos fue
+		fueron ha había han hasta hay la las le les lo los más me mi mis mucho muy nada ni no nos nosotros o os otra
+		otras otro otros para pero poco por porque que quien quienes se sea ser si sí sin sobre son su sus también
+		tanto te tiene tienen todo todos tu tus un una uno unos y ya yo`),
+	"portuguese": fields(`a ao aos aquela aquele aquilo as até com como da das de dela dele deles depois do dos e
+		ela elas ele eles em entre era eram essa esse esta está estão este eu foi foram há isso isto já la lhe
+		lhes mais mas me mesmo meu minha muito na não nas nem no nos nós num numa o os ou para pela pelas pelo pelos
+		por quando que quem se sem ser seu seus só sua suas também te tem têm teu tu tua um uma você vocês`),
+	"italian": fields(`a ad al alla alle allo agli ai anche avere c che chi ci come con contro cui da dal dalla
+		dalle dei del della delle dello di dove e è ed era erano essere gli ha hanno ho i il in io la le lei li lo
+		loro lui ma mi mia mio molto ne nei nel nella nelle noi non nostro o per perché più poi quale quando quella
+		quelle quello questa queste questo se sei si sia siamo sono su sua sue sui sul sulla suo tra tu tutti tutto
+		un una uno voi`),
+	"dutch": fields(`aan al alles als altijd andere ben bij daar dan dat de der deze die dit doch doen door dus
+		een eens en er ge geen geweest haar had heb hebben heeft hem het hier hij hoe hun iemand iets ik in is ja je
+		kan kon kunnen maar me meer men met mij mijn moet na naar niet niets nog nu of om omdat onder ons ook op over
+		reeds te tegen toch toen tot u uit uw van veel voor want waren was wat we wel werd wezen wie wij wil worden
+		zal ze zei zelf zich zij zijn zo zonder zou`),
+}
+
+func fields(words string) map[string]bool {
+	return set(strings.Fields(words)...)
+}
+
+func IsStopWord(language, word string) bool {
+	return stopWords[language][word]
+}
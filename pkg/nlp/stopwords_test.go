@@ -0,0 +1,53 @@
+package nlp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsStopWord(t *testing.T) {
+	cases := []struct {
+		name     string
+		language string
+		word     string
+		expected bool
+	}{
+		{
+			name:     "should match an english stop word",
+			language: "english",
+			word:     "the",
+			expected: true,
+		},
+		{
+			name:     "should not match a content word",
+			language: "english",
+			word:     "postgres",
+			expected: false,
+		},
+		{
+			name:     "should only match lowercase words",
+			language: "english",
+			word:     "The",
+			expected: false,
+		},
+		{
+			name:     "should match the stop words of the language only",
+			language: "german",
+			word:     "the",
+			expected: false,
+		},
+		{
+			name:     "should not match in a language without stop words",
+			language: "simple",
+			word:     "the",
+			expected: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, IsStopWord(c.language, c.word))
+		})
+	}
+}
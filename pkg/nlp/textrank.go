@@ -0,0 +1,120 @@
+package nlp
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	// minSummaryWords leaves headings and captions out of summaries.
+	minSummaryWords = 5
+	// maxRankedSentences bounds the sentence graph, which grows quadratically.
+	maxRankedSentences = 400
+
+	damping    = 0.85
+	iterations = 50
+	tolerance  = 1e-5
+)
+
+// Summarize returns the n most central sentences in the order they appear, ranked with TextRank.
+func Summarize(text, language string, n int) []string {
+	all := Sentences(text, language)
+	var sentences []string
+	var words []map[string]bool
+	for _, s := range all {
+		if len(Words(s)) < minSummaryWords {
+			continue
+		}
+		sentences = append(sentences, s)
+		words = append(words, set(ContentWords(s, language)...))
+		if len(sentences) == maxRankedSentences {
+			break
+		}
+	}
+	if len(sentences) == 0 {
+		return all[:min(n, len(all))]
+	}
+	if len(sentences) <= n {
+		return sentences
+	}
+
+	scores := rank(similarities(words))
+	order := make([]int, len(sentences))
+	for i := range order {
+		order[i] = i
+	}
+	// ties go to the earlier sentence
+	sort.SliceStable(order, func(a, b int) bool {
+		return scores[order[a]] > scores[order[b]]
+	})
+	top := order[:n]
+	sort.Ints(top)
+
+	summary := make([]string, n)
+	for i, idx := range top {
+		summary[i] = sentences[idx]
+	}
+	return summary
+}
+
+// similarities normalizes shared words by sentence length so long sentences aren't favored.
+func similarities(words []map[string]bool) [][]float64 {
+	sim := make([][]float64, len(words))
+	for i := range sim {
+		sim[i] = make([]float64, len(words))
+	}
+	for i := range words {
+		for j := i + 1; j < len(words); j++ {
+			shared := 0
+			for w := range words[i] {
+				if words[j][w] {
+					shared++
+				}
+			}
+			if shared == 0 {
+				continue
+			}
+			norm := math.Log(float64(len(words[i]))) + math.Log(float64(len(words[j])))
+			if norm <= 0 {
+				norm = 1
+			}
+			sim[i][j] = float64(shared) / norm
+			sim[j][i] = sim[i][j]
+		}
+	}
+	return sim
+}
+
+func rank(sim [][]float64) []float64 {
+	n := len(sim)
+	out := make([]float64, n)
+	for i := range sim {
+		for _, w := range sim[i] {
+			out[i] += w
+		}
+	}
+
+	scores := make([]float64, n)
+	for i := range scores {
+		scores[i] = 1
+	}
+	next := make([]float64, n)
+	for it := 0; it < iterations; it++ {
+		delta := 0.0
+		for i := range sim {
+			sum := 0.0
+			for j := range sim {
+				if sim[j][i] > 0 {
+					sum += sim[j][i] / out[j] * scores[j]
+				}
+			}
+			next[i] = 1 - damping + damping*sum
+			delta += math.Abs(next[i] - scores[i])
+		}
+		scores, next = next, scores
+		if delta < tolerance {
+			break
+		}
+	}
+	return scores
+}
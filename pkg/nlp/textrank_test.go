@@ -0,0 +1,60 @@
+package nlp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarize(t *testing.T) {
+	central := "Postgres indexes speed up slow queries. Btree indexes keep the keys sorted for range scans. " +
+		"The postgres planner picks indexes for queries with range scans. Cooking pasta takes about ten minutes in boiling water."
+
+	cases := []struct {
+		name     string
+		text     string
+		n        int
+		expected []string
+	}{
+		{
+			name:     "should return no sentences for empty text",
+			text:     "",
+			n:        3,
+			expected: nil,
+		},
+		{
+			name:     "should summarize fragments by the first ones",
+			text:     "Intro\nSetup\nUsage",
+			n:        2,
+			expected: []string{"Intro", "Setup"},
+		},
+		{
+			name:     "should return every sentence and leave out fragments when there are fewer than n",
+			text:     "Indexes\nPostgres indexes make lookups fast. Btree indexes keep their keys sorted.",
+			n:        3,
+			expected: []string{"Postgres indexes make lookups fast.", "Btree indexes keep their keys sorted."},
+		},
+		{
+			name:     "should pick the most central sentence",
+			text:     central,
+			n:        1,
+			expected: []string{"The postgres planner picks indexes for queries with range scans."},
+		},
+		{
+			name: "should keep the picked sentences in reading order",
+			text: central,
+			n:    3,
+			expected: []string{
+				"Postgres indexes speed up slow queries.",
+				"Btree indexes keep the keys sorted for range scans.",
+				"The postgres planner picks indexes for queries with range scans.",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, Summarize(c.text, "english", c.n))
+		})
+	}
+}
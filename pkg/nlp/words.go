@@ -0,0 +1,57 @@
+package nlp
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Words splits text into lowercase words, scripts without spaces are split into single characters.
+func Words(text string) (words []string) {
+	var b strings.Builder
+	flush := func() {
+		if b.Len() > 0 {
+			words = append(words, b.String())
+			b.Reset()
+		}
+	}
+
+	runes := []rune(text)
+	for i, r := range runes {
+		switch {
+		case isUnspaced(r):
+			flush()
+			words = append(words, string(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r):
+			b.WriteRune(unicode.ToLower(r))
+		case (r == '\'' || r == '’' || r == '-') && b.Len() > 0 && i+1 < len(runes) && unicode.IsLetter(runes[i+1]):
+			if r == '’' {
+				r = '\''
+			}
+			b.WriteRune(r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return
+}
+
+func isUnspaced(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Thai, unicode.Lao, unicode.Khmer, unicode.Myanmar)
+}
+
+// ContentWords leaves out stop words, numbers and single letters.
+func ContentWords(text, language string) []string {
+	words := Words(text)
+	content := words[:0]
+	for _, w := range words {
+		if IsStopWord(language, w) || isNumber(w) {
+			continue
+		}
+		if r := []rune(w); len(r) == 1 && !isUnspaced(r[0]) {
+			continue
+		}
+		content = append(content, w)
+	}
+	return content
+}
@@ -0,0 +1,87 @@
+package nlp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWords(t *testing.T) {
+	cases := []struct {
+		name     string
+		text     string
+		expected []string
+	}{
+		{
+			name:     "should return no words for empty text",
+			text:     "",
+			expected: nil,
+		},
+		{
+			name:     "should lowercase words and drop punctuation",
+			text:     "Postgres, MySQL & SQLite!",
+			expected: []string{"postgres", "mysql", "sqlite"},
+		},
+		{
+			name:     "should keep apostrophes and hyphens inside words",
+			text:     "It’s a well-known don't -dash- trick'",
+			expected: []string{"it's", "a", "well-known", "don't", "dash", "trick"},
+		},
+		{
+			name:     "should keep digits and accents",
+			text:     "Café 2024 naïve",
+			expected: []string{"café", "2024", "naïve"},
+		},
+		{
+			name:     "should split unspaced scripts into characters",
+			text:     "Go语言 test",
+			expected: []string{"go", "语", "言", "test"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, Words(c.text))
+		})
+	}
+}
+
+func TestContentWords(t *testing.T) {
+	cases := []struct {
+		name     string
+		text     string
+		language string
+		expected []string
+	}{
+		{
+			name:     "should leave out stop words, numbers and single letters",
+			text:     "The 3 indexes of a table in Postgres",
+			language: "english",
+			expected: []string{"indexes", "table", "postgres"},
+		},
+		{
+			name:     "should use the stop words of the language",
+			text:     "Die Indizes der Tabelle",
+			language: "german",
+			expected: []string{"indizes", "tabelle"},
+		},
+		{
+			name:     "should keep every word of a language without stop words",
+			text:     "the btree",
+			language: "simple",
+			expected: []string{"the", "btree"},
+		},
+		{
+			name:     "should keep single unspaced characters",
+			text:     "语言",
+			language: "simple",
+			expected: []string{"语", "言"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, ContentWords(c.text, c.language))
+		})
+	}
+}
@@ -2,6 +2,9 @@ package sanitizer
 
 import "github.com/microcosm-cc/bluemonday"
 
+// Version is bumped whenever the policy changes so older content is migrated.
+const Version = 1
+
 type Sanitizer interface {
 	Sanitize(s string) string
 }
@@ -0,0 +1,95 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ryanadiputraa/unclatter/pkg/logger"
+)
+
+// Job receives a context canceled when the scheduler stops.
+type Job func(ctx context.Context) error
+
+type Scheduler interface {
+	// Every must be called before Start.
+	Every(name string, interval time.Duration, job Job)
+	Start()
+	Stop()
+}
+
+type entry struct {
+	name     string
+	interval time.Duration
+	job      Job
+}
+
+type scheduler struct {
+	log     logger.Logger
+	entries []entry
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func NewScheduler(log logger.Logger) Scheduler {
+	return &scheduler{
+		log: log,
+	}
+}
+
+func (s *scheduler) Every(name string, interval time.Duration, job Job) {
+	s.entries = append(s.entries, entry{name: name, interval: interval, job: job})
+}
+
+func (s *scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, e := range s.entries {
+		s.wg.Add(1)
+		go s.run(ctx, e)
+	}
+}
+
+func (s *scheduler) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *scheduler) run(ctx context.Context, e entry) {
+	defer s.wg.Done()
+
+	// time.NewTicker panics on non-positive intervals
+	if e.interval <= 0 {
+		s.log.Error("scheduler: job", e.name, "has invalid interval", e.interval)
+		return
+	}
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		s.exec(ctx, e)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *scheduler) exec(ctx context.Context, e entry) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.log.Error("scheduler: job", e.name, "panicked", r)
+		}
+	}()
+
+	if err := e.job(ctx); err != nil {
+		s.log.Error("scheduler: job", e.name, "failed", err)
+	}
+}
@@ -0,0 +1,58 @@
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ryanadiputraa/unclatter/pkg/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvery(t *testing.T) {
+	cases := []struct {
+		name     string
+		interval time.Duration
+		ran      bool
+	}{
+		{
+			name:     "should run job with a positive interval",
+			interval: time.Hour,
+			ran:      true,
+		},
+		{
+			name:     "should skip job with a zero interval",
+			interval: 0,
+			ran:      false,
+		},
+		{
+			name:     "should skip job with a negative interval",
+			interval: -time.Second,
+			ran:      false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var runs atomic.Int32
+			ran := make(chan struct{}, 1)
+			s := NewScheduler(logger.NewLogger())
+			s.Every("test", c.interval, func(ctx context.Context) error {
+				runs.Add(1)
+				ran <- struct{}{}
+				return nil
+			})
+			s.Start()
+
+			if c.ran {
+				select {
+				case <-ran:
+				case <-time.After(5 * time.Second):
+				}
+			}
+			s.Stop()
+			assert.Equal(t, c.ran, runs.Load() > 0)
+		})
+	}
+}
@@ -2,39 +2,114 @@ package scrapper
 
 import (
 	"fmt"
+	"io"
+	"net/http"
+	"strings"
 
+	"github.com/PuerkitoBio/goquery"
 	"github.com/gocolly/colly/v2"
+	"github.com/ryanadiputraa/unclatter/pkg/netguard"
 )
 
+const (
+	readableSelectors = "p, blockquote, pre, code, var"
+	// keywords past maxKeywords are usually keyword stuffing
+	maxKeywords      = 50
+	maxKeywordLength = 256
+)
+
+// Page keywords are the meta keywords and article tags.
+type Page struct {
+	Content  string
+	Keywords []string
+}
+
 type Scrapper interface {
-	ScrapeTextContent(url string) (string, error)
+	ScrapePage(url string) (*Page, error)
 }
 
 type scrapper struct {
 	c *colly.Collector
 }
 
+// NewScrapper only connects to public addresses.
 func NewScrapper() Scrapper {
+	return newScrapper(netguard.NewTransport())
+}
+
+func newScrapper(transport http.RoundTripper) *scrapper {
 	c := colly.NewCollector()
 	c.AllowURLRevisit = true
+	c.WithTransport(transport)
 	return &scrapper{
 		c: c,
 	}
 }
 
-func (s *scrapper) ScrapeTextContent(url string) (content string, err error) {
-	s.c.OnHTML("body", func(h *colly.HTMLElement) {
-		selectors := "p, blockquote, pre, code, var"
-		h.ForEach(selectors, func(i int, h *colly.HTMLElement) {
-			content += fmt.Sprintf("<%s>%s</%s>", h.Name, h.Text, h.Name)
-		})
+func (s *scrapper) ScrapePage(url string) (*Page, error) {
+	page := new(Page)
+	var err error
+
+	// callbacks are registered on a clone so they don't pile up on the shared collector
+	c := s.c.Clone()
+	c.OnHTML("html", func(h *colly.HTMLElement) {
+		page.Keywords = Keywords(h.DOM)
+	})
+	c.OnHTML("body", func(h *colly.HTMLElement) {
+		page.Content += Readable(h.DOM)
 	})
 
-	s.c.OnError(func(r *colly.Response, e error) {
+	c.OnError(func(r *colly.Response, e error) {
 		err = e
 	})
 
-	err = s.c.Visit(url)
+	err = c.Visit(url)
+
+	return page, err
+}
 
-	return content, err
+// Readable includes nested elements, like code in pre, once for each.
+func Readable(s *goquery.Selection) string {
+	var b strings.Builder
+	s.Find(readableSelectors).Each(func(_ int, e *goquery.Selection) {
+		name := goquery.NodeName(e)
+		fmt.Fprintf(&b, "<%s>%s</%s>", name, e.Text(), name)
+	})
+	return b.String()
+}
+
+func ReadableHTML(r io.Reader) (string, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return "", err
+	}
+	return Readable(doc.Find("body")), nil
+}
+
+// Keywords returns the meta keywords and article:tag properties without duplicates.
+func Keywords(s *goquery.Selection) []string {
+	var keywords []string
+	seen := make(map[string]bool)
+	add := func(keyword string) {
+		keyword = strings.TrimSpace(keyword)
+		key := strings.ToLower(keyword)
+		if keyword == "" || len(keyword) > maxKeywordLength || seen[key] || len(keywords) == maxKeywords {
+			return
+		}
+		seen[key] = true
+		keywords = append(keywords, keyword)
+	}
+
+	s.Find("meta").Each(func(_ int, m *goquery.Selection) {
+		content := m.AttrOr("content", "")
+		switch {
+		case strings.EqualFold(m.AttrOr("name", ""), "keywords"):
+			for _, keyword := range strings.Split(content, ",") {
+				add(keyword)
+			}
+		case strings.EqualFold(m.AttrOr("property", ""), "article:tag"):
+			add(content)
+		}
+	})
+	return keywords
 }
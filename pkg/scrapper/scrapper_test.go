@@ -0,0 +1,132 @@
+package scrapper
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/ryanadiputraa/unclatter/pkg/netguard"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadableHTML(t *testing.T) {
+	cases := []struct {
+		name     string
+		page     string
+		expected string
+	}{
+		{
+			name:     "should keep readable elements in order",
+			page:     `<html><head><title>Title</title></head><body><h1>Heading</h1><p>First &amp; <b>bold</b></p><blockquote>Quote</blockquote><div>Skipped</div><var>x</var></body></html>`,
+			expected: "<p>First & bold</p><blockquote>Quote</blockquote><var>x</var>",
+		},
+		{
+			name:     "should include nested elements once for each",
+			page:     `<body><pre><code>go run .</code></pre></body>`,
+			expected: "<pre>go run .</pre><code>go run .</code>",
+		},
+		{
+			name:     "should return empty content without readable elements",
+			page:     `<body><div>Nothing</div></body>`,
+			expected: "",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			content, err := ReadableHTML(strings.NewReader(c.page))
+			assert.Nil(t, err)
+			assert.Equal(t, c.expected, content)
+		})
+	}
+}
+
+func TestKeywords(t *testing.T) {
+	cases := []struct {
+		name     string
+		head     string
+		expected []string
+	}{
+		{
+			name:     "should split meta keywords and add article tags",
+			head:     `<meta name="Keywords" content="postgres, indexes,, performance"><meta property="article:tag" content="Databases"><meta property="article:tag" content="Postgres">`,
+			expected: []string{"postgres", "indexes", "performance", "Databases"},
+		},
+		{
+			name:     "should ignore other meta tags",
+			head:     `<meta name="description" content="postgres"><meta property="og:title" content="Indexes">`,
+			expected: nil,
+		},
+		{
+			name:     "should skip overly long keywords",
+			head:     `<meta name="keywords" content="` + strings.Repeat("a", maxKeywordLength+1) + `, postgres">`,
+			expected: []string{"postgres"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader("<html><head>" + c.head + "</head><body></body></html>"))
+			assert.Nil(t, err)
+			assert.Equal(t, c.expected, Keywords(doc.Selection))
+		})
+	}
+}
+
+func TestScrapePageMatchesReadableHTML(t *testing.T) {
+	page := `<head><meta name="keywords" content="go, testing"></head><body><p>Intro</p><pre><code>go test ./...</code></pre><blockquote><p>Nested</p></blockquote></body>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(page))
+	}))
+	defer server.Close()
+
+	scraped, err := newScrapper(http.DefaultTransport).ScrapePage(server.URL)
+	assert.Nil(t, err)
+	content, err := ReadableHTML(strings.NewReader(page))
+	assert.Nil(t, err)
+	assert.Equal(t, content, scraped.Content)
+	assert.Equal(t, []string{"go", "testing"}, scraped.Keywords)
+}
+
+func TestScrapePageKeepsPagesApart(t *testing.T) {
+	pages := map[string]string{
+		"/a": `<body><p>First</p></body>`,
+		"/b": `<body><p>Second</p></body>`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(pages[r.URL.Path]))
+	}))
+	defer server.Close()
+
+	// the scrapper is shared by every caller, a page's callbacks shouldn't run again for the next one
+	s := newScrapper(http.DefaultTransport)
+	for _, c := range []struct {
+		path     string
+		expected string
+	}{
+		{path: "/a", expected: "<p>First</p>"},
+		{path: "/b", expected: "<p>Second</p>"},
+		{path: "/a", expected: "<p>First</p>"},
+	} {
+		page, err := s.ScrapePage(server.URL + c.path)
+		assert.Nil(t, err)
+		assert.Equal(t, c.expected, page.Content)
+	}
+}
+
+func TestScrapePageRefusesNonPublicAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(`<body><p>Internal</p></body>`))
+	}))
+	defer server.Close()
+
+	page, err := NewScrapper().ScrapePage(server.URL)
+	assert.True(t, errors.Is(err, netguard.ErrNonPublicAddress))
+	assert.Empty(t, page.Content)
+}
@@ -50,6 +50,14 @@ func FieldErrMsg(err validator.FieldError) string {
 		return fmt.Sprintf("%s should have a maximum length of %s", field, err.Param())
 	case "min":
 		return fmt.Sprintf("%s should have a minimum length of %s", field, err.Param())
+	case "gte":
+		return fmt.Sprintf("%s should be greater than or equal to %s", field, err.Param())
+	case "lte":
+		return fmt.Sprintf("%s should be less than or equal to %s", field, err.Param())
+	case "gtefield":
+		return fmt.Sprintf("%s should be greater than or equal to %s", field, fieldToSnakeCase(err.Param()))
+	case "oneof":
+		return fmt.Sprintf("%s should be one of [%s]", field, err.Param())
 	case "email":
 		return fmt.Sprintf("%s should be a valid email address", field)
 	case "http_url":
@@ -0,0 +1,105 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ryanadiputraa/unclatter/pkg/netguard"
+)
+
+const (
+	EventHeader     = "X-Unclatter-Event"
+	TimestampHeader = "X-Unclatter-Timestamp"
+	// SignatureHeader is the hex hmac-sha256 of the timestamp, a dot and the body.
+	SignatureHeader = "X-Unclatter-Signature"
+
+	maxResponseSize = 4 << 10
+)
+
+type Request struct {
+	URL     string
+	Secret  string
+	Event   string
+	Payload any
+}
+
+type Sender interface {
+	Send(ctx context.Context, req Request) error
+	// CheckURL rejects urls that can't receive webhooks, like internal addresses.
+	CheckURL(ctx context.Context, url string) error
+}
+
+type sender struct {
+	client *http.Client
+}
+
+// NewSender doesn't follow redirects and only connects to public addresses.
+func NewSender(timeout time.Duration) Sender {
+	return &sender{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: netguard.NewTransport(),
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (s *sender) Send(ctx context.Context, req Request) error {
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported webhook url scheme %q", u.Scheme)
+	}
+	body, err := json.Marshal(req.Payload)
+	if err != nil {
+		return err
+	}
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("User-Agent", "Unclatter-Webhook")
+	r.Header.Set(EventHeader, req.Event)
+	r.Header.Set(TimestampHeader, timestamp)
+	r.Header.Set(SignatureHeader, "sha256="+Sign(req.Secret, timestamp, body))
+
+	res, err := s.client.Do(r)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(res.Body, maxResponseSize))
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d posting webhook", res.StatusCode)
+	}
+	return nil
+}
+
+func (s *sender) CheckURL(ctx context.Context, url string) error {
+	return netguard.CheckURL(ctx, url)
+}
+
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -31,31 +31,46 @@ var (
 		CreatedAt:      time.Now().UTC(),
 	}
 	TestArticle = &article.Article{
-		ID:          uuid.NewString(),
-		Title:       "Title",
-		Content:     "<div><a onblur=\"alert(secret)\" href=\"http://www.google.com\">Google</a><p>article content</p></div>",
-		ArticleLink: "https://unclatter.com",
-		UserID:      TestUser.ID,
-		CreatedAt:   time.Now().UTC(),
-		UpdatedAt:   time.Now().UTC(),
+		ID:             uuid.NewString(),
+		Title:          "Title",
+		Content:        "<div><a onblur=\"alert(secret)\" href=\"http://www.google.com\">Google</a><p>article content</p></div>",
+		ArticleLink:    "https://unclatter.com",
+		NormalizedLink: "https://unclatter.com",
+		Language:       "english",
+		Domain:         "unclatter.com",
+		ReadingTime:    1,
+		Version:        1,
+		UserID:         TestUser.ID,
+		CreatedAt:      time.Now().UTC(),
+		UpdatedAt:      time.Now().UTC(),
 	}
 	TestArticle2 = &article.Article{
-		ID:          uuid.NewString(),
-		Title:       "Title 2",
-		Content:     "<div><a onblur=\"alert(secret)\" href=\"http://www.google.com\">Google</a><p>article content 2</p></div>",
-		ArticleLink: "https://unclatter.com/2",
-		UserID:      TestUser.ID,
-		CreatedAt:   time.Now().UTC(),
-		UpdatedAt:   time.Now().UTC(),
+		ID:             uuid.NewString(),
+		Title:          "Title 2",
+		Content:        "<div><a onblur=\"alert(secret)\" href=\"http://www.google.com\">Google</a><p>article content 2</p></div>",
+		ArticleLink:    "https://unclatter.com/2",
+		NormalizedLink: "https://unclatter.com/2",
+		Language:       "english",
+		Domain:         "unclatter.com",
+		ReadingTime:    1,
+		Version:        1,
+		UserID:         TestUser.ID,
+		CreatedAt:      time.Now().UTC(),
+		UpdatedAt:      time.Now().UTC(),
 	}
 	TestArticle3 = &article.Article{
-		ID:          uuid.NewString(),
-		Title:       "Title 3",
-		Content:     "<div><a onblur=\"alert(secret)\" href=\"http://www.google.com\">Google</a><p>article content 3</p></div>",
-		ArticleLink: "https://unclatter.com/3",
-		UserID:      TestUser.ID,
-		CreatedAt:   time.Now().UTC(),
-		UpdatedAt:   time.Now().UTC(),
+		ID:             uuid.NewString(),
+		Title:          "Title 3",
+		Content:        "<div><a onblur=\"alert(secret)\" href=\"http://www.google.com\">Google</a><p>article content 3</p></div>",
+		ArticleLink:    "https://unclatter.com/3",
+		NormalizedLink: "https://unclatter.com/3",
+		Language:       "english",
+		Domain:         "unclatter.com",
+		ReadingTime:    1,
+		Version:        1,
+		UserID:         TestUser.ID,
+		CreatedAt:      time.Now().UTC(),
+		UpdatedAt:      time.Now().UTC(),
 	}
 )
 
@@ -84,3 +99,7 @@ func (a AnyTime) Match(v driver.Value) bool {
 	_, ok := v.(time.Time)
 	return ok
 }
+
+func Ptr[T any](v T) *T {
+	return &v
+}